@@ -0,0 +1,24 @@
+package main
+
+import "github.com/supermodeltools/arch-docs/internal/pssg/build"
+
+func runBuild(args []string) error {
+	fs := newFlagSet("build")
+	var cf configFlags
+	cf.register(fs)
+	force := fs.Bool("force", false, "rebuild everything, ignoring caches")
+	watchFlag := fs.Bool("watch", false, "rebuild when the config, data, templates or static files change")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *watchFlag {
+		return watch(&cf, *force)
+	}
+
+	cfg, err := cf.load()
+	if err != nil {
+		return err
+	}
+	return build.NewBuilder(cfg, *force).Build()
+}
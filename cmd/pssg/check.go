@@ -0,0 +1,63 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/supermodeltools/arch-docs/internal/pssg/check"
+	"github.com/supermodeltools/arch-docs/internal/pssg/loader"
+)
+
+func runCheck(args []string) error {
+	fs := newFlagSet("check")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: pssg check [flags]\n\nRun every validation without writing output: config, content profiles,\ntemplates, duplicate slugs, dangling relations and JSON-LD.\n\n")
+		fs.PrintDefaults()
+	}
+	var cf configFlags
+	cf.register(fs)
+	failOnWarning := fs.Bool("fail-on-warning", false, "exit non-zero on warnings too")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	start := time.Now()
+	cfg, err := cf.load()
+	if err != nil {
+		return err
+	}
+	entities, err := loader.New(cfg).Load()
+	if err != nil {
+		entities = nil // reported by the config checks
+	}
+
+	var diags []check.Diagnostic
+	diags = append(diags, check.Config(cfg, entities)...)
+	diags = append(diags, check.Templates(cfg)...)
+	diags = append(diags, check.Validation(cfg, entities)...)
+	diags = append(diags, check.Slugs(cfg, entities)...)
+	diags = append(diags, check.Content(cfg, entities)...)
+	diags = append(diags, check.Schema(cfg, entities)...)
+	if outputFormat == "json" {
+		if diags == nil {
+			diags = []check.Diagnostic{}
+		}
+		if err := writeJSON(diags); err != nil {
+			return err
+		}
+	} else {
+		for _, d := range diags {
+			fmt.Fprintln(os.Stderr, d)
+		}
+	}
+
+	errs := check.Errors(diags)
+	warns := len(diags) - errs
+	if errs > 0 || (*failOnWarning && warns > 0) {
+		return fmt.Errorf("%d error(s), %d warning(s)", errs, warns)
+	}
+	fmt.Fprintf(os.Stderr, "%d entities checked in %s: ok (%d warning(s))\n",
+		len(entities), time.Since(start).Round(time.Millisecond), warns)
+	return nil
+}
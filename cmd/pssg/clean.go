@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/supermodeltools/arch-docs/internal/pssg/clean"
+)
+
+func runClean(args []string) error {
+	fs := newFlagSet("clean")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: pssg clean [flags]\n\nRemove the files the last build generated, leaving anything else in the output directory alone.\n\n")
+		fs.PrintDefaults()
+	}
+	var cf configFlags
+	cf.register(fs)
+	var opts clean.Options
+	fs.BoolVar(&opts.Cache, "cache", false, "also remove paths.cache (output manifests and deploy state)")
+	fs.BoolVar(&opts.Enrichment, "enrichment", false, "also remove enrichment.cache_dir (costs API calls to rebuild)")
+	fs.BoolVar(&opts.Force, "force", false, "remove generated files even if they were edited since the build")
+	fs.BoolVar(&opts.DryRun, "dry-run", false, "print what would be removed without removing it")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	cfg, err := cf.load()
+	if err != nil {
+		return err
+	}
+	if opts.DryRun {
+		opts.Log = os.Stdout
+	}
+	res, err := clean.Run(cfg, opts)
+	if err != nil {
+		return err
+	}
+
+	verb := "Removed"
+	if opts.DryRun {
+		verb = "Would remove"
+	}
+	fmt.Printf("%s %d generated file(s) from %s\n", verb, res.Removed, cfg.Paths.Output)
+	if res.Missing > 0 {
+		fmt.Printf("%d file(s) in the manifest were already gone\n", res.Missing)
+	}
+	if len(res.Modified) > 0 {
+		fmt.Printf("Kept %d file(s) edited since the build (use --force to remove):\n", len(res.Modified))
+		for _, rel := range res.Modified {
+			fmt.Printf("  %s\n", rel)
+		}
+	}
+	return nil
+}
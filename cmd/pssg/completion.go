@@ -0,0 +1,213 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// subcommands are positional words that select a nested command with its
+// own flags.
+var subcommands = map[string][]string{
+	"list": {"entities", "terms"},
+}
+
+// argWords are the fixed positional words a command accepts.
+var argWords = map[string][]string{
+	"deploy":     {"github-pages", "s3"},
+	"completion": {"bash", "zsh", "fish"},
+}
+
+func runCompletion(args []string) error {
+	fs := newFlagSet("completion")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: pssg completion bash|zsh|fish\n\n"+
+			"Print a completion script. For example:\n\n"+
+			"  pssg completion bash > /etc/bash_completion.d/pssg\n"+
+			"  pssg completion zsh > \"${fpath[1]}/_pssg\"\n"+
+			"  pssg completion fish > ~/.config/fish/completions/pssg.fish\n")
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("expected a shell")
+	}
+
+	specs := completionSpecs()
+	switch fs.Arg(0) {
+	case "bash":
+		writeBash(os.Stdout, specs)
+	case "zsh":
+		writeZsh(os.Stdout, specs)
+	case "fish":
+		writeFish(os.Stdout, specs)
+	default:
+		return fmt.Errorf("unknown shell %q (want bash, zsh or fish)", fs.Arg(0))
+	}
+	return nil
+}
+
+// completionSpec is what a shell needs to complete one command.
+type completionSpec struct {
+	name    string
+	summary string
+	flags   []*flag.Flag
+	words   []string
+}
+
+// completionSpecs runs every command with -h while collecting the flag
+// sets it creates. Commands parse their flags before doing anything else,
+// so this has no side effects.
+func completionSpecs() []completionSpec {
+	var specs []completionSpec
+	for _, c := range commands {
+		if c.name == "help" || c.name == "completion" {
+			specs = append(specs, completionSpec{name: c.name, summary: c.summary, words: helpWords(c.name)})
+			continue
+		}
+		flagSets = make(map[string]*flag.FlagSet)
+		if subs, ok := subcommands[c.name]; ok {
+			for _, sub := range subs {
+				c.run([]string{sub, "-h"})
+			}
+		} else {
+			c.run([]string{"-h"})
+		}
+
+		seen := make(map[string]bool)
+		spec := completionSpec{name: c.name, summary: c.summary, words: argWords[c.name]}
+		spec.words = append(spec.words, subcommands[c.name]...)
+		for _, fs := range flagSets {
+			fs.VisitAll(func(f *flag.Flag) {
+				if !seen[f.Name] {
+					seen[f.Name] = true
+					spec.flags = append(spec.flags, f)
+				}
+			})
+		}
+		sort.Slice(spec.flags, func(i, j int) bool { return spec.flags[i].Name < spec.flags[j].Name })
+		specs = append(specs, spec)
+	}
+	flagSets = nil
+	return specs
+}
+
+func helpWords(name string) []string {
+	if name == "completion" {
+		return argWords[name]
+	}
+	var words []string
+	for _, c := range commands {
+		if c.name != "help" {
+			words = append(words, c.name)
+		}
+	}
+	return words
+}
+
+// dashes renders a flag as users type it: -o for one letter, --name otherwise.
+func dashes(f *flag.Flag) string {
+	if len(f.Name) == 1 {
+		return "-" + f.Name
+	}
+	return "--" + f.Name
+}
+
+func isBoolFlag(f *flag.Flag) bool {
+	b, ok := f.Value.(interface{ IsBoolFlag() bool })
+	return ok && b.IsBoolFlag()
+}
+
+// describe shortens a usage string to its first clause for shells that
+// show descriptions inline.
+func describe(usage string) string {
+	if i := strings.IndexAny(usage, ",;("); i > 0 {
+		usage = usage[:i]
+	}
+	return strings.TrimSpace(usage)
+}
+
+func writeBash(w io.Writer, specs []completionSpec) {
+	var names []string
+	for _, s := range specs {
+		names = append(names, s.name)
+	}
+	fmt.Fprintf(w, "# bash completion for pssg\n\n_pssg() {\n")
+	fmt.Fprintf(w, "\tlocal cur=\"${COMP_WORDS[COMP_CWORD]}\" opts=\"\" words=\"\"\n")
+	fmt.Fprintf(w, "\tif [ \"$COMP_CWORD\" -eq 1 ]; then\n")
+	fmt.Fprintf(w, "\t\tCOMPREPLY=($(compgen -W %q -- \"$cur\"))\n\t\treturn\n\tfi\n", strings.Join(names, " "))
+	fmt.Fprintf(w, "\tcase \"${COMP_WORDS[1]}\" in\n")
+	for _, s := range specs {
+		var flags []string
+		for _, f := range s.flags {
+			flags = append(flags, dashes(f))
+		}
+		flags = append(flags, "--output")
+		fmt.Fprintf(w, "\t%s) opts=%q; words=%q ;;\n", s.name, strings.Join(flags, " "), strings.Join(s.words, " "))
+	}
+	fmt.Fprintf(w, "\tesac\n")
+	fmt.Fprintf(w, "\tif [[ \"$cur\" == -* ]]; then\n")
+	fmt.Fprintf(w, "\t\tCOMPREPLY=($(compgen -W \"$opts\" -- \"$cur\"))\n")
+	fmt.Fprintf(w, "\telif [ -n \"$words\" ]; then\n")
+	fmt.Fprintf(w, "\t\tCOMPREPLY=($(compgen -W \"$words\" -- \"$cur\"))\n")
+	fmt.Fprintf(w, "\tfi\n}\n\ncomplete -o default -F _pssg pssg\n")
+}
+
+func writeZsh(w io.Writer, specs []completionSpec) {
+	esc := func(s string) string {
+		return strings.NewReplacer("'", "'\\''", "[", "\\[", "]", "\\]", ":", "\\:").Replace(s)
+	}
+	fmt.Fprintf(w, "#compdef pssg\n\n_pssg() {\n")
+	fmt.Fprintf(w, "\tif (( CURRENT == 2 )); then\n\t\tlocal -a cmds\n\t\tcmds=(\n")
+	for _, s := range specs {
+		fmt.Fprintf(w, "\t\t\t'%s:%s'\n", s.name, esc(s.summary))
+	}
+	fmt.Fprintf(w, "\t\t)\n\t\t_describe 'command' cmds\n\t\treturn\n\tfi\n")
+	fmt.Fprintf(w, "\tlocal cmd=$words[2]\n\tshift words\n\t(( CURRENT-- ))\n\tcase $cmd in\n")
+	for _, s := range specs {
+		fmt.Fprintf(w, "\t%s)\n\t\t_arguments \\\n", s.name)
+		for _, f := range s.flags {
+			if isBoolFlag(f) {
+				fmt.Fprintf(w, "\t\t\t'%s[%s]' \\\n", dashes(f), esc(describe(f.Usage)))
+			} else {
+				fmt.Fprintf(w, "\t\t\t'%s=[%s]:value:_files' \\\n", dashes(f), esc(describe(f.Usage)))
+			}
+		}
+		fmt.Fprintf(w, "\t\t\t'--output=[report format]:format:(text json)' \\\n")
+		if len(s.words) > 0 {
+			fmt.Fprintf(w, "\t\t\t'1:argument:(%s)' \\\n", strings.Join(s.words, " "))
+		}
+		fmt.Fprintf(w, "\t\t\t'*:file:_files'\n\t\t;;\n")
+	}
+	fmt.Fprintf(w, "\tesac\n}\n\n_pssg \"$@\"\n")
+}
+
+func writeFish(w io.Writer, specs []completionSpec) {
+	esc := func(s string) string { return strings.ReplaceAll(s, "'", "\\'") }
+	fmt.Fprintf(w, "# fish completion for pssg\n\n")
+	fmt.Fprintf(w, "complete -c pssg -l output -x -a 'text json' -d 'report format'\n")
+	for _, s := range specs {
+		fmt.Fprintf(w, "complete -c pssg -f -n __fish_use_subcommand -a %s -d '%s'\n", s.name, esc(s.summary))
+	}
+	for _, s := range specs {
+		cond := fmt.Sprintf("'__fish_seen_subcommand_from %s'", s.name)
+		if len(s.words) > 0 {
+			fmt.Fprintf(w, "complete -c pssg -f -n %s -a '%s'\n", cond, strings.Join(s.words, " "))
+		}
+		for _, f := range s.flags {
+			opt := "-l " + f.Name
+			if len(f.Name) == 1 {
+				opt = "-s " + f.Name
+			}
+			if !isBoolFlag(f) {
+				opt += " -r"
+			}
+			fmt.Fprintf(w, "complete -c pssg -n %s %s -d '%s'\n", cond, opt, esc(describe(f.Usage)))
+		}
+	}
+}
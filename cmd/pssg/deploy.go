@@ -0,0 +1,40 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/supermodeltools/arch-docs/internal/pssg/deploy"
+)
+
+func runDeploy(args []string) error {
+	fs := newFlagSet("deploy")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: pssg deploy [flags] github-pages|s3\n\nPublish the built output. Run pssg build first.\n\n")
+		fs.PrintDefaults()
+	}
+	var cf configFlags
+	cf.register(fs)
+	var opts deploy.Options
+	fs.BoolVar(&opts.DryRun, "dry-run", false, "print the commands without running them")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("expected a deploy target")
+	}
+	opts.Log = os.Stderr
+
+	cfg, err := cf.load()
+	if err != nil {
+		return err
+	}
+	switch fs.Arg(0) {
+	case "github-pages", "gh-pages":
+		return deploy.GitHubPages(cfg, opts)
+	case "s3":
+		return deploy.S3(cfg, opts)
+	}
+	return fmt.Errorf("unknown deploy target %q (want github-pages or s3)", fs.Arg(0))
+}
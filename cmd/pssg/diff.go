@@ -0,0 +1,193 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/supermodeltools/arch-docs/internal/pssg/manifest"
+	"github.com/supermodeltools/arch-docs/internal/pssg/sitediff"
+)
+
+func runDiff(args []string) error {
+	fs := newFlagSet("diff")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: pssg diff [flags] [dirA dirB]\n\n"+
+			"Compare two build outputs. With no directories, compare the last two\n"+
+			"builds of the configured site by their manifests (file lists only).\n\n")
+		fs.PrintDefaults()
+	}
+	var cf configFlags
+	cf.register(fs)
+	maxLines := fs.Int("lines", 20, "maximum text diff lines shown per page (0 for none)")
+	all := fs.Bool("all", false, "list non-page files and markup-only changes too")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	switch fs.NArg() {
+	case 0:
+		return diffManifests(&cf, *all)
+	case 2:
+		return diffDirs(fs.Arg(0), fs.Arg(1), *maxLines, *all)
+	}
+	fs.Usage()
+	return fmt.Errorf("expected two directories or none")
+}
+
+func diffDirs(a, b string, maxLines int, all bool) error {
+	for _, dir := range []string{a, b} {
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			return fmt.Errorf("%s is not a directory", dir)
+		}
+	}
+	changes, err := sitediff.Dirs(a, b)
+	if err != nil {
+		return err
+	}
+	if outputFormat == "json" {
+		report := newDiffReport(changes)
+		for i, c := range report.Changed {
+			if !c.Page {
+				continue
+			}
+			d, err := sitediff.Page(a, b, c.Path)
+			if err != nil {
+				return err
+			}
+			report.Changed[i].Lines = d.Lines
+			report.Changed[i].TooLarge = d.TooLarge
+		}
+		return writeJSON(report)
+	}
+
+	printList("Added", changes.Added, all)
+	printList("Removed", changes.Removed, all)
+
+	var markupOnly []string
+	header := false
+	for _, rel := range changes.Changed {
+		if !sitediff.IsPage(rel) {
+			if all {
+				fmt.Printf("~ %s\n", rel)
+			}
+			continue
+		}
+		d, err := sitediff.Page(a, b, rel)
+		if err != nil {
+			return err
+		}
+		if !d.Significant() {
+			markupOnly = append(markupOnly, rel)
+			continue
+		}
+		if !header {
+			fmt.Printf("\nChanged:\n")
+			header = true
+		}
+		fmt.Printf("~ %s\n", rel)
+		if d.TooLarge {
+			fmt.Printf("    (too large to diff)\n")
+			continue
+		}
+		for i, line := range d.Lines {
+			if i == maxLines {
+				fmt.Printf("    ... %d more line(s)\n", len(d.Lines)-i)
+				break
+			}
+			fmt.Printf("    %s\n", line)
+		}
+	}
+	if len(markupOnly) > 0 {
+		fmt.Printf("\n%d page(s) changed markup only\n", len(markupOnly))
+		if all {
+			for _, rel := range markupOnly {
+				fmt.Printf("~ %s\n", rel)
+			}
+		}
+	}
+
+	fmt.Printf("\n%s\n", sitediff.Summary(changes))
+	return nil
+}
+
+func diffManifests(cf *configFlags, all bool) error {
+	cfg, err := cf.load()
+	if err != nil {
+		return err
+	}
+	dir := cfg.Paths.Cache
+	cur, err := manifest.Load(filepath.Join(dir, manifest.FileName))
+	if err != nil {
+		return err
+	}
+	prev, err := manifest.Load(filepath.Join(dir, manifest.PrevFileName))
+	if err != nil {
+		return err
+	}
+	if cur == nil || prev == nil {
+		return fmt.Errorf("need two builds to compare; pass two output directories instead")
+	}
+
+	changes := manifest.Compare(prev.Files, cur.Files)
+	if outputFormat == "json" {
+		report := newDiffReport(changes)
+		report.From, report.To = &prev.Built, &cur.Built
+		return writeJSON(report)
+	}
+	fmt.Printf("Comparing build of %s with %s\n", prev.Built.Local().Format("2006-01-02 15:04"), cur.Built.Local().Format("2006-01-02 15:04"))
+	printList("Added", changes.Added, all)
+	printList("Removed", changes.Removed, all)
+	printList("Changed", changes.Changed, all)
+	fmt.Printf("\n%s\n", sitediff.Summary(changes))
+	return nil
+}
+
+// diffReport is the --output json form of a diff. It lists every file,
+// ignoring --all and --lines; changed pages carry their full text diff.
+type diffReport struct {
+	From    *time.Time    `json:"from,omitempty"` // build times, for manifest diffs
+	To      *time.Time    `json:"to,omitempty"`
+	Added   []string      `json:"added"`
+	Removed []string      `json:"removed"`
+	Changed []changedFile `json:"changed"`
+	Summary string        `json:"summary"`
+}
+
+type changedFile struct {
+	Path     string   `json:"path"`
+	Page     bool     `json:"page"`
+	Lines    []string `json:"lines,omitempty"` // empty for markup-only page changes
+	TooLarge bool     `json:"too_large,omitempty"`
+}
+
+func newDiffReport(c manifest.Changes) *diffReport {
+	r := &diffReport{
+		Added:   append([]string{}, c.Added...),
+		Removed: append([]string{}, c.Removed...),
+		Changed: []changedFile{},
+		Summary: sitediff.Summary(c),
+	}
+	for _, rel := range c.Changed {
+		r.Changed = append(r.Changed, changedFile{Path: rel, Page: sitediff.IsPage(rel)})
+	}
+	return r
+}
+
+func printList(title string, paths []string, all bool) {
+	var shown []string
+	for _, rel := range paths {
+		if all || sitediff.IsPage(rel) {
+			shown = append(shown, rel)
+		}
+	}
+	if len(shown) == 0 {
+		return
+	}
+	fmt.Printf("\n%s:\n", title)
+	mark := map[string]string{"Added": "+", "Removed": "-", "Changed": "~"}[title]
+	for _, rel := range shown {
+		fmt.Printf("%s %s\n", mark, rel)
+	}
+}
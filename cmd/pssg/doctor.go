@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+
+	"github.com/supermodeltools/arch-docs/internal/pssg/check"
+	"github.com/supermodeltools/arch-docs/internal/pssg/loader"
+)
+
+func runDoctor(args []string) error {
+	fs := newFlagSet("doctor")
+	var cf configFlags
+	cf.register(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	cfg, err := cf.load()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("pssg built with %s (%s/%s)\n", runtime.Version(), runtime.GOOS, runtime.GOARCH)
+
+	entities, err := loader.New(cfg).Load()
+	if err != nil {
+		entities = nil // reported by the config checks
+	}
+	fmt.Printf("%d entities in %s\n", len(entities), cfg.Paths.Data)
+
+	sections := []struct {
+		name  string
+		diags []check.Diagnostic
+	}{
+		{"Environment", check.Environment(cfg)},
+		{"Config", check.Config(cfg, entities)},
+		{"Content", check.Content(cfg, entities)},
+	}
+
+	var errs, warns int
+	for _, s := range sections {
+		fmt.Printf("\n%s:\n", s.name)
+		if len(s.diags) == 0 {
+			fmt.Printf("  ok\n")
+			continue
+		}
+		for _, d := range s.diags {
+			fmt.Printf("  %s\n", d)
+			if d.Hint != "" {
+				fmt.Printf("      fix: %s\n", d.Hint)
+			}
+		}
+		n := check.Errors(s.diags)
+		errs += n
+		warns += len(s.diags) - n
+	}
+
+	fmt.Println()
+	if errs > 0 {
+		return fmt.Errorf("%d error(s), %d warning(s)", errs, warns)
+	}
+	fmt.Fprintf(os.Stderr, "no errors (%d warning(s))\n", warns)
+	return nil
+}
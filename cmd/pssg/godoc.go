@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/supermodeltools/arch-docs/internal/pssg/enrichment"
+	"github.com/supermodeltools/arch-docs/internal/pssg/loader"
+	"github.com/supermodeltools/arch-docs/internal/pssg/scan"
+)
+
+func runGoDoc(args []string) error {
+	fs := newFlagSet("godoc")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: pssg godoc [flags] [module]\n\n"+
+			"Store the package docs, exported API and examples of a Go module in the\n"+
+			"enrichment cache, for each entity whose import path field names a package.\n"+
+			"The module defaults to enrichment.godoc.module, then paths.source_dir, then\n"+
+			"the config directory. Nothing is sent anywhere; the source is parsed locally.\n\n")
+		fs.PrintDefaults()
+	}
+	var cf configFlags
+	cf.register(fs)
+	field := fs.String("field", "", "entity field holding the import path (default enrichment.godoc.field)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	cfg, err := cf.load()
+	if err != nil {
+		return err
+	}
+	if cfg.Enrichment.CacheDir == "" {
+		return fmt.Errorf("enrichment.cache_dir is not set")
+	}
+	if *field == "" {
+		*field = cfg.Enrichment.GoDoc.Field
+	}
+	root := cfg.Enrichment.GoDoc.Module
+	switch {
+	case fs.NArg() > 0:
+		root = fs.Arg(0)
+	case root == "" && cfg.Paths.SourceDir != "":
+		root = cfg.Paths.SourceDir
+	case root == "":
+		root = cfg.ConfigDir
+	}
+
+	docs, err := scan.Docs(root)
+	if err != nil {
+		return err
+	}
+	entities, err := loader.New(cfg).Load()
+	if err != nil {
+		return err
+	}
+	byPath := make(map[string]*scan.Doc, len(docs))
+	for _, d := range docs {
+		byPath[d.ImportPath] = d
+	}
+
+	written := 0
+	used := make(map[string]bool)
+	for _, e := range entities {
+		d, ok := byPath[e.GetString(*field)]
+		if !ok {
+			continue
+		}
+		if err := enrichment.MergeCache(cfg.Enrichment.CacheDir, e.Slug, e.SourceHash, "godoc", d); err != nil {
+			return err
+		}
+		used[d.ImportPath] = true
+		written++
+	}
+	for _, d := range docs {
+		if !used[d.ImportPath] {
+			fmt.Fprintf(os.Stderr, "no entity for %s\n", d.ImportPath)
+		}
+	}
+	fmt.Fprintf(os.Stderr, "documented %d packages, wrote godoc for %d entities to %s\n", len(docs), written, cfg.Enrichment.CacheDir)
+	return nil
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/supermodeltools/arch-docs/internal/pssg/scaffold"
+)
+
+func runInit(args []string) error {
+	fs := newFlagSet("init")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: pssg init [flags] [dir]\n\nScaffold a starter site in dir (default: the current directory).\n\n")
+		fs.PrintDefaults()
+	}
+	var opts scaffold.Options
+	fs.StringVar(&opts.Name, "name", "", "site name (default \"My Site\")")
+	fs.StringVar(&opts.BaseURL, "base-url", "", "site base URL (default http://localhost:8080)")
+	fs.BoolVar(&opts.Force, "force", false, "overwrite existing files")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 1 {
+		return fmt.Errorf("expected at most one directory, got %d", fs.NArg())
+	}
+
+	dir := "."
+	if fs.NArg() == 1 {
+		dir = fs.Arg(0)
+	}
+	written, err := scaffold.Init(dir, opts)
+	if err != nil {
+		return err
+	}
+	for _, name := range written {
+		fmt.Fprintf(os.Stderr, "  created %s\n", filepath.Join(dir, name))
+	}
+	fmt.Fprintf(os.Stderr, "\nNext: pssg build --config %s\n", filepath.Join(dir, "pssg.yaml"))
+	return nil
+}
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/supermodeltools/arch-docs/internal/pssg/entity"
+	"github.com/supermodeltools/arch-docs/internal/pssg/loader"
+	"github.com/supermodeltools/arch-docs/internal/pssg/taxonomy"
+)
+
+func runList(args []string) error {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		fmt.Fprintf(os.Stderr, "Usage: pssg list entities [flags]\n       pssg list terms [flags] <taxonomy>\n")
+		if len(args) > 0 && (args[0] == "-h" || args[0] == "--help") {
+			return flag.ErrHelp
+		}
+		return fmt.Errorf("expected entities or terms")
+	}
+	switch args[0] {
+	case "entities":
+		return listEntities(args[1:])
+	case "terms":
+		return listTerms(args[1:])
+	}
+	return fmt.Errorf("unknown list %q (want entities or terms)", args[0])
+}
+
+func listEntities(args []string) error {
+	fs := newFlagSet("list entities")
+	var cf configFlags
+	cf.register(fs)
+	var filters stringList
+	fs.Var(&filters, "filter", "only entities whose field matches, e.g. cuisine=italian or nutrition.vegan=true (repeatable)")
+	format := fs.String("format", "table", "output format: table or json")
+	fields := fs.String("fields", "title", "comma-separated fields to show in table output (dot paths allowed)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if outputFormat == "json" {
+		*format = "json"
+	}
+
+	cfg, err := cf.load()
+	if err != nil {
+		return err
+	}
+	entities, err := loader.New(cfg).Load()
+	if err != nil {
+		return err
+	}
+
+	var matched []*entity.Entity
+	for _, e := range entities {
+		ok := true
+		for _, f := range filters {
+			key, want, found := strings.Cut(f, "=")
+			if !found {
+				return fmt.Errorf("invalid --filter %q (want field=value)", f)
+			}
+			if !matchField(e.GetPath(key), want) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			matched = append(matched, e)
+		}
+	}
+
+	switch *format {
+	case "json":
+		type item struct {
+			Slug   string                 `json:"slug"`
+			File   string                 `json:"file"`
+			Fields map[string]interface{} `json:"fields"`
+		}
+		items := make([]item, 0, len(matched))
+		for _, e := range matched {
+			items = append(items, item{Slug: e.Slug, File: e.SourceFile, Fields: e.Fields})
+		}
+		return writeJSON(items)
+	case "table":
+		cols := strings.Split(*fields, ",")
+		tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintf(tw, "SLUG\t%s\n", strings.ToUpper(strings.Join(cols, "\t")))
+		for _, e := range matched {
+			row := []string{e.Slug}
+			for _, c := range cols {
+				row = append(row, formatValue(e.GetPath(strings.TrimSpace(c))))
+			}
+			fmt.Fprintln(tw, strings.Join(row, "\t"))
+		}
+		return tw.Flush()
+	}
+	return fmt.Errorf("unknown format %q (want table or json)", *format)
+}
+
+func listTerms(args []string) error {
+	fs := newFlagSet("list terms")
+	var cf configFlags
+	cf.register(fs)
+	format := fs.String("format", "table", "output format: table or json")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if outputFormat == "json" {
+		*format = "json"
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("expected a taxonomy name")
+	}
+	name := fs.Arg(0)
+
+	cfg, err := cf.load()
+	if err != nil {
+		return err
+	}
+	entities, err := loader.New(cfg).Load()
+	if err != nil {
+		return err
+	}
+
+	var names []string
+	for _, tax := range taxonomy.BuildAll(entities, cfg.Taxonomies, nil) {
+		names = append(names, tax.Name)
+		if tax.Name != name {
+			continue
+		}
+		type term struct {
+			Name     string   `json:"name"`
+			Slug     string   `json:"slug"`
+			Entities []string `json:"entities"`
+		}
+		terms := make([]term, 0, len(tax.Entries))
+		for _, entry := range tax.Entries {
+			t := term{Name: entry.Name, Slug: entry.Slug}
+			for _, e := range entry.Entities {
+				t.Entities = append(t.Entities, e.Slug)
+			}
+			terms = append(terms, t)
+		}
+		switch *format {
+		case "json":
+			return writeJSON(terms)
+		case "table":
+			tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+			fmt.Fprintln(tw, "SLUG\tNAME\tCOUNT")
+			for _, t := range terms {
+				fmt.Fprintf(tw, "%s\t%s\t%d\n", t.Slug, t.Name, len(t.Entities))
+			}
+			return tw.Flush()
+		}
+		return fmt.Errorf("unknown format %q (want table or json)", *format)
+	}
+	return fmt.Errorf("unknown taxonomy %q (have %s)", name, strings.Join(names, ", "))
+}
+
+// matchField reports whether a field value equals want. Strings compare by
+// slug, so "italian" matches "Italian"; lists match if any item does.
+func matchField(v interface{}, want string) bool {
+	switch val := v.(type) {
+	case nil:
+		return want == ""
+	case []interface{}:
+		for _, item := range val {
+			if matchField(item, want) {
+				return true
+			}
+		}
+		return false
+	case string:
+		return val == want || entity.ToSlug(val) == entity.ToSlug(want)
+	case bool:
+		b, err := strconv.ParseBool(want)
+		return err == nil && b == val
+	}
+	return fmt.Sprint(v) == want
+}
+
+func formatValue(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case []interface{}:
+		parts := make([]string, len(val))
+		for i, item := range val {
+			parts[i] = formatValue(item)
+		}
+		return strings.Join(parts, ", ")
+	case map[string]interface{}:
+		data, _ := json.Marshal(val)
+		return string(data)
+	}
+	return fmt.Sprint(v)
+}
+
+func writeJSON(v interface{}) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
@@ -0,0 +1,212 @@
+// Command pssg builds a static site from markdown entities and a YAML config.
+//
+// Usage:
+//
+//	pssg <command> [flags]
+//
+// Run "pssg help" for the list of commands.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/supermodeltools/arch-docs/internal/pssg/config"
+	"github.com/supermodeltools/arch-docs/internal/pssg/entity"
+	"github.com/supermodeltools/arch-docs/internal/pssg/logging"
+)
+
+// command is a pssg subcommand.
+type command struct {
+	name    string
+	summary string
+	run     func(args []string) error
+}
+
+var commands []command
+
+func init() {
+	commands = []command{
+		{"init", "Scaffold a starter site", runInit},
+		{"build", "Build the site", runBuild},
+		{"clean", "Remove generated output, keeping files the build did not write", runClean},
+		{"new", "Create a new entity file", runNew},
+		{"scan", "Write an entity per package of a Go repository", runScan},
+		{"godoc", "Add Go package docs and examples to the enrichment cache", runGoDoc},
+		{"metrics", "Add Go package size, dependency and coverage metrics to the enrichment cache", runMetrics},
+		{"deploy", "Publish the output to GitHub Pages or S3", runDeploy},
+		{"diff", "Compare two builds page by page", runDiff},
+		{"list", "List entities or taxonomy terms", runList},
+		{"stats", "Summarize content: counts, field fill rates, orphans, coverage", runStats},
+		{"check", "Run every validation without building (for pre-commit and CI)", runCheck},
+		{"validate", "Check the config, templates, paths and env vars", runValidate},
+		{"doctor", "Check the environment, templates and content health", runDoctor},
+		{"schema", "Print the JSON Schema for the config file", runSchema},
+		{"completion", "Print a shell completion script (bash, zsh or fish)", runCompletion},
+		{"help", "Show help for pssg or a command", runHelp},
+	}
+}
+
+// outputFormat is the global --output flag: "text" (the default) or
+// "json". Commands with a report to print honor it.
+var outputFormat = "text"
+
+func main() {
+	args, err := globalFlags(os.Args[1:])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "pssg: %v\n\n", err)
+		usage()
+		os.Exit(2)
+	}
+	if len(args) < 1 {
+		usage()
+		os.Exit(2)
+	}
+
+	name := args[0]
+	if name == "-h" || name == "--help" {
+		name = "help"
+	}
+	for _, c := range commands {
+		if c.name != name {
+			continue
+		}
+		if err := c.run(args[1:]); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				os.Exit(0)
+			}
+			fmt.Fprintf(os.Stderr, "pssg %s: %v\n", name, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	fmt.Fprintf(os.Stderr, "pssg: unknown command %q\n\n", name)
+	usage()
+	os.Exit(2)
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: pssg <command> [flags]\n\nCommands:\n")
+	for _, c := range commands {
+		fmt.Fprintf(os.Stderr, "  %-11s %s\n", c.name, c.summary)
+	}
+	fmt.Fprintf(os.Stderr, "\nGlobal flags:\n  --output text|json  print reports (stats, list, diff, check, validate) as JSON\n")
+	fmt.Fprintf(os.Stderr, "\nRun \"pssg <command> -h\" for command flags.\n")
+}
+
+// globalFlags removes --output from args, wherever it appears before a
+// "--" terminator, and records its value.
+func globalFlags(args []string) ([]string, error) {
+	var rest []string
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		if a == "--" {
+			rest = append(rest, args[i:]...)
+			break
+		}
+		name, value, hasValue := strings.Cut(strings.TrimLeft(a, "-"), "=")
+		if !strings.HasPrefix(a, "-") || name != "output" {
+			rest = append(rest, a)
+			continue
+		}
+		if !hasValue {
+			if i+1 == len(args) {
+				return nil, fmt.Errorf("--output needs a value (text or json)")
+			}
+			i++
+			value = args[i]
+		}
+		if value != "text" && value != "json" {
+			return nil, fmt.Errorf("unknown --output %q (want text or json)", value)
+		}
+		outputFormat = value
+	}
+	return rest, nil
+}
+
+// flagSets collects every flag set created while it is non-nil, so
+// completion scripts can list each command's flags.
+var flagSets map[string]*flag.FlagSet
+
+func newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	if flagSets != nil {
+		fs.SetOutput(io.Discard)
+		flagSets[name] = fs
+	}
+	return fs
+}
+
+func runHelp(args []string) error {
+	if len(args) == 0 {
+		usage()
+		return nil
+	}
+	for _, c := range commands {
+		if c.name == args[0] && c.name != "help" {
+			return c.run([]string{"-h"})
+		}
+	}
+	return fmt.Errorf("unknown command %q", args[0])
+}
+
+// stringList is a repeatable string flag.
+type stringList []string
+
+func (s *stringList) String() string { return strings.Join(*s, ", ") }
+
+func (s *stringList) Set(v string) error {
+	*s = append(*s, v)
+	return nil
+}
+
+// configFlags are the flags shared by every command that loads a config.
+type configFlags struct {
+	path      string
+	overrides stringList
+	strict    bool
+	verbose   bool
+	quiet     bool
+	logFormat string
+}
+
+func (c *configFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&c.path, "config", "pssg.yaml", "path to the site config, or an https URL pinned with #sha256=<digest>")
+	fs.Var(&c.overrides, "set", "override a config key, e.g. --set site.base_url=https://preview.example.com (repeatable)")
+	fs.BoolVar(&c.strict, "strict", false, "reject unknown config keys")
+	fs.BoolVar(&c.verbose, "verbose", false, "log per-stage detail")
+	fs.BoolVar(&c.quiet, "quiet", false, "log warnings and errors only")
+	fs.StringVar(&c.logFormat, "log-format", "text", "log format: text or json (one object per line)")
+}
+
+// load sets up logging from the flags and loads the config.
+func (c *configFlags) load() (*config.Config, error) {
+	if err := c.setupLogging(); err != nil {
+		return nil, err
+	}
+	cfg, err := config.LoadWithOptions(c.path, config.LoadOptions{Overrides: c.overrides, Strict: c.strict})
+	if err != nil {
+		return nil, err
+	}
+	entity.SetSlugStyle(cfg.Site.Language, cfg.Site.Slugs == "unicode")
+	return cfg, nil
+}
+
+func (c *configFlags) setupLogging() error {
+	level := slog.LevelInfo
+	switch {
+	case c.verbose && c.quiet:
+		return fmt.Errorf("--verbose and --quiet are mutually exclusive")
+	case c.verbose:
+		level = slog.LevelDebug
+	case c.quiet:
+		level = slog.LevelWarn
+	}
+	return logging.Setup(os.Stderr, level, c.logFormat)
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/supermodeltools/arch-docs/internal/pssg/enrichment"
+	"github.com/supermodeltools/arch-docs/internal/pssg/loader"
+	"github.com/supermodeltools/arch-docs/internal/pssg/scan"
+)
+
+func runMetrics(args []string) error {
+	fs := newFlagSet("metrics")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: pssg metrics [flags] [module]\n\n"+
+			"Store the lines of code, file count, dependency counts and test coverage of\n"+
+			"a Go module's packages in the enrichment cache, for each entity whose import\n"+
+			"path field names a package. Coverage comes from a go test -coverprofile\n"+
+			"file. The module defaults to enrichment.metrics.module, then\n"+
+			"paths.source_dir, then the config directory.\n\n")
+		fs.PrintDefaults()
+	}
+	var cf configFlags
+	cf.register(fs)
+	field := fs.String("field", "", "entity field holding the import path (default enrichment.metrics.field)")
+	profile := fs.String("coverprofile", "", "coverage profile from go test -coverprofile (default enrichment.metrics.cover_profile)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	cfg, err := cf.load()
+	if err != nil {
+		return err
+	}
+	if cfg.Enrichment.CacheDir == "" {
+		return fmt.Errorf("enrichment.cache_dir is not set")
+	}
+	if *field == "" {
+		*field = cfg.Enrichment.Metrics.Field
+	}
+	if *profile == "" {
+		*profile = cfg.Enrichment.Metrics.CoverProfile
+	}
+	root := cfg.Enrichment.Metrics.Module
+	switch {
+	case fs.NArg() > 0:
+		root = fs.Arg(0)
+	case root == "" && cfg.Paths.SourceDir != "":
+		root = cfg.Paths.SourceDir
+	case root == "":
+		root = cfg.ConfigDir
+	}
+
+	res, err := scan.Scan(scan.Options{Root: root, Tests: true})
+	if err != nil {
+		return err
+	}
+	var coverage map[string]scan.Coverage
+	if *profile != "" {
+		if coverage, err = scan.ReadCoverProfile(*profile); err != nil {
+			return err
+		}
+	}
+	metrics := scan.PackageMetrics(res, coverage)
+	entities, err := loader.New(cfg).Load()
+	if err != nil {
+		return err
+	}
+	byPath := make(map[string]*scan.Metrics, len(metrics))
+	for _, m := range metrics {
+		byPath[m.ImportPath] = m
+	}
+
+	written := 0
+	used := make(map[string]bool)
+	for _, e := range entities {
+		m, ok := byPath[e.GetString(*field)]
+		if !ok {
+			continue
+		}
+		if err := enrichment.MergeCache(cfg.Enrichment.CacheDir, e.Slug, e.SourceHash, "metrics", m); err != nil {
+			return err
+		}
+		used[m.ImportPath] = true
+		written++
+	}
+	for _, m := range metrics {
+		if !used[m.ImportPath] {
+			fmt.Fprintf(os.Stderr, "no entity for %s\n", m.ImportPath)
+		}
+	}
+	fmt.Fprintf(os.Stderr, "measured %d packages, wrote metrics for %d entities to %s\n", len(metrics), written, cfg.Enrichment.CacheDir)
+	return nil
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/supermodeltools/arch-docs/internal/pssg/scaffold"
+)
+
+func runNew(args []string) error {
+	fs := newFlagSet("new")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: pssg new [flags] <slug>\n\nCreate a markdown entity pre-populated with the configured fields.\n\n")
+		fs.PrintDefaults()
+	}
+	var cf configFlags
+	cf.register(fs)
+	var opts scaffold.EntityOptions
+	fs.StringVar(&opts.Type, "type", "", "node type, selecting the archetype and type-specific fields")
+	fs.StringVar(&opts.Title, "title", "", "entity title (default: derived from the slug)")
+	fs.BoolVar(&opts.Force, "force", false, "overwrite an existing file")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("expected exactly one slug")
+	}
+	opts.Slug = fs.Arg(0)
+
+	cfg, err := cf.load()
+	if err != nil {
+		return err
+	}
+	path, err := scaffold.NewEntity(cfg, opts)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "created %s\n", path)
+	return nil
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/supermodeltools/arch-docs/internal/pssg/scan"
+)
+
+func runScan(args []string) error {
+	fs := newFlagSet("scan")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: pssg scan [flags] [repo]\n\n"+
+			"Describe each Go package of a repository (default: the current directory)\n"+
+			"as an entity: doc comment, import graph, lines of code and CODEOWNERS.\n"+
+			"Markdown files go to paths.data unless -o is given.\n\n")
+		fs.PrintDefaults()
+	}
+	var cf configFlags
+	cf.register(fs)
+	var opts scan.Options
+	out := fs.String("o", "", "output directory (markdown) or file (json); default paths.data, or stdout for json")
+	format := fs.String("format", "markdown", "output format: markdown (one entity file per package) or json")
+	fs.StringVar(&opts.RepoName, "repo-name", "", "repository name used in titles (default: last element of the module path)")
+	fs.StringVar(&opts.RepoURL, "repo-url", "", "repository URL for source links (default: site.repo_url)")
+	fs.StringVar(&opts.Branch, "branch", "main", "branch for source links")
+	fs.BoolVar(&opts.Tests, "tests", false, "count _test.go files towards files and lines")
+	force := fs.Bool("force", false, "overwrite entity files that scan did not write")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if outputFormat == "json" {
+		*format = "json"
+	}
+	if *format != "markdown" && *format != "json" {
+		return fmt.Errorf("unknown --format %q (want markdown or json)", *format)
+	}
+	opts.Root = "."
+	if fs.NArg() > 0 {
+		opts.Root = fs.Arg(0)
+	}
+
+	if *format == "markdown" && *out == "" || opts.RepoURL == "" {
+		cfg, err := cf.load()
+		if err != nil && *format == "markdown" && *out == "" {
+			return fmt.Errorf("%w (pass -o to scan without a config)", err)
+		}
+		if err == nil {
+			if *out == "" && *format == "markdown" {
+				*out = cfg.Paths.Data
+			}
+			if opts.RepoURL == "" {
+				opts.RepoURL = cfg.Site.RepoURL
+			}
+		}
+	} else if err := cf.setupLogging(); err != nil {
+		return err
+	}
+
+	res, err := scan.Scan(opts)
+	if err != nil {
+		return err
+	}
+
+	if *format == "json" {
+		data, err := json.MarshalIndent(res, "", "  ")
+		if err != nil {
+			return err
+		}
+		data = append(data, '\n')
+		if *out == "" || *out == "-" {
+			_, err = os.Stdout.Write(data)
+			return err
+		}
+		return os.WriteFile(*out, data, 0644)
+	}
+
+	wr, err := scan.WriteMarkdown(res, *out, *force)
+	if err != nil {
+		return err
+	}
+	for _, path := range wr.Skipped {
+		fmt.Fprintf(os.Stderr, "skipped %s: not written by pssg scan (use --force to replace it)\n", path)
+	}
+	fmt.Fprintf(os.Stderr, "scanned %d packages of %s, wrote %d entities to %s\n", len(res.Packages), res.Module, wr.Written, *out)
+	return nil
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/supermodeltools/arch-docs/internal/pssg/config"
+)
+
+func runSchema(args []string) error {
+	fs := newFlagSet("schema")
+	out := fs.String("o", "", "write the schema to a file instead of stdout")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(config.JSONSchema(), "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	if *out == "" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	return os.WriteFile(*out, data, 0644)
+}
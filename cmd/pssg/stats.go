@@ -0,0 +1,62 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/supermodeltools/arch-docs/internal/pssg/enrichment"
+	"github.com/supermodeltools/arch-docs/internal/pssg/loader"
+	"github.com/supermodeltools/arch-docs/internal/pssg/owners"
+	"github.com/supermodeltools/arch-docs/internal/pssg/stats"
+	"github.com/supermodeltools/arch-docs/internal/pssg/taxonomy"
+)
+
+func runStats(args []string) error {
+	fs := newFlagSet("stats")
+	var cf configFlags
+	cf.register(fs)
+	jsonOut := fs.String("json", "", "also write the report as JSON to this file (\"-\" for stdout)")
+	limit := fs.Int("limit", 10, "maximum items to print per list")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	cfg, err := cf.load()
+	if err != nil {
+		return err
+	}
+	entities, err := loader.New(cfg).Load()
+	if err != nil {
+		return err
+	}
+
+	if cfg.Owners.Enabled {
+		set, err := owners.ForConfig(cfg)
+		if err != nil {
+			return err
+		}
+		owners.Assign(entities, set, cfg.Owners)
+	}
+
+	var enrichmentData map[string]map[string]interface{}
+	if cfg.Enrichment.CacheDir != "" {
+		if enrichmentData, err = enrichment.ReadAllCaches(cfg.Enrichment.CacheDir); err != nil {
+			return err
+		}
+	}
+	taxonomies := taxonomy.BuildAll(entities, cfg.Taxonomies, enrichmentData)
+	report := stats.Compute(cfg, entities, taxonomies, enrichmentData)
+
+	if *jsonOut == "-" || outputFormat == "json" {
+		return writeJSON(report)
+	}
+	report.WriteText(os.Stdout, *limit)
+	if *jsonOut == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(*jsonOut, append(data, '\n'), 0644)
+}
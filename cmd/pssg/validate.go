@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/supermodeltools/arch-docs/internal/pssg/check"
+	"github.com/supermodeltools/arch-docs/internal/pssg/loader"
+)
+
+func runValidate(args []string) error {
+	fs := newFlagSet("validate")
+	var cf configFlags
+	cf.register(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	cfg, err := cf.load()
+	if err != nil {
+		return err
+	}
+
+	// Entities are only needed to confirm taxonomy fields are in use; a
+	// missing data dir is reported by the path checks instead.
+	entities, _ := loader.New(cfg).Load()
+
+	diags := check.Config(cfg, entities)
+	if outputFormat == "json" {
+		if diags == nil {
+			diags = []check.Diagnostic{}
+		}
+		if err := writeJSON(diags); err != nil {
+			return err
+		}
+	} else {
+		for _, d := range diags {
+			fmt.Fprintln(os.Stderr, d)
+		}
+	}
+
+	errs := check.Errors(diags)
+	if errs > 0 {
+		return fmt.Errorf("%d error(s), %d warning(s)", errs, len(diags)-errs)
+	}
+	fmt.Fprintf(os.Stderr, "%s: ok (%d warning(s))\n", cf.path, len(diags))
+	return nil
+}
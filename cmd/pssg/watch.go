@@ -0,0 +1,124 @@
+package main
+
+import (
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/supermodeltools/arch-docs/internal/pssg/build"
+	"github.com/supermodeltools/arch-docs/internal/pssg/config"
+)
+
+// watchInterval is how often watched files are polled for changes.
+const watchInterval = time.Second
+
+// watch rebuilds whenever the config (or a file it includes), the data,
+// the templates or the static files change. Config changes are reloaded and
+// validated first; an invalid config is reported and the previous config is
+// kept until the file is fixed. It only returns if the first load fails.
+func watch(cf *configFlags, force bool) error {
+	cfg, err := cf.load()
+	if err != nil {
+		return err
+	}
+	if err := build.NewBuilder(cfg, force).Build(); err != nil {
+		slog.Error("Build failed", "error", err)
+	}
+
+	state := snapshot(cfg.Files, watchedSite(cfg))
+	slog.Info("Watching for changes (Ctrl-C to stop)", "files", len(state))
+	for {
+		time.Sleep(watchInterval)
+		next := snapshot(cfg.Files, watchedSite(cfg))
+		changed := changedFiles(state, next)
+		if len(changed) == 0 {
+			continue
+		}
+		state = next
+		slog.Info("Changed", "file", changed[0], "more", len(changed)-1)
+
+		if touchesConfig(cfg, changed) {
+			reloaded, err := cf.load()
+			if err != nil {
+				slog.Error("Config error, keeping previous config", "error", err)
+				continue
+			}
+			slog.Info("Reloaded config", "config", cf.path)
+			cfg = reloaded
+			state = snapshot(cfg.Files, watchedSite(cfg))
+		}
+
+		if err := build.NewBuilder(cfg, force).Build(); err != nil {
+			slog.Error("Build failed", "error", err)
+		}
+	}
+}
+
+// watchedSite lists the directories whose contents feed the build.
+func watchedSite(cfg *config.Config) []string {
+	dirs := []string{cfg.Paths.Data, cfg.Paths.Templates, cfg.Paths.I18n}
+	if cfg.Paths.Static != "" {
+		dirs = append(dirs, cfg.Paths.Static)
+	}
+	if cfg.Theme != "" && cfg.Theme != config.DefaultTheme {
+		dirs = append(dirs, cfg.Theme)
+	}
+	return dirs
+}
+
+type fileStamp struct {
+	mod  time.Time
+	size int64
+}
+
+// snapshot records the modification time and size of the given files and
+// of every file under the given directories. Missing paths are skipped so a
+// deleted file shows up as a change.
+func snapshot(files, dirs []string) map[string]fileStamp {
+	state := make(map[string]fileStamp)
+	for _, f := range files {
+		if info, err := os.Stat(f); err == nil {
+			state[f] = fileStamp{info.ModTime(), info.Size()}
+		}
+	}
+	for _, dir := range dirs {
+		filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return nil
+			}
+			if info, err := d.Info(); err == nil {
+				state[path] = fileStamp{info.ModTime(), info.Size()}
+			}
+			return nil
+		})
+	}
+	return state
+}
+
+func changedFiles(prev, next map[string]fileStamp) []string {
+	var changed []string
+	for path, stamp := range next {
+		if old, ok := prev[path]; !ok || old != stamp {
+			changed = append(changed, path)
+		}
+	}
+	for path := range prev {
+		if _, ok := next[path]; !ok {
+			changed = append(changed, path)
+		}
+	}
+	return changed
+}
+
+func touchesConfig(cfg *config.Config, changed []string) bool {
+	for _, path := range changed {
+		for _, f := range cfg.Files {
+			if path == f {
+				return true
+			}
+		}
+	}
+	return false
+}
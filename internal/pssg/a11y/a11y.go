@@ -0,0 +1,293 @@
+// Package a11y checks generated pages for the accessibility problems a
+// site generator itself can cause: images rendered without alt text,
+// headings that skip levels, links with nothing to announce, and pages
+// without a language. It reads the HTML with a small tag scanner rather
+// than a full parser, which is enough for markup the templates produce.
+package a11y
+
+import (
+	"fmt"
+	"html"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Rules are the checks, by the name issues carry.
+const (
+	RuleImgAlt       = "img-alt"
+	RuleHeadingOrder = "heading-order"
+	RuleEmptyHeading = "empty-heading"
+	RuleEmptyLink    = "empty-link"
+	RuleHTMLLang     = "html-lang"
+)
+
+// Issue is one problem found on a page.
+type Issue struct {
+	Page     string `json:"page"` // slash-separated, relative to the output directory
+	Line     int    `json:"line"`
+	Selector string `json:"selector"`
+	Rule     string `json:"rule"`
+	Message  string `json:"message"`
+}
+
+func (is Issue) String() string {
+	return fmt.Sprintf("%s:%d: %s: %s (%s)", is.Page, is.Line, is.Selector, is.Message, is.Rule)
+}
+
+var (
+	tagRe  = regexp.MustCompile(`^<(/?)([a-zA-Z][a-zA-Z0-9-]*)((?:\s+[^\s=/>]+(?:\s*=\s*(?:"[^"]*"|'[^']*'|[^\s"'>]+))?)*)\s*/?>`)
+	attrRe = regexp.MustCompile(`([^\s=/>]+)(?:\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s"'>]+)))?`)
+)
+
+// void elements have no closing tag.
+var void = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true, "hr": true, "img": true,
+	"input": true, "link": true, "meta": true, "source": true, "track": true, "wbr": true,
+}
+
+// element is an open element on the scanner's stack.
+type element struct {
+	tag     string
+	attrs   map[string]string
+	nth     int            // position among siblings of the same tag
+	counts  map[string]int // children seen so far, by tag
+	line    int
+	text    strings.Builder // for links and headings: the text inside
+	labeled bool            // for links: an image or label inside names it
+}
+
+// Check audits one page; rel names it in the issues.
+func Check(rel, page string) []Issue {
+	var issues []Issue
+	report := func(line int, selector, rule, format string, args ...interface{}) {
+		issues = append(issues, Issue{Page: rel, Line: line, Selector: selector, Rule: rule, Message: fmt.Sprintf(format, args...)})
+	}
+
+	root := &element{counts: make(map[string]int)}
+	stack := []*element{root}
+	line, last := 1, 0 // last is the level of the previous heading
+	for i := 0; i < len(page); {
+		lt := strings.IndexByte(page[i:], '<')
+		if lt < 0 {
+			addText(stack, page[i:])
+			break
+		}
+		addText(stack, page[i:i+lt])
+		line += strings.Count(page[i:i+lt], "\n")
+		i += lt
+
+		rest := page[i:]
+		if strings.HasPrefix(rest, "<!--") {
+			end := strings.Index(rest, "-->")
+			if end < 0 {
+				break
+			}
+			line += strings.Count(rest[:end], "\n")
+			i += end + 3
+			continue
+		}
+		m := tagRe.FindStringSubmatch(rest)
+		if m == nil {
+			// A doctype, or a "<" that starts no tag.
+			addText(stack, "<")
+			i++
+			continue
+		}
+		tagLine := line
+		line += strings.Count(m[0], "\n")
+		i += len(m[0])
+		tag := strings.ToLower(m[2])
+
+		if m[1] == "/" {
+			for j := len(stack) - 1; j > 0; j-- {
+				if stack[j].tag != tag {
+					continue
+				}
+				el := stack[j]
+				switch {
+				case tag == "a":
+					if _, ok := el.attrs["href"]; ok && !el.labeled && !hidden(el) && blank(el.text.String()) {
+						report(el.line, selector(stack[:j+1]), RuleEmptyLink, "link to %q has no text or accessible name", el.attrs["href"])
+					}
+				case heading(tag) > 0:
+					if !labeled(el) && blank(el.text.String()) {
+						report(el.line, selector(stack[:j+1]), RuleEmptyHeading, "%s has no text", tag)
+					}
+				}
+				stack = stack[:j]
+				break
+			}
+			continue
+		}
+
+		parent := stack[len(stack)-1]
+		parent.counts[tag]++
+		el := &element{tag: tag, attrs: attributes(m[3]), nth: parent.counts[tag], counts: make(map[string]int), line: tagLine}
+		path := append(stack[:len(stack):len(stack)], el)
+
+		switch tag {
+		case "html":
+			if strings.TrimSpace(el.attrs["lang"]) == "" {
+				report(tagLine, "html", RuleHTMLLang, "page does not declare its language")
+			}
+		case "img":
+			alt, ok := el.attrs["alt"]
+			switch {
+			case !ok && !hidden(el):
+				report(tagLine, selector(path), RuleImgAlt, "image %q has no alt attribute", el.attrs["src"])
+			case !blank(alt):
+				labelLink(stack)
+			}
+		case "svg":
+			if labeled(el) {
+				labelLink(stack)
+			}
+		case "script", "style", "textarea":
+			end := strings.Index(page[i:], "</"+tag)
+			if end < 0 {
+				end = len(page) - i
+			}
+			line += strings.Count(page[i:i+end], "\n")
+			i += end
+		}
+		if level := heading(tag); level > 0 {
+			if last > 0 && level > last+1 {
+				report(tagLine, selector(path), RuleHeadingOrder, "h%d follows h%d, skipping a level", level, last)
+			}
+			last = level
+		}
+		if tag == "a" && labeled(el) {
+			el.labeled = true
+		}
+		if !void[tag] && !strings.HasSuffix(m[0], "/>") {
+			stack = path
+		}
+	}
+	return issues
+}
+
+// CheckDir audits the HTML pages under dir modified at or after since,
+// so pages left over from earlier builds are not reported. Issues are
+// sorted by page and line.
+func CheckDir(dir string, since time.Time) ([]Issue, error) {
+	var issues []Issue
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !strings.HasSuffix(p, ".html") {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil || info.ModTime().Before(since) {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		issues = append(issues, Check(filepath.ToSlash(rel), string(data))...)
+		return nil
+	})
+	sort.SliceStable(issues, func(i, j int) bool {
+		if issues[i].Page != issues[j].Page {
+			return issues[i].Page < issues[j].Page
+		}
+		return issues[i].Line < issues[j].Line
+	})
+	return issues, err
+}
+
+// selector describes the last element of path as a CSS selector: from the
+// nearest ancestor with an id, or from the body, down to the element.
+func selector(path []*element) string {
+	start := 1
+	for j := len(path) - 1; j > 0; j-- {
+		if path[j].attrs["id"] != "" || path[j].tag == "body" {
+			start = j
+			break
+		}
+	}
+	parts := make([]string, 0, len(path)-start)
+	for _, el := range path[start:] {
+		parts = append(parts, simple(el))
+	}
+	return strings.Join(parts, " > ")
+}
+
+// simple is one element's part of a selector: its id, or its tag with its
+// first class and its position among siblings of that tag.
+func simple(el *element) string {
+	if id := el.attrs["id"]; id != "" {
+		return "#" + id
+	}
+	s := el.tag
+	if class := strings.Fields(el.attrs["class"]); len(class) > 0 {
+		s += "." + class[0]
+	}
+	if el.nth > 1 {
+		s += fmt.Sprintf(":nth-of-type(%d)", el.nth)
+	}
+	return s
+}
+
+func attributes(s string) map[string]string {
+	attrs := make(map[string]string)
+	for _, m := range attrRe.FindAllStringSubmatch(s, -1) {
+		attrs[strings.ToLower(m[1])] = html.UnescapeString(m[2] + m[3] + m[4])
+	}
+	return attrs
+}
+
+// addText adds text to every open link and heading.
+func addText(stack []*element, text string) {
+	for _, el := range stack {
+		if el.tag == "a" || heading(el.tag) > 0 {
+			el.text.WriteString(text)
+		}
+	}
+}
+
+// labelLink marks the open links as named by an image inside them.
+func labelLink(stack []*element) {
+	for _, el := range stack {
+		if el.tag == "a" {
+			el.labeled = true
+		}
+	}
+}
+
+// labeled reports whether an element names itself with an ARIA label or
+// a title.
+func labeled(el *element) bool {
+	for _, a := range []string{"aria-label", "aria-labelledby", "title"} {
+		if !blank(el.attrs[a]) {
+			return true
+		}
+	}
+	return false
+}
+
+// hidden reports whether an element is hidden from assistive technology,
+// so it needs no name.
+func hidden(el *element) bool {
+	return el.attrs["aria-hidden"] == "true" || el.attrs["role"] == "presentation" || el.attrs["role"] == "none"
+}
+
+// heading returns the level of an h1-h6 tag, or 0.
+func heading(tag string) int {
+	if len(tag) == 2 && tag[0] == 'h' && tag[1] >= '1' && tag[1] <= '6' {
+		return int(tag[1] - '0')
+	}
+	return 0
+}
+
+func blank(s string) bool {
+	return strings.TrimSpace(html.UnescapeString(s)) == ""
+}
@@ -0,0 +1,135 @@
+// Package adr indexes architecture decision records: their numbers,
+// dates and statuses, and which decisions supersede which.
+package adr
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/supermodeltools/arch-docs/internal/pssg/config"
+	"github.com/supermodeltools/arch-docs/internal/pssg/entity"
+	"github.com/supermodeltools/arch-docs/internal/pssg/relation"
+)
+
+// StatusField is the field Build sets on every record to its effective
+// status, which the ADR status taxonomy groups by.
+const StatusField = "adr_status"
+
+// Statuses are the statuses a record may declare, in lifecycle order.
+var Statuses = []string{"proposed", "accepted", "rejected", "deprecated", "superseded"}
+
+// Record is one decision.
+type Record struct {
+	Entity       *entity.Entity
+	Number       int // 0 when the record has none
+	Date         time.Time
+	Status       string
+	Supersedes   []*entity.Entity
+	SupersededBy []*entity.Entity
+}
+
+// ID is the record's conventional name, e.g. "ADR-0007", or "" without a
+// number.
+func (r *Record) ID() string {
+	if r.Number == 0 {
+		return ""
+	}
+	return fmt.Sprintf("ADR-%04d", r.Number)
+}
+
+// Index holds every record, ordered by number, then date, then title.
+type Index struct {
+	All    []*Record
+	URL    string // root-relative URL of the index page
+	bySlug map[string]*Record
+}
+
+// Record returns the record for an entity, or nil if it is not an ADR.
+func (idx *Index) Record(slug string) *Record { return idx.bySlug[slug] }
+
+// Build collects the entities whose type field is cfg.Type. Which records
+// supersede which comes from the supersedes relation in relGraph. A record that another supersedes is
+// superseded whatever it declares; other records keep their status field,
+// defaulting to proposed. Unknown statuses are reported in the returned
+// warnings and kept.
+func Build(entities []*entity.Entity, relGraph *relation.Graph, typeField string, cfg config.ADRConfig) (*Index, []string) {
+	idx := &Index{URL: "/" + cfg.Dir + "/", bySlug: make(map[string]*Record)}
+	var warnings []string
+	for _, e := range entities {
+		if !strings.EqualFold(e.GetString(typeField), cfg.Type) {
+			continue
+		}
+		r := &Record{
+			Entity:       e,
+			Number:       number(e, cfg.NumberField),
+			Date:         e.GetTime(cfg.DateField),
+			Supersedes:   relGraph.Related(e.Slug, "supersedes"),
+			SupersededBy: relGraph.Related(e.Slug, "superseded_by"),
+		}
+		r.Status = strings.ToLower(strings.TrimSpace(e.GetString(cfg.StatusField)))
+		switch {
+		case len(r.SupersededBy) > 0:
+			r.Status = "superseded"
+		case r.Status == "":
+			r.Status = "proposed"
+		case !slices.Contains(Statuses, r.Status):
+			warnings = append(warnings, fmt.Sprintf("%s: unknown %s %q (want %s)", e.Slug, cfg.StatusField, r.Status, strings.Join(Statuses, ", ")))
+		}
+		e.Fields[StatusField] = r.Status
+		idx.All = append(idx.All, r)
+		idx.bySlug[e.Slug] = r
+	}
+
+	sort.SliceStable(idx.All, func(i, j int) bool {
+		a, b := idx.All[i], idx.All[j]
+		if (a.Number == 0) != (b.Number == 0) {
+			return a.Number != 0
+		}
+		if a.Number != b.Number {
+			return a.Number < b.Number
+		}
+		if !a.Date.Equal(b.Date) {
+			return a.Date.Before(b.Date)
+		}
+		return a.Entity.GetString("title") < b.Entity.GetString("title")
+	})
+	seen := make(map[int]string)
+	for _, r := range idx.All {
+		if r.Number == 0 {
+			continue
+		}
+		if other, ok := seen[r.Number]; ok {
+			warnings = append(warnings, fmt.Sprintf("%s: %s shared with %s", r.Entity.Slug, r.ID(), other))
+			continue
+		}
+		seen[r.Number] = r.Entity.Slug
+	}
+	return idx, warnings
+}
+
+// number reads the record number from its field, which may be an integer
+// or a string such as "0007" or "ADR-7", and falls back to a leading
+// number in the slug, as in "0007-use-postgres".
+func number(e *entity.Entity, field string) int {
+	if n := e.GetInt(field); n > 0 {
+		return n
+	}
+	s := strings.TrimSpace(e.GetString(field))
+	if i := strings.IndexFunc(s, isDigit); i >= 0 {
+		s = s[i:]
+	} else {
+		s = e.Slug
+	}
+	end := strings.IndexFunc(s, func(r rune) bool { return !isDigit(r) })
+	if end < 0 {
+		end = len(s)
+	}
+	n, _ := strconv.Atoi(s[:end])
+	return n
+}
+
+func isDigit(r rune) bool { return r >= '0' && r <= '9' }
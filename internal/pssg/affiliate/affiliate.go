@@ -3,6 +3,8 @@ package affiliate
 import (
 	"net/url"
 	"os"
+	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/supermodeltools/arch-docs/internal/pssg/config"
@@ -12,7 +14,34 @@ import (
 type Link struct {
 	Provider string
 	Term     string
-	URL      string
+	URL      string // outbound URL
+	Href     string // URL to render in anchors: a local redirect stub when enabled, else URL
+	Rel      string // e.g. "sponsored nofollow"; render as rel="{{.Rel}}"
+	Region   string // region of URL, if the provider declares one
+	Product  bool   // hand-picked product from frontmatter rather than a search link
+
+	// Alternates are the same link at the provider's other regional
+	// storefronts, for client-side geo switching.
+	Alternates []Alternate
+}
+
+// Alternate is a regional variant of a Link.
+type Alternate struct {
+	Region string `json:"region"`
+	URL    string `json:"url"`
+}
+
+// GeoURLs returns region -> URL for the primary link and all alternates,
+// suitable for serializing into a data attribute with toJSON.
+func (l Link) GeoURLs() map[string]string {
+	urls := make(map[string]string, len(l.Alternates)+1)
+	if l.Region != "" {
+		urls[l.Region] = l.URL
+	}
+	for _, a := range l.Alternates {
+		urls[a.Region] = a.URL
+	}
+	return urls
 }
 
 // Provider generates affiliate links for a given search term.
@@ -20,29 +49,160 @@ type Provider struct {
 	Name        string
 	URLTemplate string // e.g., "https://www.amazon.com/s?k={{term}}&tag={{tag}}"
 	Tag         string
+	Locale      string
+	Rel         string
+	// TrackingParams are appended to the query string of every generated URL.
+	TrackingParams map[string]string
+	Region         string
+	Variants       []Provider // regional storefronts; each carries its own Region and Tag
+	// ProductURLTemplate links a specific product, e.g. "https://www.amazon.com/dp/{{asin}}?tag={{tag}}".
+	ProductURLTemplate string
+}
+
+// LinkContext holds the page-level values available to URL templates.
+// Term is filled in per search term by GenerateLinks.
+type LinkContext struct {
+	Term     string
+	Category string
+	Locale   string
+	Slug     string
+	ASIN     string
 }
 
 // GenerateLink creates an affiliate URL for the given search term.
 func (p *Provider) GenerateLink(term string) string {
-	encoded := url.QueryEscape(term)
-	encoded = strings.ReplaceAll(encoded, "+", "%20")
-	// Also support + encoding like the TS version
-	plusEncoded := strings.ReplaceAll(url.QueryEscape(term), "%20", "+")
+	return p.GenerateLinkFor(LinkContext{Term: term})
+}
+
+// GenerateLinkFor creates an affiliate URL, expanding all supported placeholders.
+//
+// Each of term, category, locale and slug is available in several forms:
+//
+//	{{term}}         query-encoded with spaces as "+"
+//	{{term_query}}   query-encoded with spaces as "%20"
+//	{{term_path}}    path-segment encoded
+//	{{term_hyphen}}  lowercased, whitespace joined with "-", path encoded
+//	{{term_raw}}     inserted verbatim
+//
+// {{tag}} is always inserted verbatim.
+func (p *Provider) GenerateLinkFor(ctx LinkContext) string {
+	return p.expand(p.URLTemplate, ctx)
+}
+
+// expand fills placeholders in tmpl and appends tracking parameters.
+func (p *Provider) expand(tmpl string, ctx LinkContext) string {
+	if ctx.Locale == "" {
+		ctx.Locale = p.Locale
+	}
+
+	result := strings.ReplaceAll(tmpl, "{{tag}}", p.Tag)
+	for _, v := range ctx.vars() {
+		result = expandPlaceholder(result, v.name, v.value)
+	}
+	return appendTracking(result, p.TrackingParams, p.Name, ctx)
+}
+
+type linkVar struct{ name, value string }
+
+func (ctx LinkContext) vars() []linkVar {
+	return []linkVar{
+		{"term", ctx.Term},
+		{"category", ctx.Category},
+		{"locale", ctx.Locale},
+		{"slug", ctx.Slug},
+		{"asin", ctx.ASIN},
+	}
+}
+
+// appendTracking adds params to rawURL's query string. Values may reference
+// {{provider}} and any LinkContext placeholder; they are expanded raw and
+// encoded by url.Values.
+func appendTracking(rawURL string, params map[string]string, providerName string, ctx LinkContext) string {
+	if len(params) == 0 {
+		return rawURL
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	q := u.Query()
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		val := strings.ReplaceAll(params[k], "{{provider}}", providerName)
+		for _, v := range ctx.vars() {
+			val = strings.ReplaceAll(val, "{{"+v.name+"}}", v.value)
+		}
+		q.Set(k, val)
+	}
+	u.RawQuery = q.Encode()
+	return u.String()
+}
 
-	result := p.URLTemplate
-	result = strings.ReplaceAll(result, "{{term}}", plusEncoded)
-	result = strings.ReplaceAll(result, "{{tag}}", p.Tag)
-	return result
+// expandPlaceholder replaces every encoding variant of {{name}} in s.
+func expandPlaceholder(s, name, value string) string {
+	if !strings.Contains(s, "{{"+name) {
+		return s
+	}
+	queryEncoded := url.QueryEscape(value)
+	hyphen := strings.Join(strings.Fields(strings.ToLower(value)), "-")
+
+	r := strings.NewReplacer(
+		"{{"+name+"}}", queryEncoded,
+		"{{"+name+"_query}}", strings.ReplaceAll(queryEncoded, "+", "%20"),
+		"{{"+name+"_path}}", url.PathEscape(value),
+		"{{"+name+"_hyphen}}", url.PathEscape(hyphen),
+		"{{"+name+"_raw}}", value,
+	)
+	return r.Replace(s)
 }
 
 // Registry holds all configured affiliate providers.
 type Registry struct {
 	Providers []Provider
+	// Skipped lists providers and regional variants left out because their
+	// env var was unset.
+	Skipped []SkippedProvider
+
+	maxLinks       int
+	maxPerTerm     int
+	rules          []termRule
+	trackingParams map[string]string
+	rel            string
+	asinProvider   string
+}
+
+// SkippedProvider is a configured provider (or regional variant) that was
+// not loaded because its tag env var was empty.
+type SkippedProvider struct {
+	Name   string
+	Region string
+	EnvVar string
+}
+
+// termRule is a compiled config.AffiliateTermRule.
+type termRule struct {
+	match     *regexp.Regexp
+	terms     map[string]bool
+	providers []string
+}
+
+func (r termRule) matches(term string) bool {
+	if r.terms[strings.ToLower(term)] {
+		return true
+	}
+	return r.match != nil && r.match.MatchString(term)
 }
 
 // NewRegistry creates a Registry from config, reading env vars for tags.
+// Providers are ordered by ascending priority, keeping config order for ties.
 func NewRegistry(cfg config.AffiliatesConfig) *Registry {
 	var providers []Provider
+	var priorities []int
+	var skipped []SkippedProvider
 	for _, pc := range cfg.Providers {
 		tag := ""
 		if pc.EnvVar != "" {
@@ -50,37 +210,204 @@ func NewRegistry(cfg config.AffiliatesConfig) *Registry {
 		}
 		// Skip providers that require an env var but don't have one set
 		if tag == "" && !pc.AlwaysInclude {
+			skipped = append(skipped, SkippedProvider{Name: pc.Name, Region: pc.Region, EnvVar: pc.EnvVar})
+			continue
+		}
+		p := Provider{
+			Name:               pc.Name,
+			URLTemplate:        pc.URLTemplate,
+			Tag:                tag,
+			Locale:             pc.Locale,
+			Rel:                pc.Rel,
+			TrackingParams:     mergeParams(cfg.TrackingParams, pc.TrackingParams),
+			Region:             pc.Region,
+			ProductURLTemplate: pc.ProductURLTemplate,
+		}
+		for _, vc := range pc.Variants {
+			vtag := ""
+			if vc.EnvVar != "" {
+				vtag = os.Getenv(vc.EnvVar)
+			}
+			if vtag == "" && !vc.AlwaysInclude {
+				skipped = append(skipped, SkippedProvider{Name: pc.Name, Region: vc.Region, EnvVar: vc.EnvVar})
+				continue
+			}
+			v := p
+			v.Variants = nil
+			v.Region = vc.Region
+			v.Tag = vtag
+			if vc.URLTemplate != "" {
+				v.URLTemplate = vc.URLTemplate
+			}
+			if vc.Locale != "" {
+				v.Locale = vc.Locale
+			}
+			if vc.ProductURLTemplate != "" {
+				v.ProductURLTemplate = vc.ProductURLTemplate
+			}
+			p.Variants = append(p.Variants, v)
+		}
+		providers = append(providers, p)
+		priorities = append(priorities, pc.Priority)
+	}
+	sort.Stable(byPriority{providers, priorities})
+
+	var rules []termRule
+	for _, rc := range cfg.TermRules {
+		rule := termRule{terms: make(map[string]bool), providers: rc.Providers}
+		if rc.Match != "" {
+			// Validated at config load time
+			rule.match, _ = regexp.Compile("(?i)" + rc.Match)
+		}
+		for _, t := range rc.Terms {
+			rule.terms[strings.ToLower(t)] = true
+		}
+		rules = append(rules, rule)
+	}
+
+	return &Registry{
+		Providers:      providers,
+		Skipped:        skipped,
+		maxLinks:       cfg.MaxLinksPerPage,
+		maxPerTerm:     cfg.MaxProvidersPerTerm,
+		rules:          rules,
+		trackingParams: cfg.TrackingParams,
+		rel:            cfg.Rel,
+		asinProvider:   cfg.ASINProvider,
+	}
+}
+
+// mergeParams returns base overlaid with override, or nil if both are empty.
+func mergeParams(base, override map[string]string) map[string]string {
+	if len(base) == 0 && len(override) == 0 {
+		return nil
+	}
+	merged := make(map[string]string, len(base)+len(override))
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range override {
+		merged[k] = v
+	}
+	return merged
+}
+
+type byPriority struct {
+	providers  []Provider
+	priorities []int
+}
+
+func (p byPriority) Len() int           { return len(p.providers) }
+func (p byPriority) Less(i, j int) bool { return p.priorities[i] < p.priorities[j] }
+func (p byPriority) Swap(i, j int) {
+	p.providers[i], p.providers[j] = p.providers[j], p.providers[i]
+	p.priorities[i], p.priorities[j] = p.priorities[j], p.priorities[i]
+}
+
+// providersFor returns the providers to use for a term, in preference order.
+func (r *Registry) providersFor(term string) []*Provider {
+	var selected []*Provider
+	for _, rule := range r.rules {
+		if !rule.matches(term) {
 			continue
 		}
-		providers = append(providers, Provider{
-			Name:        pc.Name,
-			URLTemplate: pc.URLTemplate,
-			Tag:         tag,
-		})
+		for _, name := range rule.providers {
+			for i := range r.Providers {
+				if r.Providers[i].Name == name {
+					selected = append(selected, &r.Providers[i])
+				}
+			}
+		}
+		break
+	}
+	if selected == nil {
+		for i := range r.Providers {
+			selected = append(selected, &r.Providers[i])
+		}
 	}
-	return &Registry{Providers: providers}
+	if r.maxPerTerm > 0 && len(selected) > r.maxPerTerm {
+		selected = selected[:r.maxPerTerm]
+	}
+	return selected
 }
 
 // GenerateLinks creates affiliate links for all search terms from enrichment data.
-func (r *Registry) GenerateLinks(enrichmentData map[string]interface{}, searchTermPaths []string) []Link {
+// pageCtx supplies the page-level placeholder values; its Term is ignored.
+//
+// Terms are de-duplicated case-insensitively and links are ordered term by
+// term, so a per-page limit keeps coverage of the most relevant terms rather
+// than exhausting one provider first.
+func (r *Registry) GenerateLinks(enrichmentData map[string]interface{}, searchTermPaths []string, pageCtx LinkContext) []Link {
+	return r.GenerateEntityLinks(nil, enrichmentData, searchTermPaths, pageCtx)
+}
+
+// GenerateEntityLinks creates links for hand-picked products followed by
+// enrichment-derived search links. Products take precedence: they are listed
+// first, count toward the per-page limit, and suppress search links for the
+// same term.
+func (r *Registry) GenerateEntityLinks(products []Product, enrichmentData map[string]interface{}, searchTermPaths []string, pageCtx LinkContext) []Link {
+	links := r.generateEntityLinks(products, enrichmentData, searchTermPaths, pageCtx)
+	for i := range links {
+		links[i].Href = links[i].URL
+	}
+	return links
+}
+
+func (r *Registry) generateEntityLinks(products []Product, enrichmentData map[string]interface{}, searchTermPaths []string, pageCtx LinkContext) []Link {
+	seen := make(map[string]bool)
+	var links []Link
+	for _, product := range products {
+		if r.maxLinks > 0 && len(links) >= r.maxLinks {
+			return links
+		}
+		link, ok := r.productLink(product, pageCtx)
+		if !ok {
+			continue
+		}
+		seen[strings.ToLower(product.Name)] = true
+		links = append(links, link)
+	}
+
 	if len(r.Providers) == 0 || enrichmentData == nil {
-		return nil
+		return links
 	}
 
 	// Extract search terms from enrichment data using configured paths
 	var terms []string
 	for _, path := range searchTermPaths {
-		terms = append(terms, extractTerms(enrichmentData, path)...)
+		for _, term := range extractTerms(enrichmentData, path) {
+			term = strings.TrimSpace(term)
+			key := strings.ToLower(term)
+			if term == "" || seen[key] {
+				continue
+			}
+			seen[key] = true
+			terms = append(terms, term)
+		}
 	}
 
-	var links []Link
-	for _, provider := range r.Providers {
-		for _, term := range terms {
-			links = append(links, Link{
+	for _, term := range terms {
+		for _, provider := range r.providersFor(term) {
+			if r.maxLinks > 0 && len(links) >= r.maxLinks {
+				return links
+			}
+			ctx := pageCtx
+			ctx.Term = term
+			link := Link{
 				Provider: provider.Name,
 				Term:     term,
-				URL:      provider.GenerateLink(term),
-			})
+				URL:      provider.GenerateLinkFor(ctx),
+				Rel:      provider.Rel,
+				Region:   provider.Region,
+			}
+			for i := range provider.Variants {
+				v := &provider.Variants[i]
+				link.Alternates = append(link.Alternates, Alternate{
+					Region: v.Region,
+					URL:    v.GenerateLinkFor(ctx),
+				})
+			}
+			links = append(links, link)
 		}
 	}
 	return links
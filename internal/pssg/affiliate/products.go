@@ -0,0 +1,122 @@
+package affiliate
+
+import "strings"
+
+// Product is a hand-picked product declared in entity frontmatter:
+//
+//	affiliate_products:
+//	  - name: "Lodge 10-inch cast iron skillet"
+//	    asin: "B00006JSUA"
+//	  - name: "Microplane zester"
+//	    url: "https://example.com/microplane"
+//	    provider: "example"
+//
+// Entries with a url are linked directly; entries with an asin are resolved
+// through the provider's product_url_template.
+type Product struct {
+	Name     string
+	URL      string
+	ASIN     string
+	Provider string
+}
+
+// ParseProducts converts a raw frontmatter value into products, skipping
+// entries that have neither a url nor an asin.
+func ParseProducts(v interface{}) []Product {
+	items, ok := v.([]interface{})
+	if !ok {
+		return nil
+	}
+
+	str := func(m map[string]interface{}, key string) string {
+		s, _ := m[key].(string)
+		return strings.TrimSpace(s)
+	}
+
+	var products []Product
+	for _, item := range items {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		p := Product{
+			Name:     str(m, "name"),
+			URL:      str(m, "url"),
+			ASIN:     str(m, "asin"),
+			Provider: str(m, "provider"),
+		}
+		if p.URL == "" && p.ASIN == "" {
+			continue
+		}
+		if p.Name == "" {
+			p.Name = p.ASIN
+		}
+		products = append(products, p)
+	}
+	return products
+}
+
+// findProvider returns the configured provider with the given name, or nil.
+func (r *Registry) findProvider(name string) *Provider {
+	for i := range r.Providers {
+		if r.Providers[i].Name == name {
+			return &r.Providers[i]
+		}
+	}
+	return nil
+}
+
+// productLink builds the link for a hand-picked product.
+func (r *Registry) productLink(product Product, pageCtx LinkContext) (Link, bool) {
+	ctx := pageCtx
+	ctx.Term = product.Name
+	ctx.ASIN = product.ASIN
+
+	if product.URL != "" {
+		link := Link{
+			Provider: product.Provider,
+			Term:     product.Name,
+			Rel:      r.rel,
+			Product:  true,
+		}
+		if link.Provider == "" {
+			link.Provider = "direct"
+		}
+		params := r.trackingParams
+		if p := r.findProvider(product.Provider); p != nil {
+			params = p.TrackingParams
+			link.Rel = p.Rel
+		}
+		link.URL = appendTracking(product.URL, params, link.Provider, ctx)
+		return link, true
+	}
+
+	name := product.Provider
+	if name == "" {
+		name = r.asinProvider
+	}
+	p := r.findProvider(name)
+	if p == nil || p.ProductURLTemplate == "" {
+		return Link{}, false
+	}
+
+	link := Link{
+		Provider: p.Name,
+		Term:     product.Name,
+		URL:      p.expand(p.ProductURLTemplate, ctx),
+		Rel:      p.Rel,
+		Region:   p.Region,
+		Product:  true,
+	}
+	for i := range p.Variants {
+		v := &p.Variants[i]
+		if v.ProductURLTemplate == "" {
+			continue
+		}
+		link.Alternates = append(link.Alternates, Alternate{
+			Region: v.Region,
+			URL:    v.expand(v.ProductURLTemplate, ctx),
+		})
+	}
+	return link, true
+}
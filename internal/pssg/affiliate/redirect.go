@@ -0,0 +1,115 @@
+package affiliate
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/supermodeltools/arch-docs/internal/pssg/config"
+	"github.com/supermodeltools/arch-docs/internal/pssg/entity"
+)
+
+// RedirectSet collects local redirect stubs for outbound affiliate links.
+// It is safe for concurrent use by the entity render workers.
+type RedirectSet struct {
+	cfg   config.AffiliateRedirectsConfig
+	mu    sync.Mutex
+	stubs map[string]Link // relative path -> link
+}
+
+// NewRedirectSet returns a collector, or nil when redirects are disabled.
+func NewRedirectSet(cfg config.AffiliateRedirectsConfig) *RedirectSet {
+	if !cfg.Enabled {
+		return nil
+	}
+	return &RedirectSet{cfg: cfg, stubs: make(map[string]Link)}
+}
+
+// Rewrite points each link's Href at its redirect stub. A nil set leaves
+// links unchanged.
+func (s *RedirectSet) Rewrite(links []Link) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range links {
+		path := s.stubPath(links[i])
+		s.stubs[path] = links[i]
+		links[i].Href = "/" + path
+	}
+}
+
+// stubPath returns the relative output path for a link's stub. The hash
+// covers the full outbound URL rather than just the term, since tracking
+// parameters can make the same term resolve differently per page.
+func (s *RedirectSet) stubPath(l Link) string {
+	sum := sha1.Sum([]byte(l.URL))
+	provider := entity.ToSlug(l.Provider)
+	if provider == "" {
+		provider = "link"
+	}
+	return fmt.Sprintf("%s/%s/%s.html", s.cfg.Dir, provider, hex.EncodeToString(sum[:])[:12])
+}
+
+// Write emits every collected stub under outDir and returns the number written.
+func (s *RedirectSet) Write(outDir string) (int, error) {
+	if s == nil {
+		return 0, nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	paths := make([]string, 0, len(s.stubs))
+	for p := range s.stubs {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	for _, p := range paths {
+		full := filepath.Join(outDir, filepath.FromSlash(p))
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			return 0, err
+		}
+		if err := os.WriteFile(full, []byte(s.renderStub(s.stubs[p])), 0644); err != nil {
+			return 0, fmt.Errorf("writing redirect %s: %w", p, err)
+		}
+	}
+	return len(paths), nil
+}
+
+func (s *RedirectSet) renderStub(l Link) string {
+	target := html.EscapeString(l.URL)
+	jsURL, _ := json.Marshal(l.URL)
+	jsProvider, _ := json.Marshal(l.Provider)
+	jsTerm, _ := json.Marshal(l.Term)
+
+	var sb strings.Builder
+	sb.WriteString("<!DOCTYPE html>\n<html>\n<head>\n")
+	sb.WriteString(`<meta charset="utf-8">` + "\n")
+	sb.WriteString(`<meta name="robots" content="noindex, nofollow">` + "\n")
+	if s.cfg.Beacon == "" {
+		sb.WriteString(fmt.Sprintf(`<meta http-equiv="refresh" content="0;url=%s">`+"\n", target))
+	} else {
+		// Give the beacon a moment before the no-JS fallback fires.
+		sb.WriteString(fmt.Sprintf(`<meta http-equiv="refresh" content="1;url=%s">`+"\n", target))
+	}
+	sb.WriteString(fmt.Sprintf("<title>Redirecting to %s</title>\n", html.EscapeString(l.Provider)))
+	sb.WriteString("<script>\n(function(provider, term, url) {\n")
+	if s.cfg.Beacon != "" {
+		sb.WriteString("  try {\n" + s.cfg.Beacon + "\n  } catch (e) {}\n")
+	}
+	sb.WriteString("  window.location.replace(url);\n")
+	sb.WriteString(fmt.Sprintf("})(%s, %s, %s);\n</script>\n", jsProvider, jsTerm, jsURL))
+	sb.WriteString("</head>\n<body>\n")
+	sb.WriteString(fmt.Sprintf(`<p><a href="%s" rel="%s">Continue to %s</a></p>`+"\n", target, html.EscapeString(l.Rel), html.EscapeString(l.Provider)))
+	sb.WriteString("</body>\n</html>\n")
+	return sb.String()
+}
@@ -0,0 +1,145 @@
+// Package analytics builds the tracking snippet for a site's analytics
+// provider and adds it to generated pages.
+package analytics
+
+import (
+	"fmt"
+	"html"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/supermodeltools/arch-docs/internal/pssg/config"
+)
+
+// script is one <script> element of a snippet: external when src is set,
+// inline otherwise.
+type script struct {
+	src   string
+	attrs [][2]string // in order, after src
+	body  string
+}
+
+// Snippet returns the HTML to add to the head of tracked pages, or "" when
+// no provider is configured.
+func Snippet(cfg *config.Config) string {
+	scripts := providerScripts(cfg.Analytics, cfg.Site.BaseURL)
+	if len(scripts) == 0 {
+		return ""
+	}
+	if cfg.Analytics.RespectDNT {
+		return "<script>" + loader(scripts) + "</script>"
+	}
+	var b strings.Builder
+	for _, s := range scripts {
+		b.WriteString("<script")
+		if s.src != "" {
+			fmt.Fprintf(&b, ` src="%s"`, html.EscapeString(s.src))
+		}
+		for _, a := range s.attrs {
+			if a[1] == "" {
+				fmt.Fprintf(&b, " %s", a[0])
+			} else {
+				fmt.Fprintf(&b, ` %s="%s"`, a[0], html.EscapeString(a[1]))
+			}
+		}
+		b.WriteString(">" + s.body + "</script>")
+	}
+	return b.String()
+}
+
+func providerScripts(ac config.AnalyticsConfig, baseURL string) []script {
+	switch ac.Provider {
+	case "plausible":
+		domain := ac.ID
+		if domain == "" {
+			if u, err := url.Parse(baseURL); err == nil {
+				domain = u.Host
+			}
+		}
+		return []script{{
+			src:   or(ac.ScriptURL, "https://plausible.io/js/script.js"),
+			attrs: [][2]string{{"defer", ""}, {"data-domain", domain}},
+		}}
+	case "goatcounter":
+		endpoint := ac.ID
+		if !strings.Contains(endpoint, "/") {
+			endpoint = "https://" + endpoint + ".goatcounter.com/count"
+		}
+		return []script{{
+			src:   or(ac.ScriptURL, "https://gc.zgo.at/count.js"),
+			attrs: [][2]string{{"async", ""}, {"data-goatcounter", endpoint}},
+		}}
+	case "ga4":
+		id := strconv.Quote(ac.ID)
+		return []script{
+			{src: or(ac.ScriptURL, "https://www.googletagmanager.com/gtag/js?id="+url.QueryEscape(ac.ID)), attrs: [][2]string{{"async", ""}}},
+			{body: "window.dataLayer=window.dataLayer||[];window.gtag=function(){dataLayer.push(arguments)};gtag('js',new Date());gtag('config'," + id + ");"},
+		}
+	}
+	return nil
+}
+
+// loader is an inline script that adds the snippet's scripts only when
+// the visitor sends neither Do Not Track nor Global Privacy Control.
+func loader(scripts []script) string {
+	var b strings.Builder
+	b.WriteString(`(function(){var n=navigator;if(n.doNotTrack=="1"||window.doNotTrack=="1"||n.globalPrivacyControl)return;`)
+	for _, s := range scripts {
+		if s.src == "" {
+			b.WriteString(s.body)
+			continue
+		}
+		fmt.Fprintf(&b, "var s=document.createElement(\"script\");s.src=%s;", strconv.Quote(s.src))
+		for _, a := range s.attrs {
+			switch a[0] {
+			case "async", "defer":
+				fmt.Fprintf(&b, "s.%s=true;", a[0])
+			default:
+				fmt.Fprintf(&b, "s.setAttribute(%s,%s);", strconv.Quote(a[0]), strconv.Quote(a[1]))
+			}
+		}
+		b.WriteString("document.head.appendChild(s);")
+	}
+	b.WriteString("})();")
+	return b.String()
+}
+
+// Tracked reports whether the page at the URL path p (e.g. "/about.html")
+// gets the snippet.
+func Tracked(ac config.AnalyticsConfig, p string) bool {
+	if len(ac.Include) > 0 && !matchAny(ac.Include, p) {
+		return false
+	}
+	return !matchAny(ac.Exclude, p)
+}
+
+func matchAny(patterns []string, p string) bool {
+	for _, pat := range patterns {
+		if strings.HasSuffix(pat, "/") && strings.HasPrefix(p, pat) {
+			return true
+		}
+		if ok, _ := path.Match(pat, p); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Inject adds the snippet to a page's head. A page without a </head>, or
+// that already has the snippet, is returned unchanged.
+func Inject(page, snippet string) string {
+	i := strings.Index(page, "</head>")
+	if i < 0 || strings.Contains(page, snippet) {
+		return page
+	}
+	return page[:i] + snippet + "\n" + page[i:]
+}
+
+func or(s, def string) string {
+	if s != "" {
+		return s
+	}
+	return def
+}
@@ -0,0 +1,152 @@
+// Package apispec reads OpenAPI documents and protobuf files into a common
+// reference model: the operations an API offers and the schemas or
+// messages they exchange. Entity pages render it as an API reference.
+package apispec
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Spec is an API description.
+type Spec struct {
+	Kind        string // "openapi" or "proto"
+	Path        string // as the entity names it
+	Title       string
+	Version     string
+	Description string
+	Operations  []Operation
+	Schemas     []Schema
+}
+
+// Operation is an HTTP endpoint or an RPC method.
+type Operation struct {
+	Method      string // HTTP method in upper case, or "RPC"
+	Path        string // URL path template, or "Service/Method"
+	ID          string // operationId, or the RPC's full name
+	Summary     string
+	Description string
+	Tags        []string
+	Params      []Param
+	Request     string // request body or input message type
+	Responses   []Response
+	Deprecated  bool
+	Streaming   string // "client", "server" or "bidi" for streaming RPCs
+}
+
+// Anchor is the operation's fragment on the entity page.
+func (op Operation) Anchor() string {
+	if op.ID == "" {
+		return "op-" + anchor(op.Method+"-"+op.Path)
+	}
+	return "op-" + anchor(op.ID)
+}
+
+// Param is an operation parameter.
+type Param struct {
+	Name        string
+	In          string // path, query, header or cookie
+	Type        string
+	Required    bool
+	Description string
+}
+
+// Response is one status an operation answers with.
+type Response struct {
+	Status      string // e.g. "200" or "default"; "" for RPC output
+	Description string
+	Type        string
+}
+
+// Schema is a named object schema, protobuf message or enum.
+type Schema struct {
+	Name        string
+	Kind        string // "object", "message" or "enum"
+	Description string
+	Fields      []Field
+	Values      []string // enum values
+}
+
+// Anchor is the schema's fragment on the entity page.
+func (s Schema) Anchor() string { return "schema-" + anchor(s.Name) }
+
+// Field is a schema property or message field.
+type Field struct {
+	Name        string
+	Type        string
+	Required    bool
+	Number      int // protobuf field number
+	Description string
+}
+
+// OperationIDs lists the IDs of the spec's operations, for search. A nil
+// spec has none.
+func (s *Spec) OperationIDs() []string {
+	if s == nil {
+		return nil
+	}
+	var ids []string
+	for _, op := range s.Operations {
+		if op.ID != "" {
+			ids = append(ids, op.ID)
+		}
+	}
+	return ids
+}
+
+// SchemaFor returns the schema a type names, so templates can link to
+// it: "Pet", "Pet[]" or, for protobuf, a name qualified by the package or
+// an enclosing message. It returns nil for other types.
+func (s *Spec) SchemaFor(typ string) *Schema {
+	typ = strings.TrimPrefix(strings.TrimSuffix(typ, "[]"), ".")
+	for i := range s.Schemas {
+		if name := s.Schemas[i].Name; name == typ || strings.HasSuffix(typ, "."+name) || strings.HasSuffix(name, "."+typ) {
+			return &s.Schemas[i]
+		}
+	}
+	return nil
+}
+
+// Load reads the spec at full; rel is the path the entity gave. Files
+// ending in .proto are protobuf; .yaml, .yml and .json files are OpenAPI
+// 3 or Swagger 2 documents.
+func Load(full, rel string) (*Spec, error) {
+	data, err := os.ReadFile(full)
+	if err != nil {
+		return nil, err
+	}
+	var spec *Spec
+	switch strings.ToLower(filepath.Ext(full)) {
+	case ".proto":
+		spec, err = parseProto(string(data))
+	case ".yaml", ".yml", ".json":
+		spec, err = parseOpenAPI(data)
+	default:
+		return nil, fmt.Errorf("%s: not an OpenAPI (.yaml, .yml, .json) or protobuf (.proto) file", rel)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", rel, err)
+	}
+	spec.Path = rel
+	if spec.Title == "" {
+		spec.Title = filepath.Base(rel)
+	}
+	return spec, nil
+}
+
+// anchor makes a fragment from a name, keeping letters, digits, '-', '_'
+// and '.'.
+func anchor(name string) string {
+	var b strings.Builder
+	for _, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+			b.WriteRune(r)
+		default:
+			b.WriteByte('-')
+		}
+	}
+	return b.String()
+}
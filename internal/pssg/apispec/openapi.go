@@ -0,0 +1,324 @@
+package apispec
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// methods are the HTTP methods a path item may hold, in the order the
+// OpenAPI specification lists them.
+var methods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// openapi walks a document as a node tree rather than decoding it into
+// structs, so paths, responses and properties keep the order the author
+// wrote them in, and $refs resolve against the whole document.
+type openapi struct {
+	root *yaml.Node
+}
+
+func parseOpenAPI(data []byte) (*Spec, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	root := deref(&doc)
+	if root == nil || root.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("not an OpenAPI document")
+	}
+	if get(root, "openapi") == nil && get(root, "swagger") == nil {
+		return nil, fmt.Errorf("not an OpenAPI document: no openapi or swagger version")
+	}
+	o := &openapi{root: root}
+
+	info := get(root, "info")
+	spec := &Spec{
+		Kind:        "openapi",
+		Title:       str(info, "title"),
+		Version:     str(info, "version"),
+		Description: str(info, "description"),
+	}
+
+	for _, p := range pairs(get(root, "paths")) {
+		path, item := p[0].Value, o.resolve(p[1])
+		shared := o.params(get(item, "parameters"))
+		for _, m := range methods {
+			node := o.resolve(get(item, m))
+			if node == nil {
+				continue
+			}
+			spec.Operations = append(spec.Operations, o.operation(strings.ToUpper(m), path, node, shared))
+		}
+	}
+
+	schemas := get(get(root, "components"), "schemas")
+	if schemas == nil {
+		schemas = get(root, "definitions") // Swagger 2
+	}
+	for _, p := range pairs(schemas) {
+		spec.Schemas = append(spec.Schemas, o.schema(p[0].Value, o.resolve(p[1])))
+	}
+	return spec, nil
+}
+
+func (o *openapi) operation(method, path string, node *yaml.Node, shared []Param) Operation {
+	op := Operation{
+		Method:      method,
+		Path:        path,
+		ID:          str(node, "operationId"),
+		Summary:     str(node, "summary"),
+		Description: str(node, "description"),
+		Deprecated:  str(node, "deprecated") == "true",
+	}
+	for _, t := range seq(get(node, "tags")) {
+		op.Tags = append(op.Tags, t.Value)
+	}
+
+	// Operation parameters replace path-level ones with the same name and
+	// location; Swagger 2 carries the request body as an "in: body" one.
+	own := o.params(get(node, "parameters"))
+	for _, sp := range shared {
+		overridden := false
+		for _, p := range own {
+			if p.Name == sp.Name && p.In == sp.In {
+				overridden = true
+			}
+		}
+		if !overridden {
+			op.Params = append(op.Params, sp)
+		}
+	}
+	for _, p := range own {
+		if p.In == "body" {
+			op.Request = p.Type
+			continue
+		}
+		op.Params = append(op.Params, p)
+	}
+
+	if body := o.resolve(get(node, "requestBody")); body != nil {
+		op.Request = o.contentType(get(body, "content"))
+	}
+	for _, p := range pairs(get(node, "responses")) {
+		resp := o.resolve(p[1])
+		r := Response{Status: p[0].Value, Description: str(resp, "description")}
+		if content := get(resp, "content"); content != nil {
+			r.Type = o.contentType(content)
+		} else if s := get(resp, "schema"); s != nil {
+			r.Type = o.typeName(s)
+		}
+		op.Responses = append(op.Responses, r)
+	}
+	return op
+}
+
+func (o *openapi) params(list *yaml.Node) []Param {
+	var params []Param
+	for _, n := range seq(list) {
+		n = o.resolve(n)
+		p := Param{
+			Name:        str(n, "name"),
+			In:          str(n, "in"),
+			Required:    str(n, "required") == "true",
+			Description: str(n, "description"),
+		}
+		if s := get(n, "schema"); s != nil {
+			p.Type = o.typeName(s)
+		} else {
+			p.Type = o.typeName(n) // Swagger 2 types parameters inline
+		}
+		params = append(params, p)
+	}
+	return params
+}
+
+// contentType names the schema of a content map, preferring JSON.
+func (o *openapi) contentType(content *yaml.Node) string {
+	media := get(content, "application/json")
+	if media == nil {
+		if p := pairs(content); len(p) > 0 {
+			media = p[0][1]
+		}
+	}
+	if s := get(media, "schema"); s != nil {
+		return o.typeName(s)
+	}
+	return ""
+}
+
+func (o *openapi) schema(name string, node *yaml.Node) Schema {
+	s := Schema{Name: name, Kind: "object", Description: str(node, "description")}
+	if enum := seq(get(node, "enum")); len(enum) > 0 {
+		s.Kind = "enum"
+		for _, v := range enum {
+			s.Values = append(s.Values, v.Value)
+		}
+		return s
+	}
+	s.Fields = o.fields(node, map[*yaml.Node]bool{})
+	return s
+}
+
+// fields lists an object's properties, flattening allOf members into it.
+// seen holds the schemas already flattened, so a schema whose allOf refers
+// back to itself, directly or through others, is listed once.
+func (o *openapi) fields(node *yaml.Node, seen map[*yaml.Node]bool) []Field {
+	if node == nil || seen[node] {
+		return nil
+	}
+	seen[node] = true
+	var fields []Field
+	for _, member := range seq(get(node, "allOf")) {
+		fields = append(fields, o.fields(o.resolve(member), seen)...)
+	}
+	required := make(map[string]bool)
+	for _, r := range seq(get(node, "required")) {
+		required[r.Value] = true
+	}
+	for _, p := range pairs(get(node, "properties")) {
+		prop := p[1]
+		desc := str(prop, "description")
+		if desc == "" {
+			desc = str(o.resolve(prop), "description")
+		}
+		fields = append(fields, Field{
+			Name:        p[0].Value,
+			Type:        o.typeName(prop),
+			Required:    required[p[0].Value],
+			Description: desc,
+		})
+	}
+	return fields
+}
+
+// typeName describes a schema briefly: a referenced schema by name,
+// arrays as "T[]", maps as "map<string, T>", and scalars by type and
+// format, e.g. "integer (int64)".
+func (o *openapi) typeName(node *yaml.Node) string {
+	node = deref(node)
+	if node == nil {
+		return ""
+	}
+	if ref := str(node, "$ref"); ref != "" {
+		return ref[strings.LastIndex(ref, "/")+1:]
+	}
+	for _, c := range []struct{ key, sep string }{{"oneOf", " | "}, {"anyOf", " | "}, {"allOf", " & "}} {
+		if members := seq(get(node, c.key)); len(members) > 0 {
+			var names []string
+			for _, m := range members {
+				names = append(names, o.typeName(m))
+			}
+			return strings.Join(names, c.sep)
+		}
+	}
+
+	typ := str(node, "type")
+	if t := get(node, "type"); t != nil && t.Kind == yaml.SequenceNode { // OpenAPI 3.1 type lists
+		var names []string
+		for _, n := range t.Content {
+			names = append(names, n.Value)
+		}
+		typ = strings.Join(names, " | ")
+	}
+	switch {
+	case typ == "array":
+		return o.typeName(get(node, "items")) + "[]"
+	case typ == "object" || typ == "":
+		if extra := get(node, "additionalProperties"); extra != nil && extra.Kind == yaml.MappingNode {
+			return "map<string, " + o.typeName(extra) + ">"
+		}
+		if typ == "" && get(node, "properties") == nil {
+			return "any"
+		}
+		return "object"
+	}
+	if format := str(node, "format"); format != "" {
+		return typ + " (" + format + ")"
+	}
+	return typ
+}
+
+// resolve follows a local $ref ("#/components/...") to its target; other
+// nodes and refs it cannot follow are returned as they are.
+func (o *openapi) resolve(node *yaml.Node) *yaml.Node {
+	node = deref(node)
+	for hops := 0; hops < 16; hops++ {
+		ref := str(node, "$ref")
+		if !strings.HasPrefix(ref, "#/") {
+			return node
+		}
+		target := o.root
+		for _, part := range strings.Split(ref[2:], "/") {
+			part = strings.NewReplacer("~1", "/", "~0", "~").Replace(part)
+			target = get(target, part)
+		}
+		if target == nil {
+			return node
+		}
+		node = target
+	}
+	return node
+}
+
+// deref unwraps documents and aliases.
+func deref(n *yaml.Node) *yaml.Node {
+	for n != nil {
+		switch n.Kind {
+		case yaml.DocumentNode:
+			if len(n.Content) == 0 {
+				return nil
+			}
+			n = n.Content[0]
+		case yaml.AliasNode:
+			n = n.Alias
+		default:
+			return n
+		}
+	}
+	return nil
+}
+
+// get returns the value of key in a mapping, or nil.
+func get(n *yaml.Node, key string) *yaml.Node {
+	n = deref(n)
+	if n == nil || n.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == key {
+			return deref(n.Content[i+1])
+		}
+	}
+	return nil
+}
+
+// str returns the scalar value of key in a mapping, or "".
+func str(n *yaml.Node, key string) string {
+	if v := get(n, key); v != nil && v.Kind == yaml.ScalarNode {
+		return v.Value
+	}
+	return ""
+}
+
+// pairs returns a mapping's key/value pairs in order.
+func pairs(n *yaml.Node) [][2]*yaml.Node {
+	n = deref(n)
+	if n == nil || n.Kind != yaml.MappingNode {
+		return nil
+	}
+	var list [][2]*yaml.Node
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		list = append(list, [2]*yaml.Node{n.Content[i], deref(n.Content[i+1])})
+	}
+	return list
+}
+
+// seq returns a sequence's items.
+func seq(n *yaml.Node) []*yaml.Node {
+	n = deref(n)
+	if n == nil || n.Kind != yaml.SequenceNode {
+		return nil
+	}
+	return n.Content
+}
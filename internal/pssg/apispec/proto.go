@@ -0,0 +1,374 @@
+package apispec
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// token is a protobuf token with the comment block written just above it.
+type token struct {
+	text string
+	doc  string
+}
+
+// protoParser reads the declarations reference pages need: the package,
+// services and their RPCs, messages with their fields, and enums. Options,
+// reserved ranges and extensions are skipped.
+type protoParser struct {
+	toks []token
+	pos  int
+	pkg  string
+	spec *Spec
+}
+
+func parseProto(src string) (*Spec, error) {
+	p := &protoParser{toks: tokenize(src), spec: &Spec{Kind: "proto"}}
+	for !p.done() {
+		t := p.next()
+		switch t.text {
+		case "package":
+			p.pkg = p.next().text
+			p.spec.Title = p.pkg
+			p.spec.Description = t.doc
+			p.expect(";")
+		case "message":
+			p.message("", t.doc)
+		case "enum":
+			p.enum("", t.doc)
+		case "service":
+			p.service(t.doc)
+		case "syntax", "edition", "import", "option", "extend":
+			p.skip()
+		}
+	}
+	if len(p.spec.Operations) == 0 && len(p.spec.Schemas) == 0 {
+		return nil, fmt.Errorf("no services, messages or enums")
+	}
+	return p.spec, nil
+}
+
+func (p *protoParser) message(prefix, doc string) {
+	name := prefix + p.next().text
+	p.expect("{")
+	idx := len(p.spec.Schemas)
+	p.spec.Schemas = append(p.spec.Schemas, Schema{Name: name, Kind: "message", Description: doc})
+	var fields []Field
+	for !p.done() {
+		t := p.peek()
+		switch t.text {
+		case "}":
+			p.next()
+			p.spec.Schemas[idx].Fields = fields
+			return
+		case "message":
+			p.next()
+			p.message(name+".", t.doc)
+		case "enum":
+			p.next()
+			p.enum(name+".", t.doc)
+		case "oneof":
+			p.next()
+			group := p.next().text
+			p.expect("{")
+			for !p.done() && p.peek().text != "}" {
+				if p.peek().text == "option" {
+					p.skip()
+					continue
+				}
+				f := p.field()
+				f.Description = strings.TrimSpace("One of " + group + ". " + f.Description)
+				fields = append(fields, f)
+			}
+			p.expect("}")
+		case "option", "reserved", "extensions", "extend", "group":
+			p.skip()
+		case ";":
+			p.next()
+		default:
+			fields = append(fields, p.field())
+		}
+	}
+}
+
+// field reads "[label] type name = number [options];".
+func (p *protoParser) field() Field {
+	first := p.peek()
+	f := Field{Description: first.doc}
+	label := ""
+	if t := first.text; t == "repeated" || t == "optional" || t == "required" {
+		label = t
+		p.next()
+	}
+	if p.peek().text == "map" {
+		p.next()
+		p.expect("<")
+		key := p.next().text
+		p.expect(",")
+		value := p.next().text
+		p.expect(">")
+		f.Type = "map<" + key + ", " + value + ">"
+	} else {
+		f.Type = p.next().text
+	}
+	if label == "repeated" {
+		f.Type += "[]"
+	}
+	f.Required = label == "required"
+	f.Name = p.next().text
+	if p.peek().text == "=" {
+		p.next()
+		fmt.Sscan(p.next().text, &f.Number)
+	}
+	p.skip() // field options and the closing semicolon
+	return f
+}
+
+func (p *protoParser) enum(prefix, doc string) {
+	s := Schema{Name: prefix + p.next().text, Kind: "enum", Description: doc}
+	p.expect("{")
+	for !p.done() {
+		t := p.next()
+		switch t.text {
+		case "}":
+			p.spec.Schemas = append(p.spec.Schemas, s)
+			return
+		case "option", "reserved":
+			p.skip()
+		case ";":
+		default:
+			s.Values = append(s.Values, t.text)
+			p.skip()
+		}
+	}
+	p.spec.Schemas = append(p.spec.Schemas, s)
+}
+
+func (p *protoParser) service(doc string) {
+	name := p.next().text
+	if p.spec.Title == "" {
+		p.spec.Title = name
+	}
+	if p.spec.Description == "" {
+		p.spec.Description = doc
+	}
+	p.expect("{")
+	full := name
+	if p.pkg != "" {
+		full = p.pkg + "." + name
+	}
+	for !p.done() {
+		t := p.next()
+		switch t.text {
+		case "}":
+			return
+		case "rpc":
+			method := p.next().text
+			in, inStream := p.rpcType()
+			p.expect("returns")
+			out, outStream := p.rpcType()
+			op := Operation{
+				Method:      "RPC",
+				Path:        "/" + full + "/" + method,
+				ID:          name + "." + method,
+				Description: t.doc,
+				Request:     in,
+				Responses:   []Response{{Type: out}},
+			}
+			switch {
+			case inStream && outStream:
+				op.Streaming = "bidi"
+			case inStream:
+				op.Streaming = "client"
+			case outStream:
+				op.Streaming = "server"
+			}
+			if p.peek().text == "{" {
+				op.Deprecated = p.rpcDeprecated()
+			} else {
+				p.expect(";")
+			}
+			p.spec.Operations = append(p.spec.Operations, op)
+		case "option":
+			p.skip()
+		}
+	}
+}
+
+// rpcType reads "( [stream] Type )".
+func (p *protoParser) rpcType() (string, bool) {
+	p.expect("(")
+	stream := false
+	if p.peek().text == "stream" {
+		stream = true
+		p.next()
+	}
+	typ := p.next().text
+	p.expect(")")
+	return typ, stream
+}
+
+// rpcDeprecated skips an RPC's option block, reporting whether it sets
+// deprecated = true.
+func (p *protoParser) rpcDeprecated() bool {
+	deprecated := false
+	depth := 0
+	for !p.done() {
+		t := p.next()
+		switch t.text {
+		case "{":
+			depth++
+		case "}":
+			depth--
+			if depth == 0 {
+				if p.peek().text == ";" {
+					p.next()
+				}
+				return deprecated
+			}
+		case "deprecated":
+			if p.peek().text == "=" && p.pos+1 < len(p.toks) && p.toks[p.pos+1].text == "true" {
+				deprecated = true
+			}
+		}
+	}
+	return deprecated
+}
+
+// skip consumes the rest of a statement: through the next semicolon
+// outside braces, or through a brace block that ends it.
+func (p *protoParser) skip() {
+	depth := 0
+	for !p.done() {
+		switch p.next().text {
+		case "{":
+			depth++
+		case "}":
+			depth--
+			if depth <= 0 {
+				if depth < 0 {
+					p.pos-- // the enclosing block's brace
+				}
+				return
+			}
+		case ";":
+			if depth == 0 {
+				return
+			}
+		}
+	}
+}
+
+func (p *protoParser) done() bool { return p.pos >= len(p.toks) }
+
+func (p *protoParser) peek() token {
+	if p.done() {
+		return token{}
+	}
+	return p.toks[p.pos]
+}
+
+func (p *protoParser) next() token {
+	t := p.peek()
+	if !p.done() {
+		p.pos++
+	}
+	return t
+}
+
+// expect consumes text if it comes next; malformed input is read as far
+// as it makes sense rather than rejected.
+func (p *protoParser) expect(text string) {
+	if p.peek().text == text {
+		p.next()
+	}
+}
+
+// tokenize splits protobuf source into identifiers (with dots), numbers,
+// strings and single-character symbols. A comment block on the lines
+// just above a token becomes its doc; comments trailing other tokens on
+// their line, or separated from the next token by a blank line, are
+// dropped.
+func tokenize(src string) []token {
+	var toks []token
+	var doc []string
+	line, lastLine := 1, 0
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == '\n':
+			line++
+			i++
+			if i < len(src) && blankLine(src[i:]) {
+				doc = nil // detached from the next declaration
+			}
+		case c == ' ' || c == '\t' || c == '\r':
+			i++
+		case strings.HasPrefix(src[i:], "//"):
+			end := strings.IndexByte(src[i:], '\n')
+			if end < 0 {
+				end = len(src) - i
+			}
+			if line != lastLine {
+				doc = append(doc, strings.TrimSpace(strings.TrimLeft(src[i+2:i+end], "/")))
+			}
+			i += end
+		case strings.HasPrefix(src[i:], "/*"):
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				end = len(src) - i - 2
+			}
+			body := src[i+2 : i+2+end]
+			if line != lastLine {
+				for _, l := range strings.Split(body, "\n") {
+					doc = append(doc, strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(l), "*")))
+				}
+			}
+			line += strings.Count(body, "\n")
+			i += end + 4
+		case c == '"' || c == '\'':
+			j := i + 1
+			for j < len(src) && src[j] != c {
+				if src[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			if j >= len(src) {
+				j = len(src) - 1
+			}
+			toks = append(toks, token{text: src[i : j+1], doc: joinDoc(doc)})
+			doc, lastLine = nil, line
+			i = j + 1
+		case isIdent(rune(c)):
+			j := i
+			for j < len(src) && isIdent(rune(src[j])) {
+				j++
+			}
+			toks = append(toks, token{text: src[i:j], doc: joinDoc(doc)})
+			doc, lastLine = nil, line
+			i = j
+		default:
+			toks = append(toks, token{text: string(c), doc: joinDoc(doc)})
+			doc, lastLine = nil, line
+			i++
+		}
+	}
+	return toks
+}
+
+func blankLine(s string) bool {
+	end := strings.IndexByte(s, '\n')
+	if end < 0 {
+		end = len(s)
+	}
+	return strings.TrimSpace(s[:end]) == ""
+}
+
+func joinDoc(lines []string) string {
+	return strings.TrimSpace(strings.Join(lines, " "))
+}
+
+func isIdent(r rune) bool {
+	return r == '_' || r == '.' || r == '-' || r == '+' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
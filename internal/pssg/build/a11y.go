@@ -0,0 +1,60 @@
+package build
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/supermodeltools/arch-docs/internal/pssg/a11y"
+	"github.com/supermodeltools/arch-docs/internal/pssg/logging"
+)
+
+// auditAccessibility checks the pages this build wrote under outDir,
+// writes the issues to the configured report and logs how many each rule
+// found, with the first as an example. It fails when a11y.fail is set
+// and there are issues.
+func (b *Builder) auditAccessibility(outDir string, since time.Time) error {
+	issues, err := a11y.CheckDir(outDir, since)
+	if err != nil {
+		return err
+	}
+	if issues == nil {
+		issues = []a11y.Issue{}
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false) // selectors use ">"
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(issues); err != nil {
+		return err
+	}
+	if err := os.WriteFile(b.cfg.A11y.Report, buf.Bytes(), 0644); err != nil {
+		return err
+	}
+
+	log := logging.Stage("output")
+	first := make(map[string]a11y.Issue)
+	count := make(map[string]int)
+	for _, is := range issues {
+		if count[is.Rule] == 0 {
+			first[is.Rule] = is
+		}
+		count[is.Rule]++
+	}
+	rules := make([]string, 0, len(count))
+	for rule := range count {
+		rules = append(rules, rule)
+	}
+	sort.Strings(rules)
+	for _, rule := range rules {
+		log.Warn("Accessibility issues", "rule", rule, "count", count[rule], "first", first[rule].String())
+	}
+	log.Info("Audited accessibility", "issues", len(issues), "report", b.cfg.A11y.Report)
+	if b.cfg.A11y.Fail && len(issues) > 0 {
+		return fmt.Errorf("%d accessibility issues; see %s", len(issues), b.cfg.A11y.Report)
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package build
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/supermodeltools/arch-docs/internal/pssg/adr"
+	"github.com/supermodeltools/arch-docs/internal/pssg/logging"
+	"github.com/supermodeltools/arch-docs/internal/pssg/render"
+	"github.com/supermodeltools/arch-docs/internal/pssg/schema"
+	"github.com/supermodeltools/arch-docs/internal/pssg/taxonomy"
+)
+
+// renderADRIndex writes the page listing every decision record in order.
+func (b *Builder) renderADRIndex(
+	engine *render.Engine,
+	schemaGen *schema.Generator,
+	idx *adr.Index,
+	allTaxonomies []taxonomy.Taxonomy,
+	outDir string,
+	addSitemapEntry func(string, string, string, string),
+) error {
+	dir := filepath.Join(outDir, b.cfg.ADR.Dir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("creating adr dir: %w", err)
+	}
+
+	name := "Architecture Decisions"
+	pageURL := b.pageURL(idx.URL)
+	description := fmt.Sprintf("The %d architecture decision records of %s, in order.", len(idx.All), b.cfg.Site.Name)
+
+	imageURL, err := b.shareImage(outDir, "hub", []interface{}{b.cfg.Site.Name, name, "ADR", len(idx.All), nil}, func(s render.ShareImages) string {
+		return s.Hub(b.cfg.Site.Name, name, "ADR", len(idx.All), nil)
+	})
+	if err != nil {
+		logging.Stage("render").Warn("Failed to write share SVG", "page", idx.URL, "error", err)
+	}
+
+	var items []schema.ItemListEntry
+	for _, r := range idx.All {
+		items = append(items, schema.ItemListEntry{
+			Name: r.Entity.GetString("title"),
+			URL:  b.pageURL("/" + r.Entity.Slug + ".html"),
+		})
+	}
+	collectionSchema := schemaGen.GenerateCollectionPageSchema(name, description, pageURL, items, imageURL)
+	breadcrumbs := []render.Breadcrumb{
+		{Name: "Home", URL: b.cfg.Site.BaseURL + "/"},
+		{Name: name, URL: ""},
+	}
+	breadcrumbSchema := schemaGen.GenerateBreadcrumbSchema(toBreadcrumbItems(breadcrumbs))
+
+	ctx := render.ADRIndexContext{
+		Site:          b.cfg.Site,
+		Languages:     b.languageLinks(idx.URL),
+		Records:       idx.All,
+		Statuses:      b.adrStatusCounts(idx),
+		JsonLD:        toTemplateHTML(schema.MarshalSchemas(collectionSchema, breadcrumbSchema)),
+		Breadcrumbs:   breadcrumbs,
+		AllTaxonomies: allTaxonomies,
+		OG: render.OGMeta{
+			Title:       name + " — " + b.cfg.Site.Name,
+			Description: description,
+			URL:         pageURL,
+			ImageURL:    imageURL,
+			Type:        "website",
+			SiteName:    b.cfg.Site.Name,
+		},
+		CTA: b.cfg.Extra.CTA,
+	}
+	html, err := engine.RenderADRIndex(ctx)
+	if err != nil {
+		return fmt.Errorf("rendering adr index: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "index.html"), []byte(html), 0644); err != nil {
+		return fmt.Errorf("writing adr index: %w", err)
+	}
+	addSitemapEntry("adr", idx.URL, b.cfg.Sitemap.Priorities["taxonomy_index"], b.cfg.Sitemap.ChangeFreqs["taxonomy_index"])
+	return nil
+}
+
+// adrStatusCounts counts the records in each status, in lifecycle order,
+// leaving out statuses no record has.
+func (b *Builder) adrStatusCounts(idx *adr.Index) []render.ADRStatusCount {
+	counts := make(map[string]int)
+	order := append([]string(nil), adr.Statuses...)
+	for _, r := range idx.All {
+		if counts[r.Status] == 0 && !isStatus(r.Status) {
+			order = append(order, r.Status)
+		}
+		counts[r.Status]++
+	}
+	var list []render.ADRStatusCount
+	for _, s := range order {
+		if counts[s] > 0 {
+			list = append(list, render.ADRStatusCount{Status: s, Count: counts[s]})
+		}
+	}
+	return list
+}
+
+func isStatus(s string) bool {
+	for _, known := range adr.Statuses {
+		if s == known {
+			return true
+		}
+	}
+	return false
+}
+
+// adrSchema is the decision JSON-LD for a record's entity page.
+func (b *Builder) adrSchema(schemaGen *schema.Generator, r *adr.Record, entityURL string) map[string]interface{} {
+	d := schema.Decision{ID: r.ID(), Status: r.Status}
+	if !r.Date.IsZero() {
+		d.Date = r.Date.Format("2006-01-02")
+	}
+	for _, e := range r.Supersedes {
+		d.Supersedes = append(d.Supersedes, schema.ItemListEntry{
+			Name: e.GetString("title"),
+			URL:  b.pageURL("/" + e.Slug + ".html"),
+		})
+	}
+	return schemaGen.GenerateDecisionSchema(r.Entity, entityURL, d)
+}
@@ -0,0 +1,71 @@
+package build
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"github.com/supermodeltools/arch-docs/internal/pssg/affiliate"
+	"github.com/supermodeltools/arch-docs/internal/pssg/logging"
+)
+
+// affiliateStats counts entity pages by whether they rendered affiliate links.
+type affiliateStats struct {
+	withLinks    int64
+	withoutLinks int64
+}
+
+func (s *affiliateStats) record(links []affiliate.Link) {
+	if len(links) > 0 {
+		atomic.AddInt64(&s.withLinks, 1)
+	} else {
+		atomic.AddInt64(&s.withoutLinks, 1)
+	}
+}
+
+// checkAffiliateProviders logs providers skipped for missing env vars, and
+// fails when affiliates.fail_on_missing is set.
+func (b *Builder) checkAffiliateProviders(reg *affiliate.Registry) error {
+	for _, sp := range reg.Skipped {
+		name := sp.Name
+		if sp.Region != "" {
+			name += " (" + sp.Region + ")"
+		}
+		logging.Stage("affiliates").Warn("Affiliate provider skipped: env var is not set", "provider", name, "env", sp.EnvVar)
+	}
+	if len(reg.Skipped) > 0 && b.cfg.Affiliates.FailOnMissing {
+		return fmt.Errorf("%d affiliate provider(s) missing env vars", len(reg.Skipped))
+	}
+	return nil
+}
+
+// logAffiliateReport summarizes how many pages were monetized.
+func (b *Builder) logAffiliateReport(reg *affiliate.Registry, stats *affiliateStats) {
+	if len(b.cfg.Affiliates.Providers) == 0 {
+		return
+	}
+	alog := logging.Stage("affiliates")
+	alog.Info("Affiliates",
+		"active", len(reg.Providers), "configured", len(b.cfg.Affiliates.Providers), "skipped", countSkippedProviders(reg),
+		"pages_with_links", stats.withLinks, "pages_without_links", stats.withoutLinks)
+	if len(reg.Providers) == 0 {
+		alog.Warn("No affiliate providers are active; every page is unmonetized")
+	}
+}
+
+// countSkippedProviders counts skipped top-level providers, ignoring variants.
+func countSkippedProviders(reg *affiliate.Registry) int {
+	n := 0
+	for _, sp := range reg.Skipped {
+		skippedVariant := false
+		for _, p := range reg.Providers {
+			if p.Name == sp.Name {
+				skippedVariant = true
+				break
+			}
+		}
+		if !skippedVariant {
+			n++
+		}
+	}
+	return n
+}
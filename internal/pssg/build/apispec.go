@@ -0,0 +1,44 @@
+package build
+
+import (
+	"path/filepath"
+
+	"github.com/supermodeltools/arch-docs/internal/pssg/apispec"
+	"github.com/supermodeltools/arch-docs/internal/pssg/entity"
+	"github.com/supermodeltools/arch-docs/internal/pssg/logging"
+)
+
+// loadAPISpecs reads the OpenAPI and protobuf files entities reference,
+// keyed by entity slug. Files that cannot be read are reported and the
+// entity is rendered without a reference.
+func (b *Builder) loadAPISpecs(entities []*entity.Entity) map[string]*apispec.Spec {
+	base := b.cfg.Paths.SourceDir
+	if base == "" {
+		base = b.cfg.ConfigDir
+	}
+	log := logging.Stage("load")
+	specs := make(map[string]*apispec.Spec)
+	cache := make(map[string]*apispec.Spec) // several entities may share a spec
+	for _, e := range entities {
+		rel := e.GetString(b.cfg.API.Field)
+		if rel == "" {
+			continue
+		}
+		full := filepath.FromSlash(rel)
+		if !filepath.IsAbs(full) {
+			full = filepath.Join(base, full)
+		}
+		spec, ok := cache[full]
+		if !ok {
+			var err error
+			if spec, err = apispec.Load(full, rel); err != nil {
+				log.Warn("Failed to read API spec", "slug", e.Slug, "error", err)
+			}
+			cache[full] = spec
+		}
+		if spec != nil {
+			specs[e.Slug] = spec
+		}
+	}
+	return specs
+}
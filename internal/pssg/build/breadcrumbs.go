@@ -0,0 +1,58 @@
+package build
+
+import (
+	"strings"
+
+	"github.com/supermodeltools/arch-docs/internal/pssg/entity"
+	"github.com/supermodeltools/arch-docs/internal/pssg/render"
+	"github.com/supermodeltools/arch-docs/internal/pssg/taxonomy"
+)
+
+// entityBreadcrumbs returns the crumbs breadcrumbs.entity lists for an
+// entity, skipping those it has no value for.
+func (b *Builder) entityBreadcrumbs(e *entity.Entity, taxonomies []taxonomy.Taxonomy) []render.Breadcrumb {
+	var crumbs []render.Breadcrumb
+	for _, src := range b.cfg.Breadcrumbs.Entity {
+		if src.Taxonomy != "" {
+			if entry, ok := termOf(e, src.Taxonomy, taxonomies); ok {
+				crumbs = append(crumbs, render.Breadcrumb{
+					Name: entry.Name,
+					URL:  b.pageURL(taxonomy.HubPageURL(src.Taxonomy, entry.Slug, 1)),
+				})
+			}
+			continue
+		}
+		value := e.GetString(src.Field)
+		if values := e.GetStringSlice(src.Field); value == "" && len(values) > 0 {
+			value = values[0]
+		}
+		if value == "" {
+			continue
+		}
+		crumb := render.Breadcrumb{Name: value}
+		if src.URL != "" {
+			u := strings.NewReplacer("{{value}}", value, "{{slug}}", entity.ToSlug(value)).Replace(src.URL)
+			crumb.URL = absoluteURL(b.cfg.Site.BaseURL, u)
+		}
+		crumbs = append(crumbs, crumb)
+	}
+	return crumbs
+}
+
+// termOf returns the first entry of the named taxonomy that lists e, so
+// only terms with a hub page are linked.
+func termOf(e *entity.Entity, name string, taxonomies []taxonomy.Taxonomy) (taxonomy.Entry, bool) {
+	for _, tax := range taxonomies {
+		if tax.Name != name {
+			continue
+		}
+		for _, entry := range tax.Entries {
+			for _, member := range entry.Entities {
+				if member == e {
+					return entry, true
+				}
+			}
+		}
+	}
+	return taxonomy.Entry{}, false
+}
@@ -1,33 +1,114 @@
 package build
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"html/template"
-	"log"
+	"log/slog"
 	"os"
+	"path"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
 
+	"github.com/supermodeltools/arch-docs/internal/pssg/adr"
 	"github.com/supermodeltools/arch-docs/internal/pssg/affiliate"
+	"github.com/supermodeltools/arch-docs/internal/pssg/apispec"
+	"github.com/supermodeltools/arch-docs/internal/pssg/c4"
+	"github.com/supermodeltools/arch-docs/internal/pssg/collection"
+	"github.com/supermodeltools/arch-docs/internal/pssg/charts"
 	"github.com/supermodeltools/arch-docs/internal/pssg/config"
+	"github.com/supermodeltools/arch-docs/internal/pssg/contributors"
+	"github.com/supermodeltools/arch-docs/internal/pssg/engagement"
 	"github.com/supermodeltools/arch-docs/internal/pssg/enrichment"
 	"github.com/supermodeltools/arch-docs/internal/pssg/entity"
+	"github.com/supermodeltools/arch-docs/internal/pssg/featured"
+	"github.com/supermodeltools/arch-docs/internal/pssg/freshness"
+	"github.com/supermodeltools/arch-docs/internal/pssg/history"
+	"github.com/supermodeltools/arch-docs/internal/pssg/imaging"
 	"github.com/supermodeltools/arch-docs/internal/pssg/loader"
+	"github.com/supermodeltools/arch-docs/internal/pssg/logging"
 	"github.com/supermodeltools/arch-docs/internal/pssg/output"
+	"github.com/supermodeltools/arch-docs/internal/pssg/owners"
+	"github.com/supermodeltools/arch-docs/internal/pssg/relation"
 	"github.com/supermodeltools/arch-docs/internal/pssg/render"
 	"github.com/supermodeltools/arch-docs/internal/pssg/schema"
+	"github.com/supermodeltools/arch-docs/internal/pssg/series"
+	"github.com/supermodeltools/arch-docs/internal/pssg/similar"
+	"github.com/supermodeltools/arch-docs/internal/pssg/source"
 	"github.com/supermodeltools/arch-docs/internal/pssg/taxonomy"
+	"github.com/supermodeltools/arch-docs/internal/pssg/text"
+	"github.com/supermodeltools/arch-docs/internal/pssg/theme"
+	"github.com/supermodeltools/arch-docs/internal/pssg/validation"
 )
 
 // Builder orchestrates the entire static site generation pipeline.
 type Builder struct {
 	cfg   *config.Config
 	force bool
+	// skipManifest is set for the scratch builds of extra output targets,
+	// which must not replace the main output's manifest.
+	skipManifest bool
+	// edition is set for the builds of non-default languages, which leave
+	// site-wide files such as CNAME and robots.txt to the root build.
+	edition  bool
+	editions []edition
+	// sitemaps are the URLs of the per-language sitemap files written so
+	// far, for the index the root build writes last.
+	sitemaps []string
+	// images are the processed hero images by entity slug.
+	images map[string]*imaging.Set
+	// shareSVGs records the share image files written so far.
+	shareSVGs sync.Map
+	// shares caches share images across builds; editions and targets use
+	// the root build's.
+	shares *shareCache
+	// robotsPages maps URL paths to the directive robots.pages gives them.
+	robotsPages sync.Map
+	// sources maps entities to the source they cite; nil unless
+	// paths.source_dir is set.
+	sources *source.Index
+	// graphed holds the entities with a relation subgraph file; nil unless
+	// graph.enabled is set.
+	graphed map[string]bool
+	// c4 is the C4 model of the site; nil unless c4.enabled is set.
+	c4 *c4.Model
+	// adrs indexes the decision records; nil unless adr.enabled is set.
+	adrs *adr.Index
+	// apis are the API specs entities reference, by slug.
+	apis map[string]*apispec.Spec
+	// owners is the ownership file; nil unless owners.enabled is set and
+	// the file could be read.
+	owners *owners.Set
+	// changes are the recent commits to each entity's file, by slug; nil
+	// unless history.enabled is set.
+	changes map[string][]history.Commit
+	// freshness compares entities with the code they document; nil
+	// unless freshness.enabled is set.
+	freshness *freshness.Report
+	// similar maps slugs to the entities with the most similar text; nil
+	// unless similar.enabled is set.
+	similar map[string][]similar.Match
+	// profiles are the people in extra.contributors; nil without one.
+	profiles *contributors.Set
+	// engagement holds the counts from extra.engagement, by slug.
+	engagement map[string]*engagement.Counts
+	// collections are the curated lists in extra.collections.
+	collections []*collection.Collection
+	// featuredSeed picks the homepage's featured entities; editions and
+	// targets use the root build's.
+	featuredSeed string
+	// dates are when builds first saw each entity and saw it change, by
+	// slug; nil unless recent.enabled is set.
+	dates map[string]entityDates
+	// entityCount is the number of entities loaded, for hooks.notify.
+	entityCount int
 }
 
 // NewBuilder creates a new builder.
@@ -35,19 +116,65 @@ func NewBuilder(cfg *config.Config, force bool) *Builder {
 	return &Builder{cfg: cfg, force: force}
 }
 
-// Build runs the complete build pipeline.
+// Build runs the complete build pipeline, writes the warnings it logged
+// to output.warnings and reports the result to hooks.notify.
 func (b *Builder) Build() error {
 	start := time.Now()
-	log.Printf("Building site: %s", b.cfg.Site.Name)
+	if b.skipManifest {
+		return b.build()
+	}
+	collector, restore := logging.Collect()
+	err := b.build()
+	restore()
+	b.reportWarnings(collector.Warnings())
+	b.notifyHooks(start, err)
+	return err
+}
+
+func (b *Builder) build() error {
+	start := time.Now()
+	slog.Info("Building site", "site", b.cfg.Site.Name)
+
+	if !b.edition {
+		if err := b.planEditions(); err != nil {
+			return err
+		}
+	}
+	useSlugStyle(b.cfg)
 
 	// 1. Load entities
-	log.Printf("Loading entities from %s...", b.cfg.Paths.Data)
+	logging.Stage("load").Info("Loading entities", "dir", b.cfg.Paths.Data)
 	ldr := loader.New(b.cfg)
 	entities, err := ldr.Load()
 	if err != nil {
 		return fmt.Errorf("loading entities: %w", err)
 	}
-	log.Printf("Loaded %d entities", len(entities))
+	logging.Stage("load").Info("Loaded entities", "count", len(entities))
+	b.entityCount = len(entities)
+
+	// 1b. Enforce per-type validation profiles
+	if issues := validation.Check(entities, b.cfg.Data.Validation); len(issues) > 0 {
+		vlog := logging.Stage("validate")
+		vlog.Warn("Validation issues", "count", len(issues))
+		for _, is := range issues {
+			vlog.Warn("Validation issue", "slug", is.Slug, "type", is.Type, "field", is.Field, "issue", is.Message)
+		}
+		if b.cfg.Data.Validation.FailOnError {
+			return fmt.Errorf("%d entity validation issue(s)", len(issues))
+		}
+	}
+
+	// 1c. Apply featured/weight ordering
+	sortEntities(entities, b.cfg.Data.Ordering)
+
+	// Entities marked noindex are rendered but not promoted
+	indexable := entity.Indexable(entities)
+	if n := len(entities) - len(indexable); n > 0 {
+		logging.Stage("load").Debug("Entities marked noindex", "count", n)
+	}
+	// Syndicated entities with an external canonical_url stay searchable but
+	// are left out of the sitemap and feeds
+	feedEntities := withoutExternalCanonical(indexable)
 
 	// 2. Build slug lookup
 	slugMap := make(map[string]*entity.Entity)
@@ -55,31 +182,147 @@ func (b *Builder) Build() error {
 		slugMap[e.Slug] = e
 	}
 
+	// 2b. Resolve typed relations and their backlinks
+	relGraph := relation.Build(entities, slugMap, b.cfg.Data.Relations)
+	if len(b.cfg.Links.Siblings) > 0 {
+		b.linkSiblings(relGraph)
+	}
+	if n := len(relGraph.Dangling); n > 0 {
+		dlog := logging.Stage("relations")
+		dlog.Warn("Relation targets not found", "count", n)
+		for i, d := range relGraph.Dangling {
+			if i == 20 {
+				dlog.Warn("More relation targets not found", "count", n-i)
+				break
+			}
+			dlog.Warn("Relation target not found", "from", d.From, "relation", d.Relation, "target", d.Target)
+		}
+	}
+
+	relData := relGraph.Data(entities)
+	if b.cfg.Graph.Enabled {
+		b.graphed = make(map[string]bool, len(relData.Nodes))
+		for _, n := range relData.Nodes {
+			b.graphed[n.Slug] = true
+		}
+	}
+
+	if b.cfg.C4.Enabled {
+		var warnings []string
+		b.c4, warnings = c4.Build(entities, slugMap, relGraph.Edges(entities), b.cfg.C4)
+		clog := logging.Stage("c4")
+		for _, w := range warnings {
+			clog.Warn("Misplaced C4 element", "detail", w)
+		}
+		clog.Info("Built C4 model", "views", len(b.c4.Views)+1)
+	}
+
+	// 2c. Group entities into ordered series
+	seriesIdx := series.Build(entities, b.cfg.Series)
+	if len(seriesIdx.All) > 0 {
+		logging.Stage("load").Info("Found series", "count", len(seriesIdx.All))
+	}
+
+	// 2d. Resolve the source files entities cite
+	if b.cfg.Paths.SourceDir != "" {
+		b.sources = source.Build(entities, b.cfg)
+		logging.Stage("load").Info("Found source files", "files", len(b.sources.Files), "dirs", len(b.sources.Dirs))
+	}
+
+	// 2e. Index architecture decision records; this sets the status field
+	// the ADR status taxonomy reads, so it runs before taxonomies are built
+	if b.cfg.ADR.Enabled {
+		var warnings []string
+		b.adrs, warnings = adr.Build(entities, relGraph, b.cfg.Data.Validation.TypeField, b.cfg.ADR)
+		alog := logging.Stage("adr")
+		for _, w := range warnings {
+			alog.Warn("Invalid decision record", "detail", w)
+		}
+		alog.Info("Found decision records", "count", len(b.adrs.All))
+	}
+
+	// 2f. Read the API specs entities reference
+	b.apis = b.loadAPISpecs(entities)
+	if len(b.apis) > 0 {
+		logging.Stage("load").Info("Found API specs", "entities", len(b.apis))
+	}
+
+	// 2g. Attach code owners; the owner taxonomy reads them
+	if b.cfg.Owners.Enabled {
+		b.assignOwners(entities)
+	}
+
+	// 2h. Read the git history of entity files, and date entities by it
+	// and by earlier builds
+	if b.cfg.History.Enabled {
+		b.changes = b.loadHistory(entities)
+	}
+	if b.cfg.Recent.Enabled {
+		b.dates = b.loadDates(entities, time.Now().UTC())
+	}
+
+	// 2i. Date entities against the code they document
+	if b.cfg.Freshness.Enabled {
+		b.freshness = freshness.Build(entities, b.cfg, time.Now())
+		logging.Stage("freshness").Info("Checked documentation freshness", "checked", b.freshness.Checked, "stale", b.freshness.Stale)
+	}
+
+	// 2j. Find entities with similar text
+	if b.cfg.Similar.Enabled {
+		b.similar = similar.Compute(entities, b.cfg.Similar)
+		logging.Stage("similar").Info("Computed similar entities", "entities", len(b.similar))
+	}
+
 	// 3. Load enrichment cache
 	enrichmentData := make(map[string]map[string]interface{})
 	if b.cfg.Enrichment.CacheDir != "" {
-		log.Printf("Loading enrichment cache from %s...", b.cfg.Enrichment.CacheDir)
+		elog := logging.Stage("enrichment")
+		elog.Info("Loading enrichment cache", "dir", b.cfg.Enrichment.CacheDir)
 		var err error
 		enrichmentData, err = enrichment.ReadAllCaches(b.cfg.Enrichment.CacheDir)
 		if err != nil {
-			log.Printf("Warning: failed to load enrichment cache: %v", err)
+			elog.Warn("Failed to load enrichment cache", "error", err)
 		} else {
-			log.Printf("Loaded enrichment data for %d entities", len(enrichmentData))
+			elog.Info("Loaded enrichment data", "entities", len(enrichmentData))
 		}
+
+		sourceHashes := make(map[string]string, len(entities))
+		for _, e := range entities {
+			sourceHashes[e.Slug] = e.SourceHash
+		}
+		// Translated entities never match the default language's hashes
+		if stale := enrichment.StaleSlugs(b.cfg.Enrichment.CacheDir, sourceHashes); len(stale) > 0 && !b.edition {
+			elog.Warn("Enrichment cache entries are stale (source changed since enrichment)", "count", len(stale))
+		}
+	}
+	for _, e := range entities {
+		e.ComputeContentHash(enrichmentData[e.Slug])
 	}
 
 	// 4. Load extra data
 	favorites := b.loadFavorites(slugMap)
-	contributors := b.loadContributors()
+	b.profiles = b.loadContributors()
+	b.collections = b.loadCollections(slugMap)
+	contributors := b.profiles.Raw()
+	b.engagement = b.loadEngagement()
 
 	// 5. Set up affiliate registry
 	affiliateRegistry := affiliate.NewRegistry(b.cfg.Affiliates)
+	redirects := affiliate.NewRedirectSet(b.cfg.Affiliates.Redirects)
+	if err := b.checkAffiliateProviders(affiliateRegistry); err != nil {
+		return err
+	}
+	var affStats affiliateStats
 
 	// 6. Build taxonomies
-	log.Printf("Building taxonomies...")
+	tlog := logging.Stage("taxonomies")
+	tlog.Info("Building taxonomies")
 	taxonomies := taxonomy.BuildAll(entities, b.cfg.Taxonomies, enrichmentData)
 	for _, tax := range taxonomies {
-		log.Printf("  %s: %d entries", tax.Label, len(tax.Entries))
+		tlog.Debug("Built taxonomy", "taxonomy", tax.Name, "entries", len(tax.Entries))
+	}
+	if err := reportCollisions(taxonomies); err != nil {
+		return err
 	}
 
 	// 7. Build valid taxonomy slug lookup
@@ -97,19 +340,36 @@ func (b *Builder) Build() error {
 	if err := os.MkdirAll(outDir, 0755); err != nil {
 		return fmt.Errorf("creating output dir: %w", err)
 	}
+	writeStart := fsNow(outDir)
+	if b.shares == nil {
+		b.shares = loadShareCache(filepath.Join(b.cfg.Paths.Cache, shareCacheFile), b.force)
+	}
+
+	// 8b. Process hero images and download remote ones
+	if b.cfg.Images.Enabled || len(b.cfg.Images.RemoteFields) > 0 {
+		if b.images, err = b.processImages(entities, outDir); err != nil {
+			return err
+		}
+	}
 
 	// 9. Initialize render engine
-	log.Printf("Loading templates from %s...", b.cfg.Paths.Templates)
+	rlog := logging.Stage("render")
+	rlog.Info("Loading templates", "dir", b.cfg.Paths.Templates)
 	engine, err := render.NewEngine(b.cfg)
 	if err != nil {
 		return fmt.Errorf("initializing render engine: %w", err)
 	}
+	engine.SetImages(b.images)
+	if b.featuredSeed == "" {
+		b.featuredSeed = featured.Seed(b.cfg.Featured, time.Now())
+	}
+	engine.SetFeaturedSeed(b.featuredSeed)
 
 	// 10. Extract CSS/JS
 	if b.cfg.Output.ExtractCSS != "" {
 		cssContent, err := engine.RenderCSS()
 		if err != nil {
-			log.Printf("Warning: failed to render CSS: %v", err)
+			rlog.Warn("Failed to render CSS", "error", err)
 		} else if cssContent != "" {
 			cssPath := filepath.Join(outDir, b.cfg.Output.ExtractCSS)
 			if err := os.WriteFile(cssPath, []byte(cssContent), 0644); err != nil {
@@ -120,7 +380,7 @@ func (b *Builder) Build() error {
 	if b.cfg.Output.ExtractJS != "" {
 		jsContent, err := engine.RenderJS()
 		if err != nil {
-			log.Printf("Warning: failed to render JS: %v", err)
+			rlog.Warn("Failed to render JS", "error", err)
 		} else if jsContent != "" {
 			jsPath := filepath.Join(outDir, b.cfg.Output.ExtractJS)
 			if err := os.WriteFile(jsPath, []byte(jsContent), 0644); err != nil {
@@ -137,11 +397,14 @@ func (b *Builder) Build() error {
 	var sitemapMu sync.Mutex
 	today := time.Now().Format("2006-01-02")
 
-	addSitemapEntry := func(path, priority, changefreq string) {
+	addSitemapEntry := func(kind, path, priority, changefreq string) {
+		if !output.SitemapIncludes(b.cfg.Sitemap, kind, path) || b.noindexed(path) {
+			return
+		}
 		sitemapMu.Lock()
 		defer sitemapMu.Unlock()
 		sitemapEntries = append(sitemapEntries, output.NewSitemapEntry(
-			b.cfg.Site.BaseURL, path, today, priority, changefreq,
+			b.cfg.Site.BaseURL, b.link(path), today, priority, changefreq,
 		))
 	}
 
@@ -149,7 +412,7 @@ func (b *Builder) Build() error {
 	categoryEntries := make(map[string][]*entity.Entity)
 
 	// 11. Render entity pages (concurrent)
-	log.Printf("Rendering %d entity pages...", len(entities))
+	rlog.Info("Rendering entity pages", "count", len(entities))
 	var entityErrors int64
 	var wg sync.WaitGroup
 	sem := make(chan struct{}, 32) // 32-goroutine pool
@@ -161,23 +424,37 @@ func (b *Builder) Build() error {
 			defer wg.Done()
 			defer func() { <-sem }() // release
 
-			err := b.renderEntityPage(e, engine, schemaGen, slugMap, enrichmentData,
-				affiliateRegistry, taxonomies, validSlugs, contributors, outDir, addSitemapEntry)
+			err := b.renderEntityPage(e, engine, schemaGen, slugMap, relGraph, seriesIdx, enrichmentData,
+				affiliateRegistry, redirects, &affStats, taxonomies, validSlugs, contributors, outDir, addSitemapEntry)
 			if err != nil {
 				atomic.AddInt64(&entityErrors, 1)
-				fmt.Fprintf(os.Stderr, "Warning: failed to render %s: %v\n", e.Slug, err)
+				rlog.Warn("Failed to render entity page", "slug", e.Slug, "error", err)
 			}
 		}(e)
 	}
 	wg.Wait()
 	if entityErrors > 0 {
-		log.Printf("  %d entity pages had errors", entityErrors)
+		rlog.Warn("Entity pages had errors", "count", entityErrors)
+	}
+
+	// 11a. Write affiliate click-tracking redirect stubs
+	if n, err := redirects.Write(outDir); err != nil {
+		return fmt.Errorf("writing affiliate redirects: %w", err)
+	} else if n > 0 {
+		rlog.Debug("Wrote affiliate redirect stubs", "count", n)
 	}
 
 	// 11b. Generate search index
-	if len(entities) > 0 {
-		if err := b.generateSearchIndex(entities, outDir); err != nil {
-			log.Printf("Warning: failed to generate search index: %v", err)
+	if len(indexable) > 0 {
+		if err := b.generateSearchIndex(indexable, outDir); err != nil {
+			rlog.Warn("Failed to generate search index", "error", err)
+		}
+	}
+
+	// 11c. Publish the entity manifest for sibling sites
+	if b.cfg.Links.Publish && !b.edition {
+		if err := b.writeEntityManifest(indexable, outDir); err != nil {
+			return fmt.Errorf("writing entity manifest: %w", err)
 		}
 	}
 
@@ -185,13 +462,13 @@ func (b *Builder) Build() error {
 	for _, tax := range taxonomies {
 		if tax.Name == b.cfg.RSS.CategoryTaxonomy {
 			for _, entry := range tax.Entries {
-				categoryEntries[entry.Slug] = entry.Entities
+				categoryEntries[entry.Slug] = withoutExternalCanonical(entity.Indexable(entry.Entities))
 			}
 		}
 	}
 
 	// 12. Render taxonomy pages
-	log.Printf("Rendering taxonomy pages...")
+	rlog.Info("Rendering taxonomy pages")
 	for _, tax := range taxonomies {
 		if err := b.renderTaxonomyPages(tax, engine, schemaGen, taxonomies, contributors, outDir, addSitemapEntry, today); err != nil {
 			return fmt.Errorf("rendering taxonomy %s: %w", tax.Name, err)
@@ -199,27 +476,92 @@ func (b *Builder) Build() error {
 	}
 
 	// 12b. Render all-entities pages
-	log.Printf("Rendering all-entities pages...")
+	rlog.Info("Rendering all-entities pages")
 	if err := b.renderAllEntitiesPages(engine, schemaGen, entities, taxonomies, outDir, addSitemapEntry); err != nil {
 		return fmt.Errorf("rendering all-entities pages: %w", err)
 	}
 
+	// 12c. Render series index pages
+	if len(seriesIdx.All) > 0 {
+		rlog.Info("Rendering series pages", "count", len(seriesIdx.All))
+		if err := b.renderSeriesPages(engine, schemaGen, seriesIdx, taxonomies, outDir, addSitemapEntry); err != nil {
+			return fmt.Errorf("rendering series pages: %w", err)
+		}
+	}
+
+	// 12d. Render source pages; editions link to the root edition's
+	if b.sources != nil && !b.edition {
+		rlog.Info("Rendering source pages", "files", len(b.sources.Files), "dirs", len(b.sources.Dirs))
+		if err := b.renderSourcePages(engine, schemaGen, b.sources, taxonomies, outDir, addSitemapEntry); err != nil {
+			return fmt.Errorf("rendering source pages: %w", err)
+		}
+	}
+
+	// 12e. Render the relation graph page and data
+	if b.cfg.Graph.Enabled {
+		rlog.Info("Rendering relation graph", "nodes", len(relData.Nodes), "edges", len(relData.Edges))
+		if err := b.renderGraph(engine, schemaGen, relData, taxonomies, outDir, addSitemapEntry); err != nil {
+			return fmt.Errorf("rendering relation graph: %w", err)
+		}
+	}
+
+	// 12f. Render C4 model pages
+	if b.c4 != nil {
+		rlog.Info("Rendering C4 pages", "views", len(b.c4.Views)+1)
+		if err := b.renderC4Pages(engine, schemaGen, b.c4, taxonomies, outDir, addSitemapEntry); err != nil {
+			return fmt.Errorf("rendering C4 pages: %w", err)
+		}
+	}
+
+	// 12g. Render the ADR index
+	if b.adrs != nil {
+		rlog.Info("Rendering ADR index", "records", len(b.adrs.All))
+		if err := b.renderADRIndex(engine, schemaGen, b.adrs, taxonomies, outDir, addSitemapEntry); err != nil {
+			return fmt.Errorf("rendering ADR index: %w", err)
+		}
+	}
+
+	// 12h. Render the stale docs report
+	if b.freshness != nil {
+		rlog.Info("Rendering freshness report", "stale", b.freshness.Stale)
+		if err := b.renderFreshness(engine, schemaGen, b.freshness, taxonomies, outDir); err != nil {
+			return err
+		}
+	}
+
+	// 12i. Render the contributors index
+	if b.cfg.Contributors.Enabled && b.profiles != nil {
+		rlog.Info("Rendering contributors", "profiles", len(b.profiles.Profiles))
+		if err := b.renderContributors(engine, schemaGen, taxonomies, outDir, addSitemapEntry); err != nil {
+			return fmt.Errorf("rendering contributors: %w", err)
+		}
+	}
+
+	// 12j. Render curated collections
+	if len(b.collections) > 0 {
+		rlog.Info("Rendering collections", "count", len(b.collections))
+		if err := b.renderCollections(engine, schemaGen, taxonomies, outDir, addSitemapEntry); err != nil {
+			return fmt.Errorf("rendering collections: %w", err)
+		}
+	}
+
 	// 13. Render homepage
-	log.Printf("Rendering homepage...")
-	if err := b.renderHomepage(engine, schemaGen, entities, taxonomies, favorites, contributors, outDir); err != nil {
+	rlog.Info("Rendering homepage")
+	if err := b.renderHomepage(engine, schemaGen, entities, taxonomies, favorites, contributors, relData, outDir); err != nil {
 		return fmt.Errorf("rendering homepage: %w", err)
 	}
-	addSitemapEntry("/index.html", b.cfg.Sitemap.Priorities["homepage"], b.cfg.Sitemap.ChangeFreqs["homepage"])
+	addSitemapEntry("homepage", "/index.html", b.cfg.Sitemap.Priorities["homepage"], b.cfg.Sitemap.ChangeFreqs["homepage"])
 
 	// 14. Render static pages
 	for path, tmpl := range b.cfg.Templates.StaticPages {
 		ctx := render.StaticPageContext{
 			Site:          b.cfg.Site,
+			Languages:     b.languageLinks("/" + path),
 			AllTaxonomies: taxonomies,
 		}
 		html, err := engine.RenderStatic(tmpl, ctx)
 		if err != nil {
-			log.Printf("Warning: failed to render static page %s: %v", path, err)
+			rlog.Warn("Failed to render static page", "path", path, "error", err)
 			continue
 		}
 		outPath := filepath.Join(outDir, path)
@@ -231,18 +573,36 @@ func (b *Builder) Build() error {
 		}
 	}
 
+	// 14b. Render the share card audit page from the pages written above
+	if b.cfg.Previews.Enabled {
+		if err := b.renderPreviews(engine, taxonomies, outDir, writeStart); err != nil {
+			return err
+		}
+	}
+
 	// 15. Generate sitemap
-	log.Printf("Generating sitemap (%d entries)...", len(sitemapEntries))
-	sitemapFiles := output.GenerateSitemapFiles(sitemapEntries, b.cfg.Site.BaseURL, b.cfg.Sitemap.MaxURLsPerFile)
+	olog := logging.Stage("output")
+	olog.Info("Generating sitemap", "entries", len(sitemapEntries))
+	var sitemapFiles []output.SitemapFile
+	if len(b.editions) > 0 {
+		// Each edition writes sitemap-<lang>.xml; the root build indexes them all
+		sitemapFiles = output.GenerateSitemapChunks(sitemapEntries, "sitemap-"+b.cfg.Site.Language, b.cfg.Sitemap.MaxURLsPerFile)
+		for _, sf := range sitemapFiles {
+			b.sitemaps = append(b.sitemaps, b.cfg.Site.BaseURL+"/"+sf.Filename)
+		}
+	} else {
+		sitemapFiles = output.GenerateSitemapFiles(sitemapEntries, b.cfg.Site.BaseURL, b.cfg.Sitemap.MaxURLsPerFile)
+	}
 	for _, sf := range sitemapFiles {
 		if err := os.WriteFile(filepath.Join(outDir, sf.Filename), []byte(sf.Content), 0644); err != nil {
 			return fmt.Errorf("writing %s: %w", sf.Filename, err)
 		}
 	}
-	log.Printf("  Generated %d sitemap file(s)", len(sitemapFiles))
+	olog.Debug("Generated sitemap files", "count", len(sitemapFiles))
 
 	// 16. Generate RSS
-	rssFeeds := output.GenerateRSSFeeds(entities, b.cfg, categoryEntries)
+	rssFeeds := output.GenerateRSSFeeds(feedEntities, b.cfg, categoryEntries)
+	rssFeeds = append(rssFeeds, output.GenerateCollectionFeeds(b.collections, b.cfg)...)
 	for _, feed := range rssFeeds {
 		feedPath := filepath.Join(outDir, feed.RelativePath)
 		if err := os.MkdirAll(filepath.Dir(feedPath), 0755); err != nil {
@@ -253,50 +613,166 @@ func (b *Builder) Build() error {
 		}
 	}
 	if len(rssFeeds) > 0 {
-		log.Printf("Generated %d RSS feed(s)", len(rssFeeds))
+		olog.Info("Generated RSS feeds", "count", len(rssFeeds))
+	}
+
+	// 16b. Generate per-entity change feeds
+	if b.changes != nil {
+		n, err := b.writeChangeFeeds(entities, outDir)
+		if err != nil {
+			return err
+		}
+		olog.Info("Generated change feeds", "count", n)
 	}
 
 	// 17. Generate robots.txt
-	robotsContent := output.GenerateRobotsTxt(b.cfg)
-	if err := os.WriteFile(filepath.Join(outDir, "robots.txt"), []byte(robotsContent), 0644); err != nil {
-		return fmt.Errorf("writing robots.txt: %w", err)
+	if !b.edition {
+		robotsContent := output.GenerateRobotsTxt(b.cfg)
+		if err := os.WriteFile(filepath.Join(outDir, "robots.txt"), []byte(robotsContent), 0644); err != nil {
+			return fmt.Errorf("writing robots.txt: %w", err)
+		}
 	}
 
 	// 18. Generate llms.txt
 	if b.cfg.LlmsTxt.Enabled {
-		llmsContent := output.GenerateLlmsTxt(b.cfg, entities, taxonomies)
+		llmsEntities := indexable
+		if b.edition {
+			// Untranslated entities point their canonical URL at the
+			// default edition and are listed in its llms.txt
+			llmsEntities = feedEntities
+		}
+		llmsContent := output.GenerateLlmsTxt(b.cfg, llmsEntities, taxonomies)
 		if err := os.WriteFile(filepath.Join(outDir, "llms.txt"), []byte(llmsContent), 0644); err != nil {
 			return fmt.Errorf("writing llms.txt: %w", err)
 		}
 	}
 
-	// 19. Generate manifest.json
-	manifestContent := output.GenerateManifest(b.cfg)
+	// 19. Generate the icon set and manifest.json
+	var manifestIcons []output.ManifestIcon
+	for _, icon := range imaging.SiteIcons(b.cfg) {
+		if icon.Purpose != "" {
+			manifestIcons = append(manifestIcons, output.ManifestIcon{
+				Src: icon.Href, Sizes: icon.Sizes(), Type: icon.Type, Purpose: icon.Purpose,
+			})
+		}
+	}
+	if b.cfg.Icons.Source != "" && !b.edition {
+		if err := imaging.WriteIcons(b.cfg.Icons.Source, b.cfg.Icons.Background, outDir); err != nil {
+			return fmt.Errorf("writing icons: %w", err)
+		}
+	}
+	manifestContent := output.GenerateManifest(b.cfg, manifestIcons)
 	if err := os.WriteFile(filepath.Join(outDir, "manifest.json"), []byte(manifestContent), 0644); err != nil {
 		return fmt.Errorf("writing manifest.json: %w", err)
 	}
 
+	// 19b. Generate _headers; with a CSP it waits for the last page (21c)
+	if b.cfg.Headers.File && (len(b.cfg.Headers.Rules) > 0 || len(b.cfg.Robots.Pages) > 0) && !b.edition && !b.cfg.Headers.CSP.Enabled {
+		if err := os.WriteFile(filepath.Join(outDir, "_headers"), []byte(output.GenerateHeaders(b.cfg, "", b.robotsRules())), 0644); err != nil {
+			return fmt.Errorf("writing _headers: %w", err)
+		}
+	}
+
 	// 20. Write CNAME if configured
-	if b.cfg.Site.CNAME != "" {
+	if b.cfg.Site.CNAME != "" && !b.edition {
 		if err := os.WriteFile(filepath.Join(outDir, "CNAME"), []byte(b.cfg.Site.CNAME+"\n"), 0644); err != nil {
 			return fmt.Errorf("writing CNAME: %w", err)
 		}
 	}
 
-	// 21. Copy static assets
+	// 20b. Run the pages written above through output.postprocess
+	if b.cfg.Analytics.Provider != "" && !slices.Contains(b.cfg.Output.Postprocess, "analytics") {
+		olog.Warn("analytics.provider is set but output.postprocess does not list analytics", "provider", b.cfg.Analytics.Provider)
+	}
+	n, err := b.postProcess(outDir, writeStart)
+	if err != nil {
+		return fmt.Errorf("post-processing pages: %w", err)
+	}
+	if n > 0 {
+		olog.Info("Post-processed pages", "processors", strings.Join(b.cfg.Output.Postprocess, ", "), "pages", n)
+	}
+
+	// 21. Copy static assets, theme first so the site's files win
+	if t, err := theme.Open(b.cfg); err == nil && t != nil {
+		if err := theme.CopyStatic(t, outDir); err != nil {
+			olog.Warn("Failed to copy theme static assets", "error", err)
+		}
+	}
 	if b.cfg.Paths.Static != "" {
 		if err := copyDir(b.cfg.Paths.Static, outDir); err != nil {
-			log.Printf("Warning: failed to copy static assets: %v", err)
+			olog.Warn("Failed to copy static assets", "error", err)
+		}
+	}
+
+	b.logAffiliateReport(affiliateRegistry, &affStats)
+
+	// 21b. Build the other language editions into their subdirectories,
+	// then index every edition's sitemap
+	if !b.edition && len(b.editions) > 0 {
+		if err := b.buildEditions(); err != nil {
+			return err
+		}
+		index := output.GenerateSitemapIndex(b.sitemaps, today)
+		if err := os.WriteFile(filepath.Join(outDir, "sitemap.xml"), []byte(index), 0644); err != nil {
+			return fmt.Errorf("writing sitemap.xml: %w", err)
+		}
+	}
+
+	// 21c. Allow every edition's inline scripts and styles in the CSP and
+	// generate _headers
+	if b.cfg.Headers.File && b.cfg.Headers.CSP.Enabled && !b.edition {
+		policy, err := b.contentSecurityPolicy(outDir)
+		if err != nil {
+			return fmt.Errorf("building content security policy: %w", err)
+		}
+		if err := os.WriteFile(filepath.Join(outDir, "_headers"), []byte(output.GenerateHeaders(b.cfg, policy, b.robotsRules())), 0644); err != nil {
+			return fmt.Errorf("writing _headers: %w", err)
+		}
+	}
+
+	// 21d. Audit every edition's pages for accessibility issues
+	if b.cfg.A11y.Enabled && !b.edition {
+		if err := b.auditAccessibility(outDir, writeStart); err != nil {
+			return fmt.Errorf("auditing accessibility: %w", err)
+		}
+	}
+
+	// 21e. Record the inputs and tool behind this build
+	if (b.cfg.Output.Provenance == nil || *b.cfg.Output.Provenance) && !b.edition {
+		if err := b.writeProvenance(outDir, start); err != nil {
+			return fmt.Errorf("writing %s: %w", provenanceFile, err)
 		}
 	}
 
+	// 22. Record the generated files
+	if !b.skipManifest {
+		if err := b.writeManifest(writeStart); err != nil {
+			olog.Warn("Failed to write output manifest", "error", err)
+		}
+		if err := b.shares.save(filepath.Join(b.cfg.Paths.Cache, shareCacheFile)); err != nil {
+			olog.Warn("Failed to write share image cache", "error", err)
+		}
+	}
+	if b.dates != nil && (!b.skipManifest || b.edition) {
+		if err := b.saveDates(); err != nil {
+			olog.Warn("Failed to write entity dates", "error", err)
+		}
+	}
+
+	// 23. Write extra output targets
+	if err := b.writeTargets(); err != nil {
+		return fmt.Errorf("writing output targets: %w", err)
+	}
+
 	elapsed := time.Since(start)
-	log.Printf("\nBuild complete!")
-	log.Printf("  Entities:  %d", len(entities))
-	log.Printf("  Taxonomies: %d (%d total entries)", len(taxonomies), countTaxEntries(taxonomies))
-	log.Printf("  Sitemap:   %d URLs in %d file(s)", len(sitemapEntries), len(sitemapFiles))
-	log.Printf("  Output:    %s", outDir)
-	log.Printf("  Duration:  %s", elapsed.Round(time.Millisecond))
+	slog.Info("Build complete",
+		"entities", len(entities),
+		"taxonomies", len(taxonomies),
+		"taxonomy_entries", countTaxEntries(taxonomies),
+		"sitemap_urls", len(sitemapEntries),
+		"sitemap_files", len(sitemapFiles),
+		"output", outDir,
+		"duration", elapsed.Round(time.Millisecond).String())
 
 	return nil
 }
@@ -306,37 +782,66 @@ func (b *Builder) renderEntityPage(
 	engine *render.Engine,
 	schemaGen *schema.Generator,
 	slugMap map[string]*entity.Entity,
+	relGraph *relation.Graph,
+	seriesIdx *series.Index,
 	enrichmentData map[string]map[string]interface{},
 	affiliateReg *affiliate.Registry,
+	redirects *affiliate.RedirectSet,
+	affStats *affiliateStats,
 	taxonomies []taxonomy.Taxonomy,
 	validSlugs map[string]map[string]bool,
 	contributors map[string]interface{},
 	outDir string,
-	addSitemapEntry func(string, string, string),
+	addSitemapEntry func(string, string, string, string),
 ) error {
-	entityURL := fmt.Sprintf("%s/%s.html", b.cfg.Site.BaseURL, e.Slug)
+	entityURL := b.pageURL("/" + e.Slug + ".html")
+	canonicalURL := entityURL
+	if override := e.CanonicalOverride(); override != "" {
+		canonicalURL = absoluteURL(b.cfg.Site.BaseURL, override)
+	}
 
 	// Resolve pairings
-	var pairings []*entity.Entity
-	if pairingsSlugs := e.GetStringSlice("pairings"); len(pairingsSlugs) > 0 {
-		for _, ps := range pairingsSlugs {
-			if paired, ok := slugMap[ps]; ok {
-				pairings = append(pairings, paired)
-			}
-		}
-	}
+	pairings := relGraph.Related(e.Slug, "pairings")
+	runbooks := b.runbooks(e)
 
 	// Enrichment data for this entity
 	eData := enrichmentData[e.Slug]
 
-	// Generate affiliate links
-	var affLinks []affiliate.Link
-	if eData != nil {
-		affLinks = affiliateReg.GenerateLinks(eData, b.cfg.Affiliates.SearchTermPaths)
+	// Generate affiliate links (hand-picked products first, then enrichment search terms)
+	affLinks := affiliateReg.GenerateEntityLinks(
+		affiliate.ParseProducts(e.Fields[b.cfg.Affiliates.ProductsField]),
+		eData, b.cfg.Affiliates.SearchTermPaths,
+		affiliate.LinkContext{
+			Category: e.GetString(b.cfg.Affiliates.CategoryField),
+			Slug:     e.Slug,
+		})
+
+	redirects.Rewrite(affLinks)
+	affStats.record(affLinks)
+
+	disclosure, err := engine.AffiliateDisclosure(affLinks)
+	if err != nil {
+		return fmt.Errorf("rendering affiliate disclosure: %w", err)
 	}
 
 	// Cook mode prompt
-	cookPrompt := render.GenerateCookModePrompt(e, eData, affLinks)
+	cookPrompt, err := engine.CookModePrompt(render.PromptContext{
+		Site:           b.cfg.Site,
+		Entity:         e,
+		URL:            entityURL,
+		Enrichment:     eData,
+		AffiliateLinks: affLinks,
+	})
+	if err != nil {
+		return err
+	}
+	promptURL := ""
+	if b.cfg.Prompts.Enabled && cookPrompt != "" {
+		if err := b.writePrompt(e, entityURL, cookPrompt, outDir); err != nil {
+			return fmt.Errorf("writing prompt: %w", err)
+		}
+		promptURL = b.promptURL(e.Slug, ".txt")
+	}
 
 	// JSON-LD
 	recipeSchema := schemaGen.GenerateRecipeSchema(e, entityURL)
@@ -352,16 +857,21 @@ func (b *Builder) renderEntityPage(
 		}
 	}
 
+	// Decision records describe themselves as articles, not recipes
+	var record *adr.Record
+	if b.adrs != nil {
+		if record = b.adrs.Record(e.Slug); record != nil {
+			recipeSchema = b.adrSchema(schemaGen, record, entityURL)
+		}
+	}
+
 	// Breadcrumbs
 	var breadcrumbs []render.Breadcrumb
 	breadcrumbs = append(breadcrumbs, render.Breadcrumb{Name: "Home", URL: b.cfg.Site.BaseURL + "/"})
-	if cat := e.GetString("recipe_category"); cat != "" {
-		catSlug := entity.ToSlug(cat)
-		breadcrumbs = append(breadcrumbs, render.Breadcrumb{
-			Name: cat,
-			URL:  fmt.Sprintf("%s/category/%s.html", b.cfg.Site.BaseURL, catSlug),
-		})
+	if record != nil {
+		breadcrumbs = append(breadcrumbs, render.Breadcrumb{Name: "Architecture Decisions", URL: b.pageURL(b.adrs.URL)})
 	}
+	breadcrumbs = append(breadcrumbs, b.entityBreadcrumbs(e, taxonomies)...)
 	breadcrumbs = append(breadcrumbs, render.Breadcrumb{Name: e.GetString("title"), URL: ""})
 
 	breadcrumbSchema := schemaGen.GenerateBreadcrumbSchema(toBreadcrumbItems(breadcrumbs))
@@ -372,53 +882,151 @@ func (b *Builder) renderEntityPage(
 		faqSchema = schemaGen.GenerateFAQSchema(faqs)
 	}
 
-	// Share image
-	svgContent := render.GenerateEntityShareSVG(
+	// Share image, over the entity's photo when it has one
+	hero := b.images[e.Slug]
+	photoKey := ""
+	if hero != nil {
+		photoKey = hero.ShareKey()
+	}
+	shareInputs := []interface{}{
 		b.cfg.Site.Name,
 		e.GetString("title"),
 		e.GetString("recipe_category"),
 		e.GetString("cuisine"),
 		e.GetString("skill_level"),
-	)
-	svgFilename := e.Slug + ".svg"
-	if err := writeShareSVG(outDir, svgFilename, svgContent); err != nil {
-		log.Printf("Warning: failed to write entity share SVG for %s: %v", e.Slug, err)
+		photoKey,
+	}
+	imageURL, err := b.shareImage(outDir, "entity", shareInputs, func(s render.ShareImages) string {
+		photo := ""
+		if hero != nil {
+			var err error
+			if photo, err = hero.ShareDataURI(); err != nil {
+				logging.Stage("render").Warn("Failed to read share photo", "slug", e.Slug, "error", err)
+			}
+		}
+		if photo != "" {
+			return s.EntityPhoto(
+				b.cfg.Site.Name,
+				e.GetString("title"),
+				photo,
+				e.GetString("recipe_category"),
+				e.GetString("cuisine"),
+				e.GetString("skill_level"),
+			)
+		}
+		return s.Entity(
+			b.cfg.Site.Name,
+			e.GetString("title"),
+			e.GetString("recipe_category"),
+			e.GetString("cuisine"),
+			e.GetString("skill_level"),
+		)
+	})
+	if err != nil {
+		logging.Stage("render").Warn("Failed to write share SVG", "slug", e.Slug, "error", err)
+	}
+
+	// Set share image on recipe schema, preferring the entity's own photo
+	caption := e.GetString("image_caption")
+	if caption == "" {
+		caption = e.GetString("title")
+	}
+	if hero != nil {
+		largest, thumb := hero.Largest(), hero.Thumbnail()
+		recipeSchema["image"] = []interface{}{
+			schema.ImageObject(largest.URL, largest.Width, largest.Height, caption),
+			schema.ImageObject(thumb.URL, thumb.Width, thumb.Height, caption),
+		}
+	} else {
+		recipeSchema["image"] = []interface{}{schema.ShareImageObject(imageURL, caption)}
 	}
-	imageURL := shareImageURL(b.cfg.Site.BaseURL, svgFilename)
 
-	// Set share image on recipe schema
-	recipeSchema["image"] = []string{imageURL}
+	schema.AddEngagement(recipeSchema, b.engagement[e.Slug])
 
 	jsonLD := schema.MarshalSchemas(recipeSchema, breadcrumbSchema, faqSchema)
 
+	// Relation subgraph, drawn on the page
+	graphURL := ""
+	if b.graphed[e.Slug] {
+		graphURL = b.graphURL("nodes/" + e.Slug + ".json")
+	}
+
+	// Highlighted source the entity cites
+	var sourceCode template.HTML
+	var sourceLang, sourceURL string
+	if b.sources != nil {
+		if ref, ok := b.sources.Ref(e.Slug); ok {
+			sourceURL = ref.URL()
+			if sourceCode, sourceLang, err = b.sources.Excerpt(e.Slug); err != nil {
+				logging.Stage("render").Warn("Failed to read source", "slug", e.Slug, "error", err)
+			}
+		}
+	}
+
 	title := e.GetString("title")
 	description := e.GetString("description")
 
 	ctx := render.EntityPageContext{
 		Site:           b.cfg.Site,
+		Languages:      b.languageLinks("/" + e.Slug + ".html"),
 		Entity:         e,
+		Image:          hero,
 		Slug:           e.Slug,
 		URL:            entityURL,
-		CanonicalURL:   entityURL,
+		CanonicalURL:   canonicalURL,
 		Breadcrumbs:    breadcrumbs,
 		Pairings:       pairings,
+		Relations:      relGraph.Map(e.Slug),
+		RelationGroups: relGraph.Groups(e.Slug),
+		Series:         seriesIdx.Nav(e.Slug),
 		Enrichment:     eData,
 		AffiliateLinks: affLinks,
+		AffiliateDisclosure: disclosure,
 		CookModePrompt: cookPrompt,
+		PromptURL:      promptURL,
 		JsonLD:         toTemplateHTML(jsonLD),
 		Taxonomies:     taxonomies,
 		AllTaxonomies:  taxonomies,
 		ValidSlugs:     validSlugs,
+		NoIndex:        e.NoIndex(),
+		Robots:         b.robots("entity", "/"+e.Slug+".html"),
 		Contributors:   contributors,
 		CTA: b.cfg.Extra.CTA,
-		OG: render.OGMeta{
+		SourceCode:     sourceCode,
+		SourceLang:     sourceLang,
+		SourceURL:      sourceURL,
+		GraphURL:       graphURL,
+		C4:             b.c4Element(e.Slug),
+		C4URL:          b.link("/" + b.cfg.C4.Dir + "/"),
+		ADR:            record,
+		ADRURL:         b.link("/" + b.cfg.ADR.Dir + "/"),
+		API:            b.apis[e.Slug],
+		Owners:         b.entityOwners(e),
+		OwnerDir:       b.ownerDir(),
+		Changes:        b.changes[e.Slug],
+		ChangesFeed:    b.changesFeed(e.Slug),
+		CommitURL:      b.commitURL(),
+		Stale:          b.staleItem(e.Slug),
+		Runbooks:       runbooks,
+		Comments:       b.comments(e),
+		Similar:        b.similar[e.Slug],
+		Engagement:     b.engagement[e.Slug],
+		Author:         b.profiles.Get(entity.ToSlug(e.GetString("author"))),
+		OG: b.entityOG(e, render.OGMeta{
 			Title:       title + " \u2014 " + b.cfg.Site.Name,
 			Description: description,
 			URL:         entityURL,
 			ImageURL:    imageURL,
 			Type:        "article",
 			SiteName:    b.cfg.Site.Name,
-		},
+		}),
+	}
+
+	if len(runbooks) > 0 {
+		ctx.StepsURL = stepsURL(e.Slug)
+	}
+	if b.cfg.Print.Enabled {
+		ctx.PrintURL = b.link(printPath(e.Slug))
 	}
 
 	html, err := engine.RenderEntity(ctx)
@@ -430,10 +1038,22 @@ func (b *Builder) renderEntityPage(
 	if err := os.WriteFile(outPath, []byte(html), 0644); err != nil {
 		return fmt.Errorf("writing %s: %w", outPath, err)
 	}
+	if b.cfg.Print.Enabled {
+		if err := b.writePrint(engine, ctx, outDir); err != nil {
+			return fmt.Errorf("writing print copy of %s: %w", e.Slug, err)
+		}
+	}
+	if len(runbooks) > 0 {
+		if err := writeSteps(e, runbooks, entityURL, outDir); err != nil {
+			return fmt.Errorf("writing steps for %s: %w", e.Slug, err)
+		}
+	}
 
-	addSitemapEntry("/"+e.Slug+".html",
-		b.cfg.Sitemap.Priorities["entity"],
-		b.cfg.Sitemap.ChangeFreqs["entity"])
+	if !ctx.NoIndex && canonicalURL == entityURL {
+		addSitemapEntry("entity", "/"+e.Slug+".html",
+			b.cfg.Sitemap.Priorities["entity"],
+			b.cfg.Sitemap.ChangeFreqs["entity"])
+	}
 
 	return nil
 }
@@ -445,7 +1065,7 @@ func (b *Builder) renderTaxonomyPages(
 	allTaxonomies []taxonomy.Taxonomy,
 	contributors map[string]interface{},
 	outDir string,
-	addSitemapEntry func(string, string, string),
+	addSitemapEntry func(string, string, string, string),
 	today string,
 ) error {
 	// Ensure taxonomy type directory exists
@@ -456,8 +1076,26 @@ func (b *Builder) renderTaxonomyPages(
 
 	perPage := b.cfg.Pagination.EntitiesPerPage
 
-	// Render hub pages for each entry
-	for _, entry := range tax.Entries {
+	// Render hub pages for each entry, and zero-state hubs for sparse
+	// entries when the taxonomy asks for them
+	entries := tax.Entries
+	switch tax.Config.Sparse {
+	case "render":
+		entries = append(entries[:len(entries):len(entries)], tax.Sparse...)
+	case "redirect":
+		if err := b.writeSparseRedirects(tax, taxDir); err != nil {
+			return err
+		}
+	}
+	for i, entry := range entries {
+		zeroState := i >= len(tax.Entries)
+		hubJSONURL := ""
+		if b.cfg.Output.JSONTwins {
+			if err := b.writeHubTwin(tax, entry, taxDir); err != nil {
+				return fmt.Errorf("writing hub twin %s/%s: %w", tax.Name, entry.Slug, err)
+			}
+			hubJSONURL = twinURL(taxonomy.HubPageURL(tax.Name, entry.Slug, 1))
+		}
 		totalPages := (len(entry.Entities) + perPage - 1) / perPage
 		if totalPages == 0 {
 			totalPages = 1
@@ -465,29 +1103,21 @@ func (b *Builder) renderTaxonomyPages(
 
 		// Hub share image (generate once per entry, reuse for all pages)
 		typeDist := countFieldDistribution(entry.Entities, "recipe_category", 8)
-		hubSVGFilename := fmt.Sprintf("%s-%s.svg", tax.Name, entry.Slug)
-		hubImageURL := shareImageURL(b.cfg.Site.BaseURL, hubSVGFilename)
-		if totalPages >= 1 {
-			hubSVG := render.GenerateHubShareSVG(b.cfg.Site.Name, entry.Name, tax.Label, len(entry.Entities), typeDist)
-			if err := writeShareSVG(outDir, hubSVGFilename, hubSVG); err != nil {
-				log.Printf("Warning: failed to write hub share SVG for %s/%s: %v", tax.Name, entry.Slug, err)
-			}
+		hubImageURL, err := b.shareImage(outDir, "hub", []interface{}{b.cfg.Site.Name, entry.Name, tax.Label, len(entry.Entities), typeDist}, func(s render.ShareImages) string {
+			return s.Hub(b.cfg.Site.Name, entry.Name, tax.Label, len(entry.Entities), typeDist)
+		})
+		if err != nil {
+			logging.Stage("render").Warn("Failed to write share SVG", "taxonomy", tax.Name, "slug", entry.Slug, "error", err)
 		}
 
 		// Hub chart data (same for all pages)
-		type hubChart struct {
-			EntryName        string             `json:"entryName"`
-			TotalEntities    int                `json:"totalEntities"`
-			TypeDistribution []render.NameCount `json:"typeDistribution"`
-		}
-		hubChartJSON, _ := json.Marshal(hubChart{
-			EntryName:        entry.Name,
-			TotalEntities:    len(entry.Entities),
-			TypeDistribution: typeDist,
-		})
+		hubChartJSON, err := b.chartData("hub", charts.Source{Entities: entry.Entities, Taxonomies: allTaxonomies, Taxonomy: &tax})
+		if err != nil {
+			return err
+		}
 
 		for page := 1; page <= totalPages; page++ {
-			pagination := taxonomy.ComputePagination(entry, page, perPage, tax.Name)
+			pagination := b.paginationLinks(taxonomy.ComputePagination(entry, page, perPage, tax.Name))
 
 			// Get entities for this page
 			pageEntities := entry.Entities
@@ -500,12 +1130,12 @@ func (b *Builder) renderTaxonomyPages(
 			}
 
 			// JSON-LD
-			pageURL := fmt.Sprintf("%s%s", b.cfg.Site.BaseURL, taxonomy.HubPageURL(tax.Name, entry.Slug, page))
+			pageURL := b.pageURL(taxonomy.HubPageURL(tax.Name, entry.Slug, page))
 			var items []schema.ItemListEntry
 			for _, e := range pageEntities {
 				items = append(items, schema.ItemListEntry{
 					Name: e.GetString("title"),
-					URL:  fmt.Sprintf("%s/%s.html", b.cfg.Site.BaseURL, e.Slug),
+					URL:  b.pageURL("/" + e.Slug + ".html"),
 				})
 			}
 			collectionSchema := schemaGen.GenerateCollectionPageSchema(
@@ -516,11 +1146,10 @@ func (b *Builder) renderTaxonomyPages(
 			// Breadcrumbs
 			breadcrumbs := []render.Breadcrumb{
 				{Name: "Home", URL: b.cfg.Site.BaseURL + "/"},
-				{Name: tax.Label, URL: fmt.Sprintf("%s/%s/", b.cfg.Site.BaseURL, tax.Name)},
+				{Name: tax.Label, URL: b.pageURL("/" + tax.Name + "/")},
 				{Name: entry.Name, URL: ""},
 			}
 			breadcrumbSchema := schemaGen.GenerateBreadcrumbSchema(toBreadcrumbItems(breadcrumbs))
-			jsonLD := schema.MarshalSchemas(collectionSchema, breadcrumbSchema)
 
 			// Contributor profile for author taxonomy
 			var contributorProfile map[string]interface{}
@@ -529,11 +1158,17 @@ func (b *Builder) renderTaxonomyPages(
 					contributorProfile, _ = profiles[entry.Slug].(map[string]interface{})
 				}
 			}
+			contributor := b.hubContributor(tax.Name, entry.Slug)
+			jsonLD := schema.MarshalSchemas(collectionSchema, breadcrumbSchema)
+			if contributor != nil {
+				jsonLD = schema.MarshalSchemas(collectionSchema, breadcrumbSchema, schemaGen.GeneratePersonSchema(contributor, b.contributorURL(contributor.Slug)))
+			}
 
 			hubDesc := fmt.Sprintf("Browse %d %s %s recipes on %s.", len(entry.Entities), entry.Name, tax.LabelSingular, b.cfg.Site.Name)
 
 			ctx := render.HubPageContext{
 				Site:               b.cfg.Site,
+				Languages:          b.languageLinks(taxonomy.HubPageURL(tax.Name, entry.Slug, 1)),
 				Taxonomy:           tax,
 				Entry:              entry,
 				Entities:           pageEntities,
@@ -543,6 +1178,7 @@ func (b *Builder) renderTaxonomyPages(
 				AllTaxonomies:      allTaxonomies,
 				Contributors:       contributors,
 				ContributorProfile: contributorProfile,
+				Contributor:        contributor,
 				OG: render.OGMeta{
 					Title:       entry.Name + " \u2014 " + tax.Label + " \u2014 " + b.cfg.Site.Name,
 					Description: hubDesc,
@@ -551,8 +1187,17 @@ func (b *Builder) renderTaxonomyPages(
 					Type:        "article",
 					SiteName:    b.cfg.Site.Name,
 				},
-				ChartData: template.HTML(hubChartJSON),
+				ChartData: hubChartJSON,
 				CTA:       b.cfg.Extra.CTA,
+				JSONURL:   hubJSONURL,
+				ZeroState: zeroState,
+				RecentlyAdded:   b.recentlyAdded(entry.Entities),
+				RecentlyUpdated: b.recentlyUpdated(entry.Entities),
+			}
+			if page == 1 {
+				ctx.Robots = b.robots("hub", taxonomy.HubPageURL(tax.Name, entry.Slug, page))
+			} else {
+				ctx.Robots = b.robots("hub_page_n", taxonomy.HubPageURL(tax.Name, entry.Slug, page))
 			}
 
 			html, err := engine.RenderHub(ctx)
@@ -573,11 +1218,14 @@ func (b *Builder) renderTaxonomyPages(
 			}
 
 			// Sitemap
+			if zeroState {
+				continue
+			}
 			priority := b.cfg.Sitemap.Priorities["hub_page_1"]
 			if page > 1 {
 				priority = b.cfg.Sitemap.Priorities["hub_page_n"]
 			}
-			addSitemapEntry(fmt.Sprintf("/%s/%s", tax.Name, filename), priority, b.cfg.Sitemap.ChangeFreqs["hub"])
+			addSitemapEntry("hub", fmt.Sprintf("/%s/%s", tax.Name, filename), priority, b.cfg.Sitemap.ChangeFreqs["hub"])
 		}
 	}
 
@@ -597,33 +1245,33 @@ func (b *Builder) renderTaxonomyPages(
 	for _, entry := range taxonomy.TopEntries(tax.Entries, 20) {
 		taxIndexEntries = append(taxIndexEntries, render.NameCount{Name: entry.Name, Count: len(entry.Entities)})
 	}
-	taxIndexSVGFilename := fmt.Sprintf("%s-index.svg", tax.Name)
-	taxIndexSVG := render.GenerateTaxIndexShareSVG(b.cfg.Site.Name, tax.Label, taxIndexEntries)
-	if err := writeShareSVG(outDir, taxIndexSVGFilename, taxIndexSVG); err != nil {
-		log.Printf("Warning: failed to write taxonomy index share SVG for %s: %v", tax.Name, err)
+	taxIndexImageURL, err := b.shareImage(outDir, "taxonomy_index", []interface{}{b.cfg.Site.Name, tax.Label, taxIndexEntries}, func(s render.ShareImages) string {
+		return s.TaxIndex(b.cfg.Site.Name, tax.Label, taxIndexEntries)
+	})
+	if err != nil {
+		logging.Stage("render").Warn("Failed to write share SVG", "taxonomy", tax.Name, "error", err)
 	}
-	taxIndexImageURL := shareImageURL(b.cfg.Site.BaseURL, taxIndexSVGFilename)
 
 	// Taxonomy index chart data
-	type taxChart struct {
-		TaxonomyName string             `json:"taxonomyName"`
-		Entries      []render.NameCount `json:"entries"`
+	taxChartJSON, err := b.chartData("taxonomy_index", charts.Source{Entities: entryEntities(tax.Entries), Taxonomies: allTaxonomies, Taxonomy: &tax, Entries: tax.Entries})
+	if err != nil {
+		return err
 	}
-	taxChartJSON, _ := json.Marshal(taxChart{
-		TaxonomyName: tax.Label,
-		Entries:      taxIndexEntries,
-	})
 
 	// Index page JSON-LD
 	var indexItems []schema.ItemListEntry
 	for _, entry := range tax.Entries {
 		indexItems = append(indexItems, schema.ItemListEntry{
 			Name: entry.Name,
-			URL:  fmt.Sprintf("%s/%s/%s.html", b.cfg.Site.BaseURL, tax.Name, entry.Slug),
+			URL:  b.pageURL(taxonomy.HubPageURL(tax.Name, entry.Slug, 1)),
 		})
 	}
-	indexURL := fmt.Sprintf("%s/%s/", b.cfg.Site.BaseURL, tax.Name)
-	indexSchema := schemaGen.GenerateItemListSchema(tax.Label, fmt.Sprintf("Browse all %s", tax.Label), indexItems, taxIndexImageURL)
+	indexURL := b.pageURL("/" + tax.Name + "/")
+	indexDesc, err := b.describe("index_description", tax.Config.IndexDescription, tax, "", tax.Entries)
+	if err != nil {
+		return err
+	}
+	indexSchema := schemaGen.GenerateItemListSchema(tax.Label, indexDesc, indexItems, taxIndexImageURL)
 	breadcrumbs := []render.Breadcrumb{
 		{Name: "Home", URL: b.cfg.Site.BaseURL + "/"},
 		{Name: tax.Label, URL: ""},
@@ -633,7 +1281,9 @@ func (b *Builder) renderTaxonomyPages(
 
 	ctx := render.TaxonomyIndexContext{
 		Site:          b.cfg.Site,
+		Languages:     b.languageLinks("/" + tax.Name + "/"),
 		Taxonomy:      tax,
+		Description:   indexDesc,
 		Entries:       tax.Entries,
 		TopEntries:    topEntries,
 		LetterGroups:  letterGroups,
@@ -644,14 +1294,15 @@ func (b *Builder) renderTaxonomyPages(
 		AllTaxonomies: allTaxonomies,
 		OG: render.OGMeta{
 			Title:       tax.Label + " \u2014 " + b.cfg.Site.Name,
-			Description: tax.Config.IndexDescription,
+			Description: indexDesc,
 			URL:         indexURL,
 			ImageURL:    taxIndexImageURL,
 			Type:        "article",
 			SiteName:    b.cfg.Site.Name,
 		},
-		ChartData: template.HTML(taxChartJSON),
+		ChartData: taxChartJSON,
 		CTA:       b.cfg.Extra.CTA,
+		Robots:    b.robots("taxonomy_index", "/"+tax.Name+"/"),
 	}
 
 	html, err := engine.RenderTaxonomyIndex(ctx)
@@ -662,7 +1313,7 @@ func (b *Builder) renderTaxonomyPages(
 	if err := os.WriteFile(filepath.Join(taxDir, "index.html"), []byte(html), 0644); err != nil {
 		return fmt.Errorf("writing taxonomy index: %w", err)
 	}
-	addSitemapEntry(fmt.Sprintf("/%s/", tax.Name), b.cfg.Sitemap.Priorities["taxonomy_index"], b.cfg.Sitemap.ChangeFreqs["taxonomy_index"])
+	addSitemapEntry("taxonomy_index", fmt.Sprintf("/%s/", tax.Name), b.cfg.Sitemap.Priorities["taxonomy_index"], b.cfg.Sitemap.ChangeFreqs["taxonomy_index"])
 
 	// Render letter pages if threshold met
 	if hasLetters {
@@ -672,44 +1323,36 @@ func (b *Builder) renderTaxonomyPages(
 			if lg.Letter == "#" {
 				letterSlug = "num"
 			}
-			letterSVGFilename := fmt.Sprintf("%s-letter-%s.svg", tax.Name, letterSlug)
-			letterSVG := render.GenerateLetterShareSVG(b.cfg.Site.Name, tax.Label, lg.Letter, len(lg.Entries))
-			if err := writeShareSVG(outDir, letterSVGFilename, letterSVG); err != nil {
-				log.Printf("Warning: failed to write letter share SVG for %s/%s: %v", tax.Name, lg.Letter, err)
+			letterImageURL, err := b.shareImage(outDir, "letter", []interface{}{b.cfg.Site.Name, tax.Label, lg.Letter, len(lg.Entries)}, func(s render.ShareImages) string {
+				return s.Letter(b.cfg.Site.Name, tax.Label, lg.Letter, len(lg.Entries))
+			})
+			if err != nil {
+				logging.Stage("render").Warn("Failed to write share SVG", "taxonomy", tax.Name, "letter", lg.Letter, "error", err)
 			}
-			letterImageURL := shareImageURL(b.cfg.Site.BaseURL, letterSVGFilename)
 
 			// Letter chart data
-			var letterEntries []render.NameCount
-			limit := 15
-			if len(lg.Entries) < limit {
-				limit = len(lg.Entries)
-			}
-			for _, e := range lg.Entries[:limit] {
-				letterEntries = append(letterEntries, render.NameCount{Name: e.Name, Count: len(e.Entities)})
+			letterChartJSON, err := b.chartData("letter_page", charts.Source{Entities: entryEntities(lg.Entries), Taxonomies: allTaxonomies, Taxonomy: &tax, Entries: lg.Entries})
+			if err != nil {
+				return err
 			}
-			type letterChart struct {
-				Letter       string             `json:"letter"`
-				TaxonomyName string             `json:"taxonomyName"`
-				Entries      []render.NameCount `json:"entries"`
+
+			letterDesc, err := b.describe("letter_description", tax.Config.LetterDescription, tax, lg.Letter, lg.Entries)
+			if err != nil {
+				return err
 			}
-			letterChartJSON, _ := json.Marshal(letterChart{
-				Letter:       lg.Letter,
-				TaxonomyName: tax.Label,
-				Entries:      letterEntries,
-			})
 
 			letterFile := fmt.Sprintf("letter-%s.html", letterSlug)
-			letterPageURL := fmt.Sprintf("%s/%s/%s", b.cfg.Site.BaseURL, tax.Name, letterFile)
+			letterPageURL := b.pageURL("/" + tax.Name + "/" + letterFile)
 
 			letterBreadcrumbs := []render.Breadcrumb{
 				{Name: "Home", URL: b.cfg.Site.BaseURL + "/"},
-				{Name: tax.Label, URL: fmt.Sprintf("%s/%s/", b.cfg.Site.BaseURL, tax.Name)},
+				{Name: tax.Label, URL: b.pageURL("/" + tax.Name + "/")},
 				{Name: fmt.Sprintf("Letter %s", lg.Letter), URL: ""},
 			}
 
 			letterCtx := render.LetterPageContext{
 				Site:          b.cfg.Site,
+				Languages:     b.languageLinks(taxonomy.LetterPageURL(tax.Name, lg.Letter)),
 				Taxonomy:      tax,
 				Letter:        lg.Letter,
 				Entries:       lg.Entries,
@@ -718,14 +1361,21 @@ func (b *Builder) renderTaxonomyPages(
 				AllTaxonomies: allTaxonomies,
 				OG: render.OGMeta{
 					Title:       fmt.Sprintf("%s \u2014 Letter %s \u2014 %s", tax.Label, lg.Letter, b.cfg.Site.Name),
-					Description: fmt.Sprintf("Browse %s starting with %s on %s.", tax.Label, lg.Letter, b.cfg.Site.Name),
+					Description: letterDesc,
 					URL:         letterPageURL,
 					ImageURL:    letterImageURL,
 					Type:        "article",
 					SiteName:    b.cfg.Site.Name,
 				},
-				ChartData: template.HTML(letterChartJSON),
+				ChartData: letterChartJSON,
 				CTA:       b.cfg.Extra.CTA,
+				Robots:    b.robots("letter_page", taxonomy.LetterPageURL(tax.Name, lg.Letter)),
+			}
+			if b.cfg.Output.JSONTwins {
+				if err := b.writeLetterTwin(tax, lg, taxDir); err != nil {
+					return fmt.Errorf("writing letter twin %s/%s: %w", tax.Name, lg.Letter, err)
+				}
+				letterCtx.JSONURL = twinURL(taxonomy.LetterPageURL(tax.Name, lg.Letter))
 			}
 
 			letterHTML, err := engine.RenderLetter(letterCtx)
@@ -736,7 +1386,7 @@ func (b *Builder) renderTaxonomyPages(
 			if err := os.WriteFile(filepath.Join(taxDir, letterFile), []byte(letterHTML), 0644); err != nil {
 				return fmt.Errorf("writing letter page: %w", err)
 			}
-			addSitemapEntry(fmt.Sprintf("/%s/%s", tax.Name, letterFile),
+			addSitemapEntry("letter_page", fmt.Sprintf("/%s/%s", tax.Name, letterFile),
 				b.cfg.Sitemap.Priorities["letter_page"],
 				b.cfg.Sitemap.ChangeFreqs["letter_page"])
 		}
@@ -751,7 +1401,7 @@ func (b *Builder) renderAllEntitiesPages(
 	entities []*entity.Entity,
 	allTaxonomies []taxonomy.Taxonomy,
 	outDir string,
-	addSitemapEntry func(string, string, string),
+	addSitemapEntry func(string, string, string, string),
 ) error {
 	// Ensure all/ directory exists
 	allDir := filepath.Join(outDir, "all")
@@ -763,73 +1413,39 @@ func (b *Builder) renderAllEntitiesPages(
 	typeDist := countFieldDistribution(entities, "recipe_category", 10)
 
 	// Share image (once)
-	allSVG := render.GenerateAllEntitiesShareSVG(b.cfg.Site.Name, len(entities), typeDist)
-	if err := writeShareSVG(outDir, "all-entities.svg", allSVG); err != nil {
-		log.Printf("Warning: failed to write all-entities share SVG: %v", err)
+	imageURL, err := b.shareImage(outDir, "all_entities", []interface{}{b.cfg.Site.Name, len(entities), typeDist}, func(s render.ShareImages) string {
+		return s.AllEntities(b.cfg.Site.Name, len(entities), typeDist)
+	})
+	if err != nil {
+		logging.Stage("render").Warn("Failed to write share SVG", "page", "all-entities", "error", err)
 	}
-	imageURL := shareImageURL(b.cfg.Site.BaseURL, "all-entities.svg")
 
 	// Chart data
-	type allChart struct {
-		TotalEntities    int                `json:"totalEntities"`
-		TypeDistribution []render.NameCount `json:"typeDistribution"`
-	}
-	chartJSON, _ := json.Marshal(allChart{
-		TotalEntities:    len(entities),
-		TypeDistribution: typeDist,
-	})
-
-	perPage := b.cfg.Pagination.EntitiesPerPage
-	totalPages := (len(entities) + perPage - 1) / perPage
-	if totalPages == 0 {
-		totalPages = 1
+	chartJSON, err := b.chartData("all_entities", charts.Source{Entities: entities, Taxonomies: allTaxonomies})
+	if err != nil {
+		return err
 	}
 
-	for page := 1; page <= totalPages; page++ {
-		start := (page - 1) * perPage
-		end := start + perPage
-		if end > len(entities) {
-			end = len(entities)
-		}
-		pageEntities := entities[start:end]
-
-		// Build pagination info
-		pagination := taxonomy.PaginationInfo{
-			CurrentPage: page,
-			TotalPages:  totalPages,
-			TotalItems:  len(entities),
-			StartIndex:  start,
-			EndIndex:    end,
-		}
-		for p := 1; p <= totalPages; p++ {
-			url := "/all/index.html"
-			if p > 1 {
-				url = fmt.Sprintf("/all/page-%d.html", p)
-			}
-			pagination.PageURLs = append(pagination.PageURLs, taxonomy.PageURL{Number: p, URL: url})
-		}
-		if page > 1 {
-			if page == 2 {
-				pagination.PrevURL = "/all/index.html"
-			} else {
-				pagination.PrevURL = fmt.Sprintf("/all/page-%d.html", page-1)
-			}
-		}
-		if page < totalPages {
-			pagination.NextURL = fmt.Sprintf("/all/page-%d.html", page+1)
+	// Listing order and A-Z navigation
+	entities = sortListing(entities, b.cfg.AllEntities, b.cfg.Data.Ordering)
+	var letterGroups []taxonomy.LetterGroup
+	var letters []string
+	if b.cfg.AllEntities.Letters {
+		letterGroups = taxonomy.GroupByLetter(titleEntries(entities))
+		for _, g := range letterGroups {
+			letters = append(letters, g.Letter)
 		}
+	}
 
-		pageURL := fmt.Sprintf("%s/all/index.html", b.cfg.Site.BaseURL)
-		if page > 1 {
-			pageURL = fmt.Sprintf("%s/all/page-%d.html", b.cfg.Site.BaseURL, page)
-		}
+	writePage := func(filename, kind string, pageEntities []*entity.Entity, pagination taxonomy.PaginationInfo, letter string, chartData template.HTML) error {
+		pageURL := b.pageURL("/all/" + filename)
 
 		// JSON-LD
 		var items []schema.ItemListEntry
 		for _, e := range pageEntities {
 			items = append(items, schema.ItemListEntry{
 				Name: e.GetString("title"),
-				URL:  fmt.Sprintf("%s/%s.html", b.cfg.Site.BaseURL, e.Slug),
+				URL:  b.pageURL("/" + e.Slug + ".html"),
 			})
 		}
 		collectionSchema := schemaGen.GenerateCollectionPageSchema(
@@ -841,19 +1457,18 @@ func (b *Builder) renderAllEntitiesPages(
 			{Name: "Home", URL: b.cfg.Site.BaseURL + "/"},
 			{Name: "All Recipes", URL: ""},
 		}
+		if letter != "" {
+			breadcrumbs[1].URL = b.pageURL("/all/index.html")
+			breadcrumbs = append(breadcrumbs, render.Breadcrumb{Name: letter, URL: ""})
+		}
 		breadcrumbSchema := schemaGen.GenerateBreadcrumbSchema(toBreadcrumbItems(breadcrumbs))
 		jsonLD := schema.MarshalSchemas(collectionSchema, breadcrumbSchema)
 
-		// Only include chart data on page 1
-		var pageChartData template.HTML
-		if page == 1 {
-			pageChartData = template.HTML(chartJSON)
-		}
-
 		ctx := render.AllEntitiesPageContext{
 			Site:          b.cfg.Site,
+			Languages:     b.languageLinks("/all/index.html"),
 			Entities:      pageEntities,
-			Pagination:    pagination,
+			Pagination:    b.paginationLinks(pagination),
 			JsonLD:        toTemplateHTML(jsonLD),
 			Breadcrumbs:   breadcrumbs,
 			AllTaxonomies: allTaxonomies,
@@ -867,25 +1482,100 @@ func (b *Builder) renderAllEntitiesPages(
 				Type:        "article",
 				SiteName:    b.cfg.Site.Name,
 			},
-			ChartData: pageChartData,
+			ChartData: chartData,
 			CTA:       b.cfg.Extra.CTA,
+			Letters:   letters,
+			Letter:    letter,
+			Robots:    b.robots(kind, "/all/"+filename),
 		}
 
 		html, err := engine.RenderAllEntities(ctx)
 		if err != nil {
-			return fmt.Errorf("rendering all-entities page %d: %w", page, err)
+			return fmt.Errorf("rendering all-entities page %s: %w", filename, err)
+		}
+		if err := os.WriteFile(filepath.Join(allDir, filename), []byte(html), 0644); err != nil {
+			return fmt.Errorf("writing all-entities page: %w", err)
+		}
+
+		addSitemapEntry("all_entities", fmt.Sprintf("/all/%s", filename), "0.5", "weekly")
+		return nil
+	}
+
+	perPage := b.cfg.Pagination.EntitiesPerPage
+	totalPages := (len(entities) + perPage - 1) / perPage
+	if totalPages == 0 {
+		totalPages = 1
+	}
+
+	for page := 1; page <= totalPages; page++ {
+		start := (page - 1) * perPage
+		end := start + perPage
+		if end > len(entities) {
+			end = len(entities)
+		}
+
+		// Build pagination info
+		pagination := taxonomy.PaginationInfo{
+			CurrentPage: page,
+			TotalPages:  totalPages,
+			TotalItems:  len(entities),
+			StartIndex:  start,
+			EndIndex:    end,
+		}
+		for p := 1; p <= totalPages; p++ {
+			url := "/all/index.html"
+			if p > 1 {
+				url = fmt.Sprintf("/all/page-%d.html", p)
+			}
+			pagination.PageURLs = append(pagination.PageURLs, taxonomy.PageURL{Number: p, URL: url})
+		}
+		if page > 1 {
+			if page == 2 {
+				pagination.PrevURL = "/all/index.html"
+			} else {
+				pagination.PrevURL = fmt.Sprintf("/all/page-%d.html", page-1)
+			}
+		}
+		if page < totalPages {
+			pagination.NextURL = fmt.Sprintf("/all/page-%d.html", page+1)
+		}
+
+		// Only include chart data on page 1
+		var pageChartData template.HTML
+		if page == 1 {
+			pageChartData = chartJSON
 		}
 
 		filename := "index.html"
 		if page > 1 {
 			filename = fmt.Sprintf("page-%d.html", page)
 		}
-
-		if err := os.WriteFile(filepath.Join(allDir, filename), []byte(html), 0644); err != nil {
-			return fmt.Errorf("writing all-entities page: %w", err)
+		kind := "all_entities"
+		if page > 1 {
+			kind = "all_entities_page_n"
+		}
+		if err := writePage(filename, kind, entities[start:end], pagination, "", pageChartData); err != nil {
+			return err
 		}
+	}
 
-		addSitemapEntry(fmt.Sprintf("/all/%s", filename), "0.5", "weekly")
+	// One unpaged page per letter
+	for _, g := range letterGroups {
+		url := taxonomy.LetterPageURL("all", g.Letter)
+		var letterEntities []*entity.Entity
+		for _, entry := range g.Entries {
+			letterEntities = append(letterEntities, entry.Entities...)
+		}
+		pagination := taxonomy.PaginationInfo{
+			CurrentPage: 1,
+			TotalPages:  1,
+			TotalItems:  len(letterEntities),
+			EndIndex:    len(letterEntities),
+			PageURLs:    []taxonomy.PageURL{{Number: 1, URL: url}},
+		}
+		if err := writePage(path.Base(url), "letter_page", letterEntities, pagination, g.Letter, ""); err != nil {
+			return err
+		}
 	}
 
 	return nil
@@ -898,6 +1588,7 @@ func (b *Builder) renderHomepage(
 	taxonomies []taxonomy.Taxonomy,
 	favorites []*entity.Entity,
 	contributors map[string]interface{},
+	relData relation.Data,
 	outDir string,
 ) error {
 	// Share image
@@ -905,35 +1596,24 @@ func (b *Builder) renderHomepage(
 	for _, tax := range taxonomies {
 		taxStats = append(taxStats, render.NameCount{Name: tax.Label, Count: len(tax.Entries)})
 	}
-	svgContent := render.GenerateHomepageShareSVG(b.cfg.Site.Name, b.cfg.Site.Description, taxStats, len(entities))
-	if err := writeShareSVG(outDir, "homepage.svg", svgContent); err != nil {
-		log.Printf("Warning: failed to write homepage share SVG: %v", err)
+	imageURL, err := b.shareImage(outDir, "homepage", []interface{}{b.cfg.Site.Name, b.cfg.Site.Description, taxStats, len(entities)}, func(s render.ShareImages) string {
+		return s.Homepage(b.cfg.Site.Name, b.cfg.Site.Description, taxStats, len(entities))
+	})
+	if err != nil {
+		logging.Stage("render").Warn("Failed to write share SVG", "page", "homepage", "error", err)
 	}
-	imageURL := shareImageURL(b.cfg.Site.BaseURL, "homepage.svg")
 
 	// Chart data: treemap of taxonomies -> entries
-	type chartEntry struct {
-		Name  string `json:"name"`
-		Count int    `json:"count"`
-	}
-	type chartTax struct {
-		Label      string       `json:"label"`
-		TopEntries []chartEntry `json:"topEntries"`
-	}
-	type homepageChart struct {
-		Taxonomies    []chartTax `json:"taxonomies"`
-		TotalEntities int        `json:"totalEntities"`
+	chartJSON, err := b.chartData("homepage", charts.Source{Entities: entities, Taxonomies: taxonomies})
+	if err != nil {
+		return err
 	}
-	var chartTaxonomies []chartTax
-	for _, tax := range taxonomies {
-		top := taxonomy.TopEntries(tax.Entries, 10)
-		var entries []chartEntry
-		for _, e := range top {
-			entries = append(entries, chartEntry{Name: e.Name, Count: len(e.Entities)})
-		}
-		chartTaxonomies = append(chartTaxonomies, chartTax{Label: tax.Label, TopEntries: entries})
+
+	// Architecture overview: the best-connected entities and their relations
+	var archJSON []byte
+	if len(relData.Nodes) > 1 {
+		archJSON, _ = json.Marshal(relData.Top(archOverviewNodes))
 	}
-	chartJSON, _ := json.Marshal(homepageChart{Taxonomies: chartTaxonomies, TotalEntities: len(entities)})
 
 	// JSON-LD
 	websiteSchema := schemaGen.GenerateWebSiteSchema(imageURL)
@@ -942,7 +1622,7 @@ func (b *Builder) renderHomepage(
 	for _, e := range entities {
 		items = append(items, schema.ItemListEntry{
 			Name: e.GetString("title"),
-			URL:  fmt.Sprintf("%s/%s.html", b.cfg.Site.BaseURL, e.Slug),
+			URL:  b.pageURL("/" + e.Slug + ".html"),
 		})
 	}
 	itemListSchema := schemaGen.GenerateItemListSchema(
@@ -956,9 +1636,15 @@ func (b *Builder) renderHomepage(
 
 	ctx := render.HomepageContext{
 		Site:         b.cfg.Site,
+		Languages:    b.languageLinks("/"),
 		Entities:     entities,
 		Taxonomies:   taxonomies,
 		Favorites:    favorites,
+		Featured:     featured.Pick(entities, b.featuredSeed, b.cfg.Featured.Limit),
+		Collections:  collection.Featured(b.collections),
+		Sections:     b.homepageSections(entities, taxonomies),
+		RecentlyAdded:   b.recentlyAdded(entities),
+		RecentlyUpdated: b.recentlyUpdated(entities),
 		JsonLD:       toTemplateHTML(jsonLD),
 		EntityCount:  len(entities),
 		Contributors: contributors,
@@ -970,7 +1656,8 @@ func (b *Builder) renderHomepage(
 			Type:        "website",
 			SiteName:    b.cfg.Site.Name,
 		},
-		ChartData: template.HTML(chartJSON),
+		ChartData: chartJSON,
+		ArchData:  template.HTML(archJSON),
 		CTA:       b.cfg.Extra.CTA,
 	}
 
@@ -989,13 +1676,13 @@ func (b *Builder) loadFavorites(slugMap map[string]*entity.Entity) []*entity.Ent
 
 	data, err := os.ReadFile(b.cfg.Extra.Favorites)
 	if err != nil {
-		log.Printf("Warning: failed to load favorites: %v", err)
+		logging.Stage("load").Warn("Failed to load favorites", "error", err)
 		return nil
 	}
 
 	var slugs []string
 	if err := json.Unmarshal(data, &slugs); err != nil {
-		log.Printf("Warning: failed to parse favorites: %v", err)
+		logging.Stage("load").Warn("Failed to parse favorites", "error", err)
 		return nil
 	}
 
@@ -1008,23 +1695,30 @@ func (b *Builder) loadFavorites(slugMap map[string]*entity.Entity) []*entity.Ent
 	return result
 }
 
-func (b *Builder) loadContributors() map[string]interface{} {
+func (b *Builder) loadContributors() *contributors.Set {
 	if b.cfg.Extra.Contributors == "" {
 		return nil
 	}
 
-	data, err := os.ReadFile(b.cfg.Extra.Contributors)
+	set, err := contributors.Load(b.cfg.Extra.Contributors)
 	if err != nil {
-		log.Printf("Warning: failed to load contributors: %v", err)
+		logging.Stage("load").Warn("Failed to load contributors", "error", err)
 		return nil
 	}
+	return set
+}
 
-	var result map[string]interface{}
-	if err := json.Unmarshal(data, &result); err != nil {
-		log.Printf("Warning: failed to parse contributors: %v", err)
+func (b *Builder) loadEngagement() map[string]*engagement.Counts {
+	if b.cfg.Extra.Engagement == "" {
 		return nil
 	}
-	return result
+
+	counts, err := engagement.Load(b.cfg.Extra.Engagement)
+	if err != nil {
+		logging.Stage("load").Warn("Failed to load engagement counts", "error", err)
+		return nil
+	}
+	return counts
 }
 
 func toBreadcrumbItems(breadcrumbs []render.Breadcrumb) []schema.BreadcrumbItem {
@@ -1072,13 +1766,24 @@ func countFieldDistribution(entities []*entity.Entity, field string, limit int)
 	return result
 }
 
-// writeShareSVG writes an SVG share image to the images/share/ directory.
-func writeShareSVG(outDir, filename, svg string) error {
+// writeShareSVG minifies an SVG share image and writes it to the
+// images/share/ directory under a name derived from its content, returning
+// its URL. Identical images, such as the cards of hub pages with the same
+// counts, share one file, and a changed image gets a new URL that caches
+// and social networks will fetch afresh.
+func (b *Builder) writeShareSVG(outDir, svg string) (string, error) {
+	svg = render.MinifySVG(svg)
+	sum := sha256.Sum256([]byte(svg))
+	filename := hex.EncodeToString(sum[:8]) + ".svg"
+	url := shareImageURL(b.cfg.Site.BaseURL, filename)
+	if _, done := b.shareSVGs.LoadOrStore(filename, true); done {
+		return url, nil
+	}
 	dir := filepath.Join(outDir, "images", "share")
 	if err := os.MkdirAll(dir, 0755); err != nil {
-		return err
+		return url, err
 	}
-	return os.WriteFile(filepath.Join(dir, filename), []byte(svg), 0644)
+	return url, os.WriteFile(filepath.Join(dir, filename), []byte(svg), 0644)
 }
 
 // shareImageURL returns the full URL for a share image.
@@ -1086,6 +1791,26 @@ func shareImageURL(baseURL, filename string) string {
 	return fmt.Sprintf("%s/images/share/%s", baseURL, filename)
 }
 
+// absoluteURL resolves a root-relative URL against baseURL and leaves
+// absolute URLs unchanged.
+func absoluteURL(baseURL, u string) string {
+	if strings.HasPrefix(u, "/") && !strings.HasPrefix(u, "//") {
+		return baseURL + u
+	}
+	return u
+}
+
+// withoutExternalCanonical drops entities whose canonical_url points elsewhere.
+func withoutExternalCanonical(entities []*entity.Entity) []*entity.Entity {
+	result := make([]*entity.Entity, 0, len(entities))
+	for _, e := range entities {
+		if e.CanonicalOverride() == "" {
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
 type searchEntry struct {
 	T string `json:"t"`           // title
 	D string `json:"d,omitempty"` // description (truncated)
@@ -1093,6 +1818,20 @@ type searchEntry struct {
 	N string `json:"n,omitempty"` // node_type
 	L string `json:"l,omitempty"` // language
 	M string `json:"m,omitempty"` // domain
+	K []string `json:"k,omitempty"` // API operation IDs
+}
+
+// searchField returns a field for the search index, shortened to its
+// search.max_lengths entry, or for descriptions to search.snippet_length.
+func (b *Builder) searchField(e *entity.Entity, name string) string {
+	limit, ok := b.cfg.Search.MaxLengths[name]
+	if !ok && name == "description" {
+		limit = b.cfg.Search.SnippetLength
+	}
+	if limit == 0 {
+		return e.GetString(name)
+	}
+	return text.Truncate(e.GetString(name), limit)
 }
 
 func (b *Builder) generateSearchIndex(entities []*entity.Entity, outDir string) error {
@@ -1102,17 +1841,14 @@ func (b *Builder) generateSearchIndex(entities []*entity.Entity, outDir string)
 
 	entries := make([]searchEntry, 0, len(entities))
 	for _, e := range entities {
-		desc := e.GetString("description")
-		if len(desc) > 120 {
-			desc = desc[:120]
-		}
 		entries = append(entries, searchEntry{
-			T: e.GetString("title"),
-			D: desc,
+			T: b.searchField(e, "title"),
+			D: b.searchField(e, "description"),
 			S: e.Slug,
-			N: e.GetString("node_type"),
-			L: e.GetString("language"),
-			M: e.GetString("domain"),
+			N: b.searchField(e, "node_type"),
+			L: b.searchField(e, "language"),
+			M: b.searchField(e, "domain"),
+			K: b.apis[e.Slug].OperationIDs(),
 		})
 	}
 
@@ -1125,7 +1861,7 @@ func (b *Builder) generateSearchIndex(entities []*entity.Entity, outDir string)
 	if err := os.WriteFile(outPath, data, 0644); err != nil {
 		return err
 	}
-	log.Printf("  Generated search index (%d entries, %dKB)", len(entries), len(data)/1024)
+	logging.Stage("render").Debug("Generated search index", "entries", len(entries), "kb", len(data)/1024)
 	return nil
 }
 
@@ -0,0 +1,87 @@
+package build
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/supermodeltools/arch-docs/internal/pssg/c4"
+	"github.com/supermodeltools/arch-docs/internal/pssg/render"
+	"github.com/supermodeltools/arch-docs/internal/pssg/schema"
+	"github.com/supermodeltools/arch-docs/internal/pssg/taxonomy"
+)
+
+// renderC4Pages writes the system context page and a page for every C4
+// element with children.
+func (b *Builder) renderC4Pages(
+	engine *render.Engine,
+	schemaGen *schema.Generator,
+	model *c4.Model,
+	allTaxonomies []taxonomy.Taxonomy,
+	outDir string,
+	addSitemapEntry func(string, string, string, string),
+) error {
+	home := "/" + b.cfg.C4.Dir + "/"
+	write := func(v *c4.View, page, title, description string, trail []render.Breadcrumb) error {
+		breadcrumbs := append([]render.Breadcrumb{{Name: "Home", URL: b.cfg.Site.BaseURL + "/"}}, trail...)
+		ctx := render.C4PageContext{
+			Site:          b.cfg.Site,
+			Languages:     b.languageLinks(page),
+			View:          v,
+			Diagram:       v.Mermaid(),
+			JsonLD:        toTemplateHTML(schema.MarshalSchemas(schemaGen.GenerateBreadcrumbSchema(toBreadcrumbItems(breadcrumbs)))),
+			Breadcrumbs:   breadcrumbs,
+			AllTaxonomies: allTaxonomies,
+			OG: render.OGMeta{
+				Title:       title + " — " + b.cfg.Site.Name,
+				Description: description,
+				URL:         b.pageURL(page),
+				Type:        "article",
+				SiteName:    b.cfg.Site.Name,
+			},
+			CTA: b.cfg.Extra.CTA,
+		}
+		html, err := engine.RenderC4(ctx)
+		if err != nil {
+			return err
+		}
+		outPath := filepath.Join(outDir, filepath.FromSlash(strings.TrimPrefix(page, "/")))
+		if strings.HasSuffix(page, "/") {
+			outPath = filepath.Join(outPath, "index.html")
+		}
+		if err := os.MkdirAll(filepath.Dir(outPath), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(outPath, []byte(html), 0644); err != nil {
+			return fmt.Errorf("writing %s: %w", outPath, err)
+		}
+		addSitemapEntry("c4", page, b.cfg.Sitemap.Priorities["taxonomy_index"], b.cfg.Sitemap.ChangeFreqs["taxonomy_index"])
+		return nil
+	}
+
+	description := fmt.Sprintf("The systems of %s and how they relate.", b.cfg.Site.Name)
+	if err := write(model.Context, home, "System Context", description, []render.Breadcrumb{{Name: "System Context", URL: ""}}); err != nil {
+		return err
+	}
+	for _, v := range model.Views {
+		trail := []render.Breadcrumb{{Name: "System Context", URL: b.pageURL(home)}}
+		for _, a := range v.Scope.Ancestors() {
+			trail = append(trail, render.Breadcrumb{Name: a.Title(), URL: b.pageURL(a.URL)})
+		}
+		trail = append(trail, render.Breadcrumb{Name: v.Scope.Title(), URL: ""})
+		description := fmt.Sprintf("The %ss inside %s and how they relate.", v.Level, v.Scope.Title())
+		if err := write(v, v.Scope.URL, v.Scope.Title(), description, trail); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// c4Element is the entity's place in the C4 model, or nil.
+func (b *Builder) c4Element(slug string) *c4.Element {
+	if b.c4 == nil {
+		return nil
+	}
+	return b.c4.Element(slug)
+}
@@ -0,0 +1,55 @@
+package build
+
+import (
+	"encoding/json"
+	"html/template"
+	"strings"
+
+	"github.com/supermodeltools/arch-docs/internal/pssg/charts"
+	"github.com/supermodeltools/arch-docs/internal/pssg/entity"
+	"github.com/supermodeltools/arch-docs/internal/pssg/taxonomy"
+)
+
+// chartData builds the charts charts.pages lists for a kind of page, as
+// the JSON its template embeds.
+func (b *Builder) chartData(page string, src charts.Source) (template.HTML, error) {
+	src.Config = b.cfg.Charts
+	set, err := charts.Build(page, src)
+	if err != nil {
+		return "", err
+	}
+	for i := range set.Charts {
+		withBasePath(set.Charts[i].Items, b.cfg.Site.BasePath)
+	}
+	data, err := json.Marshal(set)
+	if err != nil {
+		return "", err
+	}
+	return template.HTML(data), nil
+}
+
+// withBasePath prefixes the root-relative URLs of chart items, which
+// scripts link to as they are, with site.base_path.
+func withBasePath(items []charts.Item, base string) {
+	for i := range items {
+		if strings.HasPrefix(items[i].URL, "/") {
+			items[i].URL = base + items[i].URL
+		}
+		withBasePath(items[i].Children, base)
+	}
+}
+
+// entryEntities lists the entities of taxonomy entries, each once.
+func entryEntities(entries []taxonomy.Entry) []*entity.Entity {
+	seen := make(map[*entity.Entity]bool)
+	var entities []*entity.Entity
+	for _, entry := range entries {
+		for _, e := range entry.Entities {
+			if !seen[e] {
+				seen[e] = true
+				entities = append(entities, e)
+			}
+		}
+	}
+	return entities
+}
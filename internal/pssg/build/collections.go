@@ -0,0 +1,99 @@
+package build
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/supermodeltools/arch-docs/internal/pssg/collection"
+	"github.com/supermodeltools/arch-docs/internal/pssg/entity"
+	"github.com/supermodeltools/arch-docs/internal/pssg/logging"
+	"github.com/supermodeltools/arch-docs/internal/pssg/render"
+	"github.com/supermodeltools/arch-docs/internal/pssg/schema"
+	"github.com/supermodeltools/arch-docs/internal/pssg/taxonomy"
+)
+
+// loadCollections reads extra.collections, warning about slugs no entity
+// has, as they may name a page that was renamed or removed.
+func (b *Builder) loadCollections(slugMap map[string]*entity.Entity) []*collection.Collection {
+	if b.cfg.Extra.Collections == "" {
+		return nil
+	}
+
+	list, err := collection.Load(b.cfg.Extra.Collections, slugMap)
+	if err != nil {
+		logging.Stage("load").Warn("Failed to load collections", "error", err)
+		return nil
+	}
+	for _, c := range list {
+		c.URL = b.pageURL("/" + b.cfg.Collections.Dir + "/" + c.Slug + "/")
+		if len(c.Missing) > 0 {
+			logging.Stage("load").Warn("Collection lists unknown entities", "collection", c.Slug, "slugs", c.Missing)
+		}
+	}
+	return list
+}
+
+// renderCollections writes a page for each curated collection, with the
+// collection as a CollectionPage in its JSON-LD.
+func (b *Builder) renderCollections(
+	engine *render.Engine,
+	schemaGen *schema.Generator,
+	allTaxonomies []taxonomy.Taxonomy,
+	outDir string,
+	addSitemapEntry func(string, string, string, string),
+) error {
+	for _, c := range b.collections {
+		dir := filepath.Join(outDir, b.cfg.Collections.Dir, c.Slug)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("creating collection dir: %w", err)
+		}
+
+		page := "/" + b.cfg.Collections.Dir + "/" + c.Slug + "/"
+		description := c.Description
+		if description == "" {
+			description = fmt.Sprintf("%s: %d pages from %s.", c.Title, len(c.Entities), b.cfg.Site.Name)
+		}
+		breadcrumbs := []render.Breadcrumb{
+			{Name: "Home", URL: b.cfg.Site.BaseURL + "/"},
+			{Name: c.Title, URL: ""},
+		}
+		items := make([]schema.ItemListEntry, len(c.Entities))
+		for i, e := range c.Entities {
+			items[i] = schema.ItemListEntry{Name: e.GetString("title"), URL: b.pageURL("/" + e.Slug + ".html")}
+		}
+		jsonLD := schema.MarshalSchemas(
+			schemaGen.GenerateCollectionPageSchema(c.Title, description, c.URL, items, ""),
+			schemaGen.GenerateBreadcrumbSchema(toBreadcrumbItems(breadcrumbs)),
+		)
+
+		ctx := render.CollectionPageContext{
+			Site:          b.cfg.Site,
+			Languages:     b.languageLinks(page),
+			Collection:    c,
+			JsonLD:        toTemplateHTML(jsonLD),
+			Breadcrumbs:   breadcrumbs,
+			AllTaxonomies: allTaxonomies,
+			OG: render.OGMeta{
+				Title:       c.Title + " — " + b.cfg.Site.Name,
+				Description: description,
+				URL:         c.URL,
+				Type:        "website",
+				SiteName:    b.cfg.Site.Name,
+			},
+			CTA: b.cfg.Extra.CTA,
+		}
+		if b.cfg.RSS.Enabled {
+			ctx.FeedURL = c.URL + "feed.xml"
+		}
+		html, err := engine.RenderCollection(ctx)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(dir, "index.html"), []byte(html), 0644); err != nil {
+			return err
+		}
+		addSitemapEntry("collection", page, b.cfg.Sitemap.Priorities["taxonomy_index"], b.cfg.Sitemap.ChangeFreqs["taxonomy_index"])
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package build
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/supermodeltools/arch-docs/internal/pssg/logging"
+	"github.com/supermodeltools/arch-docs/internal/pssg/taxonomy"
+)
+
+// reportCollisions logs the term names that share a slug, and fails for
+// taxonomies whose collisions setting is "error".
+func reportCollisions(taxonomies []taxonomy.Taxonomy) error {
+	tlog := logging.Stage("taxonomies")
+	var errs []string
+	for _, tax := range taxonomies {
+		for _, c := range tax.Collisions {
+			switch tax.Config.Collisions {
+			case "error":
+				errs = append(errs, fmt.Sprintf("%s/%s: %s", tax.Name, c.Slug, strings.Join(quoted(c.Names), ", ")))
+			case "suffix":
+				tlog.Warn("Gave colliding taxonomy terms their own slugs", "taxonomy", tax.Name, "names", c.Names, "slugs", c.Slugs)
+			default:
+				tlog.Warn("Merged taxonomy terms sharing a slug", "taxonomy", tax.Name, "slug", c.Slug, "name", c.Names[0], "merged", c.Names[1:])
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("taxonomy terms share a slug (set collisions to merge or suffix to allow it):\n  %s", strings.Join(errs, "\n  "))
+	}
+	return nil
+}
+
+func quoted(names []string) []string {
+	out := make([]string, len(names))
+	for i, n := range names {
+		out[i] = fmt.Sprintf("%q", n)
+	}
+	return out
+}
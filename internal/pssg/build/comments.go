@@ -0,0 +1,40 @@
+package build
+
+import (
+	"github.com/supermodeltools/arch-docs/internal/pssg/entity"
+	"github.com/supermodeltools/arch-docs/internal/pssg/render"
+)
+
+// comments returns the discussion thread settings for an entity's page,
+// or nil when the site has no comments provider or the entity opts out.
+func (b *Builder) comments(e *entity.Entity) *render.Comments {
+	cc := b.cfg.Comments
+	if cc.Provider == "" {
+		return nil
+	}
+	on := cc.Default == nil || *cc.Default
+	if _, set := e.Fields[cc.Field]; set {
+		on = e.GetBool(cc.Field)
+	}
+	if !on {
+		return nil
+	}
+	c := &render.Comments{
+		Provider:   cc.Provider,
+		Repo:       cc.Repo,
+		RepoID:     cc.RepoID,
+		Category:   cc.Category,
+		CategoryID: cc.CategoryID,
+		Mapping:    cc.Mapping,
+		Label:      cc.Label,
+		Theme:      cc.Theme,
+		Lang:       b.cfg.Site.Language,
+	}
+	if cc.Mapping == "slug" {
+		c.Term = e.Slug
+		if cc.Provider == "giscus" {
+			c.Mapping = "specific"
+		}
+	}
+	return c
+}
@@ -0,0 +1,83 @@
+package build
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/supermodeltools/arch-docs/internal/pssg/contributors"
+	"github.com/supermodeltools/arch-docs/internal/pssg/render"
+	"github.com/supermodeltools/arch-docs/internal/pssg/schema"
+	"github.com/supermodeltools/arch-docs/internal/pssg/taxonomy"
+)
+
+// contributorURL is a contributor's entry on the contributors page, which
+// identifies them in JSON-LD.
+func (b *Builder) contributorURL(slug string) string {
+	return b.cfg.Site.BaseURL + "/" + b.cfg.Contributors.Dir + "/#" + slug
+}
+
+// hubContributor returns the profile an author hub describes, or nil for
+// other taxonomies.
+func (b *Builder) hubContributor(tax, slug string) *contributors.Profile {
+	if tax != "author" {
+		return nil
+	}
+	return b.profiles.Get(slug)
+}
+
+// renderContributors writes the contributors index, with a Person in its
+// JSON-LD for every profile.
+func (b *Builder) renderContributors(
+	engine *render.Engine,
+	schemaGen *schema.Generator,
+	allTaxonomies []taxonomy.Taxonomy,
+	outDir string,
+	addSitemapEntry func(string, string, string, string),
+) error {
+	dir := filepath.Join(outDir, b.cfg.Contributors.Dir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("creating contributors dir: %w", err)
+	}
+
+	name := "Contributors"
+	page := "/" + b.cfg.Contributors.Dir + "/"
+	pageURL := b.pageURL(page)
+	description := fmt.Sprintf("The %d people behind %s.", len(b.profiles.Profiles), b.cfg.Site.Name)
+	breadcrumbs := []render.Breadcrumb{
+		{Name: "Home", URL: b.cfg.Site.BaseURL + "/"},
+		{Name: name, URL: ""},
+	}
+	items := make([]schema.ItemListEntry, len(b.profiles.Profiles))
+	schemas := []map[string]interface{}{nil, schemaGen.GenerateBreadcrumbSchema(toBreadcrumbItems(breadcrumbs))}
+	for i, p := range b.profiles.Profiles {
+		items[i] = schema.ItemListEntry{Name: p.Name, URL: b.contributorURL(p.Slug)}
+		schemas = append(schemas, schemaGen.GeneratePersonSchema(p, b.contributorURL(p.Slug)))
+	}
+	schemas[0] = schemaGen.GenerateCollectionPageSchema(name, description, pageURL, items, "")
+
+	ctx := render.ContributorsContext{
+		Site:          b.cfg.Site,
+		Languages:     b.languageLinks(page),
+		Profiles:      b.profiles.Profiles,
+		JsonLD:        toTemplateHTML(schema.MarshalSchemas(schemas...)),
+		Breadcrumbs:   breadcrumbs,
+		AllTaxonomies: allTaxonomies,
+		OG: render.OGMeta{
+			Title:       name + " — " + b.cfg.Site.Name,
+			Description: description,
+			URL:         pageURL,
+			Type:        "website",
+			SiteName:    b.cfg.Site.Name,
+		},
+	}
+	html, err := engine.RenderContributors(ctx)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, "index.html"), []byte(html), 0644); err != nil {
+		return err
+	}
+	addSitemapEntry("contributors", page, b.cfg.Sitemap.Priorities["taxonomy_index"], b.cfg.Sitemap.ChangeFreqs["taxonomy_index"])
+	return nil
+}
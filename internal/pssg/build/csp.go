@@ -0,0 +1,25 @@
+package build
+
+import (
+	"github.com/supermodeltools/arch-docs/internal/pssg/csp"
+	"github.com/supermodeltools/arch-docs/internal/pssg/logging"
+)
+
+// contentSecurityPolicy returns the configured policy with sources for the
+// inline scripts and styles of the pages under outDir. In nonce mode the
+// pages are rewritten to carry this build's nonce.
+func (b *Builder) contentSecurityPolicy(outDir string) (string, error) {
+	nonce := ""
+	if b.cfg.Headers.CSP.Mode == "nonce" {
+		var err error
+		if nonce, err = csp.NewNonce(); err != nil {
+			return "", err
+		}
+	}
+	src, err := csp.Scan(outDir, nonce)
+	if err != nil {
+		return "", err
+	}
+	logging.Stage("output").Info("Built content security policy", "mode", b.cfg.Headers.CSP.Mode, "script_sources", len(src.Scripts), "style_sources", len(src.Styles))
+	return csp.Policy(b.cfg.Headers.CSP.Policy, src), nil
+}
@@ -0,0 +1,50 @@
+package build
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/supermodeltools/arch-docs/internal/pssg/taxonomy"
+)
+
+// descriptionData is what a taxonomy's index_description and
+// letter_description templates see.
+type descriptionData struct {
+	Site     string
+	Label    string
+	Letter   string   // on letter pages
+	Count    int      // entries on the page
+	Entities int      // entities in those entries, each once
+	Top      []string // the largest entries' names, most entities first
+}
+
+var descriptionFuncs = template.FuncMap{
+	"lower": strings.ToLower,
+	"join":  strings.Join,
+}
+
+// describe evaluates a description template for a taxonomy index or
+// letter page listing entries. key names the setting in errors.
+func (b *Builder) describe(key, tmpl string, tax taxonomy.Taxonomy, letter string, entries []taxonomy.Entry) (string, error) {
+	t, err := template.New(key).Funcs(descriptionFuncs).Parse(tmpl)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", key, err)
+	}
+	d := descriptionData{
+		Site:     b.cfg.Site.Name,
+		Label:    tax.Label,
+		Letter:   letter,
+		Count:    len(entries),
+		Entities: len(entryEntities(entries)),
+	}
+	for _, entry := range taxonomy.TopEntries(entries, 5) {
+		d.Top = append(d.Top, entry.Name)
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, d); err != nil {
+		return "", fmt.Errorf("%s: %w", key, err)
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
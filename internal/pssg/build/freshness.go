@@ -0,0 +1,79 @@
+package build
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/supermodeltools/arch-docs/internal/pssg/freshness"
+	"github.com/supermodeltools/arch-docs/internal/pssg/render"
+	"github.com/supermodeltools/arch-docs/internal/pssg/schema"
+	"github.com/supermodeltools/arch-docs/internal/pssg/taxonomy"
+)
+
+// renderFreshness writes the stale docs report page and its JSON export.
+// The report is for maintainers, so the page is kept out of the sitemap
+// and search engines.
+func (b *Builder) renderFreshness(
+	engine *render.Engine,
+	schemaGen *schema.Generator,
+	report *freshness.Report,
+	allTaxonomies []taxonomy.Taxonomy,
+	outDir string,
+) error {
+	dir := filepath.Join(outDir, b.cfg.Freshness.Dir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("creating freshness dir: %w", err)
+	}
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, "report.json"), append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("writing freshness report: %w", err)
+	}
+
+	name := "Documentation Freshness"
+	page := "/" + b.cfg.Freshness.Dir + "/"
+	breadcrumbs := []render.Breadcrumb{
+		{Name: "Home", URL: b.cfg.Site.BaseURL + "/"},
+		{Name: name, URL: ""},
+	}
+	ctx := render.FreshnessContext{
+		Site:          b.cfg.Site,
+		Languages:     b.languageLinks(page),
+		Report:        report,
+		JSONURL:       page + "report.json",
+		JsonLD:        toTemplateHTML(schema.MarshalSchemas(schemaGen.GenerateBreadcrumbSchema(toBreadcrumbItems(breadcrumbs)))),
+		Breadcrumbs:   breadcrumbs,
+		AllTaxonomies: allTaxonomies,
+		NoIndex:       true,
+		OG: render.OGMeta{
+			Title:       name + " — " + b.cfg.Site.Name,
+			Description: fmt.Sprintf("%d of %d pages of %s are older than the code they document.", report.Stale, report.Checked, b.cfg.Site.Name),
+			URL:         b.pageURL(page),
+			Type:        "website",
+			SiteName:    b.cfg.Site.Name,
+		},
+	}
+	html, err := engine.RenderFreshness(ctx)
+	if err != nil {
+		return fmt.Errorf("rendering freshness report: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "index.html"), []byte(html), 0644); err != nil {
+		return fmt.Errorf("writing freshness report: %w", err)
+	}
+	return nil
+}
+
+// staleItem returns an entity's freshness entry when its docs are stale.
+func (b *Builder) staleItem(slug string) *freshness.Item {
+	if b.freshness == nil {
+		return nil
+	}
+	if it := b.freshness.Item(slug); it != nil && it.Stale {
+		return it
+	}
+	return nil
+}
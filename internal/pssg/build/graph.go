@@ -0,0 +1,94 @@
+package build
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/supermodeltools/arch-docs/internal/pssg/relation"
+	"github.com/supermodeltools/arch-docs/internal/pssg/render"
+	"github.com/supermodeltools/arch-docs/internal/pssg/schema"
+	"github.com/supermodeltools/arch-docs/internal/pssg/taxonomy"
+)
+
+// archOverviewNodes caps the homepage's architecture overview; the graph
+// page shows the rest.
+const archOverviewNodes = 120
+
+// graphURL is the URL of a file in graph.dir.
+func (b *Builder) graphURL(name string) string {
+	return b.cfg.Site.BaseURL + "/" + b.cfg.Graph.Dir + "/" + name
+}
+
+// renderGraph writes graph.json, a subgraph JSON file per related entity
+// and the graph page.
+func (b *Builder) renderGraph(
+	engine *render.Engine,
+	schemaGen *schema.Generator,
+	graph relation.Data,
+	allTaxonomies []taxonomy.Taxonomy,
+	outDir string,
+	addSitemapEntry func(string, string, string, string),
+) error {
+	dir := filepath.Join(outDir, b.cfg.Graph.Dir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("creating graph dir: %w", err)
+	}
+	writeJSON := func(name string, v interface{}) error {
+		data, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(filepath.Join(dir, filepath.FromSlash(name)), data, 0644)
+	}
+	if err := writeJSON("graph.json", graph); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "nodes"), 0755); err != nil {
+		return err
+	}
+	for slug, sub := range graph.Subgraphs(b.cfg.Graph.Depth) {
+		if err := writeJSON("nodes/"+slug+".json", sub); err != nil {
+			return err
+		}
+	}
+
+	page := "/" + b.cfg.Graph.Dir + "/"
+	pageURL := b.pageURL(page)
+	description := fmt.Sprintf("How the %d related entries of %s connect: %d relations.", len(graph.Nodes), b.cfg.Site.Name, len(graph.Edges))
+	breadcrumbs := []render.Breadcrumb{
+		{Name: "Home", URL: b.cfg.Site.BaseURL + "/"},
+		{Name: "Graph", URL: ""},
+	}
+	breadcrumbSchema := schemaGen.GenerateBreadcrumbSchema(toBreadcrumbItems(breadcrumbs))
+
+	ctx := render.GraphPageContext{
+		Site:          b.cfg.Site,
+		Languages:     b.languageLinks(page),
+		DataURL:       b.graphURL("graph.json"),
+		NodeCount:     len(graph.Nodes),
+		EdgeCount:     len(graph.Edges),
+		Relations:     b.cfg.Data.Relations,
+		JsonLD:        toTemplateHTML(schema.MarshalSchemas(breadcrumbSchema)),
+		Breadcrumbs:   breadcrumbs,
+		AllTaxonomies: allTaxonomies,
+		OG: render.OGMeta{
+			Title:       "Graph — " + b.cfg.Site.Name,
+			Description: description,
+			URL:         pageURL,
+			Type:        "article",
+			SiteName:    b.cfg.Site.Name,
+		},
+		CTA: b.cfg.Extra.CTA,
+	}
+	html, err := engine.RenderGraph(ctx)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, "index.html"), []byte(html), 0644); err != nil {
+		return fmt.Errorf("writing graph page: %w", err)
+	}
+	addSitemapEntry("graph", page, b.cfg.Sitemap.Priorities["taxonomy_index"], b.cfg.Sitemap.ChangeFreqs["taxonomy_index"])
+	return nil
+}
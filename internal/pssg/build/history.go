@@ -0,0 +1,84 @@
+package build
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/supermodeltools/arch-docs/internal/pssg/entity"
+	"github.com/supermodeltools/arch-docs/internal/pssg/history"
+	"github.com/supermodeltools/arch-docs/internal/pssg/logging"
+	"github.com/supermodeltools/arch-docs/internal/pssg/output"
+)
+
+// loadHistory reads the recent commits to every entity's file, keyed by
+// slug. A data directory outside git is reported and yields no history.
+func (b *Builder) loadHistory(entities []*entity.Entity) map[string][]history.Commit {
+	log := logging.Stage("history")
+	files, err := history.Log(b.cfg.Paths.Data, b.cfg.History.Limit)
+	if err != nil {
+		log.Warn("Failed to read git history", "error", err)
+		return nil
+	}
+	changes := make(map[string][]history.Commit)
+	for _, e := range entities {
+		rel, err := filepath.Rel(b.cfg.Paths.Data, e.SourceFile)
+		if err != nil {
+			continue
+		}
+		if commits := files[filepath.ToSlash(rel)]; len(commits) > 0 {
+			changes[e.Slug] = commits
+		}
+	}
+	log.Info("Read git history", "entities", len(changes))
+	return changes
+}
+
+// changesFeed is the root-relative URL of an entity's change feed, ""
+// when it has no history.
+func (b *Builder) changesFeed(slug string) string {
+	if len(b.changes[slug]) == 0 {
+		return ""
+	}
+	return "/" + b.cfg.History.Dir + "/" + slug + ".xml"
+}
+
+// commitURL is the prefix commit hashes are appended to, "" without a
+// repository URL.
+func (b *Builder) commitURL() string {
+	if b.cfg.Site.RepoURL == "" {
+		return ""
+	}
+	return strings.TrimSuffix(b.cfg.Site.RepoURL, "/") + "/commit/"
+}
+
+// writeChangeFeeds writes the Atom feed of every entity with history.
+func (b *Builder) writeChangeFeeds(entities []*entity.Entity, outDir string) (int, error) {
+	dir := filepath.Join(outDir, b.cfg.History.Dir)
+	written := 0
+	for _, e := range entities {
+		feed := b.changesFeed(e.Slug)
+		if feed == "" {
+			continue
+		}
+		if written == 0 {
+			if err := os.MkdirAll(dir, 0755); err != nil {
+				return 0, fmt.Errorf("creating %s: %w", dir, err)
+			}
+		}
+		content := output.GenerateChangeFeed(
+			"Changes to "+e.GetString("title")+" — "+b.cfg.Site.Name,
+			b.pageURL("/"+e.Slug+".html"),
+			b.cfg.Site.BaseURL+feed,
+			b.commitURL(),
+			b.changes[e.Slug],
+		)
+		path := filepath.Join(dir, e.Slug+".xml")
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			return 0, fmt.Errorf("writing %s: %w", path, err)
+		}
+		written++
+	}
+	return written, nil
+}
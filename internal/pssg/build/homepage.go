@@ -0,0 +1,92 @@
+package build
+
+import (
+	"sort"
+
+	"github.com/supermodeltools/arch-docs/internal/pssg/entity"
+	"github.com/supermodeltools/arch-docs/internal/pssg/featured"
+	"github.com/supermodeltools/arch-docs/internal/pssg/logging"
+	"github.com/supermodeltools/arch-docs/internal/pssg/render"
+	"github.com/supermodeltools/arch-docs/internal/pssg/taxonomy"
+)
+
+// homepageSections computes the sections homepage.sections lists. A
+// section with nothing to show, such as a collection that failed to load,
+// is left out with a warning.
+func (b *Builder) homepageSections(entities []*entity.Entity, taxonomies []taxonomy.Taxonomy) []render.HomepageSection {
+	var sections []render.HomepageSection
+	for _, sc := range b.cfg.Homepage.Sections {
+		s := render.HomepageSection{Type: sc.Type, Title: sc.Title}
+		switch sc.Type {
+		case "collection":
+			for _, c := range b.collections {
+				if c.Slug == sc.Collection {
+					s.URL = c.URL
+					s.Entities = limit(c.Entities, sc.Limit)
+					if s.Title == "" {
+						s.Title = c.Title
+					}
+				}
+			}
+		case "newest":
+			s.Entities = limit(newest(entities, sc.DateField), sc.Limit)
+			if s.Title == "" {
+				s.Title = "Newest"
+			}
+		case "recently_added":
+			s.Entities = limit(recentEntities(b.byFirstSeen(entities)), sc.Limit)
+			if s.Title == "" {
+				s.Title = "Recently added"
+			}
+		case "recently_updated":
+			s.Entities = limit(recentEntities(b.byModified(entities)), sc.Limit)
+			if s.Title == "" {
+				s.Title = "Recently updated"
+			}
+		case "top_terms":
+			for _, tax := range taxonomies {
+				if tax.Name == sc.Taxonomy {
+					s.URL = b.pageURL("/" + tax.Name + "/")
+					s.Taxonomy = tax.Name
+					s.Terms = taxonomy.TopEntries(tax.Entries, sc.Limit)
+					if s.Title == "" {
+						s.Title = tax.Label
+					}
+				}
+			}
+		case "random":
+			s.Entities = limit(featured.Shuffle(entities, sc.Seed), sc.Limit)
+			if s.Title == "" {
+				s.Title = "Random picks"
+			}
+		}
+		if len(s.Entities) == 0 && len(s.Terms) == 0 {
+			logging.Stage("render").Warn("Homepage section is empty", "type", sc.Type, "title", s.Title)
+			continue
+		}
+		sections = append(sections, s)
+	}
+	return sections
+}
+
+// newest returns the entities with a date in field, latest first.
+func newest(entities []*entity.Entity, field string) []*entity.Entity {
+	var dated []*entity.Entity
+	for _, e := range entities {
+		if !e.GetTime(field).IsZero() {
+			dated = append(dated, e)
+		}
+	}
+	sort.SliceStable(dated, func(i, j int) bool {
+		return dated[i].GetTime(field).After(dated[j].GetTime(field))
+	})
+	return dated
+}
+
+// limit returns the first n entities, or all of them when n is 0.
+func limit(entities []*entity.Entity, n int) []*entity.Entity {
+	if n > 0 && n < len(entities) {
+		return entities[:n]
+	}
+	return entities
+}
@@ -0,0 +1,142 @@
+package build
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/supermodeltools/arch-docs/internal/pssg/config"
+	"github.com/supermodeltools/arch-docs/internal/pssg/entity"
+	"github.com/supermodeltools/arch-docs/internal/pssg/loader"
+	"github.com/supermodeltools/arch-docs/internal/pssg/logging"
+	"github.com/supermodeltools/arch-docs/internal/pssg/render"
+	"github.com/supermodeltools/arch-docs/internal/pssg/taxonomy"
+)
+
+// edition is one language version of the site. The default language is
+// built at the site root and every other language under /<code>/.
+type edition struct {
+	lang    config.LanguageConfig
+	baseURL string
+	// pages holds the entity, hub and letter paths the edition renders, so
+	// the language switcher only links to pages that exist.
+	pages map[string]bool
+}
+
+// planEditions loads every language's entities up front to learn which
+// pages each edition will have. It does nothing for single-language sites.
+func (b *Builder) planEditions() error {
+	i18n := b.cfg.I18n
+	if len(i18n.Languages) < 2 {
+		return nil
+	}
+	b.editions = nil
+	for _, lang := range i18n.Languages {
+		cfg := b.editionConfig(lang)
+		useSlugStyle(cfg)
+		entities, err := loader.New(cfg).Load()
+		if err != nil {
+			return fmt.Errorf("loading %s entities: %w", lang.Code, err)
+		}
+		pages := make(map[string]bool)
+		for _, e := range entities {
+			pages["/"+e.Slug+".html"] = true
+		}
+		for _, tax := range taxonomy.BuildAll(entities, cfg.Taxonomies, nil) {
+			for _, entry := range tax.Entries {
+				pages[taxonomy.HubPageURL(tax.Name, entry.Slug, 1)] = true
+			}
+			for _, lg := range taxonomy.GroupByLetter(tax.Entries) {
+				pages[taxonomy.LetterPageURL(tax.Name, lg.Letter)] = true
+			}
+		}
+		b.editions = append(b.editions, edition{lang: lang, baseURL: cfg.Site.BaseURL, pages: pages})
+	}
+	return nil
+}
+
+// useSlugStyle makes entity.ToSlug follow cfg's language and site.slugs.
+func useSlugStyle(cfg *config.Config) {
+	entity.SetSlugStyle(cfg.Site.Language, cfg.Site.Slugs == "unicode")
+}
+
+// shareImages returns the share image renderer for the site language.
+func (b *Builder) shareImages() render.ShareImages {
+	return render.NewShareImages(b.cfg.Site.Language)
+}
+
+// editionConfig returns the config the given language is built with.
+func (b *Builder) editionConfig(lang config.LanguageConfig) *config.Config {
+	cfg := *b.cfg
+	if lang.Code == cfg.I18n.Default {
+		return &cfg
+	}
+	cfg.Site.Language = lang.Code
+	cfg.I18n.RootURL = b.cfg.Site.BaseURL
+	cfg.Site.BaseURL += "/" + lang.Code
+	cfg.Site.BasePath += "/" + lang.Code
+	if lang.Slugs != "" {
+		cfg.Site.Slugs = lang.Slugs
+	}
+	cfg.Site.Locale = lang.Code
+	if lang.Locale != "" {
+		cfg.Site.Locale = lang.Locale
+	}
+	cfg.Paths.Output = filepath.Join(b.cfg.Paths.Output, lang.Code)
+	if lang.SiteName != "" {
+		cfg.Site.Name = lang.SiteName
+	}
+	if lang.Description != "" {
+		cfg.Site.Description = lang.Description
+	}
+	cfg.Output.Targets = nil
+	return &cfg
+}
+
+// buildEditions renders every non-default language into its subdirectory
+// of the output. The root build's manifest covers their files.
+func (b *Builder) buildEditions() error {
+	defer useSlugStyle(b.cfg)
+	for _, ed := range b.editions {
+		if ed.lang.Code == b.cfg.I18n.Default {
+			continue
+		}
+		logging.Stage("i18n").Info("Building language edition", "language", ed.lang.Code)
+		nb := NewBuilder(b.editionConfig(ed.lang), b.force)
+		nb.skipManifest = true
+		nb.edition = true
+		nb.shares = b.shares
+		nb.featuredSeed = b.featuredSeed
+		nb.editions = b.editions
+		if err := nb.Build(); err != nil {
+			return fmt.Errorf("language %s: %w", ed.lang.Code, err)
+		}
+		b.sitemaps = append(b.sitemaps, nb.sitemaps...)
+	}
+	return nil
+}
+
+// languageLinks returns the switcher entries for the page at path. Editions
+// without a counterpart entity, hub or letter page link to their homepage.
+func (b *Builder) languageLinks(path string) []render.LanguageLink {
+	if len(b.editions) == 0 {
+		return nil
+	}
+	content := false
+	for _, ed := range b.editions {
+		content = content || ed.pages[path]
+	}
+	links := make([]render.LanguageLink, 0, len(b.editions))
+	for _, ed := range b.editions {
+		url := ed.baseURL + b.link(path)
+		if content && !ed.pages[path] {
+			url = ed.baseURL + "/"
+		}
+		links = append(links, render.LanguageLink{
+			Code:    ed.lang.Code,
+			Name:    ed.lang.Name,
+			URL:     url,
+			Current: ed.lang.Code == b.cfg.Site.Language,
+		})
+	}
+	return links
+}
@@ -0,0 +1,66 @@
+package build
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"sync"
+
+	"github.com/supermodeltools/arch-docs/internal/pssg/entity"
+	"github.com/supermodeltools/arch-docs/internal/pssg/imaging"
+	"github.com/supermodeltools/arch-docs/internal/pssg/logging"
+)
+
+// processImages generates the hero image variants of every entity that
+// names one, keyed by slug, and localizes the remote images of
+// images.remote_fields. With images.on_error set to warn, an image that
+// cannot be read, decoded or downloaded is reported and its entity
+// rendered without one; with fail, the build stops.
+func (b *Builder) processImages(entities []*entity.Entity, outDir string) (map[string]*imaging.Set, error) {
+	ilog := logging.Stage("images")
+	proc := imaging.New(b.cfg)
+	sets := make(map[string]*imaging.Set)
+	var errs []error
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, runtime.NumCPU())
+
+	report := func(e *entity.Entity, err error) {
+		ilog.Warn("Failed to process image", "slug", e.Slug, "error", err)
+		mu.Lock()
+		errs = append(errs, fmt.Errorf("%s: %w", e.Slug, err))
+		mu.Unlock()
+	}
+
+	for _, e := range entities {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(e *entity.Entity) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			if b.cfg.Images.Enabled {
+				set, err := proc.Process(e, outDir)
+				if err != nil {
+					report(e, err)
+				} else if set != nil {
+					mu.Lock()
+					sets[e.Slug] = set
+					mu.Unlock()
+				}
+			}
+			if err := proc.Localize(e, outDir); err != nil {
+				report(e, err)
+			}
+		}(e)
+	}
+	wg.Wait()
+	if err := proc.Save(); err != nil {
+		ilog.Warn("Failed to save remote image index", "error", err)
+	}
+	ilog.Info("Processed images", "count", len(sets), "errors", len(errs))
+	if len(errs) > 0 && b.cfg.Images.OnError == "fail" {
+		return nil, fmt.Errorf("processing images: %w", errors.Join(errs...))
+	}
+	return sets, nil
+}
@@ -0,0 +1,38 @@
+package build
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/supermodeltools/arch-docs/internal/pssg/entity"
+	"github.com/supermodeltools/arch-docs/internal/pssg/logging"
+	"github.com/supermodeltools/arch-docs/internal/pssg/relation"
+	"github.com/supermodeltools/arch-docs/internal/pssg/sibling"
+)
+
+// linkSiblings resolves the relation targets missing here against the
+// manifests of sibling sites. Siblings that cannot be read are reported
+// and their targets stay unresolved.
+func (b *Builder) linkSiblings(relGraph *relation.Graph) {
+	log := logging.Stage("relations")
+	idx, errs := sibling.Load(b.cfg.Links.Siblings)
+	for _, err := range errs {
+		log.Warn("Failed to read sibling manifest", "error", err)
+	}
+	before := len(relGraph.Dangling)
+	relGraph.LinkExternal(idx.Resolve)
+	if n := before - len(relGraph.Dangling); n > 0 {
+		log.Info("Linked relation targets on sibling sites", "count", n)
+	}
+}
+
+// writeEntityManifest publishes the manifest sibling sites read.
+func (b *Builder) writeEntityManifest(entities []*entity.Entity, outDir string) error {
+	m := sibling.NewManifest(b.cfg, entities, b.cfg.Data.Validation.TypeField)
+	data, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(outDir, sibling.ManifestFile), data, 0644)
+}
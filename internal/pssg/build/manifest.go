@@ -0,0 +1,82 @@
+package build
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/supermodeltools/arch-docs/internal/pssg/manifest"
+)
+
+// manifestPath is where the output manifest for this site is kept.
+func (b *Builder) manifestPath() string {
+	return filepath.Join(b.cfg.Paths.Cache, manifest.FileName)
+}
+
+// fsNow returns the current time as dir's filesystem records it. Kernels
+// stamp files from a coarse clock that can lag time.Now by a few
+// milliseconds, so comparing mtimes against time.Now would miss files
+// written right after it. Falls back to time.Now if dir is not writable.
+func fsNow(dir string) time.Time {
+	f, err := os.CreateTemp(dir, ".pssg-stamp-")
+	if err != nil {
+		return time.Now()
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return time.Now()
+	}
+	return info.ModTime()
+}
+
+// writeManifest records the files this build (started at start) wrote,
+// carrying forward earlier generated files that are still on disk as stale.
+func (b *Builder) writeManifest(start time.Time) error {
+	prev, err := manifest.Load(b.manifestPath())
+	if err != nil {
+		return err
+	}
+	if prev != nil && prev.Output != b.cfg.Paths.Output {
+		prev = nil // output moved; nothing there is known to be ours
+	}
+	after, err := manifest.Scan(b.cfg.Paths.Output)
+	if err != nil {
+		return err
+	}
+	m := &manifest.Manifest{
+		Output: b.cfg.Paths.Output,
+		Built:  time.Now().UTC(),
+		Files:  manifest.Written(b.cfg.Paths.Output, after, start),
+	}
+	if prev != nil {
+		earlier := append(sortedPaths(prev.Files), prev.Stale...)
+		for _, rel := range earlier {
+			if _, onDisk := after[rel]; onDisk {
+				if _, ok := m.Files[rel]; !ok {
+					m.Stale = append(m.Stale, rel)
+				}
+			}
+		}
+		sort.Strings(m.Stale)
+		m.Stale = slices.Compact(m.Stale)
+
+		prevPath := filepath.Join(b.cfg.Paths.Cache, manifest.PrevFileName)
+		if err := os.Rename(b.manifestPath(), prevPath); err != nil {
+			return err
+		}
+	}
+	return m.Save(b.manifestPath())
+}
+
+func sortedPaths(files map[string]manifest.File) []string {
+	paths := make([]string, 0, len(files))
+	for rel := range files {
+		paths = append(paths, rel)
+	}
+	sort.Strings(paths)
+	return paths
+}
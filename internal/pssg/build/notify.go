@@ -0,0 +1,67 @@
+package build
+
+import (
+	"context"
+	"path/filepath"
+	"time"
+
+	"github.com/supermodeltools/arch-docs/internal/pssg/logging"
+	"github.com/supermodeltools/arch-docs/internal/pssg/manifest"
+	"github.com/supermodeltools/arch-docs/internal/pssg/notify"
+	"github.com/supermodeltools/arch-docs/internal/pssg/sitediff"
+)
+
+// notifyHooks posts the outcome of a build that started at start to
+// hooks.notify. A notification that cannot be sent is logged; it never
+// fails the build.
+func (b *Builder) notifyHooks(start time.Time, buildErr error) {
+	r := notify.Result{
+		Site:     b.cfg.Site.Name,
+		URL:      b.cfg.Site.BaseURL,
+		Status:   "success",
+		Entities: b.entityCount,
+		Duration: time.Since(start).Round(time.Millisecond).String(),
+	}
+	if buildErr != nil {
+		r.Status, r.Error = "failure", buildErr.Error()
+	} else {
+		r.Pages, r.Changed = b.pageChanges()
+	}
+	if !notify.Wants(b.cfg.Hooks.Notify, r) {
+		return
+	}
+	log := logging.Stage("notify")
+	if err := notify.Send(context.Background(), b.cfg.Hooks.Notify, r); err != nil {
+		log.Warn("Failed to send build notification", "error", err)
+		return
+	}
+	log.Info("Sent build notification", "status", r.Status)
+}
+
+// pageChanges counts the pages in the output manifest, and those added,
+// changed or removed since the previous build's. Every page is new on the
+// first build.
+func (b *Builder) pageChanges() (pages, changed int) {
+	cur, err := manifest.Load(b.manifestPath())
+	if err != nil || cur == nil {
+		return 0, 0
+	}
+	prev, err := manifest.Load(filepath.Join(b.cfg.Paths.Cache, manifest.PrevFileName))
+	if err != nil || prev == nil || prev.Output != cur.Output {
+		prev = &manifest.Manifest{}
+	}
+	for rel := range cur.Files {
+		if sitediff.IsPage(rel) {
+			pages++
+		}
+	}
+	c := manifest.Compare(prev.Files, cur.Files)
+	for _, list := range [][]string{c.Added, c.Changed, c.Removed} {
+		for _, rel := range list {
+			if sitediff.IsPage(rel) {
+				changed++
+			}
+		}
+	}
+	return pages, changed
+}
@@ -0,0 +1,30 @@
+package build
+
+import (
+	"github.com/supermodeltools/arch-docs/internal/pssg/entity"
+	"github.com/supermodeltools/arch-docs/internal/pssg/render"
+)
+
+// entityOG applies an entity's own og_* and twitter_* fields over the
+// OG meta derived for its page.
+func (b *Builder) entityOG(e *entity.Entity, og render.OGMeta) render.OGMeta {
+	if v := e.GetString("og_title"); v != "" {
+		og.Title = v
+	}
+	if v := e.GetString("og_description"); v != "" {
+		og.Description = v
+	}
+	if v := e.GetString("og_image"); v != "" {
+		og.ImageURL = absoluteURL(b.cfg.Site.BaseURL, v)
+	}
+	if v := e.GetString("og_image_alt"); v != "" {
+		og.ImageAlt = v
+	}
+	if v := e.GetString("twitter_card"); v != "" {
+		og.Card = v
+	}
+	if v := e.GetString("twitter_creator"); v != "" {
+		og.TwitterCreator = v
+	}
+	return og
+}
@@ -0,0 +1,101 @@
+package build
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/supermodeltools/arch-docs/internal/pssg/config"
+	"github.com/supermodeltools/arch-docs/internal/pssg/entity"
+	"github.com/supermodeltools/arch-docs/internal/pssg/taxonomy"
+)
+
+// sortEntities orders entities in place: featured first, then weighted
+// entities by ascending weight, then everything else. Ties are broken by
+// the configured secondary sort field, or left in load order.
+func sortEntities(entities []*entity.Entity, cfg config.OrderingConfig) {
+	sort.SliceStable(entities, func(i, j int) bool {
+		a, b := entities[i], entities[j]
+
+		if af, bf := a.GetBool(cfg.FeaturedField), b.GetBool(cfg.FeaturedField); af != bf {
+			return af
+		}
+
+		aw, bw := a.HasField(cfg.WeightField), b.HasField(cfg.WeightField)
+		if aw != bw {
+			return aw
+		}
+		if aw {
+			if x, y := a.GetFloat(cfg.WeightField), b.GetFloat(cfg.WeightField); x != y {
+				return x < y
+			}
+		}
+
+		if cfg.SortBy == "" {
+			return false
+		}
+		return lessByField(a, b, cfg.SortBy, cfg.Descending)
+	})
+}
+
+// lessByField compares two entities on a field, numerically when both
+// values are numbers and as strings otherwise.
+func lessByField(a, b *entity.Entity, field string, desc bool) bool {
+	if desc {
+		a, b = b, a
+	}
+	if isNumber(a.Fields[field]) && isNumber(b.Fields[field]) {
+		return a.GetFloat(field) < b.GetFloat(field)
+	}
+	if at, bt := a.GetTime(field), b.GetTime(field); !at.IsZero() && !bt.IsZero() {
+		return at.Before(bt)
+	}
+	return a.GetString(field) < b.GetString(field)
+}
+
+func isNumber(v interface{}) bool {
+	switch v.(type) {
+	case int, int64, float64:
+		return true
+	}
+	return false
+}
+
+// sortListing returns entities in the order all_entities.sort asks for,
+// leaving the slice it is given alone. Entities without the field sorted
+// on come last, in their existing order.
+func sortListing(entities []*entity.Entity, cfg config.AllEntitiesConfig, ordering config.OrderingConfig) []*entity.Entity {
+	if cfg.Sort == "" {
+		return entities
+	}
+	sorted := append([]*entity.Entity(nil), entities...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		switch cfg.Sort {
+		case "title":
+			return strings.ToLower(a.GetString("title")) < strings.ToLower(b.GetString("title"))
+		case "date":
+			at, bt := a.GetTime(cfg.DateField), b.GetTime(cfg.DateField)
+			if at.IsZero() != bt.IsZero() {
+				return bt.IsZero()
+			}
+			return at.After(bt)
+		default:
+			aw, bw := a.HasField(ordering.WeightField), b.HasField(ordering.WeightField)
+			if aw != bw {
+				return aw
+			}
+			return aw && a.GetFloat(ordering.WeightField) < b.GetFloat(ordering.WeightField)
+		}
+	})
+	return sorted
+}
+
+// titleEntries wraps each entity in a taxonomy entry named by its title,
+// so the A-Z grouping of taxonomy terms can group entities too.
+func titleEntries(entities []*entity.Entity) []taxonomy.Entry {
+	entries := make([]taxonomy.Entry, 0, len(entities))
+	for _, e := range entities {
+		entries = append(entries, taxonomy.Entry{Name: e.GetString("title"), Slug: e.Slug, Entities: []*entity.Entity{e}})
+	}
+	return entries
+}
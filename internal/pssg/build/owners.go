@@ -0,0 +1,55 @@
+package build
+
+import (
+	"github.com/supermodeltools/arch-docs/internal/pssg/entity"
+	"github.com/supermodeltools/arch-docs/internal/pssg/logging"
+	"github.com/supermodeltools/arch-docs/internal/pssg/owners"
+)
+
+// unownedListed caps the unowned slugs the build log lists.
+const unownedListed = 20
+
+// assignOwners attaches code owners to entities. A missing or unreadable
+// ownership file is reported and leaves entities with the owners they
+// set themselves.
+func (b *Builder) assignOwners(entities []*entity.Entity) {
+	log := logging.Stage("owners")
+	set, err := owners.ForConfig(b.cfg)
+	if err != nil {
+		log.Warn("Failed to read ownership file", "error", err)
+	}
+	b.owners = set
+	unowned := owners.Assign(entities, set, b.cfg.Owners)
+	log.Info("Assigned owners", "owned", len(entities)-len(unowned), "unowned", len(unowned))
+	if len(unowned) > 0 {
+		listed := unowned
+		if len(listed) > unownedListed {
+			listed = listed[:unownedListed]
+		}
+		log.Warn("Entities without an owner", "count", len(unowned), "slugs", listed)
+	}
+}
+
+// entityOwners returns an entity's owners with their contacts; nil
+// unless owners.enabled is set.
+func (b *Builder) entityOwners(e *entity.Entity) []owners.Owner {
+	if !b.cfg.Owners.Enabled {
+		return nil
+	}
+	var list []owners.Owner
+	for _, name := range e.GetStringSlice(b.cfg.Owners.Field) {
+		list = append(list, b.owners.Owner(name))
+	}
+	return list
+}
+
+// ownerDir is the directory of the taxonomy that groups entities by
+// owner, "" if there is none.
+func (b *Builder) ownerDir() string {
+	for _, t := range b.cfg.Taxonomies {
+		if t.Field == b.cfg.Owners.Field {
+			return "/" + t.Name + "/"
+		}
+	}
+	return ""
+}
@@ -0,0 +1,46 @@
+package build
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/supermodeltools/arch-docs/internal/pssg/postprocess"
+)
+
+// postProcess runs output.postprocess over the pages this build wrote
+// under outDir; pages left over from earlier builds are not touched. It
+// returns the number of pages changed.
+func (b *Builder) postProcess(outDir string, since time.Time) (int, error) {
+	pipeline, err := postprocess.Pipeline(b.cfg)
+	if err != nil || pipeline == nil {
+		return 0, err
+	}
+	count := 0
+	err = filepath.WalkDir(outDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !strings.HasSuffix(p, ".html") {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil || info.ModTime().Before(since) {
+			return err
+		}
+		rel, err := filepath.Rel(outDir, p)
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		page := pipeline("/"+filepath.ToSlash(rel), string(data))
+		if page == string(data) {
+			return nil
+		}
+		count++
+		return os.WriteFile(p, []byte(page), 0644)
+	})
+	return count, err
+}
@@ -0,0 +1,185 @@
+package build
+
+import (
+	"fmt"
+	"html"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/supermodeltools/arch-docs/internal/pssg/logging"
+	"github.com/supermodeltools/arch-docs/internal/pssg/render"
+	"github.com/supermodeltools/arch-docs/internal/pssg/taxonomy"
+)
+
+// Share cards cut text beyond roughly these lengths.
+const (
+	previewTitleMax       = 60
+	previewDescriptionMax = 160
+)
+
+var (
+	metaTagRe  = regexp.MustCompile(`(?i)<meta\s[^>]*>`)
+	metaAttrRe = regexp.MustCompile(`(?i)(property|name|content)\s*=\s*"([^"]*)"`)
+)
+
+// renderPreviews writes the share card audit page for the pages this build
+// wrote under outDir.
+func (b *Builder) renderPreviews(engine *render.Engine, allTaxonomies []taxonomy.Taxonomy, outDir string, since time.Time) error {
+	dir := filepath.Join(outDir, b.cfg.Previews.Dir)
+	cards, err := b.previewCards(outDir, dir, since)
+	if err != nil {
+		return err
+	}
+	problems := 0
+	for _, c := range cards {
+		if len(c.Problems) > 0 {
+			problems++
+		}
+	}
+
+	name := "Share Previews"
+	ctx := render.PreviewsContext{
+		Site:     b.cfg.Site,
+		Cards:    cards,
+		Problems: problems,
+		Breadcrumbs: []render.Breadcrumb{
+			{Name: "Home", URL: b.cfg.Site.BaseURL + "/"},
+			{Name: name, URL: ""},
+		},
+		AllTaxonomies: allTaxonomies,
+		NoIndex:       true,
+		OG: render.OGMeta{
+			Title:       name + " (preview only) — " + b.cfg.Site.Name,
+			Description: fmt.Sprintf("%d share cards, %d with problems.", len(cards), problems),
+			URL:         b.pageURL("/" + b.cfg.Previews.Dir + "/"),
+			Type:        "website",
+			SiteName:    b.cfg.Site.Name,
+		},
+	}
+	page, err := engine.RenderPreviews(ctx)
+	if err != nil {
+		return fmt.Errorf("rendering share previews: %w", err)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("creating previews dir: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "index.html"), []byte(page), 0644); err != nil {
+		return fmt.Errorf("writing share previews: %w", err)
+	}
+	logging.Stage("render").Info("Rendered share previews", "cards", len(cards), "problems", problems)
+	return nil
+}
+
+// previewCards reads the share card meta tags of the HTML pages written
+// since the build started, skipping the audit page's own directory.
+// Cards with problems come first.
+func (b *Builder) previewCards(outDir, skip string, since time.Time) ([]render.PreviewCard, error) {
+	var cards []render.PreviewCard
+	err := filepath.WalkDir(outDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if p == skip {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(p, ".html") {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil || info.ModTime().Before(since) {
+			return err
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(outDir, p)
+		if err != nil {
+			return err
+		}
+		cards = append(cards, b.previewCard("/"+filepath.ToSlash(rel), string(data), outDir))
+		return nil
+	})
+	sort.SliceStable(cards, func(i, j int) bool {
+		if (len(cards[i].Problems) > 0) != (len(cards[j].Problems) > 0) {
+			return len(cards[i].Problems) > 0
+		}
+		return cards[i].Page < cards[j].Page
+	})
+	return cards, err
+}
+
+// previewCard reads a page's og: tags, falling back to the twitter: and
+// description tags, and lists what a share card would get wrong.
+func (b *Builder) previewCard(page, doc, outDir string) render.PreviewCard {
+	meta := make(map[string]string)
+	if i := strings.Index(doc, "</head>"); i >= 0 {
+		doc = doc[:i]
+	}
+	for _, tag := range metaTagRe.FindAllString(doc, -1) {
+		key, content, hasContent := "", "", false
+		for _, m := range metaAttrRe.FindAllStringSubmatch(tag, -1) {
+			switch strings.ToLower(m[1]) {
+			case "content":
+				content, hasContent = html.UnescapeString(m[2]), true
+			default:
+				key = strings.ToLower(m[2])
+			}
+		}
+		if key != "" && hasContent {
+			if _, seen := meta[key]; !seen {
+				meta[key] = strings.TrimSpace(content)
+			}
+		}
+	}
+	first := func(keys ...string) string {
+		for _, k := range keys {
+			if v := meta[k]; v != "" {
+				return v
+			}
+		}
+		return ""
+	}
+
+	c := render.PreviewCard{
+		Page:        page,
+		Title:       first("og:title", "twitter:title"),
+		Description: first("og:description", "twitter:description", "description"),
+		Image:       first("og:image", "twitter:image"),
+	}
+	c.ImageSrc = c.Image
+	if local, ok := strings.CutPrefix(c.Image, b.cfg.Site.BaseURL+"/"); ok && b.cfg.Site.BaseURL != "" {
+		c.ImageSrc = "/" + local
+	}
+
+	switch {
+	case c.Image == "":
+		c.Problems = append(c.Problems, "no image")
+	case strings.HasPrefix(c.ImageSrc, "/"):
+		if _, err := os.Stat(filepath.Join(outDir, filepath.FromSlash(strings.TrimPrefix(c.ImageSrc, "/")))); err != nil {
+			c.Problems = append(c.Problems, "image not in the build")
+		}
+	}
+	switch n := utf8.RuneCountInString(c.Title); {
+	case n == 0:
+		c.Problems = append(c.Problems, "no title")
+	case n > previewTitleMax:
+		c.Problems = append(c.Problems, fmt.Sprintf("title over %d characters (%d)", previewTitleMax, n))
+	}
+	switch n := utf8.RuneCountInString(c.Description); {
+	case n == 0:
+		c.Problems = append(c.Problems, "no description")
+	case n > previewDescriptionMax:
+		c.Problems = append(c.Problems, fmt.Sprintf("description over %d characters (%d)", previewDescriptionMax, n))
+	}
+	return c
+}
@@ -0,0 +1,27 @@
+package build
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/supermodeltools/arch-docs/internal/pssg/render"
+)
+
+// printPath is the root-relative URL of an entity's printer-friendly copy.
+func printPath(slug string) string {
+	return "/" + slug + "-print.html"
+}
+
+// writePrint renders the printer-friendly copy of an entity page from the
+// page's own context. The copy points its canonical URL at the page and
+// stays out of the sitemap.
+func (b *Builder) writePrint(engine *render.Engine, ctx render.EntityPageContext, outDir string) error {
+	page := printPath(ctx.Slug)
+	ctx.Robots = b.robots("print", page)
+	ctx.Languages = nil
+	html, err := engine.RenderPrint(ctx)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(outDir, ctx.Slug+"-print.html"), []byte(html), 0644)
+}
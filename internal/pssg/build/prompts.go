@@ -0,0 +1,49 @@
+package build
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/supermodeltools/arch-docs/internal/pssg/entity"
+	"github.com/supermodeltools/arch-docs/internal/pssg/render"
+)
+
+// promptExport is the JSON variant of a published prompt.
+type promptExport struct {
+	Slug    string `json:"slug"`
+	Title   string `json:"title"`
+	URL     string `json:"url"`
+	Prompt  string `json:"prompt"`
+	ChatGPT string `json:"chatgpt_url"`
+	Claude  string `json:"claude_url"`
+}
+
+// promptURL is where an entity's prompt is published, ext being ".txt"
+// or ".json".
+func (b *Builder) promptURL(slug, ext string) string {
+	return b.cfg.Site.BaseURL + "/" + b.cfg.Prompts.Dir + "/" + slug + ext
+}
+
+// writePrompt publishes an entity's cook-with-AI prompt as text and JSON.
+func (b *Builder) writePrompt(e *entity.Entity, pageURL, prompt, outDir string) error {
+	dir := filepath.Join(outDir, filepath.FromSlash(b.cfg.Prompts.Dir))
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, e.Slug+".txt"), []byte(prompt+"\n"), 0644); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(promptExport{
+		Slug:    e.Slug,
+		Title:   e.GetString("title"),
+		URL:     pageURL,
+		Prompt:  prompt,
+		ChatGPT: render.ChatGPTURL(prompt),
+		Claude:  render.ClaudeURL(prompt),
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, e.Slug+".json"), append(data, '\n'), 0644)
+}
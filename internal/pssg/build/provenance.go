@@ -0,0 +1,91 @@
+package build
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"time"
+
+	"github.com/supermodeltools/arch-docs/internal/pssg/history"
+)
+
+// provenanceFile is written to the output root so a deployed site can be
+// traced back to the inputs that produced it.
+const provenanceFile = ".pssg-build.json"
+
+// provenance is the content of provenanceFile.
+type provenance struct {
+	Generator     string    `json:"generator"`
+	Version       string    `json:"version"`
+	ToolCommit    string    `json:"tool_commit,omitempty"`
+	GoVersion     string    `json:"go_version"`
+	ConfigHash    string    `json:"config_hash"`
+	ContentCommit string    `json:"content_commit,omitempty"`
+	ContentDirty  bool      `json:"content_dirty,omitempty"`
+	Entities      int       `json:"entities"`
+	Started       time.Time `json:"started"`
+	Finished      time.Time `json:"finished"`
+}
+
+// writeProvenance records how the build that started at start was made.
+// The content commit is that of the work tree holding paths.data; it is
+// left out when the data is not in git.
+func (b *Builder) writeProvenance(outDir string, start time.Time) error {
+	p := provenance{
+		Generator: "pssg",
+		GoVersion: runtime.Version(),
+		Entities:  b.entityCount,
+		Started:   start.UTC(),
+	}
+	p.Version, p.ToolCommit = toolVersion()
+	hash, err := configHash(b.cfg.Files)
+	if err != nil {
+		return err
+	}
+	p.ConfigHash = hash
+	if commit, dirty, ok := history.Head(b.cfg.Paths.Data); ok {
+		p.ContentCommit, p.ContentDirty = commit, dirty
+	}
+	p.Finished = time.Now().UTC()
+
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(outDir, provenanceFile), append(data, '\n'), 0644)
+}
+
+// configHash is the SHA-256 of the config file and the files it includes,
+// in load order.
+func configHash(files []string) (string, error) {
+	h := sha256.New()
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			return "", err
+		}
+		h.Write(data)
+	}
+	return "sha256:" + hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// toolVersion returns the version of the pssg binary and the commit it
+// was built from, if it was built from a work tree.
+func toolVersion() (version, commit string) {
+	version = "(devel)"
+	if info, ok := debug.ReadBuildInfo(); ok {
+		if info.Main.Version != "" {
+			version = info.Main.Version
+		}
+		for _, s := range info.Settings {
+			if s.Key == "vcs.revision" {
+				commit = s.Value
+			}
+		}
+	}
+	return version, commit
+}
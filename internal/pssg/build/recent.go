@@ -0,0 +1,150 @@
+package build
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/supermodeltools/arch-docs/internal/pssg/entity"
+	"github.com/supermodeltools/arch-docs/internal/pssg/logging"
+	"github.com/supermodeltools/arch-docs/internal/pssg/render"
+)
+
+// datesFile is where entity dates are kept, inside paths.cache. Language
+// editions keep their own, as entity-dates.<lang>.json.
+const datesFile = "entity-dates.json"
+
+// entityDates is when a build first saw an entity and when its content
+// last changed. Hash is its source hash then.
+type entityDates struct {
+	FirstSeen time.Time `json:"first_seen"`
+	Modified  time.Time `json:"modified"`
+	Hash      string    `json:"hash"`
+}
+
+func (b *Builder) datesPath() string {
+	if b.edition {
+		return filepath.Join(b.cfg.Paths.Cache, "entity-dates."+b.cfg.Site.Language+".json")
+	}
+	return filepath.Join(b.cfg.Paths.Cache, datesFile)
+}
+
+// loadDates dates every entity against the dates the last build saved:
+// an entity it did not know was first seen now, and one whose source
+// hash changed was modified now. Git history, when history.enabled reads
+// it, moves first-seen back to the oldest commit read and modified up to
+// the newest.
+func (b *Builder) loadDates(entities []*entity.Entity, now time.Time) map[string]entityDates {
+	prev := map[string]entityDates{}
+	if data, err := os.ReadFile(b.datesPath()); err == nil {
+		if err := json.Unmarshal(data, &prev); err != nil {
+			logging.Stage("recent").Warn("Ignoring unreadable entity dates", "file", b.datesPath(), "error", err)
+			prev = map[string]entityDates{}
+		}
+	}
+	dates := make(map[string]entityDates, len(entities))
+	for _, e := range entities {
+		d, known := prev[e.Slug]
+		switch {
+		case !known:
+			d = entityDates{FirstSeen: now, Modified: now}
+		case d.Hash != e.SourceHash:
+			d.Modified = now
+		}
+		d.Hash = e.SourceHash
+		if commits := b.changes[e.Slug]; len(commits) > 0 {
+			newest, oldest := commits[0].Date.UTC(), commits[len(commits)-1].Date.UTC()
+			if oldest.Before(d.FirstSeen) {
+				d.FirstSeen = oldest
+			}
+			if !known || newest.After(d.Modified) {
+				d.Modified = newest
+			}
+		}
+		dates[e.Slug] = d
+	}
+	return dates
+}
+
+// saveDates writes the dates of this build's entities, so deleted ones
+// drop out.
+func (b *Builder) saveDates() error {
+	data, err := json.MarshalIndent(b.dates, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(b.cfg.Paths.Cache, 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(b.datesPath(), append(data, '\n'), 0644)
+}
+
+// recentlyAdded returns the entities first seen most recently, at most
+// recent.limit of them.
+func (b *Builder) recentlyAdded(entities []*entity.Entity) []render.RecentEntity {
+	return firstRecent(b.byFirstSeen(entities), b.cfg.Recent.Limit)
+}
+
+// recentlyUpdated returns the entities modified most recently since they
+// were first seen, at most recent.limit of them.
+func (b *Builder) recentlyUpdated(entities []*entity.Entity) []render.RecentEntity {
+	return firstRecent(b.byModified(entities), b.cfg.Recent.Limit)
+}
+
+// byFirstSeen returns the entities by when they were first seen, latest
+// first.
+func (b *Builder) byFirstSeen(entities []*entity.Entity) []render.RecentEntity {
+	return b.recent(entities, func(d entityDates) (time.Time, bool) {
+		return d.FirstSeen, true
+	})
+}
+
+// byModified returns the entities that changed after they were first
+// seen, by when they last did, latest first.
+func (b *Builder) byModified(entities []*entity.Entity) []render.RecentEntity {
+	return b.recent(entities, func(d entityDates) (time.Time, bool) {
+		return d.Modified, d.Modified.After(d.FirstSeen)
+	})
+}
+
+func (b *Builder) recent(entities []*entity.Entity, date func(entityDates) (time.Time, bool)) []render.RecentEntity {
+	if b.dates == nil {
+		return nil
+	}
+	var list []render.RecentEntity
+	for _, e := range entities {
+		d, ok := b.dates[e.Slug]
+		if !ok {
+			continue
+		}
+		if t, ok := date(d); ok {
+			list = append(list, render.RecentEntity{Entity: e, Date: t})
+		}
+	}
+	sort.SliceStable(list, func(i, j int) bool {
+		if !list[i].Date.Equal(list[j].Date) {
+			return list[i].Date.After(list[j].Date)
+		}
+		return list[i].Entity.Slug < list[j].Entity.Slug
+	})
+	return list
+}
+
+// firstRecent returns the first n entries, or all of them when n is 0.
+func firstRecent(list []render.RecentEntity, n int) []render.RecentEntity {
+	if n > 0 && n < len(list) {
+		return list[:n]
+	}
+	return list
+}
+
+// recentEntities drops the dates of a list.
+func recentEntities(list []render.RecentEntity) []*entity.Entity {
+	entities := make([]*entity.Entity, len(list))
+	for i, r := range list {
+		entities[i] = r.Entity
+	}
+	return entities
+}
@@ -0,0 +1,55 @@
+package build
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/supermodeltools/arch-docs/internal/pssg/config"
+)
+
+// robotsFallback maps the page types of later listing pages to the type
+// they default to.
+var robotsFallback = map[string]string{
+	"hub_page_n":          "hub",
+	"all_entities_page_n": "all_entities",
+}
+
+// robots returns the directive robots.pages sets for a page, or "" for
+// the default, and records it for the page's X-Robots-Tag header.
+func (b *Builder) robots(kind, urlPath string) string {
+	d, ok := b.cfg.Robots.Pages[kind]
+	if !ok {
+		d = b.cfg.Robots.Pages[robotsFallback[kind]]
+	}
+	if d != "" {
+		b.robotsPages.Store(urlPath, d)
+	}
+	return d
+}
+
+// noindexed reports whether robots.pages keeps a page out of search
+// indexes, and so out of the sitemap.
+func (b *Builder) noindexed(urlPath string) bool {
+	d, ok := b.robotsPages.Load(urlPath)
+	return ok && strings.Contains(d.(string), "noindex")
+}
+
+// robotsRules turns the directives recorded so far into header rules, by
+// path. A directory index gets a rule for both of its URLs.
+func (b *Builder) robotsRules() []config.HeaderRule {
+	var rules []config.HeaderRule
+	b.robotsPages.Range(func(k, v interface{}) bool {
+		p := k.(string)
+		values := map[string]string{"X-Robots-Tag": v.(string)}
+		rules = append(rules, config.HeaderRule{Path: p, Values: values})
+		switch {
+		case strings.HasSuffix(p, "/"):
+			rules = append(rules, config.HeaderRule{Path: p + "index.html", Values: values})
+		case strings.HasSuffix(p, "/index.html"):
+			rules = append(rules, config.HeaderRule{Path: strings.TrimSuffix(p, "index.html"), Values: values})
+		}
+		return true
+	})
+	sort.Slice(rules, func(i, j int) bool { return rules[i].Path < rules[j].Path })
+	return rules
+}
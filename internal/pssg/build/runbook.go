@@ -0,0 +1,69 @@
+package build
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/supermodeltools/arch-docs/internal/pssg/entity"
+	"github.com/supermodeltools/arch-docs/internal/pssg/render"
+)
+
+// stepsFile is the JSON export of an entity's runbook steps, written next
+// to its page for incident tooling.
+type stepsFile struct {
+	Slug     string        `json:"slug"`
+	Title    string        `json:"title"`
+	URL      string        `json:"url"`
+	Runbooks []runbookJSON `json:"runbooks"`
+}
+
+type runbookJSON struct {
+	Name   string     `json:"name"`
+	Header string     `json:"header"`
+	Steps  []stepJSON `json:"steps"`
+}
+
+type stepJSON struct {
+	ID     string `json:"id"`
+	Text   string `json:"text"`
+	Detail string `json:"detail,omitempty"`
+	Done   bool   `json:"done"`
+	URL    string `json:"url"` // the step's anchor on the entity page
+}
+
+// runbooks returns the entity's runbook sections in config order.
+func (b *Builder) runbooks(e *entity.Entity) []render.Runbook {
+	var out []render.Runbook
+	for _, s := range b.cfg.Data.BodySections {
+		if s.Type != "runbook" {
+			continue
+		}
+		if steps := e.GetSteps(s.Name); len(steps) > 0 {
+			out = append(out, render.Runbook{Name: s.Name, Header: s.Header, Steps: steps})
+		}
+	}
+	return out
+}
+
+// stepsURL is the path of an entity's steps JSON.
+func stepsURL(slug string) string {
+	return "/" + slug + ".steps.json"
+}
+
+// writeSteps writes an entity's runbook steps as JSON.
+func writeSteps(e *entity.Entity, runbooks []render.Runbook, entityURL, outDir string) error {
+	f := stepsFile{Slug: e.Slug, Title: e.GetString("title"), URL: entityURL}
+	for _, rb := range runbooks {
+		rj := runbookJSON{Name: rb.Name, Header: rb.Header}
+		for _, s := range rb.Steps {
+			rj.Steps = append(rj.Steps, stepJSON{ID: s.ID, Text: s.Text, Detail: s.Detail, Done: s.Done, URL: entityURL + "#step-" + s.ID})
+		}
+		f.Runbooks = append(f.Runbooks, rj)
+	}
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(outDir, e.Slug+".steps.json"), append(data, '\n'), 0644)
+}
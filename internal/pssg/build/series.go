@@ -0,0 +1,93 @@
+package build
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/supermodeltools/arch-docs/internal/pssg/logging"
+	"github.com/supermodeltools/arch-docs/internal/pssg/render"
+	"github.com/supermodeltools/arch-docs/internal/pssg/schema"
+	"github.com/supermodeltools/arch-docs/internal/pssg/series"
+	"github.com/supermodeltools/arch-docs/internal/pssg/taxonomy"
+)
+
+// renderSeriesPages writes one index page per series listing its members in order.
+func (b *Builder) renderSeriesPages(
+	engine *render.Engine,
+	schemaGen *schema.Generator,
+	idx *series.Index,
+	allTaxonomies []taxonomy.Taxonomy,
+	outDir string,
+	addSitemapEntry func(string, string, string, string),
+) error {
+	seriesDir := filepath.Join(outDir, b.cfg.Series.Dir)
+	if err := os.MkdirAll(seriesDir, 0755); err != nil {
+		return fmt.Errorf("creating series dir: %w", err)
+	}
+
+	priority := b.cfg.Sitemap.Priorities["series"]
+	if priority == "" {
+		priority = b.cfg.Sitemap.Priorities["hub_page_1"]
+	}
+	changeFreq := b.cfg.Sitemap.ChangeFreqs["series"]
+	if changeFreq == "" {
+		changeFreq = b.cfg.Sitemap.ChangeFreqs["hub"]
+	}
+
+	for _, s := range idx.All {
+		pageURL := b.pageURL(s.URL)
+		description := fmt.Sprintf("%s: a %d-part series on %s.", s.Name, len(s.Entities), b.cfg.Site.Name)
+
+		imageURL, err := b.shareImage(outDir, "hub", []interface{}{b.cfg.Site.Name, s.Name, "Series", len(s.Entities), nil}, func(si render.ShareImages) string {
+			return si.Hub(b.cfg.Site.Name, s.Name, "Series", len(s.Entities), nil)
+		})
+		if err != nil {
+			logging.Stage("render").Warn("Failed to write share SVG", "series", s.Slug, "error", err)
+		}
+
+		var items []schema.ItemListEntry
+		for _, e := range s.Entities {
+			items = append(items, schema.ItemListEntry{
+				Name: e.GetString("title"),
+				URL:  b.pageURL("/" + e.Slug + ".html"),
+			})
+		}
+		listSchema := schemaGen.GenerateItemListSchema(s.Name, description, items, imageURL)
+		breadcrumbs := []render.Breadcrumb{
+			{Name: "Home", URL: b.cfg.Site.BaseURL + "/"},
+			{Name: s.Name, URL: ""},
+		}
+		breadcrumbSchema := schemaGen.GenerateBreadcrumbSchema(toBreadcrumbItems(breadcrumbs))
+		jsonLD := schema.MarshalSchemas(listSchema, breadcrumbSchema)
+
+		ctx := render.SeriesPageContext{
+			Site:          b.cfg.Site,
+			Languages:     b.languageLinks(s.URL),
+			Series:        s,
+			Entities:      s.Entities,
+			JsonLD:        toTemplateHTML(jsonLD),
+			Breadcrumbs:   breadcrumbs,
+			AllTaxonomies: allTaxonomies,
+			OG: render.OGMeta{
+				Title:       s.Name + " \u2014 " + b.cfg.Site.Name,
+				Description: description,
+				URL:         pageURL,
+				ImageURL:    imageURL,
+				Type:        "article",
+				SiteName:    b.cfg.Site.Name,
+			},
+			CTA: b.cfg.Extra.CTA,
+		}
+
+		html, err := engine.RenderSeries(ctx)
+		if err != nil {
+			return fmt.Errorf("rendering series %s: %w", s.Slug, err)
+		}
+		if err := os.WriteFile(filepath.Join(seriesDir, s.Slug+".html"), []byte(html), 0644); err != nil {
+			return fmt.Errorf("writing series page: %w", err)
+		}
+		addSitemapEntry("series", s.URL, priority, changeFreq)
+	}
+	return nil
+}
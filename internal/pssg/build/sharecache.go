@@ -0,0 +1,123 @@
+package build
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/supermodeltools/arch-docs/internal/pssg/render"
+)
+
+// shareCacheFile is where the share image cache is kept, inside
+// paths.cache.
+const shareCacheFile = "share-images.json"
+
+// shareCacheVersion changes when share image keys are computed
+// differently, so entries from older builds are dropped.
+const shareCacheVersion = 1
+
+// shareCache maps the inputs of share images to the files they were drawn
+// to, so a build can skip drawing and writing the images whose inputs
+// have not changed since the last one.
+type shareCache struct {
+	// tool is the pssg version and commit, as part of every key, since a
+	// new version may draw the same inputs differently.
+	tool string
+	mu   sync.Mutex
+	prev map[string]string // input key -> filename, from the last build
+	next map[string]string // the keys this build used
+}
+
+// shareCacheData is the content of shareCacheFile.
+type shareCacheData struct {
+	Version int               `json:"version"`
+	Images  map[string]string `json:"images"`
+}
+
+// loadShareCache reads the cache the last build saved. A missing,
+// unreadable or outdated cache is an empty one, as is any cache when
+// force is set.
+func loadShareCache(file string, force bool) *shareCache {
+	version, commit := toolVersion()
+	c := &shareCache{tool: version + " " + commit, prev: map[string]string{}, next: map[string]string{}}
+	if force {
+		return c
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return c
+	}
+	var d shareCacheData
+	if json.Unmarshal(data, &d) == nil && d.Version == shareCacheVersion && d.Images != nil {
+		c.prev = d.Images
+	}
+	return c
+}
+
+func (c *shareCache) lookup(key string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	filename, ok := c.prev[key]
+	return filename, ok
+}
+
+func (c *shareCache) store(key, filename string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.next[key] = filename
+}
+
+// save writes the keys this build used, so images no page uses any more
+// drop out of the cache.
+func (c *shareCache) save(file string) error {
+	c.mu.Lock()
+	data, err := json.MarshalIndent(shareCacheData{Version: shareCacheVersion, Images: c.next}, "", "  ")
+	c.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(file, append(data, '\n'), 0644)
+}
+
+// shareImage returns the URL of a share image. It calls draw only when
+// no earlier build drew an image from the same kind and inputs into the
+// output directory; otherwise the file from that build is kept, and only
+// its modification time is updated so the output manifest counts it as
+// written by this build. kind and inputs must cover everything draw
+// reads besides the site language.
+func (b *Builder) shareImage(outDir, kind string, inputs []interface{}, draw func(render.ShareImages) string) (string, error) {
+	key, err := json.Marshal(append([]interface{}{b.shares.tool, b.cfg.Site.Language, kind}, inputs...))
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(key)
+	k := hex.EncodeToString(sum[:16])
+
+	if filename, ok := b.shares.lookup(k); ok {
+		file := filepath.Join(outDir, "images", "share", filename)
+		if _, err := os.Stat(file); err == nil {
+			b.shares.store(k, filename)
+			if _, done := b.shareSVGs.LoadOrStore(filename, true); !done {
+				now := time.Now()
+				if err := os.Chtimes(file, now, now); err != nil {
+					return "", err
+				}
+			}
+			return shareImageURL(b.cfg.Site.BaseURL, filename), nil
+		}
+	}
+
+	url, err := b.writeShareSVG(outDir, draw(b.shareImages()))
+	if err == nil {
+		b.shares.store(k, path.Base(url))
+	}
+	return url, err
+}
@@ -0,0 +1,86 @@
+package build
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/supermodeltools/arch-docs/internal/pssg/render"
+	"github.com/supermodeltools/arch-docs/internal/pssg/schema"
+	"github.com/supermodeltools/arch-docs/internal/pssg/source"
+	"github.com/supermodeltools/arch-docs/internal/pssg/taxonomy"
+)
+
+// renderSourcePages writes a highlighted page for every source file an
+// entity names and a listing for every directory.
+func (b *Builder) renderSourcePages(
+	engine *render.Engine,
+	schemaGen *schema.Generator,
+	idx *source.Index,
+	allTaxonomies []taxonomy.Taxonomy,
+	outDir string,
+	addSitemapEntry func(string, string, string, string),
+) error {
+	priority := b.cfg.Sitemap.Priorities["source"]
+	if priority == "" {
+		priority = b.cfg.Sitemap.Priorities["hub_page_n"]
+	}
+	changeFreq := b.cfg.Sitemap.ChangeFreqs["source"]
+	if changeFreq == "" {
+		changeFreq = b.cfg.Sitemap.ChangeFreqs["entity"]
+	}
+
+	write := func(ctx render.SourcePageContext, title, page, url, outPath string) error {
+		breadcrumbs := []render.Breadcrumb{
+			{Name: "Home", URL: b.cfg.Site.BaseURL + "/"},
+			{Name: title, URL: ""},
+		}
+		ctx.Site = b.cfg.Site
+		ctx.Languages = b.languageLinks(page)
+		ctx.Breadcrumbs = breadcrumbs
+		ctx.JsonLD = toTemplateHTML(schema.MarshalSchemas(schemaGen.GenerateBreadcrumbSchema(toBreadcrumbItems(breadcrumbs))))
+		ctx.AllTaxonomies = allTaxonomies
+		ctx.CTA = b.cfg.Extra.CTA
+		ctx.OG = render.OGMeta{
+			Title:       title + " — " + b.cfg.Site.Name,
+			Description: fmt.Sprintf("Source of %s in %s, with the pages that describe it.", title, b.cfg.Site.Name),
+			URL:         url,
+			Type:        "article",
+			SiteName:    b.cfg.Site.Name,
+		}
+		html, err := engine.RenderSource(ctx)
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(filepath.Dir(outPath), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(outPath, []byte(html), 0644); err != nil {
+			return fmt.Errorf("writing %s: %w", outPath, err)
+		}
+		addSitemapEntry("source", page, priority, changeFreq)
+		return nil
+	}
+
+	for _, f := range idx.Files {
+		lines, err := f.Lines()
+		if err != nil {
+			return fmt.Errorf("%s: %w", f.Path, err)
+		}
+		ctx := render.SourcePageContext{File: f, Lines: lines, Entities: f.Entities}
+		if err := write(ctx, f.Path, f.Page, f.URL, f.OutputPath(outDir)); err != nil {
+			return err
+		}
+	}
+	for _, d := range idx.Dirs {
+		title := d.Path + "/"
+		if d.Path == "." {
+			title = "/"
+		}
+		ctx := render.SourcePageContext{Dir: d, Entities: d.Entities}
+		if err := write(ctx, title, d.Page, d.URL, d.OutputPath(outDir)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
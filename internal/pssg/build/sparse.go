@@ -0,0 +1,31 @@
+package build
+
+import (
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+
+	"github.com/supermodeltools/arch-docs/internal/pssg/taxonomy"
+)
+
+// writeSparseRedirects writes a page for each sparse entry of a taxonomy
+// that sends visitors to the taxonomy index, so links to an entry that
+// lost its hub keep working.
+func (b *Builder) writeSparseRedirects(tax taxonomy.Taxonomy, taxDir string) error {
+	target := html.EscapeString(b.pageURL("/" + tax.Name + "/"))
+	for _, entry := range tax.Sparse {
+		page := fmt.Sprintf("<!DOCTYPE html>\n<html>\n<head>\n"+
+			"<meta charset=\"utf-8\">\n"+
+			"<meta name=\"robots\" content=\"noindex\">\n"+
+			"<meta http-equiv=\"refresh\" content=\"0;url=%s\">\n"+
+			"<link rel=\"canonical\" href=\"%s\">\n"+
+			"<title>%s</title>\n"+
+			"</head>\n<body>\n<p><a href=\"%s\">%s</a></p>\n</body>\n</html>\n",
+			target, target, html.EscapeString(entry.Name), target, html.EscapeString(tax.Label))
+		if err := os.WriteFile(filepath.Join(taxDir, entry.Slug+".html"), []byte(page), 0644); err != nil {
+			return fmt.Errorf("writing redirect for %s/%s: %w", tax.Name, entry.Slug, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package build
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/supermodeltools/arch-docs/internal/pssg/config"
+	"github.com/supermodeltools/arch-docs/internal/pssg/logging"
+)
+
+// writeTargets produces each configured output target from the finished
+// build. Targets with a different base URL are rendered again into a
+// scratch directory, since URLs are baked into every page.
+func (b *Builder) writeTargets() error {
+	for _, t := range b.cfg.Output.Targets {
+		src := b.cfg.Paths.Output
+		if t.BaseURL != "" && t.BaseURL != b.cfg.Site.BaseURL {
+			tmp, err := os.MkdirTemp("", "pssg-target-")
+			if err != nil {
+				return fmt.Errorf("target %s: %w", t.Name, err)
+			}
+			defer os.RemoveAll(tmp)
+
+			logging.Stage("targets").Info("Rendering target", "target", t.Name, "base_url", t.BaseURL)
+			cfg := *b.cfg
+			cfg.Site.BaseURL = t.BaseURL
+			cfg.Site.BasePath = config.URLPath(t.BaseURL)
+			cfg.Paths.Output = tmp
+			cfg.Output.Targets = nil
+			nb := NewBuilder(&cfg, b.force)
+			nb.skipManifest = true
+			nb.shares = b.shares
+			nb.featuredSeed = b.featuredSeed
+			if err := nb.Build(); err != nil {
+				return fmt.Errorf("target %s: %w", t.Name, err)
+			}
+			src = tmp
+		}
+
+		var err error
+		switch t.Type {
+		case "tar.gz":
+			err = writeTarGz(src, t.Path)
+		default:
+			err = copyTree(src, t.Path)
+		}
+		if err != nil {
+			return fmt.Errorf("target %s: %w", t.Name, err)
+		}
+		logging.Stage("targets").Info("Wrote target", "target", t.Name, "path", t.Path)
+	}
+	return nil
+}
+
+// copyTree replaces dst with a copy of src.
+func copyTree(src, dst string) error {
+	if err := os.RemoveAll(dst); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dst, 0755); err != nil {
+		return err
+	}
+	return copyDir(src, dst)
+}
+
+// writeTarGz archives the contents of src into a gzipped tarball at path.
+// Entries are relative to src so the archive unpacks into the current
+// directory, as a static host expects.
+func writeTarGz(src, path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	err = filepath.Walk(src, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, p)
+		if err != nil || rel == "." {
+			return err
+		}
+		hdr, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		hdr.Name = filepath.ToSlash(rel)
+		if info.IsDir() {
+			hdr.Name += "/"
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		in, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer in.Close()
+		_, err = io.Copy(tw, in)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+	return f.Close()
+}
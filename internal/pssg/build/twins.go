@@ -0,0 +1,119 @@
+package build
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/supermodeltools/arch-docs/internal/pssg/entity"
+	"github.com/supermodeltools/arch-docs/internal/pssg/taxonomy"
+)
+
+// hubTwin is the JSON twin of a hub: every entity in the entry, unpaged,
+// for apps and widgets that list a category without parsing HTML.
+type hubTwin struct {
+	Taxonomy string      `json:"taxonomy"`
+	Label    string      `json:"label"`
+	Name     string      `json:"name"`
+	Slug     string      `json:"slug"`
+	URL      string      `json:"url"`
+	Count    int         `json:"count"`
+	Entities []twinEntry `json:"entities"`
+}
+
+type twinEntry struct {
+	Slug   string                 `json:"slug"`
+	Title  string                 `json:"title"`
+	URL    string                 `json:"url"`
+	Fields map[string]interface{} `json:"fields,omitempty"`
+}
+
+// letterTwin is the JSON twin of an A-Z letter page.
+type letterTwin struct {
+	Taxonomy string       `json:"taxonomy"`
+	Label    string       `json:"label"`
+	Letter   string       `json:"letter"`
+	URL      string       `json:"url"`
+	Entries  []letterItem `json:"entries"`
+}
+
+type letterItem struct {
+	Name  string `json:"name"`
+	Slug  string `json:"slug"`
+	Count int    `json:"count"`
+	URL   string `json:"url"`
+	JSON  string `json:"json"` // the entry's hub twin
+}
+
+// twinURL is the path of a page's JSON twin: the page path with .json
+// in place of .html.
+func twinURL(pageURL string) string {
+	return strings.TrimSuffix(pageURL, ".html") + ".json"
+}
+
+// writeHubTwin writes the JSON twin of a taxonomy entry's hub.
+func (b *Builder) writeHubTwin(tax taxonomy.Taxonomy, entry taxonomy.Entry, taxDir string) error {
+	t := hubTwin{
+		Taxonomy: tax.Name,
+		Label:    tax.Label,
+		Name:     entry.Name,
+		Slug:     entry.Slug,
+		URL:      b.pageURL(taxonomy.HubPageURL(tax.Name, entry.Slug, 1)),
+		Count:    len(entry.Entities),
+		Entities: make([]twinEntry, 0, len(entry.Entities)),
+	}
+	for _, e := range entry.Entities {
+		t.Entities = append(t.Entities, b.twinEntry(e))
+	}
+	return writeTwin(filepath.Join(taxDir, entry.Slug+".json"), t)
+}
+
+// writeLetterTwin writes the JSON twin of an A-Z letter page.
+func (b *Builder) writeLetterTwin(tax taxonomy.Taxonomy, lg taxonomy.LetterGroup, taxDir string) error {
+	base := b.cfg.Site.BaseURL
+	page := taxonomy.LetterPageURL(tax.Name, lg.Letter)
+	t := letterTwin{
+		Taxonomy: tax.Name,
+		Label:    tax.Label,
+		Letter:   lg.Letter,
+		URL:      b.pageURL(page),
+		Entries:  make([]letterItem, 0, len(lg.Entries)),
+	}
+	for _, entry := range lg.Entries {
+		hub := taxonomy.HubPageURL(tax.Name, entry.Slug, 1)
+		t.Entries = append(t.Entries, letterItem{
+			Name:  entry.Name,
+			Slug:  entry.Slug,
+			Count: len(entry.Entities),
+			URL:   b.pageURL(hub),
+			JSON:  base + twinURL(hub),
+		})
+	}
+	return writeTwin(filepath.Join(taxDir, filepath.Base(twinURL(page))), t)
+}
+
+func (b *Builder) twinEntry(e *entity.Entity) twinEntry {
+	te := twinEntry{
+		Slug:  e.Slug,
+		Title: e.GetString("title"),
+		URL:   b.pageURL("/" + e.Slug + ".html"),
+	}
+	for _, f := range b.cfg.Output.JSONFields {
+		if v, ok := e.Fields[f]; ok {
+			if te.Fields == nil {
+				te.Fields = make(map[string]interface{})
+			}
+			te.Fields[f] = v
+		}
+	}
+	return te
+}
+
+func writeTwin(path string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
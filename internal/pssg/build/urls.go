@@ -0,0 +1,35 @@
+package build
+
+import (
+	"github.com/supermodeltools/arch-docs/internal/pssg/output"
+	"github.com/supermodeltools/arch-docs/internal/pssg/taxonomy"
+)
+
+// link returns the root-relative URL of the page at p, in the form urls
+// sets.
+func (b *Builder) link(p string) string {
+	return output.CanonicalPath(b.cfg.URLs, p)
+}
+
+// pageURL returns the absolute URL of the page at p, in the form urls
+// sets.
+func (b *Builder) pageURL(p string) string {
+	return b.cfg.Site.BaseURL + b.link(p)
+}
+
+// paginationLinks puts the links of a listing's pagination in the form
+// urls sets.
+func (b *Builder) paginationLinks(p taxonomy.PaginationInfo) taxonomy.PaginationInfo {
+	if p.PrevURL != "" {
+		p.PrevURL = b.link(p.PrevURL)
+	}
+	if p.NextURL != "" {
+		p.NextURL = b.link(p.NextURL)
+	}
+	urls := make([]taxonomy.PageURL, len(p.PageURLs))
+	for i, u := range p.PageURLs {
+		urls[i] = taxonomy.PageURL{Number: u.Number, URL: b.link(u.URL)}
+	}
+	p.PageURLs = urls
+	return p
+}
@@ -0,0 +1,27 @@
+package build
+
+import (
+	"encoding/json"
+	"log/slog"
+	"os"
+
+	"github.com/supermodeltools/arch-docs/internal/pssg/logging"
+)
+
+// reportWarnings writes the build's warnings to output.warnings, an empty
+// list when there were none, and logs how many there were.
+func (b *Builder) reportWarnings(warnings []logging.Warning) {
+	if warnings == nil {
+		warnings = []logging.Warning{}
+	}
+	data, err := json.MarshalIndent(warnings, "", "  ")
+	if err == nil {
+		err = os.WriteFile(b.cfg.Output.Warnings, append(data, '\n'), 0644)
+	}
+	if err != nil {
+		slog.Warn("Failed to write build warnings", "file", b.cfg.Output.Warnings, "error", err)
+	}
+	if len(warnings) > 0 {
+		slog.Warn("Build logged warnings", "count", len(warnings), "file", b.cfg.Output.Warnings)
+	}
+}
@@ -0,0 +1,231 @@
+// Package c4 arranges entities into a C4 model: systems in their context,
+// the containers inside each system, the components inside each
+// container and the code inside each component. Every element with
+// children gets a view page drawing them, so readers drill down one level
+// at a time.
+package c4
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/supermodeltools/arch-docs/internal/pssg/config"
+	"github.com/supermodeltools/arch-docs/internal/pssg/entity"
+	"github.com/supermodeltools/arch-docs/internal/pssg/relation"
+)
+
+// Levels are the C4 abstraction levels, outermost first.
+var Levels = []string{"context", "container", "component", "code"}
+
+// Element is an entity placed in the model.
+type Element struct {
+	Entity   *entity.Entity
+	Level    string
+	Depth    int // index into Levels
+	Parent   *Element
+	Children []*Element
+	URL      string // root-relative URL of the element's view; "" without children
+	External bool   // outside the system being documented
+	Tech     string
+}
+
+// Title is the entity's title.
+func (el *Element) Title() string { return el.Entity.GetString("title") }
+
+// Ancestors lists the element's parents, outermost first.
+func (el *Element) Ancestors() []*Element {
+	var list []*Element
+	for p := el.Parent; p != nil; p = p.Parent {
+		list = append([]*Element{p}, list...)
+	}
+	return list
+}
+
+// View is one diagram: the elements at one level inside a scope, with the
+// elements outside it they relate to.
+type View struct {
+	Scope    *Element // nil for the system context view
+	Level    string   // level of the elements drawn
+	Elements []*Element
+	Related  []*Element // outside the scope, drawn as neighbors
+	Rels     []Rel
+}
+
+// Rel is a relationship drawn in a view, merging every relation between
+// the two elements or their descendants.
+type Rel struct {
+	From, To *Element
+	Labels   []string
+}
+
+// Model is the C4 model of a site.
+type Model struct {
+	Context  *View
+	Views    []*View // one per element with children, in model order
+	elements map[string]*Element
+	edges    []relation.Edge
+	cfg      config.C4Config
+}
+
+// Element returns the model element for an entity, or nil.
+func (m *Model) Element(slug string) *Element { return m.elements[slug] }
+
+// Build places every entity with a valid level field into the model.
+// Parents are resolved like relation targets, by slug or title; an
+// element whose parent is missing or not at an outer level is placed at
+// the top of its level and reported in the returned warnings.
+func Build(entities []*entity.Entity, slugMap map[string]*entity.Entity, edges []relation.Edge, cfg config.C4Config) (*Model, []string) {
+	m := &Model{elements: make(map[string]*Element), edges: edges, cfg: cfg}
+	var warnings []string
+	var ordered []*Element
+	for _, e := range entities {
+		level := strings.ToLower(strings.TrimSpace(e.GetString(cfg.Field)))
+		if level == "" {
+			continue
+		}
+		depth := levelDepth(level)
+		if depth < 0 {
+			warnings = append(warnings, fmt.Sprintf("%s: unknown %s %q (want %s)", e.Slug, cfg.Field, level, strings.Join(Levels, ", ")))
+			continue
+		}
+		el := &Element{Entity: e, Level: Levels[depth], Depth: depth, Tech: e.GetString(cfg.TechField), External: e.GetBool(cfg.ExternalField)}
+		m.elements[e.Slug] = el
+		ordered = append(ordered, el)
+	}
+
+	for _, el := range ordered {
+		ref := el.Entity.GetString(cfg.ParentField)
+		if ref == "" {
+			continue
+		}
+		pe, ok := relation.Resolve(slugMap, ref)
+		var parent *Element
+		if ok {
+			parent = m.elements[pe.Slug]
+		}
+		switch {
+		case !ok:
+			warnings = append(warnings, fmt.Sprintf("%s: %s %q not found", el.Entity.Slug, cfg.ParentField, ref))
+		case parent == nil:
+			warnings = append(warnings, fmt.Sprintf("%s: %s %q has no %s", el.Entity.Slug, cfg.ParentField, ref, cfg.Field))
+		case parent.Depth >= el.Depth:
+			warnings = append(warnings, fmt.Sprintf("%s: %s %q is a %s, not outside a %s", el.Entity.Slug, cfg.ParentField, ref, parent.Level, el.Level))
+		default:
+			el.Parent = parent
+			parent.Children = append(parent.Children, el)
+		}
+	}
+
+	// The context view holds every element without a parent: systems, and
+	// any deeper element whose parent is missing, so it stays reachable.
+	var top []*Element
+	for _, el := range ordered {
+		if el.Parent == nil {
+			top = append(top, el)
+		}
+	}
+	m.Context = m.view(nil, top)
+	m.Context.Level = Levels[0]
+	for _, el := range ordered {
+		if len(el.Children) == 0 {
+			continue
+		}
+		el.URL = fmt.Sprintf("/%s/%s.html", cfg.Dir, el.Entity.Slug)
+		m.Views = append(m.Views, m.view(el, el.Children))
+	}
+	return m, warnings
+}
+
+// view draws members and the relationships that touch them. Relations
+// between descendants are lifted to the members containing them, and
+// relations leaving the scope to the outside element that sits beside the
+// scope, e.g. the other container a component calls into.
+func (m *Model) view(scope *Element, members []*Element) *View {
+	v := &View{Scope: scope, Elements: members}
+	if len(members) > 0 {
+		v.Level = members[0].Level
+	}
+	in := make(map[*Element]bool, len(members))
+	for _, el := range members {
+		in[el] = true
+	}
+
+	lift := func(el *Element) (*Element, bool) {
+		for a := el; a != nil; a = a.Parent {
+			if in[a] {
+				return a, true
+			}
+		}
+		out := el
+		for out.Parent != nil && !isWithin(scope, out.Parent) {
+			out = out.Parent
+		}
+		if isWithin(scope, out) {
+			return nil, false // the scope itself or one of its ancestors
+		}
+		return out, false
+	}
+
+	rels := make(map[[2]*Element]*Rel)
+	var order [][2]*Element
+	related := make(map[*Element]bool)
+	for _, e := range m.edges {
+		from, to := m.elements[e.Source], m.elements[e.Target]
+		if from == nil || to == nil {
+			continue
+		}
+		f, fin := lift(from)
+		t, tin := lift(to)
+		if f == nil || t == nil || f == t || (!fin && !tin) {
+			continue
+		}
+		if !fin {
+			related[f] = true
+		}
+		if !tin {
+			related[t] = true
+		}
+		key := [2]*Element{f, t}
+		r, ok := rels[key]
+		if !ok {
+			r = &Rel{From: f, To: t}
+			rels[key] = r
+			order = append(order, key)
+		}
+		if !slices.Contains(r.Labels, e.Type) {
+			r.Labels = append(r.Labels, e.Type)
+		}
+	}
+	for _, key := range order {
+		v.Rels = append(v.Rels, *rels[key])
+	}
+	for el := range related {
+		v.Related = append(v.Related, el)
+	}
+	sort.Slice(v.Related, func(i, j int) bool { return v.Related[i].Entity.Slug < v.Related[j].Entity.Slug })
+	return v
+}
+
+// isWithin reports whether el is scope or one of scope's ancestors.
+func isWithin(scope, el *Element) bool {
+	for a := scope; a != nil; a = a.Parent {
+		if a == el {
+			return true
+		}
+	}
+	return false
+}
+
+func levelDepth(level string) int {
+	if level == "system" {
+		return 0
+	}
+	for i, l := range Levels {
+		if l == level {
+			return i
+		}
+	}
+	return -1
+}
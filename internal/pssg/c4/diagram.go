@@ -0,0 +1,90 @@
+package c4
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/supermodeltools/arch-docs/internal/pssg/text"
+)
+
+// descriptionLimit shortens descriptions inside diagram boxes.
+const descriptionLimit = 90
+
+// classDefs colors elements by level, as C4 diagrams conventionally do.
+var classDefs = []string{
+	"classDef context fill:#1e3a8a,stroke:#3b82f6,color:#ffffff",
+	"classDef container fill:#1d4ed8,stroke:#60a5fa,color:#ffffff",
+	"classDef component fill:#2563eb,stroke:#93c5fd,color:#ffffff",
+	"classDef code fill:#3b82f6,stroke:#bfdbfe,color:#ffffff",
+	"classDef external fill:#374151,stroke:#6b7280,color:#e5e7eb",
+}
+
+// Mermaid returns the view as a Mermaid flowchart: the scope as a
+// boundary around its elements, outside elements beside it, and one
+// labelled arrow per relationship.
+func (v *View) Mermaid() string {
+	var b strings.Builder
+	b.WriteString("flowchart TB\n")
+	for _, def := range classDefs {
+		b.WriteString("  " + def + "\n")
+	}
+
+	ids := make(map[*Element]string)
+	node := func(el *Element, indent string) {
+		id := fmt.Sprintf("n%d", len(ids))
+		ids[el] = id
+		class := el.Level
+		if el.External {
+			class = "external"
+		}
+		fmt.Fprintf(&b, "%s%s[\"%s\"]:::%s\n", indent, id, label(el), class)
+	}
+
+	if v.Scope != nil {
+		fmt.Fprintf(&b, "  subgraph scope[\"%s\"]\n", escape(v.Scope.Title()+" ["+kindName(v.Scope.Level)+"]"))
+		for _, el := range v.Elements {
+			node(el, "    ")
+		}
+		b.WriteString("  end\n")
+		b.WriteString("  style scope fill:transparent,stroke:#6b7280,stroke-dasharray:6 4\n")
+	} else {
+		for _, el := range v.Elements {
+			node(el, "  ")
+		}
+	}
+	for _, el := range v.Related {
+		node(el, "  ")
+	}
+	for _, r := range v.Rels {
+		fmt.Fprintf(&b, "  %s -->|\"%s\"| %s\n", ids[r.From], escape(strings.Join(r.Labels, ", ")), ids[r.To])
+	}
+	return b.String()
+}
+
+// label is a C4 box: the name in bold, the level and technology, and a
+// short description.
+func label(el *Element) string {
+	kind := kindName(el.Level)
+	if el.Tech != "" {
+		kind += ": " + el.Tech
+	}
+	s := "<b>" + escape(el.Title()) + "</b><br/>[" + escape(kind) + "]"
+	if desc := el.Entity.GetString("description"); desc != "" {
+		s += "<br/>" + escape(text.Truncate(desc, descriptionLimit))
+	}
+	return s
+}
+
+// kindName names an element's level the way C4 boxes do.
+func kindName(level string) string {
+	if level == "context" {
+		return "System"
+	}
+	return strings.ToUpper(level[:1]) + level[1:]
+}
+
+// escape makes text safe inside a quoted Mermaid label, which takes
+// entity codes for characters that would end it or open markup.
+func escape(s string) string {
+	return strings.NewReplacer(`"`, "#quot;", "<", "#lt;", ">", "#gt;").Replace(s)
+}
@@ -0,0 +1,219 @@
+// Package charts builds the data behind the charts on listing pages. A
+// page's charts are a Set: a schema version and a list of typed charts,
+// which the theme's script draws by type. The JSON is the contract with
+// that script, so fields are only ever added to it; a change that would
+// break a script bumps Schema instead.
+package charts
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/supermodeltools/arch-docs/internal/pssg/config"
+	"github.com/supermodeltools/arch-docs/internal/pssg/entity"
+	"github.com/supermodeltools/arch-docs/internal/pssg/taxonomy"
+)
+
+// Schema is the version of the JSON a Set marshals to.
+const Schema = 1
+
+// Chart types.
+const (
+	Bar      = "bar"      // Items, one bar each, largest first
+	Treemap  = "treemap"  // Items as cells, each holding its Children
+	Timeline = "timeline" // Points, one per month, oldest first
+)
+
+// Set is the charts of one page.
+type Set struct {
+	Schema int     `json:"schema"`
+	Total  int     `json:"total"` // entities the page covers
+	Charts []Chart `json:"charts"`
+}
+
+// Chart is one chart. Name is the chart's name in charts.pages, such as
+// "types", so scripts can tell two charts of the same type apart.
+type Chart struct {
+	Name   string  `json:"name"`
+	Type   string  `json:"type"`
+	Title  string  `json:"title,omitempty"`
+	Items  []Item  `json:"items,omitempty"`
+	Points []Point `json:"points,omitempty"`
+}
+
+// Item is a bar or a treemap cell.
+type Item struct {
+	Name     string `json:"name"`
+	Count    int    `json:"count"`
+	URL      string `json:"url,omitempty"`
+	Children []Item `json:"children,omitempty"`
+}
+
+// Point is a timeline's count for a month, as "2006-01".
+type Point struct {
+	Date  string `json:"date"`
+	Count int    `json:"count"`
+}
+
+// Source is what a page offers its charts. Entities are all the page
+// covers, not one page's worth; Taxonomy and Entries are set on taxonomy
+// index, hub and letter pages.
+type Source struct {
+	Entities   []*entity.Entity
+	Taxonomies []taxonomy.Taxonomy
+	Taxonomy   *taxonomy.Taxonomy
+	Entries    []taxonomy.Entry
+	Config     config.ChartsConfig
+}
+
+// Builder makes a chart from a page's data. It reports false when the
+// page has nothing to chart.
+type Builder func(Source) (Chart, bool)
+
+var builders = map[string]Builder{
+	"types":      types,
+	"terms":      terms,
+	"taxonomies": taxonomies,
+	"timeline":   timeline,
+}
+
+// Register adds a chart that charts.pages can name, or replaces one.
+func Register(name string, b Builder) {
+	builders[name] = b
+}
+
+// Names lists the charts charts.pages can name.
+func Names() []string {
+	names := make([]string, 0, len(builders))
+	for name := range builders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Build makes the charts charts.pages lists for a kind of page, leaving
+// out those the page has no data for.
+func Build(page string, src Source) (Set, error) {
+	set := Set{Schema: Schema, Total: len(src.Entities), Charts: []Chart{}}
+	for _, name := range src.Config.Pages[page] {
+		b, ok := builders[name]
+		if !ok {
+			return set, fmt.Errorf("charts.pages.%s: unknown chart %q", page, name)
+		}
+		if c, ok := b(src); ok {
+			c.Name = name
+			set.Charts = append(set.Charts, c)
+		}
+	}
+	return set, nil
+}
+
+// types is a bar per value of type_field, linked to its hub when a
+// taxonomy is built on the field.
+func types(src Source) (Chart, bool) {
+	field := src.Config.TypeField
+	counts := make(map[string]int)
+	for _, e := range src.Entities {
+		if v := e.GetString(field); v != "" {
+			counts[v]++
+		}
+	}
+	var hubs *taxonomy.Taxonomy
+	for i := range src.Taxonomies {
+		if src.Taxonomies[i].Config.Field == field {
+			hubs = &src.Taxonomies[i]
+			break
+		}
+	}
+	items := make([]Item, 0, len(counts))
+	for name, n := range counts {
+		item := Item{Name: name, Count: n}
+		if hubs != nil && hubs.FindEntry(entity.ToSlug(name)) != nil {
+			item.URL = taxonomy.HubPageURL(hubs.Name, entity.ToSlug(name), 1)
+		}
+		items = append(items, item)
+	}
+	items = top(items, src.Config.Limit)
+	return Chart{Type: Bar, Items: items}, len(items) > 0
+}
+
+// terms is a bar per taxonomy entry, linked to its hub.
+func terms(src Source) (Chart, bool) {
+	if src.Taxonomy == nil {
+		return Chart{}, false
+	}
+	items := make([]Item, 0, len(src.Entries))
+	for _, entry := range src.Entries {
+		items = append(items, Item{
+			Name:  entry.Name,
+			Count: len(entry.Entities),
+			URL:   taxonomy.HubPageURL(src.Taxonomy.Name, entry.Slug, 1),
+		})
+	}
+	items = top(items, src.Config.Limit)
+	return Chart{Type: Bar, Title: src.Taxonomy.Label, Items: items}, len(items) > 0
+}
+
+// taxonomies is a treemap cell per taxonomy, holding its largest entries.
+func taxonomies(src Source) (Chart, bool) {
+	var items []Item
+	for _, tax := range src.Taxonomies {
+		cell := Item{Name: tax.Label, URL: "/" + tax.Name + "/index.html"}
+		for _, entry := range taxonomy.TopEntries(tax.Entries, src.Config.Limit) {
+			cell.Children = append(cell.Children, Item{
+				Name:  entry.Name,
+				Count: len(entry.Entities),
+				URL:   taxonomy.HubPageURL(tax.Name, entry.Slug, 1),
+			})
+			cell.Count += len(entry.Entities)
+		}
+		if cell.Count > 0 {
+			items = append(items, cell)
+		}
+	}
+	return Chart{Type: Treemap, Items: items}, len(items) > 0
+}
+
+// timeline counts entities per month of date_field.
+func timeline(src Source) (Chart, bool) {
+	counts := make(map[string]int)
+	for _, e := range src.Entities {
+		if t := e.GetTime(src.Config.DateField); !t.IsZero() {
+			counts[t.Format("2006-01")]++
+		}
+	}
+	if len(counts) == 0 {
+		return Chart{}, false
+	}
+	var months []string
+	for m := range counts {
+		months = append(months, m)
+	}
+	sort.Strings(months)
+	// Fill the months in between, so the x axis is even
+	var points []Point
+	first, _ := time.Parse("2006-01", months[0])
+	last, _ := time.Parse("2006-01", months[len(months)-1])
+	for t := first; !t.After(last); t = t.AddDate(0, 1, 0) {
+		m := t.Format("2006-01")
+		points = append(points, Point{Date: m, Count: counts[m]})
+	}
+	return Chart{Type: Timeline, Points: points}, true
+}
+
+// top sorts items largest first, by name on a tie, and keeps the first n,
+// or all of them when n is 0.
+func top(items []Item, n int) []Item {
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Count != items[j].Count {
+			return items[i].Count > items[j].Count
+		}
+		return items[i].Name < items[j].Name
+	})
+	if n > 0 && len(items) > n {
+		items = items[:n]
+	}
+	return items
+}
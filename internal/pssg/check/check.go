@@ -0,0 +1,318 @@
+package check
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+
+	"github.com/supermodeltools/arch-docs/internal/pssg/adr"
+	"github.com/supermodeltools/arch-docs/internal/pssg/collection"
+	"github.com/supermodeltools/arch-docs/internal/pssg/config"
+	"github.com/supermodeltools/arch-docs/internal/pssg/contributors"
+	"github.com/supermodeltools/arch-docs/internal/pssg/entity"
+	"github.com/supermodeltools/arch-docs/internal/pssg/theme"
+)
+
+// Severity classifies a diagnostic.
+type Severity int
+
+const (
+	Warning Severity = iota
+	Error
+)
+
+func (s Severity) String() string {
+	if s == Error {
+		return "error"
+	}
+	return "warning"
+}
+
+// Diagnostic is a single finding, pointing at the config key responsible.
+type Diagnostic struct {
+	Severity Severity
+	Pos      config.Position
+	Key      string
+	Message  string
+	Hint     string // suggested fix, if any
+}
+
+func (d Diagnostic) String() string {
+	if d.Key == "" {
+		return fmt.Sprintf("%s: %s: %s", d.Pos, d.Severity, d.Message)
+	}
+	return fmt.Sprintf("%s: %s: %s: %s", d.Pos, d.Severity, d.Key, d.Message)
+}
+
+// MarshalJSON flattens the position so scripts get one object per finding.
+func (d Diagnostic) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Severity string `json:"severity"`
+		File     string `json:"file"`
+		Line     int    `json:"line,omitempty"`
+		Key      string `json:"key,omitempty"`
+		Message  string `json:"message"`
+		Hint     string `json:"hint,omitempty"`
+	}{d.Severity.String(), d.Pos.File, d.Pos.Line, d.Key, d.Message, d.Hint})
+}
+
+// Errors counts the diagnostics with Error severity.
+func Errors(diags []Diagnostic) int {
+	n := 0
+	for _, d := range diags {
+		if d.Severity == Error {
+			n++
+		}
+	}
+	return n
+}
+
+// Sitemap keys the builder looks up; anything else is a typo.
+var (
+	knownPriorityKeys   = []string{"homepage", "entity", "taxonomy_index", "hub_page_1", "hub_page_n", "letter_page", "series"}
+	knownChangeFreqKeys = []string{"homepage", "entity", "taxonomy_index", "hub", "letter_page", "series"}
+	validChangeFreqs    = []string{"always", "hourly", "daily", "weekly", "monthly", "yearly", "never"}
+)
+
+type checker struct {
+	cfg   *config.Config
+	diags []Diagnostic
+}
+
+func (c *checker) report(sev Severity, key, format string, args ...interface{}) {
+	c.diags = append(c.diags, Diagnostic{
+		Severity: sev,
+		Pos:      c.cfg.Pos(key),
+		Key:      key,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// Config validates a loaded config against the filesystem, the environment
+// and the loaded entities. Diagnostics are sorted by file and line.
+func Config(cfg *config.Config, entities []*entity.Entity) []Diagnostic {
+	c := &checker{cfg: cfg}
+	c.paths()
+	c.templates()
+	c.taxonomyFields(entities)
+	c.sitemap()
+	c.affiliateEnv()
+	return c.sorted()
+}
+
+// sorted returns the diagnostics ordered by file and line.
+func (c *checker) sorted() []Diagnostic {
+	sort.SliceStable(c.diags, func(i, j int) bool {
+		a, b := c.diags[i].Pos, c.diags[j].Pos
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		return a.Line < b.Line
+	})
+	return c.diags
+}
+
+func (c *checker) paths() {
+	dirs := []struct {
+		key, path string
+		required  bool
+	}{
+		{"paths.data", c.cfg.Paths.Data, true},
+		{"paths.templates", c.cfg.Paths.Templates, true},
+		{"paths.static", c.cfg.Paths.Static, false},
+		{"enrichment.cache_dir", c.cfg.Enrichment.CacheDir, false},
+		{"paths.source_dir", c.cfg.Paths.SourceDir, true},
+	}
+	if c.cfg.Theme != "" {
+		// The theme supplies the templates; a site directory is optional.
+		dirs[1].required = false
+		if _, err := os.Stat(dirs[1].path); os.IsNotExist(err) {
+			dirs[1].path = ""
+		}
+	}
+	for _, d := range dirs {
+		if d.path == "" {
+			continue
+		}
+		info, err := os.Stat(d.path)
+		switch {
+		case err != nil && (d.required || !os.IsNotExist(err)):
+			c.report(Error, d.key, "%s: %v", d.path, err)
+		case err != nil:
+			c.report(Warning, d.key, "%s does not exist", d.path)
+		case !info.IsDir():
+			c.report(Error, d.key, "%s is not a directory", d.path)
+		}
+	}
+
+	files := []struct{ key, path string }{
+		{"extra.favorites", c.cfg.Extra.Favorites},
+		{"extra.contributors", c.cfg.Extra.Contributors},
+		{"extra.collections", c.cfg.Extra.Collections},
+	}
+	for _, f := range files {
+		if f.path == "" {
+			continue
+		}
+		if _, err := os.Stat(f.path); err != nil {
+			c.report(Error, f.key, "%s: %v", f.path, err)
+			continue
+		}
+		switch f.key {
+		case "extra.contributors":
+			if _, err := contributors.Load(f.path); err != nil {
+				c.report(Error, f.key, "%v", err)
+			}
+		case "extra.collections":
+			if _, err := collection.Load(f.path, nil); err != nil {
+				c.report(Error, f.key, "%v", err)
+			}
+		}
+	}
+
+	if parent := filepath.Dir(c.cfg.Paths.Output); parent != "" {
+		if _, err := os.Stat(parent); err != nil {
+			c.report(Error, "paths.output", "parent directory %s: %v", parent, err)
+		}
+	}
+}
+
+func (c *checker) templates() {
+	th, err := theme.Open(c.cfg)
+	if err != nil {
+		c.report(Error, "theme", "%v", err)
+		return
+	}
+	if _, err := os.Stat(c.cfg.Paths.Templates); err != nil && th == nil {
+		return // already reported by paths()
+	}
+	need := func(key, name string) {
+		if name == "" || theme.HasTemplate(th, name) {
+			return
+		}
+		if _, err := os.Stat(filepath.Join(c.cfg.Paths.Templates, name)); err != nil {
+			c.report(Error, key, "template %s not found in %s", name, c.cfg.Paths.Templates)
+		}
+	}
+
+	t := c.cfg.Templates
+	need("templates.entity", t.Entity)
+	need("templates.homepage", t.Homepage)
+	need("templates.hub", t.Hub)
+	need("templates.taxonomy_index", t.TaxonomyIndex)
+	need("templates.letter", t.Letter)
+	need("templates", "all_entities.html")
+	for path, name := range t.StaticPages {
+		need("templates.static_pages."+path, name)
+	}
+	for i, tax := range c.cfg.Taxonomies {
+		prefix := "taxonomies." + strconv.Itoa(i)
+		need(prefix+".template", tax.Template)
+		need(prefix+".index_template", tax.IndexTemplate)
+		need(prefix+".letter_template", tax.LetterTemplate)
+	}
+	need("affiliates.disclosure.template", c.cfg.Affiliates.Disclosure.Template)
+	if c.cfg.Paths.SourceDir != "" {
+		need("source.template", c.cfg.Source.Template)
+	}
+	if c.cfg.Graph.Enabled {
+		need("graph.template", c.cfg.Graph.Template)
+	}
+	if c.cfg.ADR.Enabled {
+		need("adr.template", c.cfg.ADR.Template)
+	}
+	if c.cfg.C4.Enabled {
+		need("c4.template", c.cfg.C4.Template)
+	}
+	if c.cfg.Freshness.Enabled {
+		need("freshness.template", c.cfg.Freshness.Template)
+	}
+	if c.cfg.Previews.Enabled {
+		need("previews.template", c.cfg.Previews.Template)
+	}
+	need("prompts.template", c.cfg.Prompts.Template)
+	if c.cfg.Contributors.Enabled {
+		need("contributors.template", c.cfg.Contributors.Template)
+	}
+	if c.cfg.Extra.Collections != "" {
+		need("collections.template", c.cfg.Collections.Template)
+	}
+}
+
+func (c *checker) taxonomyFields(entities []*entity.Entity) {
+	if len(entities) == 0 {
+		return
+	}
+	for i, tax := range c.cfg.Taxonomies {
+		// Values from enrichment, or fields the build sets, are not on
+		// the loaded entities.
+		if tax.EnrichmentOverrideField != "" ||
+			c.cfg.ADR.Enabled && tax.Field == adr.StatusField ||
+			c.cfg.Owners.Enabled && tax.Field == c.cfg.Owners.Field {
+			continue
+		}
+		found := false
+		for _, e := range entities {
+			if e.HasField(tax.Field) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			c.report(Warning, "taxonomies."+strconv.Itoa(i)+".field",
+				"field %q (taxonomy %s) is not set on any of %d entities", tax.Field, tax.Name, len(entities))
+		}
+	}
+}
+
+func (c *checker) sitemap() {
+	for _, key := range sortedKeys(c.cfg.Sitemap.Priorities) {
+		if !slices.Contains(knownPriorityKeys, key) {
+			c.report(Warning, "sitemap.priorities."+key, "unknown page kind %q (known: %v)", key, knownPriorityKeys)
+		}
+		v := c.cfg.Sitemap.Priorities[key]
+		if f, err := strconv.ParseFloat(v, 64); err != nil || f < 0 || f > 1 {
+			c.report(Error, "sitemap.priorities."+key, "priority %q must be a number between 0.0 and 1.0", v)
+		}
+	}
+	for _, key := range sortedKeys(c.cfg.Sitemap.ChangeFreqs) {
+		if !slices.Contains(knownChangeFreqKeys, key) {
+			c.report(Warning, "sitemap.change_freqs."+key, "unknown page kind %q (known: %v)", key, knownChangeFreqKeys)
+		}
+		if v := c.cfg.Sitemap.ChangeFreqs[key]; !slices.Contains(validChangeFreqs, v) {
+			c.report(Error, "sitemap.change_freqs."+key, "change frequency %q must be one of %v", v, validChangeFreqs)
+		}
+	}
+}
+
+func (c *checker) affiliateEnv() {
+	sev := Warning
+	if c.cfg.Affiliates.FailOnMissing {
+		sev = Error
+	}
+	for i, p := range c.cfg.Affiliates.Providers {
+		prefix := "affiliates.providers." + strconv.Itoa(i)
+		if p.EnvVar != "" && os.Getenv(p.EnvVar) == "" {
+			c.report(sev, prefix+".env_var", "provider %s: env var %s is not set", p.Name, p.EnvVar)
+		}
+		for j, v := range p.Variants {
+			if v.EnvVar != "" && os.Getenv(v.EnvVar) == "" {
+				c.report(sev, fmt.Sprintf("%s.variants.%d.env_var", prefix, j),
+					"provider %s (%s): env var %s is not set", p.Name, v.Region, v.EnvVar)
+			}
+		}
+	}
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,173 @@
+package check
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/supermodeltools/arch-docs/internal/pssg/config"
+	"github.com/supermodeltools/arch-docs/internal/pssg/entity"
+	"github.com/supermodeltools/arch-docs/internal/pssg/relation"
+	"github.com/supermodeltools/arch-docs/internal/pssg/render"
+	"github.com/supermodeltools/arch-docs/internal/pssg/sibling"
+)
+
+// minGoVersion is the oldest toolchain the module supports (see go.mod).
+const minGoVersion = "go1.25"
+
+// Environment checks the toolchain, that the templates parse and that the
+// cache directories are usable.
+func Environment(cfg *config.Config) []Diagnostic {
+	c := &checker{cfg: cfg}
+
+	if v := runtime.Version(); strings.HasPrefix(v, "go") && versionLess(v, minGoVersion) {
+		c.hint(Warning, "", "build with "+minGoVersion+" or newer",
+			"pssg was built with %s, older than the supported %s", v, minGoVersion)
+	}
+
+	if _, err := render.NewEngine(cfg); err != nil {
+		c.hint(Error, "paths.templates", "fix the template syntax, or remove the file if it is unused", "%v", err)
+	}
+
+	caches := []struct{ key, dir string }{
+		{"paths.cache", cfg.Paths.Cache},
+		{"enrichment.cache_dir", cfg.Enrichment.CacheDir},
+	}
+	for _, cd := range caches {
+		if cd.dir == "" {
+			continue
+		}
+		info, err := os.Stat(cd.dir)
+		if os.IsNotExist(err) {
+			continue // created on first use
+		}
+		if err == nil && !info.IsDir() {
+			err = fmt.Errorf("not a directory")
+		}
+		if err == nil {
+			_, err = os.ReadDir(cd.dir)
+		}
+		if err == nil {
+			var f *os.File
+			if f, err = os.CreateTemp(cd.dir, ".pssg-doctor-"); err == nil {
+				f.Close()
+				os.Remove(f.Name())
+			}
+		}
+		if err != nil {
+			c.hint(Error, cd.key, "check the directory's permissions, or point "+cd.key+" elsewhere",
+				"%s is not readable and writable: %v", cd.dir, err)
+		}
+	}
+	return c.sorted()
+}
+
+// Content checks entities for missing titles and descriptions, duplicate
+// titles and relation targets (including pairings) that do not resolve.
+// Diagnostics point at the entity's source file.
+func Content(cfg *config.Config, entities []*entity.Entity) []Diagnostic {
+	c := &checker{cfg: cfg}
+
+	slugMap := make(map[string]*entity.Entity, len(entities))
+	titles := make(map[string][]*entity.Entity)
+	for _, e := range entities {
+		slugMap[e.Slug] = e
+		title := strings.TrimSpace(e.GetString("title"))
+		if title == "" {
+			c.entity(Error, e, "title", "add a `title:` to the frontmatter", "missing title")
+		} else {
+			key := strings.ToLower(title)
+			titles[key] = append(titles[key], e)
+		}
+		if strings.TrimSpace(e.GetString("description")) == "" {
+			c.entity(Warning, e, "description", "add a one-sentence `description:`; it feeds meta tags, search and llms.txt", "missing description")
+		}
+	}
+
+	for _, dupes := range titles {
+		if len(dupes) < 2 {
+			continue
+		}
+		slugs := make([]string, len(dupes))
+		for i, e := range dupes {
+			slugs[i] = e.Slug
+		}
+		sort.Strings(slugs)
+		for _, e := range dupes {
+			c.entity(Warning, e, "title", "give each page a distinct title so search results and tabs can tell them apart",
+				"duplicate title %q (shared by %s)", e.GetString("title"), strings.Join(slugs, ", "))
+		}
+	}
+
+	graph := relation.Build(entities, slugMap, cfg.Data.Relations)
+	for _, d := range graph.Dangling {
+		// Sibling manifests are only fetched by the build, so targets
+		// there cannot be confirmed here.
+		if sibling.Qualified(d.Target, cfg.Links.Siblings) {
+			continue
+		}
+		e := slugMap[d.From]
+		hint := "fix the slug or remove the entry"
+		if s := closestSlug(d.Target, slugMap); s != "" {
+			hint = fmt.Sprintf("did you mean %q?", s)
+		}
+		sev := Error
+		if len(cfg.Links.Siblings) > 0 {
+			sev = Warning
+			hint += ", or prefix it with the sibling site that has it (<name>:<slug>)"
+		}
+		field := d.Relation
+		for _, rc := range cfg.Data.Relations {
+			if rc.Name == d.Relation {
+				field = rc.Field
+			}
+		}
+		c.entity(sev, e, field, hint, "%s target %q does not exist", d.Relation, d.Target)
+	}
+	return c.sorted()
+}
+
+func (c *checker) hint(sev Severity, key, hint, format string, args ...interface{}) {
+	c.report(sev, key, format, args...)
+	c.diags[len(c.diags)-1].Hint = hint
+}
+
+func (c *checker) entity(sev Severity, e *entity.Entity, field, hint, format string, args ...interface{}) {
+	c.diags = append(c.diags, Diagnostic{
+		Severity: sev,
+		Pos:      config.Position{File: e.SourceFile},
+		Key:      field,
+		Message:  fmt.Sprintf(format, args...),
+		Hint:     hint,
+	})
+}
+
+// closestSlug returns the slug nearest to a dangling target, if any is
+// within a few edits.
+func closestSlug(target string, slugMap map[string]*entity.Entity) string {
+	target = entity.ToSlug(target)
+	best, bestDist := "", 4
+	for slug := range slugMap {
+		if d := config.EditDistance(target, slug); d < bestDist || (d == bestDist && slug < best) {
+			best, bestDist = slug, d
+		}
+	}
+	return best
+}
+
+// versionLess compares Go versions such as "go1.24.3" and "go1.25".
+func versionLess(a, b string) bool {
+	pa := strings.Split(strings.TrimPrefix(a, "go"), ".")
+	pb := strings.Split(strings.TrimPrefix(b, "go"), ".")
+	for i := 0; i < len(pa) && i < len(pb); i++ {
+		var x, y int
+		fmt.Sscanf(pa[i], "%d", &x)
+		fmt.Sscanf(pb[i], "%d", &y)
+		if x != y {
+			return x < y
+		}
+	}
+	return len(pa) < len(pb)
+}
@@ -0,0 +1,102 @@
+package check
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/supermodeltools/arch-docs/internal/pssg/config"
+	"github.com/supermodeltools/arch-docs/internal/pssg/entity"
+	"github.com/supermodeltools/arch-docs/internal/pssg/output"
+	"github.com/supermodeltools/arch-docs/internal/pssg/render"
+	"github.com/supermodeltools/arch-docs/internal/pssg/schema"
+	"github.com/supermodeltools/arch-docs/internal/pssg/validation"
+)
+
+// Templates parses the template set and reports syntax errors and
+// {{template}} calls to templates that do not exist.
+func Templates(cfg *config.Config) []Diagnostic {
+	c := &checker{cfg: cfg}
+	engine, err := render.NewEngine(cfg)
+	if err != nil {
+		c.hint(Error, "paths.templates", "fix the template syntax, or remove the file if it is unused", "%v", err)
+		return c.sorted()
+	}
+	for _, p := range engine.Lint() {
+		c.hint(Error, "paths.templates", "define the template, or fix the name in the {{template}} call", "%s", p)
+	}
+	return c.sorted()
+}
+
+// Slugs reports entities whose slugs collide. Only one of them would be
+// rendered, and relations to the slug would point at whichever loaded last.
+func Slugs(cfg *config.Config, entities []*entity.Entity) []Diagnostic {
+	c := &checker{cfg: cfg}
+	bySlug := make(map[string][]*entity.Entity)
+	for _, e := range entities {
+		bySlug[e.Slug] = append(bySlug[e.Slug], e)
+	}
+	for slug, dupes := range bySlug {
+		if len(dupes) < 2 {
+			continue
+		}
+		files := make([]string, len(dupes))
+		for i, e := range dupes {
+			files[i] = e.SourceFile
+		}
+		sort.Strings(files)
+		for _, e := range dupes {
+			c.entity(Error, e, "", "rename one of the files, or change the field data.entity_slug.source reads",
+				"slug %q is also used by %s", slug, strings.Join(others(files, e.SourceFile), ", "))
+		}
+	}
+	return c.sorted()
+}
+
+// Validation applies data.validation profiles, as the build does.
+func Validation(cfg *config.Config, entities []*entity.Entity) []Diagnostic {
+	c := &checker{cfg: cfg}
+	sev := Warning
+	if cfg.Data.Validation.FailOnError {
+		sev = Error
+	}
+	bySlug := make(map[string]*entity.Entity, len(entities))
+	for _, e := range entities {
+		bySlug[e.Slug] = e
+	}
+	for _, is := range validation.Check(entities, cfg.Data.Validation) {
+		if e, ok := bySlug[is.Slug]; ok {
+			c.entity(sev, e, is.Field, "", "%s", is.Message)
+		}
+	}
+	return c.sorted()
+}
+
+// Schema generates each entity's JSON-LD, at the page URL urls.style
+// gives it as the build does, and validates it.
+func Schema(cfg *config.Config, entities []*entity.Entity) []Diagnostic {
+	c := &checker{cfg: cfg}
+	gen := schema.NewGenerator(cfg.Site, cfg.Schema)
+	for _, e := range entities {
+		pageURL := cfg.Site.BaseURL + output.CanonicalPath(cfg.URLs, "/"+e.Slug+".html")
+		objects := []map[string]interface{}{gen.GenerateRecipeSchema(e, pageURL)}
+		if faqs := e.GetFAQs(); len(faqs) > 0 {
+			objects = append(objects, gen.GenerateFAQSchema(faqs))
+		}
+		for _, obj := range objects {
+			for _, p := range schema.Validate(obj) {
+				c.entity(Error, e, "", "", "JSON-LD: %s", p)
+			}
+		}
+	}
+	return c.sorted()
+}
+
+func others(list []string, self string) []string {
+	var out []string
+	for _, s := range list {
+		if s != self {
+			out = append(out, s)
+		}
+	}
+	return out
+}
@@ -0,0 +1,211 @@
+package clean
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/supermodeltools/arch-docs/internal/pssg/config"
+	"github.com/supermodeltools/arch-docs/internal/pssg/manifest"
+)
+
+// Options control what Run removes.
+type Options struct {
+	// Cache also removes paths.cache (manifests and deploy state).
+	Cache bool
+	// Enrichment also removes enrichment.cache_dir. Enrichment results cost
+	// API calls to regenerate, so this is never implied by Cache.
+	Enrichment bool
+	// Force removes generated files even if they were edited after the build.
+	Force bool
+	// DryRun reports what would be removed without removing anything.
+	DryRun bool
+	// Log receives one line per removed file or directory.
+	Log io.Writer
+}
+
+// Result summarizes a clean.
+type Result struct {
+	Removed  int
+	Modified []string // generated files edited since the build, left in place
+	Missing  int      // manifest entries already gone
+}
+
+// Run removes the files the last build recorded in the output manifest,
+// then any directories that left empty. Files the manifest does not list,
+// such as a hand-placed CNAME or assets copied in by another tool, are
+// never touched. Without a manifest nothing in a non-empty output directory
+// is known to be ours, so Run fails rather than guess.
+func Run(cfg *config.Config, opts Options) (Result, error) {
+	var res Result
+	if opts.Log == nil {
+		opts.Log = io.Discard
+	}
+	if err := guard(cfg, cfg.Paths.Output, "paths.output"); err != nil {
+		return res, err
+	}
+
+	manifestPath := filepath.Join(cfg.Paths.Cache, manifest.FileName)
+	m, err := manifest.Load(manifestPath)
+	if err != nil {
+		return res, err
+	}
+	if m == nil {
+		if entries, err := os.ReadDir(cfg.Paths.Output); err == nil && len(entries) > 0 {
+			return res, fmt.Errorf("no output manifest at %s, so nothing in %s is known to be generated; remove it by hand", manifestPath, cfg.Paths.Output)
+		}
+		return res, removeCaches(cfg, opts) // no output yet
+	}
+	if m.Output != cfg.Paths.Output {
+		return res, fmt.Errorf("manifest %s describes %s, not %s", manifestPath, m.Output, cfg.Paths.Output)
+	}
+
+	paths := make([]string, 0, len(m.Files)+len(m.Stale))
+	for rel := range m.Files {
+		paths = append(paths, rel)
+	}
+	paths = append(paths, m.Stale...)
+	sort.Strings(paths)
+
+	dirs := make(map[string]bool)
+	for _, rel := range paths {
+		path, err := within(cfg.Paths.Output, rel)
+		if err != nil {
+			return res, err
+		}
+		info, err := os.Lstat(path)
+		if os.IsNotExist(err) {
+			res.Missing++
+			continue
+		}
+		if err != nil {
+			return res, err
+		}
+		if !info.Mode().IsRegular() {
+			continue
+		}
+		if f, ok := m.Files[rel]; ok && !opts.Force {
+			if cur, err := manifest.HashFile(path); err == nil && cur.Hash != f.Hash {
+				res.Modified = append(res.Modified, rel)
+				continue
+			}
+		}
+		fmt.Fprintf(opts.Log, "remove %s\n", path)
+		if !opts.DryRun {
+			if err := os.Remove(path); err != nil {
+				return res, err
+			}
+		}
+		res.Removed++
+		for d := filepath.Dir(path); d != cfg.Paths.Output && inside(cfg.Paths.Output, d); d = filepath.Dir(d) {
+			dirs[d] = true
+		}
+	}
+	if !opts.DryRun {
+		removeEmptyDirs(dirs, opts.Log)
+		if len(res.Modified) == 0 {
+			for _, name := range []string{manifest.FileName, manifest.PrevFileName} {
+				os.Remove(filepath.Join(cfg.Paths.Cache, name))
+			}
+		}
+	}
+
+	return res, removeCaches(cfg, opts)
+}
+
+func removeCaches(cfg *config.Config, opts Options) error {
+	if opts.Cache {
+		if err := removeDir(cfg, cfg.Paths.Cache, "paths.cache", opts); err != nil {
+			return err
+		}
+	}
+	if opts.Enrichment && cfg.Enrichment.CacheDir != "" {
+		return removeDir(cfg, cfg.Enrichment.CacheDir, "enrichment.cache_dir", opts)
+	}
+	return nil
+}
+
+// within joins a manifest path onto dir, refusing paths that escape it.
+func within(dir, rel string) (string, error) {
+	path := filepath.Join(dir, filepath.FromSlash(rel))
+	if path == filepath.Clean(dir) || !inside(dir, path) {
+		return "", fmt.Errorf("manifest entry %q is outside %s", rel, dir)
+	}
+	return path, nil
+}
+
+// removeEmptyDirs removes the given directories, deepest first, skipping
+// any that still hold files.
+func removeEmptyDirs(dirs map[string]bool, log io.Writer) {
+	list := make([]string, 0, len(dirs))
+	for d := range dirs {
+		list = append(list, d)
+	}
+	sort.Slice(list, func(i, j int) bool { return len(list[i]) > len(list[j]) })
+	for _, d := range list {
+		if entries, err := os.ReadDir(d); err == nil && len(entries) == 0 {
+			if os.Remove(d) == nil {
+				fmt.Fprintf(log, "remove %s/\n", d)
+			}
+		}
+	}
+}
+
+func removeDir(cfg *config.Config, dir, key string, opts Options) error {
+	if err := guard(cfg, dir, key); err != nil {
+		return err
+	}
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return nil
+	}
+	fmt.Fprintf(opts.Log, "remove %s/\n", dir)
+	if opts.DryRun {
+		return nil
+	}
+	return os.RemoveAll(dir)
+}
+
+// guard refuses to clean a directory that holds the site's own sources:
+// the site root, a filesystem root, or any ancestor of the config, data,
+// templates or static paths. A misconfigured output of "." must not be
+// able to take the content with it.
+func guard(cfg *config.Config, dir, key string) error {
+	if dir == "" {
+		return fmt.Errorf("%s is not set", key)
+	}
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+	if filepath.Dir(abs) == abs {
+		return fmt.Errorf("refusing to clean %s: %s is a filesystem root", key, dir)
+	}
+	if home, err := os.UserHomeDir(); err == nil && abs == home {
+		return fmt.Errorf("refusing to clean %s: %s is the home directory", key, dir)
+	}
+
+	sources := []struct{ name, path string }{
+		{"the site directory", cfg.ConfigDir},
+		{"paths.data", cfg.Paths.Data},
+		{"paths.templates", cfg.Paths.Templates},
+		{"paths.static", cfg.Paths.Static},
+	}
+	for _, src := range sources {
+		if src.path == "" {
+			continue
+		}
+		if s, err := filepath.Abs(src.path); err == nil && inside(abs, s) {
+			return fmt.Errorf("refusing to clean %s: %s contains %s (%s)", key, dir, src.name, src.path)
+		}
+	}
+	return nil
+}
+
+// inside reports whether path is dir or lies beneath it.
+func inside(dir, path string) bool {
+	r, err := filepath.Rel(dir, path)
+	return err == nil && r != ".." && !strings.HasPrefix(r, ".."+string(filepath.Separator))
+}
@@ -0,0 +1,90 @@
+// Package collection reads curated collections: lists of entities in an
+// order editors choose, each with a title and description, published as
+// its own page and feed and optionally featured on the homepage.
+package collection
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/supermodeltools/arch-docs/internal/pssg/entity"
+)
+
+// Collection is a curated list of entities.
+type Collection struct {
+	Slug        string
+	Title       string
+	Description string
+	// Homepage features the collection on the homepage.
+	Homepage bool
+	// URL is the collection's page, set by the build.
+	URL string
+	// Entities are in the order the file lists them.
+	Entities []*entity.Entity
+	// Missing are the slugs the file lists that no entity has.
+	Missing []string
+}
+
+// file is the collections file format:
+//
+//	{"collections": [
+//	  {"slug": "start-here", "title": "Start Here",
+//	   "description": "The services to read first.",
+//	   "entities": ["api-gateway", "auth"], "homepage": true}]}
+type file struct {
+	Collections []struct {
+		Slug        string   `json:"slug"`
+		Title       string   `json:"title"`
+		Description string   `json:"description"`
+		Entities    []string `json:"entities"`
+		Homepage    bool     `json:"homepage"`
+	} `json:"collections"`
+}
+
+// Load reads a collections file and resolves its slugs against
+// slugMap. Every collection needs a unique slug and a title.
+func Load(path string, slugMap map[string]*entity.Entity) ([]*Collection, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var f file
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	seen := make(map[string]bool)
+	var list []*Collection
+	for i, c := range f.Collections {
+		switch {
+		case c.Slug == "" || c.Slug != entity.ToSlug(c.Slug):
+			return nil, fmt.Errorf("%s: collections[%d]: slug %q must be lowercase letters, digits and dashes", path, i, c.Slug)
+		case seen[c.Slug]:
+			return nil, fmt.Errorf("%s: collections[%d]: duplicate slug %q", path, i, c.Slug)
+		case c.Title == "":
+			return nil, fmt.Errorf("%s: collections[%d]: title is required", path, i)
+		}
+		seen[c.Slug] = true
+		col := &Collection{Slug: c.Slug, Title: c.Title, Description: c.Description, Homepage: c.Homepage}
+		for _, slug := range c.Entities {
+			if e, ok := slugMap[slug]; ok {
+				col.Entities = append(col.Entities, e)
+			} else {
+				col.Missing = append(col.Missing, slug)
+			}
+		}
+		list = append(list, col)
+	}
+	return list, nil
+}
+
+// Featured returns the collections shown on the homepage.
+func Featured(list []*Collection) []*Collection {
+	var featured []*Collection
+	for _, c := range list {
+		if c.Homepage {
+			featured = append(featured, c)
+		}
+	}
+	return featured
+}
@@ -2,25 +2,62 @@ package config
 
 import (
 	"fmt"
-	"os"
+	"net/url"
+	"path"
 	"path/filepath"
-
-	"gopkg.in/yaml.v3"
+	"regexp"
+	"strings"
 )
 
-// Load reads and parses a YAML config file, applies defaults, and validates.
+// DefaultTheme is the `theme:` value selecting the built-in theme.
+const DefaultTheme = "default"
+
+// LoadOptions adjusts how a config file is loaded.
+type LoadOptions struct {
+	// Overrides are "key.path=value" assignments applied after the config
+	// and its includes are merged, before defaults and validation.
+	Overrides []string
+	// Strict rejects keys that do not map to any config field, catching
+	// typos such as `entites_per_page` that are otherwise ignored.
+	Strict bool
+}
+
+// Load reads and parses a YAML config file and any files it includes,
+// applies defaults, and validates.
 func Load(path string) (*Config, error) {
-	data, err := os.ReadFile(path)
+	return LoadWithOptions(path, LoadOptions{})
+}
+
+// LoadWithOptions is Load with command-line adjustments such as overrides.
+func LoadWithOptions(path string, opts LoadOptions) (*Config, error) {
+	doc, files, nodeFiles, err := readMerged(path)
 	if err != nil {
-		return nil, fmt.Errorf("reading config %s: %w", path, err)
+		return nil, err
+	}
+	for _, set := range opts.Overrides {
+		if err := applyOverride(doc, set); err != nil {
+			return nil, err
+		}
+	}
+	if opts.Strict {
+		if problems := unknownKeys(doc, nodeFiles); len(problems) > 0 {
+			return nil, fmt.Errorf("strict config check failed:\n  %s", strings.Join(problems, "\n  "))
+		}
 	}
 
 	var cfg Config
-	if err := yaml.Unmarshal(data, &cfg); err != nil {
+	if err := doc.Decode(&cfg); err != nil {
 		return nil, fmt.Errorf("parsing config %s: %w", path, err)
 	}
 
+	// Paths in a remote config are relative to the working directory.
 	cfg.ConfigDir = filepath.Dir(path)
+	if isRemote(path) {
+		cfg.ConfigDir = "."
+	}
+	cfg.Files = files
+	cfg.Positions = make(map[string]Position)
+	indexPositions(doc, "", nodeFiles, cfg.Positions)
 	applyDefaults(&cfg)
 
 	if err := validate(&cfg); err != nil {
@@ -33,9 +70,46 @@ func Load(path string) (*Config, error) {
 	return &cfg, nil
 }
 
+// URLPath returns the path of a base URL without a trailing slash, such as
+// "/docs" for https://example.com/docs/, or "" for a domain root.
+func URLPath(baseURL string) string {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSuffix(u.Path, "/")
+}
+
 func applyDefaults(cfg *Config) {
 	if cfg.Site.Language == "" {
 		cfg.Site.Language = "en"
+		if len(cfg.I18n.Languages) > 0 {
+			cfg.Site.Language = cfg.I18n.Languages[0].Code
+		}
+	}
+	cfg.I18n.Default = cfg.Site.Language
+	if base := strings.Trim(cfg.Site.BasePath, "/"); base != "" {
+		cfg.Site.BasePath = "/" + base
+		if URLPath(cfg.Site.BaseURL) != cfg.Site.BasePath {
+			cfg.Site.BaseURL = strings.TrimSuffix(cfg.Site.BaseURL, "/") + cfg.Site.BasePath
+		}
+	} else {
+		cfg.Site.BasePath = URLPath(cfg.Site.BaseURL)
+	}
+	for i := range cfg.I18n.Languages {
+		l := &cfg.I18n.Languages[i]
+		if l.Name == "" {
+			l.Name = l.Code
+		}
+		if l.Code == cfg.Site.Language && l.Slugs != "" {
+			cfg.Site.Slugs = l.Slugs
+		}
+		if l.Code == cfg.Site.Language && l.Locale != "" {
+			cfg.Site.Locale = l.Locale
+		}
+	}
+	if cfg.Site.Locale == "" {
+		cfg.Site.Locale = cfg.Site.Language
 	}
 	if cfg.Paths.Output == "" {
 		cfg.Paths.Output = "docs"
@@ -43,6 +117,12 @@ func applyDefaults(cfg *Config) {
 	if cfg.Paths.Templates == "" {
 		cfg.Paths.Templates = "templates"
 	}
+	if cfg.Paths.Archetypes == "" {
+		cfg.Paths.Archetypes = "archetypes"
+	}
+	if cfg.Paths.I18n == "" {
+		cfg.Paths.I18n = "i18n"
+	}
 	if cfg.Paths.Cache == "" {
 		cfg.Paths.Cache = ".cache"
 	}
@@ -55,24 +135,461 @@ func applyDefaults(cfg *Config) {
 	if cfg.Pagination.EntitiesPerPage == 0 {
 		cfg.Pagination.EntitiesPerPage = 48
 	}
+	if cfg.AllEntities.DateField == "" {
+		cfg.AllEntities.DateField = "date"
+	}
+	if cfg.Charts.Pages == nil {
+		cfg.Charts.Pages = make(map[string][]string)
+	}
+	for page, names := range ChartPageTypes {
+		if _, ok := cfg.Charts.Pages[page]; !ok {
+			cfg.Charts.Pages[page] = names
+		}
+	}
+	if cfg.Charts.TypeField == "" {
+		cfg.Charts.TypeField = "recipe_category"
+	}
+	if cfg.Charts.DateField == "" {
+		cfg.Charts.DateField = "date"
+	}
+	if cfg.Charts.Limit == 0 {
+		cfg.Charts.Limit = 20
+	}
+	if cfg.URLs.Style == "" {
+		cfg.URLs.Style = "html"
+	}
 	if cfg.Sitemap.MaxURLsPerFile == 0 {
 		cfg.Sitemap.MaxURLsPerFile = 50000
 	}
+	if cfg.Data.Validation.TypeField == "" {
+		cfg.Data.Validation.TypeField = "node_type"
+	}
+	if cfg.Data.Ordering.WeightField == "" {
+		cfg.Data.Ordering.WeightField = "weight"
+	}
+	if cfg.Data.Ordering.FeaturedField == "" {
+		cfg.Data.Ordering.FeaturedField = "featured"
+	}
+	if cfg.Series.Field == "" {
+		cfg.Series.Field = "series"
+	}
+	if cfg.Series.OrderField == "" {
+		cfg.Series.OrderField = "series_order"
+	}
+	if cfg.Series.Dir == "" {
+		cfg.Series.Dir = "series"
+	}
+	for i := range cfg.Output.Targets {
+		t := &cfg.Output.Targets[i]
+		if t.Type == "" {
+			t.Type = "dir"
+			if strings.HasSuffix(t.Path, ".tar.gz") || strings.HasSuffix(t.Path, ".tgz") {
+				t.Type = "tar.gz"
+			}
+		}
+		if t.Name == "" {
+			t.Name = t.Path
+		}
+		t.BaseURL = strings.TrimSuffix(t.BaseURL, "/")
+	}
+	if cfg.Deploy.GitHubPages.Remote == "" {
+		cfg.Deploy.GitHubPages.Remote = "origin"
+	}
+	if cfg.Deploy.GitHubPages.Branch == "" {
+		cfg.Deploy.GitHubPages.Branch = "gh-pages"
+	}
+	if cfg.Deploy.GitHubPages.Message == "" {
+		cfg.Deploy.GitHubPages.Message = "Deploy " + cfg.Site.Name
+	}
+	if cfg.Series.Template == "" {
+		cfg.Series.Template = "series.html"
+	}
+	if cfg.C4.Field == "" {
+		cfg.C4.Field = "c4_level"
+	}
+	if cfg.C4.ParentField == "" {
+		cfg.C4.ParentField = "c4_parent"
+	}
+	if cfg.C4.TechField == "" {
+		cfg.C4.TechField = "technology"
+	}
+	if cfg.C4.ExternalField == "" {
+		cfg.C4.ExternalField = "external"
+	}
+	if cfg.C4.Dir == "" {
+		cfg.C4.Dir = "c4"
+	}
+	if cfg.C4.Template == "" {
+		cfg.C4.Template = "c4.html"
+	}
+	if cfg.Graph.Dir == "" {
+		cfg.Graph.Dir = "graph"
+	}
+	if cfg.Graph.Template == "" {
+		cfg.Graph.Template = "graph.html"
+	}
+	if cfg.Graph.Depth == 0 {
+		cfg.Graph.Depth = 1
+	}
+	if cfg.Source.Field == "" {
+		cfg.Source.Field = "file_path"
+	}
+	if cfg.Source.StartField == "" {
+		cfg.Source.StartField = "start_line"
+	}
+	if cfg.Source.EndField == "" {
+		cfg.Source.EndField = "end_line"
+	}
+	if cfg.Source.Dir == "" {
+		cfg.Source.Dir = "source"
+	}
+	if cfg.Source.Template == "" {
+		cfg.Source.Template = "source.html"
+	}
+	if cfg.Source.MaxBytes == 0 {
+		cfg.Source.MaxBytes = 1 << 20
+	}
+	if cfg.Icons.Background == "" {
+		cfg.Icons.Background = "#ffffff"
+	}
+	if cfg.Images.Field == "" {
+		cfg.Images.Field = "image"
+	}
+	if cfg.Images.Dir == "" {
+		cfg.Images.Dir = "images"
+	}
+	if len(cfg.Images.Widths) == 0 {
+		cfg.Images.Widths = []int{480, 960, 1440}
+	}
+	if cfg.Images.Formats == nil {
+		cfg.Images.Formats = []string{"webp"}
+	}
+	if cfg.Images.OnError == "" {
+		cfg.Images.OnError = "warn"
+	}
+	if cfg.Images.Quality == 0 {
+		cfg.Images.Quality = 80
+	}
+	if cfg.Images.Thumbnail.Width == 0 {
+		cfg.Images.Thumbnail.Width = 400
+	}
+	if cfg.Images.Thumbnail.Height == 0 {
+		cfg.Images.Thumbnail.Height = 300
+	}
 	if cfg.Schema.DatePublished == "" {
 		cfg.Schema.DatePublished = "2025-01-01"
 	}
 
+	if cfg.Affiliates.CategoryField == "" {
+		cfg.Affiliates.CategoryField = "recipe_category"
+	}
+	if cfg.Affiliates.ProductsField == "" {
+		cfg.Affiliates.ProductsField = "affiliate_products"
+	}
+	if cfg.Affiliates.ASINProvider == "" {
+		cfg.Affiliates.ASINProvider = "amazon"
+	}
+	if cfg.Affiliates.Disclosure.Text == "" {
+		cfg.Affiliates.Disclosure.Text = "This page contains affiliate links. We may earn a commission from qualifying purchases at no extra cost to you."
+	}
+	if cfg.Affiliates.Redirects.Dir == "" {
+		cfg.Affiliates.Redirects.Dir = "go"
+	}
+	if cfg.Affiliates.Rel == "" {
+		cfg.Affiliates.Rel = "sponsored nofollow"
+	}
+	for i := range cfg.Affiliates.Providers {
+		if cfg.Affiliates.Providers[i].Locale == "" {
+			cfg.Affiliates.Providers[i].Locale = cfg.Site.Language
+		}
+		if cfg.Affiliates.Providers[i].Rel == "" {
+			cfg.Affiliates.Providers[i].Rel = cfg.Affiliates.Rel
+		}
+	}
+
+	if cfg.Enrichment.GoDoc.Field == "" {
+		cfg.Enrichment.GoDoc.Field = "import_path"
+	}
+	if cfg.Enrichment.Metrics.Field == "" {
+		cfg.Enrichment.Metrics.Field = "import_path"
+	}
+	if cfg.API.Field == "" {
+		cfg.API.Field = "api_spec"
+	}
+	for i := range cfg.Links.Siblings {
+		sib := &cfg.Links.Siblings[i]
+		sib.BaseURL = strings.TrimSuffix(sib.BaseURL, "/")
+		if sib.Manifest == "" && sib.BaseURL != "" {
+			sib.Manifest = sib.BaseURL + "/entities.json"
+		}
+	}
+	if cfg.Freshness.DateField == "" {
+		cfg.Freshness.DateField = "date_modified"
+	}
+	if len(cfg.Freshness.PathFields) == 0 {
+		cfg.Freshness.PathFields = []string{"file_path", "dir_path"}
+	}
+	if cfg.Freshness.ThresholdDays == 0 {
+		cfg.Freshness.ThresholdDays = 30
+	}
+	if cfg.Freshness.Dir == "" {
+		cfg.Freshness.Dir = "freshness"
+	}
+	if cfg.Freshness.Template == "" {
+		cfg.Freshness.Template = "freshness.html"
+	}
+	if cfg.Headers.CSP.Mode == "" {
+		cfg.Headers.CSP.Mode = "hash"
+	}
+	if cfg.Headers.CSP.Path == "" {
+		cfg.Headers.CSP.Path = "/*"
+	}
+	if cfg.Previews.Dir == "" {
+		cfg.Previews.Dir = "previews"
+	}
+	if cfg.Previews.Template == "" {
+		cfg.Previews.Template = "previews.html"
+	}
+	if cfg.Search.SnippetLength == 0 {
+		cfg.Search.SnippetLength = 120
+	}
+	if cfg.Output.Warnings == "" {
+		cfg.Output.Warnings = "build-warnings.json"
+	}
+	if cfg.A11y.Report == "" {
+		cfg.A11y.Report = "a11y-report.json"
+	}
+	if cfg.Contributors.Dir == "" {
+		cfg.Contributors.Dir = "contributors"
+	}
+	if cfg.Contributors.Template == "" {
+		cfg.Contributors.Template = "contributors.html"
+	}
+	if cfg.Collections.Dir == "" {
+		cfg.Collections.Dir = "collections"
+	}
+	if cfg.Collections.Template == "" {
+		cfg.Collections.Template = "collection.html"
+	}
+	if cfg.OG.TwitterCard == "" {
+		cfg.OG.TwitterCard = "summary_large_image"
+	}
+	if cfg.Breadcrumbs.Entity == nil {
+		cfg.Breadcrumbs.Entity = []BreadcrumbSource{{Field: "recipe_category", URL: "/category/{{slug}}.html"}}
+	}
+	for i := range cfg.Homepage.Sections {
+		s := &cfg.Homepage.Sections[i]
+		if s.Type == "newest" && s.DateField == "" {
+			s.DateField = "date"
+		}
+		if s.Limit == 0 && s.Type != "collection" {
+			s.Limit = 6
+		}
+	}
+	if cfg.Prompts.Dir == "" {
+		cfg.Prompts.Dir = "prompts"
+	}
+	if cfg.Featured.Limit == 0 {
+		cfg.Featured.Limit = 6
+	}
+	if cfg.Featured.Rotate == "" {
+		cfg.Featured.Rotate = "day"
+	}
+	if cfg.Recent.Limit == 0 {
+		cfg.Recent.Limit = 6
+	}
+	if cfg.Print.Template == "" {
+		cfg.Print.Template = "print.html"
+	}
+	if _, ok := cfg.Robots.Pages["print"]; cfg.Print.Enabled && !ok {
+		if cfg.Robots.Pages == nil {
+			cfg.Robots.Pages = map[string]string{}
+		}
+		cfg.Robots.Pages["print"] = "noindex, follow"
+	}
+	if len(cfg.Hooks.Notify.On) == 0 {
+		cfg.Hooks.Notify.On = []string{"success", "failure"}
+	}
+	if len(cfg.Output.JSONFields) == 0 {
+		cfg.Output.JSONFields = []string{"description"}
+	}
+	if cfg.Output.Postprocess == nil {
+		cfg.Output.Postprocess = []string{"analytics", "base_path"}
+	}
+	if cfg.Output.CriticalCSS {
+		// Before base_path, so the deferred stylesheet link gets the path
+		at, listed := len(cfg.Output.Postprocess), false
+		for i, name := range cfg.Output.Postprocess {
+			listed = listed || name == "critical_css"
+			if (name == "base_path" || name == "minify") && i < at {
+				at = i
+			}
+		}
+		if !listed {
+			pp := append([]string{}, cfg.Output.Postprocess[:at]...)
+			pp = append(pp, "critical_css")
+			cfg.Output.Postprocess = append(pp, cfg.Output.Postprocess[at:]...)
+		}
+	}
+	if cfg.Output.Minify {
+		listed := false
+		for _, name := range cfg.Output.Postprocess {
+			listed = listed || name == "minify"
+		}
+		if !listed {
+			cfg.Output.Postprocess = append(cfg.Output.Postprocess, "minify")
+		}
+	}
+	if len(cfg.Similar.Fields) == 0 {
+		cfg.Similar.Fields = []string{"title", "description"}
+	}
+	if cfg.Similar.Limit == 0 {
+		cfg.Similar.Limit = 5
+	}
+	if cfg.Similar.MinScore == 0 {
+		cfg.Similar.MinScore = 0.1
+	}
+	if cfg.Similar.MaxTerms == 0 {
+		cfg.Similar.MaxTerms = 64
+	}
+	if cfg.Comments.Mapping == "" {
+		cfg.Comments.Mapping = "pathname"
+	}
+	if cfg.Comments.Field == "" {
+		cfg.Comments.Field = "comments"
+	}
+	if cfg.Comments.Theme == "" {
+		switch cfg.Comments.Provider {
+		case "giscus":
+			cfg.Comments.Theme = "preferred_color_scheme"
+		case "utterances":
+			cfg.Comments.Theme = "preferred-color-scheme"
+		}
+	}
+	if cfg.History.Limit == 0 {
+		cfg.History.Limit = 10
+	}
+	if cfg.History.Dir == "" {
+		cfg.History.Dir = "changes"
+	}
+	if cfg.Owners.Field == "" {
+		cfg.Owners.Field = "owners"
+	}
+	if cfg.Owners.ContactField == "" {
+		cfg.Owners.ContactField = "owner_contact"
+	}
+	if cfg.Owners.FileField == "" {
+		cfg.Owners.FileField = "file_path"
+	}
+	if cfg.Owners.DirField == "" {
+		cfg.Owners.DirField = "dir_path"
+	}
+	if cfg.Owners.Enabled {
+		hasTaxonomy := false
+		for _, t := range cfg.Taxonomies {
+			if t.Field == cfg.Owners.Field {
+				hasTaxonomy = true
+			}
+		}
+		if !hasTaxonomy {
+			cfg.Taxonomies = append(cfg.Taxonomies, TaxonomyConfig{
+				Name: "owner", Label: "Owners", LabelSingular: "Owner", Field: cfg.Owners.Field, MultiValue: true,
+			})
+		}
+	}
+	if cfg.ADR.Type == "" {
+		cfg.ADR.Type = "adr"
+	}
+	if cfg.ADR.StatusField == "" {
+		cfg.ADR.StatusField = "status"
+	}
+	if cfg.ADR.NumberField == "" {
+		cfg.ADR.NumberField = "number"
+	}
+	if cfg.ADR.DateField == "" {
+		cfg.ADR.DateField = "date"
+	}
+	if cfg.ADR.SupersedesField == "" {
+		cfg.ADR.SupersedesField = "supersedes"
+	}
+	if cfg.ADR.Dir == "" {
+		cfg.ADR.Dir = "adr"
+	}
+	if cfg.ADR.Template == "" {
+		cfg.ADR.Template = "adr_index.html"
+	}
+	if cfg.ADR.Enabled {
+		hasRelation, hasTaxonomy := false, false
+		for _, r := range cfg.Data.Relations {
+			if r.Name == "supersedes" {
+				hasRelation = true
+			}
+		}
+		for _, t := range cfg.Taxonomies {
+			if t.Field == "adr_status" {
+				hasTaxonomy = true
+			}
+		}
+		if !hasRelation {
+			cfg.Data.Relations = append(cfg.Data.Relations, RelationConfig{
+				Name: "supersedes", Field: cfg.ADR.SupersedesField, Label: "Supersedes",
+				Reverse: "superseded_by", ReverseLabel: "Superseded By",
+			})
+		}
+		if !hasTaxonomy {
+			// adr_status is set by the build on every record
+			cfg.Taxonomies = append(cfg.Taxonomies, TaxonomyConfig{
+				Name: "adr-status", Label: "ADR Statuses", LabelSingular: "ADR Status", Field: "adr_status",
+			})
+		}
+	}
+
+	// pairings is the original untyped relation and is always available
+	hasPairings := false
+	for _, r := range cfg.Data.Relations {
+		if r.Name == "pairings" {
+			hasPairings = true
+		}
+	}
+	if !hasPairings {
+		cfg.Data.Relations = append(cfg.Data.Relations, RelationConfig{Name: "pairings", Field: "pairings", Label: "Pairings"})
+	}
+	for i := range cfg.Data.Relations {
+		r := &cfg.Data.Relations[i]
+		if r.Field == "" {
+			r.Field = r.Name
+		}
+		if r.Label == "" {
+			r.Label = r.Name
+		}
+		if r.Reverse != "" && r.ReverseLabel == "" {
+			r.ReverseLabel = r.Reverse
+		}
+	}
+
 	// Default taxonomy settings
 	for i := range cfg.Taxonomies {
 		if cfg.Taxonomies[i].MinEntities == 0 {
 			cfg.Taxonomies[i].MinEntities = 1
 		}
+		if cfg.Taxonomies[i].Sparse == "" {
+			cfg.Taxonomies[i].Sparse = "skip"
+		}
+		if cfg.Taxonomies[i].Collisions == "" {
+			cfg.Taxonomies[i].Collisions = "merge"
+		}
 		if cfg.Taxonomies[i].LetterPageThreshold == 0 {
 			cfg.Taxonomies[i].LetterPageThreshold = 50
 		}
 		if cfg.Taxonomies[i].Template == "" {
 			cfg.Taxonomies[i].Template = "hub.html"
 		}
+		if cfg.Taxonomies[i].IndexDescription == "" {
+			cfg.Taxonomies[i].IndexDescription = "Browse {{.Site}} by {{lower .Label}}: {{.Count}} entries."
+		}
+		if cfg.Taxonomies[i].LetterDescription == "" {
+			cfg.Taxonomies[i].LetterDescription = "Browse {{lower .Label}} starting with {{.Letter}} on {{.Site}}."
+		}
 		if cfg.Taxonomies[i].IndexTemplate == "" {
 			cfg.Taxonomies[i].IndexTemplate = "taxonomy_index.html"
 		}
@@ -90,6 +607,7 @@ func applyDefaults(cfg *Config) {
 			"hub_page_1":     "0.6",
 			"hub_page_n":     "0.4",
 			"letter_page":    "0.5",
+			"series":         "0.6",
 		}
 	}
 	if cfg.Sitemap.ChangeFreqs == nil {
@@ -99,6 +617,7 @@ func applyDefaults(cfg *Config) {
 			"taxonomy_index": "weekly",
 			"hub":            "weekly",
 			"letter_page":    "weekly",
+			"series":         "weekly",
 		}
 	}
 
@@ -130,9 +649,364 @@ func validate(cfg *Config) error {
 	if cfg.Paths.Data == "" {
 		return fmt.Errorf("paths.data is required")
 	}
+	relNames := make(map[string]bool)
+	for i, r := range cfg.Data.Relations {
+		if r.Name == "" {
+			return fmt.Errorf("data.relations[%d].name is required", i)
+		}
+		for _, name := range []string{r.Name, r.Reverse} {
+			if name == "" {
+				continue
+			}
+			if relNames[name] {
+				return fmt.Errorf("data.relations: duplicate relation name %q", name)
+			}
+			relNames[name] = true
+		}
+	}
+	for i, cf := range cfg.Data.ComputedFields {
+		if cf.Name == "" {
+			return fmt.Errorf("data.computed_fields[%d].name is required", i)
+		}
+		if cf.Expr == "" {
+			return fmt.Errorf("data.computed_fields[%d].expr is required", i)
+		}
+		switch cf.Type {
+		case "", "string", "int", "float", "bool":
+		default:
+			return fmt.Errorf("data.computed_fields[%d].type: unknown type %q", i, cf.Type)
+		}
+	}
+	for i, f := range cfg.Data.Fields {
+		if f.Name == "" {
+			return fmt.Errorf("data.fields[%d].name is required", i)
+		}
+		switch f.Type {
+		case "", "string", "list", "int", "float", "bool", "date":
+		default:
+			return fmt.Errorf("data.fields[%d].type: unknown type %q", i, f.Type)
+		}
+	}
+	for i, rule := range cfg.Affiliates.TermRules {
+		if rule.Match != "" {
+			if _, err := regexp.Compile("(?i)" + rule.Match); err != nil {
+				return fmt.Errorf("affiliates.term_rules[%d].match: %w", i, err)
+			}
+		}
+	}
+	for i, rule := range cfg.Headers.Rules {
+		if !strings.HasPrefix(rule.Path, "/") {
+			return fmt.Errorf("headers.rules[%d].path must start with /", i)
+		}
+	}
+	if csp := cfg.Headers.CSP; csp.Enabled {
+		if !cfg.Headers.File {
+			return fmt.Errorf("headers.csp needs headers.file: the policy is served from the _headers file")
+		}
+		if csp.Mode != "hash" && csp.Mode != "nonce" {
+			return fmt.Errorf("headers.csp.mode must be hash or nonce, got %q", csp.Mode)
+		}
+		if !strings.HasPrefix(csp.Path, "/") {
+			return fmt.Errorf("headers.csp.path must start with /")
+		}
+	}
+	if u := cfg.Site.Units; u != "" && u != "metric" && u != "imperial" {
+		return fmt.Errorf("site.units must be metric or imperial, got %q", u)
+	}
+	if s := cfg.Site.Slugs; s != "" && s != "ascii" && s != "unicode" {
+		return fmt.Errorf("site.slugs must be ascii or unicode, got %q", s)
+	}
+	if !hexColor.MatchString(cfg.Icons.Background) {
+		return fmt.Errorf("icons.background must be a #rrggbb color, got %q", cfg.Icons.Background)
+	}
+	if strings.EqualFold(filepath.Ext(cfg.Icons.Source), ".svg") {
+		return fmt.Errorf("icons.source must be a PNG, JPEG or GIF; SVG cannot be rasterized")
+	}
+	for i, w := range cfg.Images.Widths {
+		if w <= 0 {
+			return fmt.Errorf("images.widths[%d] must be positive, got %d", i, w)
+		}
+	}
+	for i, f := range cfg.Images.Formats {
+		if f != "webp" && f != "avif" {
+			return fmt.Errorf("images.formats[%d] must be webp or avif, got %q", i, f)
+		}
+	}
+	if o := cfg.Images.OnError; o != "warn" && o != "fail" {
+		return fmt.Errorf("images.on_error must be warn or fail, got %q", o)
+	}
+	if q := cfg.Images.Quality; q < 1 || q > 100 {
+		return fmt.Errorf("images.quality must be between 1 and 100, got %d", q)
+	}
+	if t := cfg.Images.Thumbnail; t.Width < 0 || t.Height < 0 {
+		return fmt.Errorf("images.thumbnail must have a positive width and height")
+	}
+	for page, directive := range cfg.Robots.Pages {
+		known := false
+		for _, k := range RobotsPageTypes {
+			if page == k {
+				known = true
+			}
+		}
+		if !known {
+			return fmt.Errorf("robots.pages: unknown page type %q (want one of %s)", page, strings.Join(RobotsPageTypes, ", "))
+		}
+		if strings.TrimSpace(directive) == "" {
+			return fmt.Errorf("robots.pages.%s must not be empty", page)
+		}
+	}
+	for page := range cfg.Charts.Pages {
+		if _, ok := ChartPageTypes[page]; !ok {
+			return fmt.Errorf("charts.pages: unknown page type %q", page)
+		}
+	}
+	if cfg.Charts.Limit < 0 {
+		return fmt.Errorf("charts.limit must not be negative")
+	}
+	switch cfg.AllEntities.Sort {
+	case "", "title", "date", "weight":
+	default:
+		return fmt.Errorf("all_entities.sort must be title, date or weight, got %q", cfg.AllEntities.Sort)
+	}
+	for _, name := range cfg.Output.Postprocess {
+		if name == "critical_css" && cfg.Output.ExtractCSS == "" {
+			return fmt.Errorf("output.postprocess: critical_css needs output.extract_css")
+		}
+	}
+	switch cfg.URLs.Style {
+	case "html", "clean":
+	default:
+		return fmt.Errorf("urls.style must be html or clean, got %q", cfg.URLs.Style)
+	}
+	for i, t := range cfg.Taxonomies {
+		switch t.Sparse {
+		case "skip", "render", "redirect":
+		default:
+			return fmt.Errorf("taxonomies[%d].sparse must be skip, render or redirect, got %q", i, t.Sparse)
+		}
+		switch t.Collisions {
+		case "merge", "suffix", "error":
+		default:
+			return fmt.Errorf("taxonomies[%d].collisions must be merge, suffix or error, got %q", i, t.Collisions)
+		}
+		for j, bucket := range t.Buckets {
+			if bucket.Name == "" {
+				return fmt.Errorf("taxonomies[%d].buckets[%d].name is required", i, j)
+			}
+			if bucket.Min != nil && bucket.Max != nil && *bucket.Min >= *bucket.Max {
+				return fmt.Errorf("taxonomies[%d].buckets[%d]: min must be below max", i, j)
+			}
+		}
+	}
+	siblings := make(map[string]bool)
+	for i, sib := range cfg.Links.Siblings {
+		if sib.Name == "" {
+			return fmt.Errorf("links.siblings[%d].name is required", i)
+		}
+		if strings.ContainsAny(sib.Name, ": ") {
+			return fmt.Errorf("links.siblings[%d].name %q cannot contain a colon or space", i, sib.Name)
+		}
+		if siblings[sib.Name] {
+			return fmt.Errorf("links.siblings[%d].name: duplicate sibling %q", i, sib.Name)
+		}
+		siblings[sib.Name] = true
+		if sib.BaseURL == "" {
+			return fmt.Errorf("links.siblings[%d].base_url is required", i)
+		}
+	}
+	if cfg.Graph.Depth < 0 {
+		return fmt.Errorf("graph.depth must be positive, got %d", cfg.Graph.Depth)
+	}
+	if cfg.Freshness.ThresholdDays < 0 {
+		return fmt.Errorf("freshness.threshold_days must be positive, got %d", cfg.Freshness.ThresholdDays)
+	}
+	if cfg.Freshness.Enabled && cfg.Paths.SourceDir == "" {
+		return fmt.Errorf("freshness.enabled needs paths.source_dir, the code entities document")
+	}
+	switch cfg.Analytics.Provider {
+	case "", "plausible":
+	case "goatcounter", "ga4":
+		if cfg.Analytics.ID == "" {
+			return fmt.Errorf("analytics.id is required for %s", cfg.Analytics.Provider)
+		}
+	default:
+		return fmt.Errorf("analytics.provider must be plausible, goatcounter or ga4, got %q", cfg.Analytics.Provider)
+	}
+	if cfg.Analytics.Provider == "ga4" && !strings.HasPrefix(cfg.Analytics.ID, "G-") {
+		return fmt.Errorf("analytics.id must be a GA4 measurement ID (G-...), got %q", cfg.Analytics.ID)
+	}
+	for _, p := range append(cfg.Analytics.Include, cfg.Analytics.Exclude...) {
+		if _, err := path.Match(p, ""); err != nil {
+			return fmt.Errorf("analytics: invalid page pattern %q", p)
+		}
+	}
+	if cfg.Featured.Limit < 0 {
+		return fmt.Errorf("featured.limit must be positive, got %d", cfg.Featured.Limit)
+	}
+	switch cfg.Featured.Rotate {
+	case "day", "week", "build":
+	default:
+		return fmt.Errorf("featured.rotate must be day, week or build, got %q", cfg.Featured.Rotate)
+	}
+	if cfg.Recent.Limit < 0 {
+		return fmt.Errorf("recent.limit must be positive, got %d", cfg.Recent.Limit)
+	}
+	if cfg.Similar.Limit < 0 || cfg.Similar.MaxTerms < 0 {
+		return fmt.Errorf("similar.limit and similar.max_terms must be positive")
+	}
+	if cfg.Similar.MinScore < 0 || cfg.Similar.MinScore > 1 {
+		return fmt.Errorf("similar.min_score must be between 0 and 1, got %g", cfg.Similar.MinScore)
+	}
+	switch cfg.Comments.Provider {
+	case "":
+	case "giscus", "utterances":
+		if strings.Count(cfg.Comments.Repo, "/") != 1 {
+			return fmt.Errorf("comments.repo must be owner/name, got %q", cfg.Comments.Repo)
+		}
+		if cfg.Comments.Provider == "giscus" && (cfg.Comments.RepoID == "" || cfg.Comments.CategoryID == "") {
+			return fmt.Errorf("comments: giscus needs repo_id and category_id; copy them from giscus.app")
+		}
+		switch cfg.Comments.Mapping {
+		case "pathname", "url", "title", "og:title", "slug":
+		default:
+			return fmt.Errorf("comments.mapping must be pathname, url, title, og:title or slug, got %q", cfg.Comments.Mapping)
+		}
+	default:
+		return fmt.Errorf("comments.provider must be giscus or utterances, got %q", cfg.Comments.Provider)
+	}
+	if cfg.Source.MaxBytes < 0 {
+		return fmt.Errorf("source.max_bytes must be positive, got %d", cfg.Source.MaxBytes)
+	}
+	if cfg.Contributors.Enabled && cfg.Extra.Contributors == "" {
+		return fmt.Errorf("contributors.enabled needs extra.contributors, the file of profiles")
+	}
+	if u := cfg.Hooks.Notify.URL; u != "" && !strings.HasPrefix(u, "https://") && !strings.HasPrefix(u, "http://") {
+		return fmt.Errorf("hooks.notify.url must be an http or https URL, got %q", u)
+	}
+	for _, on := range cfg.Hooks.Notify.On {
+		if on != "success" && on != "failure" {
+			return fmt.Errorf("hooks.notify.on must list success or failure, got %q", on)
+		}
+	}
+	if cfg.Search.SnippetLength < 0 {
+		return fmt.Errorf("search.snippet_length must be positive, got %d", cfg.Search.SnippetLength)
+	}
+	for field, n := range cfg.Search.MaxLengths {
+		if n <= 0 {
+			return fmt.Errorf("search.max_lengths.%s must be positive, got %d", field, n)
+		}
+	}
+	for _, t := range cfg.Sitemap.ExcludeTypes {
+		known := false
+		for _, k := range SitemapPageTypes {
+			if t == k {
+				known = true
+			}
+		}
+		if !known {
+			return fmt.Errorf("sitemap.exclude_types: unknown page type %q (want one of %s)", t, strings.Join(SitemapPageTypes, ", "))
+		}
+	}
+	if c := cfg.OG.TwitterCard; c != "summary_large_image" && c != "summary" {
+		return fmt.Errorf("og.twitter_card must be summary_large_image or summary, got %q", c)
+	}
+	for i, src := range cfg.Breadcrumbs.Entity {
+		switch {
+		case (src.Taxonomy == "") == (src.Field == ""):
+			return fmt.Errorf("breadcrumbs.entity[%d]: set one of taxonomy or field", i)
+		case src.Taxonomy != "" && src.URL != "":
+			return fmt.Errorf("breadcrumbs.entity[%d]: url applies to field crumbs; taxonomy crumbs link their hub", i)
+		}
+	}
+	for i, s := range cfg.Homepage.Sections {
+		switch s.Type {
+		case "collection":
+			if s.Collection == "" {
+				return fmt.Errorf("homepage.sections[%d]: a collection section needs collection", i)
+			}
+			if cfg.Extra.Collections == "" {
+				return fmt.Errorf("homepage.sections[%d]: a collection section needs extra.collections", i)
+			}
+		case "top_terms":
+			found := false
+			for _, t := range cfg.Taxonomies {
+				if t.Name == s.Taxonomy {
+					found = true
+				}
+			}
+			if !found {
+				return fmt.Errorf("homepage.sections[%d]: taxonomy %q is not configured", i, s.Taxonomy)
+			}
+		case "recently_added", "recently_updated":
+			if !cfg.Recent.Enabled {
+				return fmt.Errorf("homepage.sections[%d]: a %s section needs recent.enabled", i, s.Type)
+			}
+		case "newest", "random":
+		default:
+			return fmt.Errorf("homepage.sections[%d]: type must be collection, newest, recently_added, recently_updated, top_terms or random, got %q", i, s.Type)
+		}
+		if s.Limit < 0 {
+			return fmt.Errorf("homepage.sections[%d]: limit must be positive, got %d", i, s.Limit)
+		}
+	}
+	if len(cfg.I18n.Languages) > 0 {
+		codes := make(map[string]bool)
+		for i, l := range cfg.I18n.Languages {
+			if !languageCode.MatchString(l.Code) {
+				return fmt.Errorf("i18n.languages[%d].code: %q is not a language tag like fr or pt-BR", i, l.Code)
+			}
+			if s := l.Slugs; s != "" && s != "ascii" && s != "unicode" {
+				return fmt.Errorf("i18n.languages[%d].slugs must be ascii or unicode, got %q", i, s)
+			}
+			if codes[l.Code] {
+				return fmt.Errorf("i18n.languages[%d].code: duplicate language %q", i, l.Code)
+			}
+			codes[l.Code] = true
+		}
+		if !codes[cfg.Site.Language] {
+			return fmt.Errorf("site.language %q is not one of i18n.languages", cfg.Site.Language)
+		}
+	}
+	for i, t := range cfg.Output.Targets {
+		if t.Path == "" {
+			return fmt.Errorf("output.targets[%d].path is required", i)
+		}
+		if t.Type != "dir" && t.Type != "tar.gz" {
+			return fmt.Errorf("output.targets[%d].type: unknown type %q (want dir or tar.gz)", i, t.Type)
+		}
+		// A dir target is replaced wholesale, so it must not overlap the
+		// output it is copied from.
+		target, output := absPath(cfg.ConfigDir, t.Path), absPath(cfg.ConfigDir, cfg.Paths.Output)
+		if within(output, target) || within(target, output) {
+			return fmt.Errorf("output.targets[%d].path %q overlaps paths.output %q", i, t.Path, cfg.Paths.Output)
+		}
+	}
 	return nil
 }
 
+// languageCode matches the BCP 47 tags used as edition directory names.
+var languageCode = regexp.MustCompile(`^[a-z]{2,3}(-[A-Za-z0-9]{2,8})*$`)
+
+var hexColor = regexp.MustCompile(`^#[0-9A-Fa-f]{6}$`)
+
+// absPath resolves p against dir, the way resolvePaths will, and makes it
+// absolute.
+func absPath(dir, p string) string {
+	if !filepath.IsAbs(p) {
+		p = filepath.Join(dir, p)
+	}
+	if abs, err := filepath.Abs(p); err == nil {
+		return abs
+	}
+	return filepath.Clean(p)
+}
+
+// within reports whether p is dir or lies inside it.
+func within(dir, p string) bool {
+	rel, err := filepath.Rel(dir, p)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
 func resolvePaths(cfg *Config) {
 	resolve := func(p string) string {
 		if filepath.IsAbs(p) {
@@ -145,16 +1019,55 @@ func resolvePaths(cfg *Config) {
 	cfg.Paths.Templates = resolve(cfg.Paths.Templates)
 	cfg.Paths.Output = resolve(cfg.Paths.Output)
 	cfg.Paths.Cache = resolve(cfg.Paths.Cache)
+	cfg.Paths.Archetypes = resolve(cfg.Paths.Archetypes)
+	cfg.Paths.I18n = resolve(cfg.Paths.I18n)
+	for i := range cfg.Output.Targets {
+		cfg.Output.Targets[i].Path = resolve(cfg.Output.Targets[i].Path)
+	}
+	if cfg.Theme != "" && cfg.Theme != DefaultTheme {
+		cfg.Theme = resolve(cfg.Theme)
+	}
 	if cfg.Paths.Static != "" {
 		cfg.Paths.Static = resolve(cfg.Paths.Static)
 	}
+	if cfg.Paths.SourceDir != "" {
+		cfg.Paths.SourceDir = resolve(cfg.Paths.SourceDir)
+	}
 	if cfg.Enrichment.CacheDir != "" {
 		cfg.Enrichment.CacheDir = resolve(cfg.Enrichment.CacheDir)
 	}
+	if cfg.Enrichment.GoDoc.Module != "" {
+		cfg.Enrichment.GoDoc.Module = resolve(cfg.Enrichment.GoDoc.Module)
+	}
+	if cfg.Enrichment.Metrics.Module != "" {
+		cfg.Enrichment.Metrics.Module = resolve(cfg.Enrichment.Metrics.Module)
+	}
+	if cfg.Enrichment.Metrics.CoverProfile != "" {
+		cfg.Enrichment.Metrics.CoverProfile = resolve(cfg.Enrichment.Metrics.CoverProfile)
+	}
+	for i := range cfg.Links.Siblings {
+		if m := cfg.Links.Siblings[i].Manifest; m != "" && !isRemote(m) {
+			cfg.Links.Siblings[i].Manifest = resolve(m)
+		}
+	}
+	if cfg.Owners.File != "" {
+		cfg.Owners.File = resolve(cfg.Owners.File)
+	}
+	if cfg.Icons.Source != "" {
+		cfg.Icons.Source = resolve(cfg.Icons.Source)
+	}
 	if cfg.Extra.Favorites != "" {
 		cfg.Extra.Favorites = resolve(cfg.Extra.Favorites)
 	}
 	if cfg.Extra.Contributors != "" {
 		cfg.Extra.Contributors = resolve(cfg.Extra.Contributors)
 	}
+	if cfg.Extra.Collections != "" {
+		cfg.Extra.Collections = resolve(cfg.Extra.Collections)
+	}
+	if cfg.Extra.Engagement != "" {
+		cfg.Extra.Engagement = resolve(cfg.Extra.Engagement)
+	}
+	cfg.A11y.Report = resolve(cfg.A11y.Report)
+	cfg.Output.Warnings = resolve(cfg.Output.Warnings)
 }
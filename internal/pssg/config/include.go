@@ -0,0 +1,136 @@
+package config
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// readMerged reads a config file and everything it includes, returning the
+// deep-merged mapping node, the list of files read (the root file first) and
+// the file each node came from.
+//
+// Includes are listed under a top-level `include:` key, relative to the file
+// that names them (or to its URL for remote configs). They are merged in order to form a base layer that the
+// including file then overrides: maps merge key by key, while lists and
+// scalars replace the base value outright. Merging works on YAML nodes so
+// scalars keep their original tags and line numbers.
+func readMerged(path string) (*yaml.Node, []string, map[*yaml.Node]string, error) {
+	var files []string
+	nodeFiles := make(map[*yaml.Node]string)
+	doc, err := readWithIncludes(path, nil, &files, nodeFiles)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return doc, files, nodeFiles, nil
+}
+
+func readWithIncludes(path string, stack []string, files *[]string, nodeFiles map[*yaml.Node]string) (*yaml.Node, error) {
+	key := configKey(path)
+	for _, p := range stack {
+		if p == key {
+			return nil, fmt.Errorf("include cycle: %s is included by itself", path)
+		}
+	}
+	stack = append(stack, key)
+	*files = append(*files, path)
+
+	data, err := readConfigFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading config %s: %w", path, err)
+	}
+	var file yaml.Node
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parsing config %s: %w", path, err)
+	}
+	doc := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	if len(file.Content) > 0 {
+		doc = file.Content[0]
+	}
+	if doc.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("parsing config %s: top level must be a mapping", path)
+	}
+	recordFile(doc, path, nodeFiles)
+
+	includes, err := takeIncludes(doc)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if len(includes) == 0 {
+		return doc, nil
+	}
+
+	base := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	for _, inc := range includes {
+		resolved, err := resolveInclude(path, inc)
+		if err != nil {
+			return nil, fmt.Errorf("%s: include %s: %w", path, inc, err)
+		}
+		incDoc, err := readWithIncludes(resolved, stack, files, nodeFiles)
+		if err != nil {
+			return nil, err
+		}
+		mergeNodes(base, incDoc)
+	}
+	mergeNodes(base, doc)
+	return base, nil
+}
+
+// takeIncludes removes the `include:` key from a mapping node and returns
+// its paths. A single path or a list of paths is accepted.
+func takeIncludes(doc *yaml.Node) ([]string, error) {
+	for i := 0; i+1 < len(doc.Content); i += 2 {
+		if doc.Content[i].Value != "include" {
+			continue
+		}
+		v := doc.Content[i+1]
+		doc.Content = append(doc.Content[:i], doc.Content[i+2:]...)
+
+		switch v.Kind {
+		case yaml.ScalarNode:
+			if v.Tag == "!!null" {
+				return nil, nil
+			}
+			return []string{v.Value}, nil
+		case yaml.SequenceNode:
+			list := make([]string, 0, len(v.Content))
+			for _, item := range v.Content {
+				if item.Kind != yaml.ScalarNode {
+					return nil, fmt.Errorf("line %d: include entries must be file paths", item.Line)
+				}
+				list = append(list, item.Value)
+			}
+			return list, nil
+		}
+		return nil, fmt.Errorf("line %d: include must be a path or list of paths", v.Line)
+	}
+	return nil, nil
+}
+
+// mergeNodes merges the override mapping into base. Nested mappings are
+// merged recursively; any other override value replaces the base value.
+func mergeNodes(base, override *yaml.Node) {
+	for i := 0; i+1 < len(override.Content); i += 2 {
+		key, val := override.Content[i], override.Content[i+1]
+		j := mappingIndex(base, key.Value)
+		if j < 0 {
+			base.Content = append(base.Content, key, val)
+			continue
+		}
+		if val.Kind == yaml.MappingNode && base.Content[j+1].Kind == yaml.MappingNode {
+			mergeNodes(base.Content[j+1], val)
+			continue
+		}
+		base.Content[j+1] = val
+	}
+}
+
+// mappingIndex returns the index of key within a mapping node's Content, or -1.
+func mappingIndex(m *yaml.Node, key string) int {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			return i
+		}
+	}
+	return -1
+}
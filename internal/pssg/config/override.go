@@ -0,0 +1,69 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// applyOverride sets a dotted key on a config document from a
+// "key.path=value" string, e.g. "site.base_url=https://preview.example.com"
+// or "taxonomies.0.min_entities=3". The value is parsed as YAML, so
+// "false", "12" and "[a, b]" get their natural types. Missing maps along the
+// path are created.
+func applyOverride(doc *yaml.Node, set string) error {
+	key, value, ok := strings.Cut(set, "=")
+	key = strings.TrimSpace(key)
+	if !ok || key == "" {
+		return fmt.Errorf("invalid override %q: want key.path=value", set)
+	}
+
+	var parsed yaml.Node
+	if err := yaml.Unmarshal([]byte(value), &parsed); err != nil {
+		return fmt.Errorf("override %s: parsing value: %w", key, err)
+	}
+	val := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: ""}
+	if len(parsed.Content) > 0 {
+		val = parsed.Content[0]
+	}
+
+	segs := strings.Split(key, ".")
+	node := doc
+	for i, seg := range segs {
+		last := i == len(segs)-1
+		switch node.Kind {
+		case yaml.MappingNode:
+			j := mappingIndex(node, seg)
+			if last {
+				if j < 0 {
+					node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: seg}, val)
+				} else {
+					node.Content[j+1] = val
+				}
+				return nil
+			}
+			if j < 0 {
+				child := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+				node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: seg}, child)
+				node = child
+			} else {
+				node = node.Content[j+1]
+			}
+		case yaml.SequenceNode:
+			idx, err := strconv.Atoi(seg)
+			if err != nil || idx < 0 || idx >= len(node.Content) {
+				return fmt.Errorf("override %s: %q is not a valid index into a list of %d", key, seg, len(node.Content))
+			}
+			if last {
+				node.Content[idx] = val
+				return nil
+			}
+			node = node.Content[idx]
+		default:
+			return fmt.Errorf("override %s: %s is not a map or list", key, strings.Join(segs[:i], "."))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Position is where a config key was defined.
+type Position struct {
+	File string
+	Line int
+}
+
+func (p Position) String() string {
+	if p.Line == 0 || p.File == "--set" {
+		return p.File
+	}
+	return fmt.Sprintf("%s:%d", p.File, p.Line)
+}
+
+// Pos returns the position of a dotted config key such as
+// "taxonomies.0.field". Keys that were not set explicitly (defaults) resolve
+// to their nearest defined parent, or to the root config file.
+func (c *Config) Pos(key string) Position {
+	for key != "" {
+		if p, ok := c.Positions[key]; ok {
+			return p
+		}
+		i := strings.LastIndex(key, ".")
+		if i < 0 {
+			break
+		}
+		key = key[:i]
+	}
+	if len(c.Files) > 0 {
+		return Position{File: c.Files[0]}
+	}
+	return Position{}
+}
+
+// recordFile tags every node in a parsed file with its file name.
+func recordFile(n *yaml.Node, file string, files map[*yaml.Node]string) {
+	files[n] = file
+	for _, child := range n.Content {
+		recordFile(child, file, files)
+	}
+}
+
+// fileOf returns the file a node was read from; nodes created by --set
+// overrides have none.
+func fileOf(n *yaml.Node, files map[*yaml.Node]string) string {
+	if f, ok := files[n]; ok {
+		return f
+	}
+	return "--set"
+}
+
+// indexPositions walks a merged document and maps each dotted key to the
+// file and line of its value.
+func indexPositions(n *yaml.Node, prefix string, files map[*yaml.Node]string, out map[string]Position) {
+	switch n.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			k, v := n.Content[i], n.Content[i+1]
+			key := k.Value
+			if prefix != "" {
+				key = prefix + "." + key
+			}
+			out[key] = Position{File: fileOf(k, files), Line: k.Line}
+			indexPositions(v, key, files, out)
+		}
+	case yaml.SequenceNode:
+		for i, item := range n.Content {
+			key := prefix + "." + strconv.Itoa(i)
+			out[key] = Position{File: fileOf(item, files), Line: item.Line}
+			indexPositions(item, key, files, out)
+		}
+	}
+}
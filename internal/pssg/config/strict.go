@@ -0,0 +1,131 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// unknownKeys walks a config document alongside the Config struct and
+// returns a message for every mapping key that no struct field decodes,
+// e.g. "pssg.yaml:12: unknown key pagination.entites_per_page".
+func unknownKeys(doc *yaml.Node, nodeFiles map[*yaml.Node]string) []string {
+	var problems []string
+	walkKnown(doc, reflect.TypeOf(Config{}), "", nodeFiles, &problems)
+	return problems
+}
+
+func walkKnown(n *yaml.Node, t reflect.Type, prefix string, nodeFiles map[*yaml.Node]string, problems *[]string) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		if n.Kind != yaml.MappingNode {
+			return
+		}
+		fields := yamlFields(t)
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			k, v := n.Content[i], n.Content[i+1]
+			key := joinKey(prefix, k.Value)
+			ft, ok := fields[k.Value]
+			if !ok {
+				msg := fmt.Sprintf("%s: unknown key %s", Position{File: fileOf(k, nodeFiles), Line: k.Line}, key)
+				if s := suggest(k.Value, fields); s != "" {
+					msg += fmt.Sprintf(" (did you mean %s?)", s)
+				}
+				*problems = append(*problems, msg)
+				continue
+			}
+			walkKnown(v, ft, key, nodeFiles, problems)
+		}
+	case reflect.Map:
+		if n.Kind != yaml.MappingNode {
+			return
+		}
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			walkKnown(n.Content[i+1], t.Elem(), joinKey(prefix, n.Content[i].Value), nodeFiles, problems)
+		}
+	case reflect.Slice:
+		if n.Kind != yaml.SequenceNode {
+			return
+		}
+		for i, item := range n.Content {
+			walkKnown(item, t.Elem(), prefix+"."+strconv.Itoa(i), nodeFiles, problems)
+		}
+	}
+}
+
+// yamlFields maps YAML key -> field type for a struct, skipping `yaml:"-"`.
+func yamlFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name := yamlName(f)
+		if name == "" {
+			continue
+		}
+		fields[name] = f.Type
+	}
+	return fields
+}
+
+// yamlName returns the YAML key for a struct field, or "" if it is skipped.
+func yamlName(f reflect.StructField) string {
+	tag := f.Tag.Get("yaml")
+	if tag == "-" {
+		return ""
+	}
+	name, _, _ := strings.Cut(tag, ",")
+	if name == "" {
+		name = strings.ToLower(f.Name)
+	}
+	return name
+}
+
+func joinKey(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
+// suggest returns the known key closest to an unknown one, if any is
+// within a couple of edits.
+func suggest(key string, fields map[string]reflect.Type) string {
+	best, bestDist := "", 3
+	for name := range fields {
+		if d := EditDistance(key, name); d < bestDist || (d == bestDist && best != "" && name < best) {
+			best, bestDist = name, d
+		}
+	}
+	return best
+}
+
+// EditDistance returns the Levenshtein distance between a and b, the
+// number of single-byte edits turning one into the other.
+func EditDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
@@ -18,14 +18,58 @@ type Config struct {
 	Output     OutputConfig     `yaml:"output"`
 	Extra      ExtraConfig      `yaml:"extra"`
 	Search     SearchConfig     `yaml:"search"`
+	Series     SeriesConfig     `yaml:"series"`
+	// Theme is a theme directory, or "default" for the built-in theme. Its
+	// templates and static files sit underneath the site's own.
+	Theme      string           `yaml:"theme"`
+	Headers    HeadersConfig    `yaml:"headers"`
+	Deploy     DeployConfig     `yaml:"deploy"`
+	I18n       I18nConfig       `yaml:"i18n"`
+	Images     ImagesConfig     `yaml:"images"`
+	Icons      IconsConfig      `yaml:"icons"`
+	Source     SourceConfig     `yaml:"source"`
+	Graph      GraphConfig      `yaml:"graph"`
+	C4         C4Config         `yaml:"c4"`
+	ADR        ADRConfig        `yaml:"adr"`
+	API        APIConfig        `yaml:"api"`
+	Owners     OwnersConfig     `yaml:"owners"`
+	History    HistoryConfig    `yaml:"history"`
+	Links      LinksConfig      `yaml:"links"`
+	Freshness  FreshnessConfig  `yaml:"freshness"`
+	Analytics  AnalyticsConfig  `yaml:"analytics"`
+	Comments   CommentsConfig   `yaml:"comments"`
+	Similar    SimilarConfig    `yaml:"similar"`
+	Previews   PreviewsConfig   `yaml:"previews"`
+	A11y       A11yConfig       `yaml:"a11y"`
+	Hooks      HooksConfig      `yaml:"hooks"`
+	Prompts    PromptsConfig    `yaml:"prompts"`
+	Print      PrintConfig      `yaml:"print"`
+	Recent     RecentConfig     `yaml:"recent"`
+	Featured   FeaturedConfig   `yaml:"featured"`
+	Contributors ContributorsConfig `yaml:"contributors"`
+	Collections CollectionsConfig `yaml:"collections"`
+	Homepage   HomepageConfig   `yaml:"homepage"`
+	Breadcrumbs BreadcrumbsConfig `yaml:"breadcrumbs"`
+	OG         OGConfig         `yaml:"og"`
+	AllEntities AllEntitiesConfig `yaml:"all_entities"`
+	Charts     ChartsConfig     `yaml:"charts"`
+	URLs       URLsConfig       `yaml:"urls"`
 
 	// ConfigDir is the directory containing the config file (set at load time).
 	ConfigDir string `yaml:"-"`
+	// Files lists the config file and every file it includes (set at load time).
+	Files []string `yaml:"-"`
+	// Positions maps dotted keys to where they were defined (set at load time).
+	Positions map[string]Position `yaml:"-"`
 }
 
 type SiteConfig struct {
 	Name        string `yaml:"name"`
 	BaseURL     string `yaml:"base_url"`
+	// BasePath is the directory the site is served from, such as "/docs",
+	// or "" at the domain root. It defaults to the path of base_url, and
+	// is added to base_url when that lacks it.
+	BasePath    string `yaml:"base_path"`
 	RepoURL     string `yaml:"repo_url"`
 	Description string `yaml:"description"`
 	Language    string `yaml:"language"`
@@ -34,6 +78,9 @@ type SiteConfig struct {
 	AuthorURL   string `yaml:"author_url"`
 	License     string `yaml:"license"`
 	CNAME       string `yaml:"cname"`
+	Slugs       string `yaml:"slugs"`  // "ascii" (transliterated, the default) or "unicode"
+	Locale      string `yaml:"locale"` // BCP 47 locale for number and date formatting, default site.language
+	Units       string `yaml:"units"`  // "metric" or "imperial"; default follows the locale
 }
 
 type PathsConfig struct {
@@ -42,6 +89,14 @@ type PathsConfig struct {
 	Output    string `yaml:"output"`
 	Cache     string `yaml:"cache"`
 	Static    string `yaml:"static"`
+	// Archetypes holds per-type templates for `pssg new`, named
+	// <node_type>.md or default.md.
+	Archetypes string `yaml:"archetypes"`
+	// I18n holds the <lang>.yaml message files for the T template function.
+	I18n string `yaml:"i18n"`
+	// SourceDir is the checkout of the code the entities describe. Setting
+	// it publishes source pages; see SourceConfig.
+	SourceDir string `yaml:"source_dir"`
 }
 
 type DataConfig struct {
@@ -49,6 +104,578 @@ type DataConfig struct {
 	EntityType  string       `yaml:"entity_type"`
 	EntitySlug  EntitySlug   `yaml:"entity_slug"`
 	BodySections []BodySection `yaml:"body_sections"`
+	Relations    []RelationConfig `yaml:"relations"`
+	ComputedFields []ComputedField `yaml:"computed_fields"`
+	Validation     ValidationConfig `yaml:"validation"`
+	Ordering       OrderingConfig   `yaml:"ordering"`
+	Fields         []FieldConfig    `yaml:"fields"`
+}
+
+// FieldConfig declares a frontmatter field so `pssg new` can pre-populate
+// new entities with it, e.g.
+//
+//	fields:
+//	  - name: tags
+//	    type: list
+//	  - name: tier
+//	    default: "3"
+//	    types: [service]
+type FieldConfig struct {
+	Name    string      `yaml:"name"`
+	Type    string      `yaml:"type"`    // "string" (default), "list", "int", "float", "bool" or "date"
+	Default interface{} `yaml:"default"` // value written for new entities
+	Types   []string    `yaml:"types"`   // node types the field applies to; empty means all
+}
+
+// OrderingConfig controls the order of entities on the homepage, the
+// all-entities pages and taxonomy hubs. Featured entities come first, then
+// entities with a weight (ascending), then the rest; ties fall back to SortBy.
+type OrderingConfig struct {
+	WeightField   string `yaml:"weight_field"`   // default "weight"
+	FeaturedField string `yaml:"featured_field"` // default "featured"
+	SortBy        string `yaml:"sort_by"`        // secondary sort field; empty keeps load order
+	Descending    bool   `yaml:"descending"`     // reverse the secondary sort
+}
+
+// ValidationConfig enforces per-type field rules at load time, e.g.
+//
+//	validation:
+//	  profiles:
+//	    service:
+//	      required: [owner, tier]
+//	      enum: {tier: ["1", "2", "3"]}
+//	      max_length: {description: 160}
+//
+// Profiles are keyed by the value of TypeField (case-insensitive); the "*"
+// profile applies to every entity.
+type ValidationConfig struct {
+	TypeField   string                       `yaml:"type_field"` // default "node_type"
+	FailOnError bool                         `yaml:"fail_on_error"`
+	Profiles    map[string]ValidationProfile `yaml:"profiles"`
+}
+
+// ValidationProfile lists the rules for one entity type.
+type ValidationProfile struct {
+	Required  []string            `yaml:"required"`
+	Enum      map[string][]string `yaml:"enum"`       // field -> allowed values
+	MaxLength map[string]int      `yaml:"max_length"` // field -> maximum characters
+}
+
+// ComputedField defines a field derived at load time from a Go template
+// expression evaluated against the entity, e.g.
+//
+//	- name: total_minutes
+//	  expr: '{{add (durationMinutes (.GetString "prep_time")) (durationMinutes (.GetString "cook_time"))}}'
+//	  type: int
+//
+// Fields are computed in order, so later expressions can use earlier results.
+type ComputedField struct {
+	Name string `yaml:"name"`
+	Expr string `yaml:"expr"`
+	Type string `yaml:"type"` // "string" (default), "int", "float", "bool"
+}
+
+// RelationConfig declares a typed link between entities, read from a
+// frontmatter field holding target slugs. When Reverse is set, targets get
+// an automatic backlink group (e.g. depends_on -> used_by).
+type RelationConfig struct {
+	Name         string `yaml:"name"`
+	Field        string `yaml:"field"`
+	Label        string `yaml:"label"`
+	Reverse      string `yaml:"reverse"`
+	ReverseLabel string `yaml:"reverse_label"`
+}
+
+// SeriesConfig groups entities sharing a frontmatter series name into an
+// ordered sequence with an index page and prev/next navigation.
+type SeriesConfig struct {
+	Field      string `yaml:"field"`       // frontmatter field naming the series, default "series"
+	OrderField string `yaml:"order_field"` // numeric position within the series, default "series_order"
+	Dir        string `yaml:"dir"`         // output subdirectory for series index pages, default "series"
+	Template   string `yaml:"template"`    // default "series.html"
+}
+
+// ImagesConfig turns the hero image named by an entity's frontmatter into
+// resized variants and a cropped thumbnail under <output>/<dir>. Values may
+// be http(s) URLs, downloaded once into paths.cache, paths under
+// paths.static ("/img/soup.jpg"), or paths relative to the entity file.
+// Variants keep the source's own format (JPEG, or PNG for PNG sources) and
+// are also encoded to each of formats with cwebp or avifenc when those
+// tools are on PATH.
+//
+// Remote images in remote_fields are downloaded into paths.cache too and
+// served from <output>/<dir>/remote/, whether or not enabled is set. A URL
+// may be pinned with #sha256=<hex> like a remote config.
+type ImagesConfig struct {
+	Enabled   bool            `yaml:"enabled"`
+	Field     string          `yaml:"field"`   // frontmatter field holding the image, default "image"
+	Dir       string          `yaml:"dir"`     // output subdirectory, default "images"
+	Widths    []int           `yaml:"widths"`  // default [480, 960, 1440]; never upscaled
+	Formats   []string        `yaml:"formats"` // extra encodings: "webp", "avif"; default ["webp"]
+	Quality   int             `yaml:"quality"` // 1-100, default 80
+	Thumbnail ThumbnailConfig `yaml:"thumbnail"`
+	// RemoteFields are other frontmatter fields whose http(s) image URLs
+	// are downloaded and rewritten to local copies.
+	RemoteFields []string `yaml:"remote_fields"`
+	// OnError is "warn" (the default) to render an entity without an image
+	// that is missing, broken or fails to download, or "fail" to stop the
+	// build.
+	OnError string `yaml:"on_error"`
+}
+
+// IconsConfig generates the favicon and app icon set from one square
+// source image (PNG, JPEG or GIF, ideally at least 512×512): favicon.ico,
+// PNG favicons, apple-touch-icon.png and the manifest.json icons, including
+// maskable ones padded onto the background color.
+type IconsConfig struct {
+	Source     string `yaml:"source"`     // path relative to the config file
+	Background string `yaml:"background"` // "#rrggbb" behind opaque and maskable icons, default "#ffffff"
+}
+
+// GraphConfig publishes the typed relations between entities (see
+// data.relations) as a graph: a page drawing all of it, <dir>/graph.json,
+// and <dir>/nodes/<slug>.json for each related entity, holding the
+// neighborhood its page draws.
+type GraphConfig struct {
+	Enabled  bool   `yaml:"enabled"`
+	Dir      string `yaml:"dir"`      // default "graph"
+	Template string `yaml:"template"` // default "graph.html"
+	Depth    int    `yaml:"depth"`    // relations followed from an entity for its subgraph, default 1
+}
+
+// C4Config arranges entities into a C4 model by their level field:
+// context (or system), container, component or code. Each element names
+// the element it sits inside with parent_field. The build writes a system
+// context page at /<dir>/ and a page for every element with children,
+// each drawing a diagram of that level with the relations between its
+// elements from data.relations.
+type C4Config struct {
+	Enabled       bool   `yaml:"enabled"`
+	Field         string `yaml:"field"`          // default "c4_level"
+	ParentField   string `yaml:"parent_field"`   // default "c4_parent"
+	TechField     string `yaml:"tech_field"`     // technology shown in diagram boxes, default "technology"
+	ExternalField string `yaml:"external_field"` // true for systems outside the one documented, default "external"
+	Dir           string `yaml:"dir"`            // default "c4"
+	Template      string `yaml:"template"`       // default "c4.html"
+}
+
+// ADRConfig treats the entities whose data.validation.type_field is Type
+// as architecture decision records. Enabling it adds a "supersedes"
+// relation read from supersedes_field, with "superseded_by" backlinks, and
+// an "adr-status" taxonomy over each record's effective status: superseded
+// once another record supersedes it, otherwise its status field. The build
+// writes an index of every record at /<dir>/, ordered by number and date.
+type ADRConfig struct {
+	Enabled         bool   `yaml:"enabled"`
+	Type            string `yaml:"type"`             // default "adr"
+	StatusField     string `yaml:"status_field"`     // proposed, accepted, rejected, deprecated or superseded; default "status"
+	NumberField     string `yaml:"number_field"`     // default "number"; a leading number in the slug is used without one
+	DateField       string `yaml:"date_field"`       // default "date"
+	SupersedesField string `yaml:"supersedes_field"` // slugs or titles of the records replaced, default "supersedes"
+	Dir             string `yaml:"dir"`              // default "adr"
+	Template        string `yaml:"template"`         // default "adr_index.html"
+}
+
+// APIConfig names the field in which an entity references an OpenAPI
+// document (.yaml, .yml or .json) or protobuf file (.proto). The path is
+// relative to paths.source_dir when that is set, otherwise to the config
+// file. The entity page gets an API reference of the operations and
+// schemas, and operation IDs are added to the search index.
+type APIConfig struct {
+	Field string `yaml:"field"` // default "api_spec"
+}
+
+// OwnersConfig attaches code owners to entities from a CODEOWNERS file or
+// an ownership YAML file (.yaml or .yml) listing owners with a contact,
+// the path patterns they own and, optionally, entity slugs. An entity is
+// matched by slug, then by the file its file_field names, then by its
+// dir_field directory; owners it sets itself are kept. An "owner"
+// taxonomy is added unless one already uses the owners field, entity
+// pages show ownership badges, and the build and `pssg stats` list the
+// entities no one owns.
+type OwnersConfig struct {
+	Enabled      bool   `yaml:"enabled"`
+	File         string `yaml:"file"`          // default: the CODEOWNERS GitHub would use in paths.source_dir, else next to the config file
+	Field        string `yaml:"field"`         // default "owners"
+	ContactField string `yaml:"contact_field"` // first owner's contact or link, default "owner_contact"
+	FileField    string `yaml:"file_field"`    // default "file_path"
+	DirField     string `yaml:"dir_field"`     // default "dir_path"
+}
+
+// HistoryConfig publishes each entity's recent changes from the git
+// history of its data file: a Recent Changes section on the entity page
+// with each commit's author, date and subject, and an Atom feed per
+// entity at <output>/<dir>/<slug>.xml. Commits link to site.repo_url when
+// it is set. Entities whose file has no commits get neither.
+type HistoryConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	Limit   int    `yaml:"limit"` // commits per entity, default 10
+	Dir     string `yaml:"dir"`   // feed subdirectory, default "changes"
+}
+
+// LinksConfig connects the site to sibling pssg sites, such as the docs
+// of other repositories. With publish set the build writes entities.json,
+// the manifest siblings read. A relation target that is not a local
+// entity is looked up in each sibling's manifest, by slug or title, in
+// the order listed; "<name>:<slug>" looks in one sibling only. Targets a
+// sibling has are rendered as links to its pages rather than dropped.
+type LinksConfig struct {
+	Publish  bool            `yaml:"publish"`
+	Siblings []SiblingConfig `yaml:"siblings"`
+}
+
+// SiblingConfig is another pssg site relations may link to.
+type SiblingConfig struct {
+	Name     string `yaml:"name"`
+	BaseURL  string `yaml:"base_url"`
+	Manifest string `yaml:"manifest"` // URL or file, default <base_url>/entities.json
+}
+
+// FreshnessConfig reports documentation that has fallen behind the code
+// it describes, once paths.source_dir is set. Each entity naming a file
+// or directory in one of path_fields is dated by date_field, or else by
+// the last commit to its file, and compared with the last commit to that
+// code. Entities whose code changed more than threshold_days after them
+// are stale. The build writes a report page at <output>/<dir>/ and the
+// same data as <output>/<dir>/report.json.
+type FreshnessConfig struct {
+	Enabled       bool     `yaml:"enabled"`
+	DateField     string   `yaml:"date_field"`     // default "date_modified"
+	PathFields    []string `yaml:"path_fields"`    // default [file_path, dir_path]
+	ThresholdDays int      `yaml:"threshold_days"` // default 30
+	Dir           string   `yaml:"dir"`            // default "freshness"
+	Template      string   `yaml:"template"`       // default "freshness.html"
+}
+
+// AnalyticsConfig adds a Plausible, GoatCounter or Google Analytics 4
+// snippet to the head of every generated page, so sites need not fork
+// templates to add tracking. Include and exclude are page path patterns
+// ("*" within a segment; a trailing "/" matches everything below); an
+// empty include covers every page and exclude wins over include. With
+// respect_dnt the snippet loads nothing for visitors who send Do Not
+// Track or Global Privacy Control.
+type AnalyticsConfig struct {
+	Provider   string   `yaml:"provider"`    // "plausible", "goatcounter" or "ga4"; "" disables analytics
+	ID         string   `yaml:"id"`          // Plausible domain (default: site.base_url's host), GoatCounter code or URL, or GA4 measurement ID
+	ScriptURL  string   `yaml:"script_url"`  // a self-hosted or proxied script; default the provider's
+	Include    []string `yaml:"include"`
+	Exclude    []string `yaml:"exclude"`
+	RespectDNT bool     `yaml:"respect_dnt"`
+}
+
+// CommentsConfig adds a giscus (GitHub Discussions) or utterances (GitHub
+// Issues) thread to entity pages. An entity's field turns comments on or
+// off for its page; entities that do not set it follow default.
+type CommentsConfig struct {
+	Provider   string `yaml:"provider"`    // "giscus" or "utterances"; "" disables comments
+	Repo       string `yaml:"repo"`        // "owner/name" holding the threads
+	RepoID     string `yaml:"repo_id"`     // giscus only, from giscus.app
+	Category   string `yaml:"category"`    // giscus discussion category
+	CategoryID string `yaml:"category_id"` // giscus only, from giscus.app
+	Mapping    string `yaml:"mapping"`     // page to thread: "pathname" (default), "url", "title", "og:title" or "slug"
+	Label      string `yaml:"label"`       // utterances issue label
+	Theme      string `yaml:"theme"`       // provider theme, default following the visitor's color scheme
+	Field      string `yaml:"field"`       // per-entity switch, default "comments"
+	Default    *bool  `yaml:"default"`     // comments for entities without the field, default true
+}
+
+// SimilarConfig lists the entities whose text is most like each entity's
+// on its page, complementing relations and taxonomies. Similarity is the
+// cosine of TF-IDF vectors over the fields and all body sections.
+// max_terms caps the terms kept per entity, which bounds build time on
+// large sites.
+type SimilarConfig struct {
+	Enabled  bool     `yaml:"enabled"`
+	Fields   []string `yaml:"fields"`    // default [title, description]
+	Limit    int      `yaml:"limit"`     // entities per page, default 5
+	MinScore float64  `yaml:"min_score"` // 0 to 1, default 0.1
+	MaxTerms int      `yaml:"max_terms"` // default 64
+}
+
+// PreviewsConfig writes an internal page at <output>/<dir>/ showing the
+// share card of every generated page: its og:image with the title and
+// description social sites display, flagging missing images and text
+// they would truncate. The page is noindex, left out of the sitemap and
+// disallowed in robots.txt.
+type PreviewsConfig struct {
+	Enabled  bool   `yaml:"enabled"`
+	Dir      string `yaml:"dir"`      // default "previews"
+	Template string `yaml:"template"` // default "previews.html"
+}
+
+// A11yConfig audits the pages a build writes for accessibility problems
+// the templates can cause: images without alt text, skipped heading
+// levels, empty headings, links with no accessible name and pages
+// without a language. The build logs a summary and writes every issue,
+// with its page, line and a CSS selector for the element, to report.
+type A11yConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	Report  string `yaml:"report"` // JSON file, default "a11y-report.json" beside the config
+	Fail    bool   `yaml:"fail"`   // fail the build when any issue is found
+}
+
+// PromptsConfig shapes each entity's assistant prompt (.CookModePrompt)
+// and publishes it. Template names a text/template in paths.templates,
+// executed with .Site, .Entity, .URL, .Enrichment and .AffiliateLinks and
+// the usual template functions; without one, entities with enrichment get
+// the built-in recipe wording. When enabled, prompts are written to
+// <output>/<dir>/<slug>.txt and a .json variant, so they can be fetched
+// or shared as a link. Entity pages get the URL as .PromptURL; the
+// chatGPTURL and claudeURL template functions link to a new chat with a
+// prompt filled in.
+type PromptsConfig struct {
+	Enabled  bool   `yaml:"enabled"`
+	Dir      string `yaml:"dir"`      // default "prompts"
+	Template string `yaml:"template"` // e.g. "prompt.txt"
+}
+
+// PrintConfig writes a printer-friendly copy of each entity page to
+// <output>/<slug>-print.html, rendered with its own template from the
+// entity page's context, for print and kiosk displays. Entity pages get
+// its URL as .PrintURL. Copies are canonicalized to their entity page,
+// left out of the sitemap and, unless robots.pages sets "print",
+// marked "noindex, follow".
+type PrintConfig struct {
+	Enabled  bool   `yaml:"enabled"`
+	Template string `yaml:"template"` // default "print.html"
+}
+
+// RecentConfig dates each entity by when a build first saw it and last
+// saw its content change, kept in paths.cache between builds and, with
+// history.enabled, taken back to its git history. The homepage and hub
+// pages get the latest as .RecentlyAdded and .RecentlyUpdated.
+type RecentConfig struct {
+	Enabled bool `yaml:"enabled"`
+	Limit   int  `yaml:"limit"` // entities per list, default 6
+}
+
+// FeaturedConfig picks the entities the homepage features, as .Featured,
+// in an order that looks random but is the same for every build with the
+// same seed. Seed pins it, for reproducible builds; without one it
+// rotates every "day" (the default), "week" or "build". The featured
+// template function picks from any list by the same seed.
+type FeaturedConfig struct {
+	Limit  int    `yaml:"limit"` // default 6
+	Seed   string `yaml:"seed"`
+	Rotate string `yaml:"rotate"`
+}
+
+// ContributorsConfig writes a contributors index at <output>/<dir>/ from
+// the profiles in extra.contributors, each with Person JSON-LD. Author hub
+// pages describe their person the same way.
+type ContributorsConfig struct {
+	Enabled  bool   `yaml:"enabled"`
+	Dir      string `yaml:"dir"`      // default "contributors"
+	Template string `yaml:"template"` // default "contributors.html"
+}
+
+// CollectionsConfig places the curated collections in extra.collections:
+// each gets a page at <output>/<dir>/<slug>/ with CollectionPage JSON-LD
+// and, when RSS is enabled, a feed beside it. Collections marked homepage
+// are passed to the homepage as .Collections.
+type CollectionsConfig struct {
+	Dir      string `yaml:"dir"`      // default "collections"
+	Template string `yaml:"template"` // default "collection.html"
+}
+
+// HomepageConfig lists the sections the build computes for the homepage,
+// passed to its template in order as .Sections, e.g.
+//
+//	homepage:
+//	  sections:
+//	    - {type: collection, collection: start-here}
+//	    - {type: newest, title: Recently added, limit: 6}
+//	    - {type: top_terms, taxonomy: domain, limit: 8}
+//	    - {type: random, title: Explore, limit: 4, seed: "2026-10"}
+type HomepageConfig struct {
+	Sections []HomepageSection `yaml:"sections"`
+}
+
+// HomepageSection is one homepage section. A collection section shows a
+// collection from extra.collections; newest shows the entities with the
+// latest date_field; recently_added and recently_updated the entities
+// recent dates latest; top_terms the taxonomy's entries with the most
+// entities; random a sample that only changes when seed does.
+type HomepageSection struct {
+	Type       string `yaml:"type"`       // "collection", "newest", "recently_added", "recently_updated", "top_terms" or "random"
+	Title      string `yaml:"title"`      // default the collection title, taxonomy label, or a heading per type
+	Collection string `yaml:"collection"` // collection slug, for collection
+	Taxonomy   string `yaml:"taxonomy"`   // taxonomy name, for top_terms
+	DateField  string `yaml:"date_field"` // for newest; default "date"
+	Limit      int    `yaml:"limit"`      // default 6; 0 shows a whole collection
+	Seed       string `yaml:"seed"`       // for random
+}
+
+// BreadcrumbsConfig lists the crumbs entity pages show between Home and
+// the entity, in order, e.g.
+//
+//	breadcrumbs:
+//	  entity:
+//	    - taxonomy: domain
+//	    - field: module
+//	      url: "/modules/{{slug}}.html"
+//
+// The trail is the same in the page and its BreadcrumbList JSON-LD. The
+// default is the recipe category, linked to /category/<slug>.html.
+type BreadcrumbsConfig struct {
+	Entity []BreadcrumbSource `yaml:"entity"`
+}
+
+// BreadcrumbSource is one crumb. A taxonomy source names the entity's
+// first term in that taxonomy and links its hub page. A field source names
+// the field's value (the first, for lists) and links url, in which
+// {{value}} is the value and {{slug}} its slug; without a url the crumb
+// is not a link. Entities without a value skip the crumb.
+type BreadcrumbSource struct {
+	Taxonomy string `yaml:"taxonomy"`
+	Field    string `yaml:"field"`
+	URL      string `yaml:"url"`
+}
+
+// OGConfig sets site-wide defaults for the Open Graph and Twitter card
+// meta on every page. Entities can override their page's with og_title,
+// og_description, og_image, og_image_alt, twitter_card and
+// twitter_creator fields.
+type OGConfig struct {
+	TwitterCard    string `yaml:"twitter_card"`    // "summary_large_image" (default) or "summary"
+	TwitterSite    string `yaml:"twitter_site"`    // the site's handle, e.g. "@acme"
+	TwitterCreator string `yaml:"twitter_creator"` // the default author handle
+	Image          string `yaml:"image"`           // for pages without a share image; site-relative or absolute
+	ImageAlt       string `yaml:"image_alt"`       // alt text for image
+}
+
+// HooksConfig acts on build events.
+type HooksConfig struct {
+	Notify NotifyConfig `yaml:"notify"`
+}
+
+// NotifyConfig posts to a webhook when a build finishes, so pipelines can
+// announce deploys without wrapper scripts. The URL comes from url, or
+// from the environment variable env_var so it can be kept secret. Payload
+// is a text/template for the request body with .Site, .URL, .Status
+// ("success" or "failure"), .Error, .Pages, .Changed (pages added, changed
+// or removed since the last build), .Entities and .Duration; its json
+// function quotes a value. Without one, Slack URLs get a message and other
+// URLs a JSON object of those fields.
+type NotifyConfig struct {
+	URL     string   `yaml:"url"`
+	EnvVar  string   `yaml:"env_var"`
+	Payload string   `yaml:"payload"`
+	On      []string `yaml:"on"` // "success", "failure"; default both
+}
+
+// SourceConfig publishes the code behind entities once paths.source_dir
+// is set. An entity whose field names a file there shows the highlighted lines from start_field to
+// end_field on its page, and every file or directory an entity names gets
+// a browsable page under <output>/<dir>/ that links back to it.
+type SourceConfig struct {
+	Field      string `yaml:"field"`       // default "file_path"
+	StartField string `yaml:"start_field"` // default "start_line"
+	EndField   string `yaml:"end_field"`   // default "end_line"
+	Dir        string `yaml:"dir"`         // output subdirectory, default "source"
+	Template   string `yaml:"template"`    // default "source.html"
+	MaxBytes   int    `yaml:"max_bytes"`   // larger files are listed but not rendered, default 1 MiB
+}
+
+// ThumbnailConfig is the size hero images are center-cropped to for cards.
+type ThumbnailConfig struct {
+	Width  int `yaml:"width"`  // default 400
+	Height int `yaml:"height"` // default 300
+}
+
+// HeadersConfig sets HTTP response headers by URL path pattern, e.g.
+//
+//	headers:
+//	  file: true
+//	  rules:
+//	    - path: "/*.html"
+//	      values: {Cache-Control: "public, max-age=300"}
+//	    - path: "/*"
+//	      values: {Cache-Control: "public, max-age=86400"}
+//
+// With File set the rules are written to a _headers file; `pssg deploy`
+// applies Cache-Control from them to S3 uploads.
+type HeadersConfig struct {
+	File  bool         `yaml:"file"`
+	Rules []HeaderRule `yaml:"rules"`
+	CSP   CSPConfig    `yaml:"csp"`
+}
+
+// CSPConfig adds a Content-Security-Policy to the _headers file that
+// allows the site's inline scripts and styles without 'unsafe-inline'.
+// In hash mode the build hashes every inline script and style element;
+// in nonce mode it gives them all one random nonce per build. The sources
+// are added to policy's script-src and style-src.
+type CSPConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	Mode    string `yaml:"mode"`   // "hash" (default) or "nonce"
+	Policy  string `yaml:"policy"` // default "default-src 'self'; object-src 'none'; base-uri 'self'"
+	Path    string `yaml:"path"`   // pages the header applies to, default "/*"
+}
+
+// HeaderRule applies header values to URL paths matching Path, where *
+// matches any characters. For a given header the first matching rule wins.
+type HeaderRule struct {
+	Path   string            `yaml:"path"`
+	Values map[string]string `yaml:"values"`
+}
+
+// I18nConfig declares the language editions of a multilingual site:
+//
+//	i18n:
+//	  languages:
+//	    - {code: en, name: English}
+//	    - {code: fr, name: Français, site_name: "Mon Site"}
+//	    - {code: de, name: Deutsch}
+//
+// The edition whose code matches site.language (by default the first) is
+// built at the site root; each other edition is built under /<code>/ from
+// the entity files in <paths.data>/<code>/. Default-language entities can
+// also carry translated fields with a language suffix, e.g. `title.fr`.
+type I18nConfig struct {
+	Languages []LanguageConfig `yaml:"languages"`
+	// IncludeUntranslated also publishes default-language entities that
+	// have no translation in an edition, rather than leaving them out.
+	IncludeUntranslated bool `yaml:"include_untranslated"`
+
+	// Default is the code of the root edition, site.language as configured
+	// (set at load time; per-edition builds change site.language).
+	Default string `yaml:"-"`
+	// RootURL is the root edition's site.base_url in the builds of other
+	// editions, whose own base_url ends in /<code>.
+	RootURL string `yaml:"-"`
+}
+
+// LanguageConfig is one language edition of the site.
+type LanguageConfig struct {
+	Code        string `yaml:"code"`        // BCP 47 tag such as "fr" or "pt-BR"
+	Name        string `yaml:"name"`        // shown in language switchers, default Code
+	SiteName    string `yaml:"site_name"`   // replaces site.name in this edition
+	Description string `yaml:"description"` // replaces site.description in this edition
+	Slugs       string `yaml:"slugs"`       // replaces site.slugs in this edition
+	Locale      string `yaml:"locale"`      // this edition's site.locale, default Code
+}
+
+// DeployConfig configures `pssg deploy` targets.
+type DeployConfig struct {
+	GitHubPages GitHubPagesDeploy `yaml:"github_pages"`
+	S3          S3Deploy          `yaml:"s3"`
+}
+
+// GitHubPagesDeploy force-pushes the output as a single commit to a branch.
+type GitHubPagesDeploy struct {
+	Remote  string `yaml:"remote"`  // git remote name or URL, default "origin"
+	Branch  string `yaml:"branch"`  // default "gh-pages"
+	Message string `yaml:"message"` // commit message, default "Deploy <site name>"
+}
+
+// S3Deploy syncs the output to a bucket with the aws CLI and optionally
+// invalidates changed paths in a CloudFront distribution.
+type S3Deploy struct {
+	Bucket       string `yaml:"bucket"`
+	Prefix       string `yaml:"prefix"`
+	Region       string `yaml:"region"`
+	Distribution string `yaml:"cloudfront_distribution"`
 }
 
 type EntitySlug struct {
@@ -58,7 +685,7 @@ type EntitySlug struct {
 type BodySection struct {
 	Name   string `yaml:"name"`
 	Header string `yaml:"header"`
-	Type   string `yaml:"type"` // "unordered_list", "ordered_list", "faq", "markdown"
+	Type   string `yaml:"type"` // "unordered_list", "ordered_list", "faq", "runbook", "markdown"
 }
 
 type TaxonomyConfig struct {
@@ -68,25 +695,66 @@ type TaxonomyConfig struct {
 	Field                    string `yaml:"field"`
 	MultiValue               bool   `yaml:"multi_value"`
 	MinEntities              int    `yaml:"min_entities"`
+	// Sparse decides what entries with fewer than min_entities entities,
+	// including buckets nothing falls into, get: "skip" (the default) for
+	// no page, "render" for a noindex hub with a zero-state message, or
+	// "redirect" for a page sending visitors to the taxonomy index.
+	Sparse                   string `yaml:"sparse"`
+	// Collisions decides what happens when distinct names share a slug,
+	// such as "Stir Fry" and "Stir-Fry": "merge" (the default) makes one
+	// entry under the name most entities use, "suffix" gives the others
+	// slugs ending in -2, -3 and so on, and "error" fails the build.
+	Collisions               string `yaml:"collisions"`
 	LetterPageThreshold      int    `yaml:"letter_page_threshold"`
 	Invert                   bool   `yaml:"invert"`
 	EnrichmentOverrideField  string `yaml:"enrichment_override_field"`
 	Template                 string `yaml:"template"`
 	IndexTemplate            string `yaml:"index_template"`
 	LetterTemplate           string `yaml:"letter_template"`
+	// Buckets group a numeric field into named ranges, e.g. test coverage
+	// into "High coverage" and "Low coverage". The value is read from
+	// enrichment_override_field (a dotted path into the enrichment entry)
+	// when that is set, otherwise from field.
+	Buckets                  []BucketConfig `yaml:"buckets"`
 
 	// Description templates (Go template strings evaluated with .Name, .Count, .Start, .End)
 	HubTitle           string `yaml:"hub_title"`
 	HubMetaDescription string `yaml:"hub_meta_description"`
 	HubSubheading      string `yaml:"hub_subheading"`
-	IndexDescription   string `yaml:"index_description"`
 	CollectionDesc     string `yaml:"collection_description"`
+	// IndexDescription and LetterDescription describe the taxonomy index
+	// and letter pages. They are Go template strings evaluated with .Site,
+	// .Label, .Letter, .Count (entries on the page), .Entities and .Top
+	// (the names of the five largest entries), with lower and join.
+	IndexDescription   string `yaml:"index_description"`
+	LetterDescription  string `yaml:"letter_description"`
+}
+
+// BucketConfig is one range of a numeric taxonomy. An entity joins the
+// first bucket whose range holds its value: min is inclusive, max is
+// exclusive, and either may be left out.
+type BucketConfig struct {
+	Name string   `yaml:"name"`
+	Min  *float64 `yaml:"min"`
+	Max  *float64 `yaml:"max"`
 }
 
 type PaginationConfig struct {
 	EntitiesPerPage int `yaml:"entities_per_page"`
 }
 
+// AllEntitiesConfig orders the /all/ listing and adds A-Z navigation to it.
+type AllEntitiesConfig struct {
+	// Sort is "" to keep the data.ordering order (the default), "title",
+	// "date" (newest first, by date_field) or "weight" (ascending, by
+	// data.ordering.weight_field).
+	Sort      string `yaml:"sort"`
+	DateField string `yaml:"date_field"` // default "date"
+	// Letters links the listing to a page per first letter of the title,
+	// at /all/letter-<x>.html, for sites too large to page through.
+	Letters bool `yaml:"letters"`
+}
+
 type SchemaConfig struct {
 	EntityType     string            `yaml:"entity_type"`
 	FieldMappings  map[string]string `yaml:"field_mappings"`
@@ -101,6 +769,41 @@ type SchemaConfig struct {
 type AffiliatesConfig struct {
 	Providers []AffiliateProviderConfig `yaml:"providers"`
 	SearchTermPaths []string            `yaml:"search_term_paths"`
+	CategoryField   string              `yaml:"category_field"` // entity field exposed as {{category}}
+	MaxLinksPerPage     int                 `yaml:"max_links_per_page"`     // 0 = unlimited
+	MaxProvidersPerTerm int                 `yaml:"max_providers_per_term"` // 0 = unlimited
+	TermRules           []AffiliateTermRule `yaml:"term_rules"`
+	TrackingParams      map[string]string   `yaml:"tracking_params"` // appended to every link; values support {{slug}}, {{term}}, {{category}}, {{locale}}, {{provider}}
+	Rel                 string              `yaml:"rel"`             // rel attribute for affiliate anchors
+	ProductsField       string              `yaml:"products_field"`  // frontmatter list of hand-picked products
+	ASINProvider        string              `yaml:"asin_provider"`   // provider whose product_url_template resolves asin entries
+	Disclosure          AffiliateDisclosureConfig `yaml:"disclosure"`
+	Redirects           AffiliateRedirectsConfig  `yaml:"redirects"`
+	FailOnMissing       bool                      `yaml:"fail_on_missing"` // fail the build when a provider's env var is unset
+}
+
+// AffiliateRedirectsConfig emits local redirect stubs (/go/<provider>/<hash>.html)
+// so outbound clicks can be measured with first-party analytics.
+type AffiliateRedirectsConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	Dir     string `yaml:"dir"`    // output subdirectory, default "go"
+	Beacon  string `yaml:"beacon"` // JS run before redirecting; provider, term and url are in scope
+}
+
+// AffiliateDisclosureConfig is the disclosure shown on every page that renders
+// affiliate links. Template, when set, names a partial rendered with .Site and
+// .Links; otherwise Text is used as plain (escaped) text.
+type AffiliateDisclosureConfig struct {
+	Text     string `yaml:"text"`
+	Template string `yaml:"template"`
+}
+
+// AffiliateTermRule restricts which providers are used for matching search terms.
+// The first matching rule wins; terms matching no rule use every provider.
+type AffiliateTermRule struct {
+	Match     string   `yaml:"match"`     // case-insensitive regular expression
+	Terms     []string `yaml:"terms"`     // exact (case-insensitive) terms
+	Providers []string `yaml:"providers"` // provider names, in preference order
 }
 
 type AffiliateProviderConfig struct {
@@ -108,17 +811,106 @@ type AffiliateProviderConfig struct {
 	URLTemplate string `yaml:"url_template"`
 	EnvVar     string `yaml:"env_var"`
 	AlwaysInclude bool `yaml:"always_include"`
+	Locale        string `yaml:"locale"` // exposed as {{locale}}, defaults to site.language
+	Priority      int    `yaml:"priority"` // lower values are listed first
+	TrackingParams map[string]string `yaml:"tracking_params"` // merged over affiliates.tracking_params
+	Rel            string            `yaml:"rel"`             // overrides affiliates.rel
+	Region         string            `yaml:"region"`          // region of the primary storefront, e.g. "US"
+	Variants       []AffiliateVariantConfig `yaml:"variants"` // regional storefronts emitted as link alternates
+	ProductURLTemplate string        `yaml:"product_url_template"` // e.g. "https://www.amazon.com/dp/{{asin}}?tag={{tag}}"
+}
+
+// AffiliateVariantConfig is a regional storefront of a provider (amazon.co.uk,
+// amazon.de, ...) with its own tag. Empty fields inherit from the provider.
+type AffiliateVariantConfig struct {
+	Region        string `yaml:"region"`
+	URLTemplate   string `yaml:"url_template"`
+	EnvVar        string `yaml:"env_var"`
+	AlwaysInclude bool   `yaml:"always_include"`
+	Locale        string `yaml:"locale"`
+	ProductURLTemplate string `yaml:"product_url_template"`
 }
 
 type EnrichmentConfig struct {
-	CacheDir string `yaml:"cache_dir"`
-	IngredientOverrideField string `yaml:"ingredient_override_field"`
+	CacheDir                string        `yaml:"cache_dir"`
+	IngredientOverrideField string        `yaml:"ingredient_override_field"`
+	GoDoc                   GoDocConfig   `yaml:"godoc"`
+	Metrics                 MetricsConfig `yaml:"metrics"`
+}
+
+// GoDocConfig controls `pssg godoc`, which stores the doc comments,
+// exported API and examples of a Go module's packages in the enrichment
+// cache under "godoc", matching packages to entities by import path.
+type GoDocConfig struct {
+	Module string `yaml:"module"` // module root; default paths.source_dir, else the config directory
+	Field  string `yaml:"field"`  // entity field holding the import path, default "import_path"
+}
+
+// MetricsConfig controls `pssg metrics`, which stores the size, test and
+// dependency counts of a Go module's packages, and their test coverage
+// from a coverage profile, in the enrichment cache under "metrics".
+// Bucket taxonomies can group entities by them.
+type MetricsConfig struct {
+	Module       string `yaml:"module"`        // module root; default paths.source_dir, else the config directory
+	Field        string `yaml:"field"`         // entity field holding the import path, default "import_path"
+	CoverProfile string `yaml:"cover_profile"` // output of go test -coverprofile; no coverage without one
 }
 
 type SitemapConfig struct {
 	MaxURLsPerFile int                       `yaml:"max_urls_per_file"`
 	Priorities     map[string]string         `yaml:"priorities"`
 	ChangeFreqs    map[string]string         `yaml:"change_freqs"`
+	// ExcludeTypes leaves pages of the listed SitemapPageTypes out.
+	ExcludeTypes   []string                  `yaml:"exclude_types"`
+	// Include and Exclude are page path patterns ("*" within a segment; a
+	// trailing "/" matches everything below), e.g. "/all/page-*.html".
+	// With include set, only matching pages are listed; exclude wins.
+	Include        []string                  `yaml:"include"`
+	Exclude        []string                  `yaml:"exclude"`
+}
+
+// SitemapPageTypes are the kinds of generated page sitemap.exclude_types
+// can name.
+var SitemapPageTypes = []string{
+	"homepage", "entity", "hub", "taxonomy_index", "letter_page", "all_entities", "series",
+	"source", "adr", "c4", "graph", "collection", "contributors",
+}
+
+// ChartsConfig picks the charts each kind of listing page carries, by
+// name: "types" (a bar per value of type_field), "terms" (a bar per
+// taxonomy entry), "taxonomies" (a treemap of the taxonomies and their
+// largest entries) or "timeline" (entities per month of date_field).
+type ChartsConfig struct {
+	// Pages is keyed by page type, one of ChartPageTypes. A page type
+	// left out keeps its default; an empty list turns its charts off.
+	Pages     map[string][]string `yaml:"pages"`
+	TypeField string              `yaml:"type_field"` // default "recipe_category"
+	DateField string              `yaml:"date_field"` // default "date"
+	Limit     int                 `yaml:"limit"`      // most bars or cells per chart, default 20
+}
+
+// ChartPageTypes are the kinds of page charts.pages can name, with the
+// charts each carries by default.
+var ChartPageTypes = map[string][]string{
+	"homepage":       {"taxonomies"},
+	"hub":            {"types"},
+	"taxonomy_index": {"terms"},
+	"letter_page":    {"terms"},
+	"all_entities":   {"types"},
+}
+
+// URLsConfig sets the form of page URLs wherever the build writes them:
+// canonical and og:url tags, the sitemap, breadcrumbs, pagination links,
+// language alternates and JSON-LD.
+type URLsConfig struct {
+	// Style is "html" (the default), which links pages by file, such as
+	// /intro.html, or "clean", which drops the extension, for hosts that
+	// serve /intro from intro.html. Either way an index.html page is
+	// linked by its directory, such as /tags/.
+	Style     string `yaml:"style"`
+	// Lowercase lowercases page URLs, and entity slugs taken from file
+	// names so pages are written where their URLs point.
+	Lowercase bool   `yaml:"lowercase"`
 }
 
 type RSSConfig struct {
@@ -131,6 +923,19 @@ type RSSConfig struct {
 type RobotsConfig struct {
 	AllowAll   bool     `yaml:"allow_all"`
 	ExtraBots  []string `yaml:"extra_bots"`
+	// Pages sets the robots directive by page type, one of
+	// RobotsPageTypes, e.g. {hub_page_n: "noindex, follow"}. Other pages
+	// are "index, follow". Directives go in the robots meta tag and, with
+	// headers.file, in an X-Robots-Tag header; noindex pages leave the
+	// sitemap.
+	Pages map[string]string `yaml:"pages"`
+}
+
+// RobotsPageTypes are the page types robots.pages can name. The "_n"
+// types are the second and later pages of a paged listing, and default to
+// the directive of the listing's first page.
+var RobotsPageTypes = []string{
+	"entity", "hub", "hub_page_n", "taxonomy_index", "letter_page", "all_entities", "all_entities_page_n", "print",
 }
 
 type LlmsTxtConfig struct {
@@ -153,11 +958,50 @@ type OutputConfig struct {
 	Minify      bool   `yaml:"minify"`
 	ExtractCSS  string `yaml:"extract_css"`
 	ExtractJS   string `yaml:"extract_js"`
+	// CriticalCSS inlines only the rules of extract_css each page uses
+	// and loads the whole stylesheet after the page renders.
+	CriticalCSS bool     `yaml:"critical_css"`
+	// JSONTwins writes a .json twin next to each hub and A-Z letter page,
+	// listing its entities with json_fields, for apps and widgets.
+	JSONTwins   bool     `yaml:"json_twins"`
+	JSONFields  []string `yaml:"json_fields"` // default [description]
+	// Provenance writes .pssg-build.json to the output root, recording the
+	// tool version, config hash and content commit behind the build.
+	// Default true.
+	Provenance  *bool    `yaml:"provenance"`
+	// Warnings is where the build writes the warnings and errors it
+	// logged, as JSON; relative to the config file, default
+	// build-warnings.json.
+	Warnings    string   `yaml:"warnings"`
+	// Targets are extra copies of the build written after paths.output,
+	// e.g. a release archive or a tree for a mirror domain.
+	Targets     []OutputTarget `yaml:"targets"`
+	// Postprocess lists the processors rendered pages go through, in
+	// order: analytics, anchors, base_path, critical_css and minify.
+	// Default [analytics, base_path]; critical_css: true adds
+	// critical_css before base_path and minify: true adds minify at the
+	// end.
+	Postprocess []string `yaml:"postprocess"`
+}
+
+// OutputTarget is an additional build output. A target with its own
+// base_url is re-rendered with that URL; otherwise the main output is reused.
+type OutputTarget struct {
+	Name    string `yaml:"name"`
+	Type    string `yaml:"type"` // "dir" or "tar.gz"; inferred from path when empty
+	Path    string `yaml:"path"`
+	BaseURL string `yaml:"base_url"`
 }
 
 type ExtraConfig struct {
 	Favorites    string    `yaml:"favorites"`
 	Contributors string    `yaml:"contributors"`
+	// Collections is a JSON file of named, ordered lists of entity slugs;
+	// see collections. It generalizes favorites, which stays supported.
+	Collections  string    `yaml:"collections"`
+	// Engagement is a JSON file of comment, reaction and rating counts by
+	// slug, fetched by a job outside the build.
+	Engagement   string    `yaml:"engagement"`
 	CTA          CTAConfig `yaml:"cta"`
 }
 
@@ -172,4 +1016,10 @@ type CTAConfig struct {
 type SearchConfig struct {
 	Enabled bool     `yaml:"enabled"`
 	Fields  []string `yaml:"fields"` // entity fields to index, default: ["title","description","node_type","language","domain","subdomain","tags"]
+	// SnippetLength caps the description in each index entry, in
+	// characters; default 120.
+	SnippetLength int `yaml:"snippet_length"`
+	// MaxLengths caps other indexed fields by name, e.g. {title: 80}; a
+	// description entry overrides snippet_length.
+	MaxLengths map[string]int `yaml:"max_lengths"`
 }
@@ -0,0 +1,116 @@
+// Package contributors reads the contributors file named by
+// extra.contributors: the people behind a site's content, with their
+// avatars, roles and links.
+package contributors
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Profile is one contributor. The file keys profiles by slug, the same
+// slug the author taxonomy gives the person:
+//
+//	{"profiles": {"ada-lovelace": {
+//	    "name": "Ada Lovelace",
+//	    "avatar": "/img/ada.png",
+//	    "role": "Maintainer",
+//	    "bio": "Writes the analytical engine docs.",
+//	    "links": [{"label": "GitHub", "url": "https://github.com/ada"}]}}}
+type Profile struct {
+	Slug   string `json:"-"`
+	Name   string `json:"name"`
+	Avatar string `json:"avatar,omitempty"`
+	Role   string `json:"role,omitempty"`
+	Bio    string `json:"bio,omitempty"`
+	Links  []Link `json:"links,omitempty"`
+}
+
+// Link is a page about a contributor elsewhere, such as a code host or
+// social profile.
+type Link struct {
+	Label string `json:"label"`
+	URL   string `json:"url"`
+}
+
+// Set is a loaded contributors file. A nil Set has no profiles.
+type Set struct {
+	// Profiles are sorted by name.
+	Profiles []*Profile
+	bySlug   map[string]*Profile
+	raw      map[string]interface{}
+}
+
+// Load reads and validates a contributors file: every profile needs a
+// name, and avatars and links must be http(s) or site-relative URLs.
+func Load(file string) (*Set, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var f struct {
+		Profiles map[string]*Profile `json:"profiles"`
+	}
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+	s := &Set{bySlug: make(map[string]*Profile, len(f.Profiles))}
+	if err := json.Unmarshal(data, &s.raw); err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+	for slug, p := range f.Profiles {
+		if p == nil || strings.TrimSpace(p.Name) == "" {
+			return nil, fmt.Errorf("%s: profiles.%s: name is required", file, slug)
+		}
+		if p.Avatar != "" && !validURL(p.Avatar) {
+			return nil, fmt.Errorf("%s: profiles.%s.avatar: %q is not an http(s) or site-relative URL", file, slug, p.Avatar)
+		}
+		for i, l := range p.Links {
+			if !validURL(l.URL) {
+				return nil, fmt.Errorf("%s: profiles.%s.links[%d]: %q is not an http(s) or site-relative URL", file, slug, i, l.URL)
+			}
+		}
+		p.Slug = slug
+		s.bySlug[slug] = p
+		s.Profiles = append(s.Profiles, p)
+	}
+	sort.Slice(s.Profiles, func(i, j int) bool {
+		if s.Profiles[i].Name != s.Profiles[j].Name {
+			return s.Profiles[i].Name < s.Profiles[j].Name
+		}
+		return s.Profiles[i].Slug < s.Profiles[j].Slug
+	})
+	return s, nil
+}
+
+// Get returns the profile with a slug, or nil.
+func (s *Set) Get(slug string) *Profile {
+	if s == nil {
+		return nil
+	}
+	return s.bySlug[slug]
+}
+
+// Raw returns the file as untyped JSON, for the deprecated template
+// fields that expose it as it was before profiles were typed.
+func (s *Set) Raw() map[string]interface{} {
+	if s == nil {
+		return nil
+	}
+	return s.raw
+}
+
+// AbsoluteURL resolves a site-relative URL against baseURL.
+func AbsoluteURL(baseURL, u string) string {
+	if strings.HasPrefix(u, "/") {
+		return strings.TrimSuffix(baseURL, "/") + u
+	}
+	return u
+}
+
+func validURL(u string) bool {
+	return strings.HasPrefix(u, "https://") || strings.HasPrefix(u, "http://") || strings.HasPrefix(u, "/") && !strings.HasPrefix(u, "//")
+}
@@ -0,0 +1,179 @@
+// Package csp builds a Content-Security-Policy that allows a site's own
+// inline scripts and styles, by hash or by a per-build nonce, so a strict
+// policy can be served without 'unsafe-inline'.
+package csp
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// DefaultPolicy is the base policy when none is configured.
+const DefaultPolicy = "default-src 'self'; object-src 'none'; base-uri 'self'"
+
+var (
+	inlineRe = regexp.MustCompile(`(?is)<(script)(\s[^>]*)?>(.*?)</script>|<(style)(\s[^>]*)?>(.*?)</style>`)
+	typeRe   = regexp.MustCompile(`(?i)\stype\s*=\s*["']?([^"'\s>]+)`)
+	srcRe    = regexp.MustCompile(`(?i)\ssrc\s*=`)
+	nonceRe  = regexp.MustCompile(`(?i)\snonce\s*=`)
+)
+
+// Sources are the inline script and style sources a policy must allow,
+// as CSP source expressions ('sha256-…' or 'nonce-…').
+type Sources struct {
+	Scripts []string
+	Styles  []string
+}
+
+// executable reports whether a script element with these attributes runs
+// as JavaScript. Data blocks such as JSON-LD and chart data are never
+// executed, so CSP does not apply to them.
+func executable(attrs string) bool {
+	m := typeRe.FindStringSubmatch(attrs)
+	if m == nil {
+		return true
+	}
+	switch strings.ToLower(m[1]) {
+	case "text/javascript", "application/javascript", "module":
+		return true
+	}
+	return false
+}
+
+// Hash returns the CSP hash source of an inline element's content.
+func Hash(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return "'sha256-" + base64.StdEncoding.EncodeToString(sum[:]) + "'"
+}
+
+// NewNonce returns a random nonce.
+func NewNonce() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// Scan collects the hashes of the inline scripts and styles in every HTML
+// page under dir. With a nonce it instead adds the nonce to each of them,
+// rewriting the pages, and the sources are just the nonce.
+func Scan(dir, nonce string) (Sources, error) {
+	scripts, styles := make(map[string]bool), make(map[string]bool)
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !strings.HasSuffix(p, ".html") {
+			return err
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		page := string(data)
+		if nonce != "" {
+			if out := AddNonce(page, nonce); out != page {
+				return os.WriteFile(p, []byte(out), 0644)
+			}
+			return nil
+		}
+		for _, m := range inlineRe.FindAllStringSubmatch(page, -1) {
+			tag, attrs, content := element(m)
+			switch {
+			case tag == "style":
+				styles[Hash(content)] = true
+			case !srcRe.MatchString(attrs) && executable(attrs):
+				scripts[Hash(content)] = true
+			}
+		}
+		return nil
+	})
+	if nonce != "" {
+		n := "'nonce-" + nonce + "'"
+		return Sources{Scripts: []string{n}, Styles: []string{n}}, err
+	}
+	return Sources{Scripts: sorted(scripts), Styles: sorted(styles)}, err
+}
+
+// AddNonce adds a nonce attribute to every script and style element of a
+// page that has none.
+func AddNonce(page, nonce string) string {
+	return inlineRe.ReplaceAllStringFunc(page, func(el string) string {
+		tag, attrs, _ := element(inlineRe.FindStringSubmatch(el))
+		if nonceRe.MatchString(attrs) {
+			return el
+		}
+		open := len(tag) + 1 // "<script" or "<style"
+		return el[:open] + ` nonce="` + nonce + `"` + el[open:]
+	})
+}
+
+// Policy adds the sources to the script-src and style-src directives of a
+// base policy. A directive the base lacks is created from default-src, so
+// adding sources never loosens what the base allows.
+func Policy(base string, src Sources) string {
+	if base == "" {
+		base = DefaultPolicy
+	}
+	var names []string
+	values := make(map[string][]string)
+	for _, d := range strings.Split(base, ";") {
+		fields := strings.Fields(d)
+		if len(fields) == 0 {
+			continue
+		}
+		name := strings.ToLower(fields[0])
+		if _, ok := values[name]; !ok {
+			names = append(names, name)
+		}
+		values[name] = fields[1:]
+	}
+	add := func(name string, sources []string) {
+		if len(sources) == 0 {
+			return
+		}
+		cur, ok := values[name]
+		if !ok {
+			names = append(names, name)
+			cur = append([]string(nil), values["default-src"]...)
+			if len(cur) == 0 {
+				cur = []string{"'self'"}
+			}
+		}
+		if len(cur) == 1 && cur[0] == "'none'" {
+			cur = nil
+		}
+		values[name] = append(cur, sources...)
+	}
+	add("script-src", src.Scripts)
+	add("style-src", src.Styles)
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, strings.TrimSpace(name+" "+strings.Join(values[name], " ")))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// element splits an inlineRe match into its tag name, attributes and
+// content.
+func element(m []string) (tag, attrs, content string) {
+	if m[1] != "" {
+		return strings.ToLower(m[1]), m[2], m[3]
+	}
+	return strings.ToLower(m[4]), m[5], m[6]
+}
+
+func sorted(set map[string]bool) []string {
+	list := make([]string, 0, len(set))
+	for s := range set {
+		list = append(list, s)
+	}
+	sort.Strings(list)
+	return list
+}
@@ -0,0 +1,238 @@
+package deploy
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/supermodeltools/arch-docs/internal/pssg/config"
+	"github.com/supermodeltools/arch-docs/internal/pssg/manifest"
+	"github.com/supermodeltools/arch-docs/internal/pssg/output"
+)
+
+// Deploys shell out to git and the aws CLI rather than linking SDKs, so they
+// use whatever credentials the CI environment already has configured.
+
+// maxInvalidationPaths is the most paths sent to CloudFront individually;
+// beyond it the whole distribution is invalidated with "/*".
+const maxInvalidationPaths = 100
+
+// Options control a deploy.
+type Options struct {
+	// DryRun prints the commands instead of running them.
+	DryRun bool
+	// Log receives progress and, in dry-run mode, the commands.
+	Log io.Writer
+}
+
+func (o Options) run(dir, name string, args ...string) error {
+	quoted := make([]string, len(args))
+	for i, a := range args {
+		quoted[i] = a
+		if strings.ContainsAny(a, " ;*") {
+			quoted[i] = strconv.Quote(a)
+		}
+	}
+	fmt.Fprintf(o.Log, "$ %s %s\n", name, strings.Join(quoted, " "))
+	if o.DryRun {
+		return nil
+	}
+	cmd := exec.Command(name, args...)
+	cmd.Dir = dir
+	cmd.Stdout = o.Log
+	cmd.Stderr = o.Log
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s %s: %w", name, strings.Join(args, " "), err)
+	}
+	return nil
+}
+
+// GitHubPages publishes the output directory as a single commit force-pushed
+// to the configured branch, the way gh-pages branches are usually managed.
+func GitHubPages(cfg *config.Config, opts Options) error {
+	gh := cfg.Deploy.GitHubPages
+	if _, err := os.Stat(cfg.Paths.Output); err != nil {
+		return fmt.Errorf("output %s: %w (run pssg build first)", cfg.Paths.Output, err)
+	}
+
+	remote := gh.Remote
+	if !strings.ContainsAny(remote, ":/") { // a remote name rather than a URL or path
+		out, err := exec.Command("git", "-C", cfg.ConfigDir, "remote", "get-url", remote).Output()
+		if err != nil {
+			return fmt.Errorf("resolving git remote %q: %w", remote, err)
+		}
+		remote = strings.TrimSpace(string(out))
+	}
+
+	tmp, err := os.MkdirTemp("", "pssg-deploy-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+	if err := copyTree(cfg.Paths.Output, tmp); err != nil {
+		return err
+	}
+	// Without it GitHub Pages runs Jekyll, which drops _-prefixed files.
+	if err := os.WriteFile(filepath.Join(tmp, ".nojekyll"), nil, 0644); err != nil {
+		return err
+	}
+
+	// The scratch repo has no config of its own; commit as the site repo's
+	// user so CI identities carry over.
+	name, email := gitConfig(cfg.ConfigDir, "user.name", "pssg"), gitConfig(cfg.ConfigDir, "user.email", "pssg@localhost")
+	steps := [][]string{
+		{"init", "-q"},
+		{"checkout", "-q", "-b", gh.Branch},
+		{"add", "-A"},
+		{"-c", "user.name=" + name, "-c", "user.email=" + email, "commit", "-q", "-m", gh.Message},
+		{"push", "-q", "--force", remote, gh.Branch},
+	}
+	for _, args := range steps {
+		if err := opts.run(tmp, "git", args...); err != nil {
+			return err
+		}
+	}
+	fmt.Fprintf(opts.Log, "Deployed %s to %s (%s)\n", cfg.Paths.Output, gh.Branch, remote)
+	return nil
+}
+
+// gitConfig reads a git config value as seen from dir, or returns fallback.
+func gitConfig(dir, key, fallback string) string {
+	out, err := exec.Command("git", "-C", dir, "config", key).Output()
+	if v := strings.TrimSpace(string(out)); err == nil && v != "" {
+		return v
+	}
+	return fallback
+}
+
+// S3 uploads new and changed files, deletes removed ones and invalidates
+// their CloudFront paths. Changes are computed against the manifest of the
+// previous S3 deploy, kept in paths.cache; the first deploy uploads
+// everything.
+func S3(cfg *config.Config, opts Options) error {
+	s3 := cfg.Deploy.S3
+	if s3.Bucket == "" {
+		return fmt.Errorf("deploy.s3.bucket is not set")
+	}
+	current, err := manifest.Scan(cfg.Paths.Output)
+	if err != nil {
+		return err
+	}
+	if len(current) == 0 {
+		return fmt.Errorf("output %s is empty (run pssg build first)", cfg.Paths.Output)
+	}
+
+	statePath := filepath.Join(cfg.Paths.Cache, "deploy-s3.json")
+	prev, err := manifest.Load(statePath)
+	if err != nil {
+		return err
+	}
+	var prevFiles map[string]manifest.File
+	if prev != nil && prev.Output == s3.Bucket+"/"+s3.Prefix {
+		prevFiles = prev.Files
+	}
+	changes := manifest.Compare(prevFiles, current)
+	if changes.Empty() {
+		fmt.Fprintf(opts.Log, "Nothing to deploy; s3://%s/%s is up to date\n", s3.Bucket, s3.Prefix)
+		return nil
+	}
+
+	var regionArgs []string
+	if s3.Region != "" {
+		regionArgs = []string{"--region", s3.Region}
+	}
+	key := func(rel string) string {
+		return "s3://" + s3.Bucket + "/" + path.Join(strings.Trim(s3.Prefix, "/"), rel)
+	}
+
+	for _, rel := range append(changes.Added, changes.Changed...) {
+		args := []string{"s3", "cp", filepath.Join(cfg.Paths.Output, filepath.FromSlash(rel)), key(rel),
+			"--content-type", contentType(rel)}
+		if cc := output.HeaderValue(cfg.Headers.Rules, "/"+rel, "Cache-Control"); cc != "" {
+			args = append(args, "--cache-control", cc)
+		}
+		if err := opts.run("", "aws", append(args, regionArgs...)...); err != nil {
+			return err
+		}
+	}
+	for _, rel := range changes.Removed {
+		if err := opts.run("", "aws", append([]string{"s3", "rm", key(rel)}, regionArgs...)...); err != nil {
+			return err
+		}
+	}
+
+	if s3.Distribution != "" {
+		paths := invalidationPaths(s3.Prefix, changes, prevFiles == nil)
+		args := append([]string{"cloudfront", "create-invalidation", "--distribution-id", s3.Distribution, "--paths"}, paths...)
+		if err := opts.run("", "aws", args...); err != nil {
+			return err
+		}
+	}
+
+	fmt.Fprintf(opts.Log, "Deployed to s3://%s/%s: %d added, %d changed, %d removed\n",
+		s3.Bucket, s3.Prefix, len(changes.Added), len(changes.Changed), len(changes.Removed))
+	if opts.DryRun {
+		return nil
+	}
+	state := &manifest.Manifest{Output: s3.Bucket + "/" + s3.Prefix, Built: time.Now().UTC(), Files: current}
+	return state.Save(statePath)
+}
+
+// invalidationPaths lists the URL paths to invalidate. Changed index.html
+// files also invalidate their directory URL, which is what visitors request.
+func invalidationPaths(prefix string, c manifest.Changes, all bool) []string {
+	base := "/" + strings.Trim(prefix, "/")
+	if base != "/" {
+		base += "/"
+	}
+	if all {
+		return []string{base + "*"}
+	}
+
+	var paths []string
+	for _, rel := range append(append(c.Changed, c.Removed...), c.Added...) {
+		paths = append(paths, base+rel)
+		if path.Base(rel) == "index.html" {
+			paths = append(paths, base+strings.TrimSuffix(rel, "index.html"))
+		}
+	}
+	if len(paths) > maxInvalidationPaths {
+		return []string{base + "*"}
+	}
+	return paths
+}
+
+func contentType(rel string) string {
+	if t := mime.TypeByExtension(path.Ext(rel)); t != "" {
+		return t
+	}
+	return "application/octet-stream"
+}
+
+func copyTree(src, dst string) error {
+	return filepath.WalkDir(src, func(p string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, p)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		if d.IsDir() {
+			return os.MkdirAll(target, 0755)
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(target, data, 0644)
+	})
+}
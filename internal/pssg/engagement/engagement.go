@@ -0,0 +1,63 @@
+// Package engagement reads popularity counts collected outside the build,
+// such as comment, reaction and rating totals fetched by a scheduled job,
+// so a static site can show them.
+package engagement
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Counts are one entity's engagement totals. The file maps slugs to
+// them:
+//
+//	{"api-gateway": {"comments": 12, "reactions": {"+1": 5, "heart": 2},
+//	                 "rating": 4.6, "rating_count": 31}}
+type Counts struct {
+	Comments    int            `json:"comments"`
+	Reactions   map[string]int `json:"reactions"`
+	Rating      float64        `json:"rating"`       // average, between 1 and 5
+	RatingCount int            `json:"rating_count"` // ratings the average is over
+}
+
+// TotalReactions is the number of reactions of every kind.
+func (c *Counts) TotalReactions() int {
+	total := 0
+	for _, n := range c.Reactions {
+		total += n
+	}
+	return total
+}
+
+// Rated reports whether the counts hold a rating worth publishing.
+func (c *Counts) Rated() bool {
+	return c.RatingCount > 0 && c.Rating > 0
+}
+
+// Load reads an engagement file and checks its counts.
+func Load(file string) (map[string]*Counts, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var counts map[string]*Counts
+	if err := json.Unmarshal(data, &counts); err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+	for slug, c := range counts {
+		if c == nil {
+			delete(counts, slug)
+			continue
+		}
+		if c.Comments < 0 || c.RatingCount < 0 || c.Rating < 0 || c.Rating > 5 {
+			return nil, fmt.Errorf("%s: %s: counts must be positive and rating at most 5", file, slug)
+		}
+		for kind, n := range c.Reactions {
+			if n < 0 {
+				return nil, fmt.Errorf("%s: %s: reaction %q must be positive", file, slug, kind)
+			}
+		}
+	}
+	return counts, nil
+}
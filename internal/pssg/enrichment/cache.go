@@ -5,11 +5,19 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"time"
 )
 
+// SourceHashVersion marks cache entries whose ContentHash is an
+// entity.SourceHash. Bump it when SourceHash changes how it hashes, so
+// old entries are not all reported stale at once.
+const SourceHashVersion = "source-sha256-v1"
+
 // CacheEntry represents the cached enrichment data for a single entity.
 type CacheEntry struct {
 	ContentHash string                 `json:"contentHash"`
+	HashVersion string                 `json:"hashVersion,omitempty"` // SourceHashVersion when ContentHash is a SourceHash
 	Enrichment  map[string]interface{} `json:"enrichment"`
 	Timestamp   string                 `json:"timestamp"`
 }
@@ -130,3 +138,67 @@ func GetCoachingPrompt(data map[string]interface{}) string {
 	}
 	return s
 }
+
+// WriteCache writes a single enrichment cache file for the given slug.
+func WriteCache(cacheDir, slug string, entry CacheEntry) error {
+	if err := os.MkdirAll(cacheDir, 0755); err != nil {
+		return fmt.Errorf("creating cache dir %s: %w", cacheDir, err)
+	}
+	if entry.Timestamp == "" {
+		entry.Timestamp = time.Now().UTC().Format(time.RFC3339)
+	}
+	data, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(cacheDir, slug+".json"), data, 0644)
+}
+
+// MergeCache stores value under key in slug's cache entry, keeping the
+// entry's other keys, such as model-written enrichment. A new entry records
+// contentHash, an entity.SourceHash, so StaleSlugs can check it.
+func MergeCache(cacheDir, slug, contentHash, key string, value interface{}) error {
+	entry := ReadCacheEntry(cacheDir, slug)
+	if entry == nil {
+		entry = &CacheEntry{ContentHash: contentHash, HashVersion: SourceHashVersion}
+	}
+	if entry.Enrichment == nil {
+		entry.Enrichment = make(map[string]interface{})
+	}
+	entry.Enrichment[key] = value
+	entry.Timestamp = ""
+	return WriteCache(cacheDir, slug, *entry)
+}
+
+// ReadCacheEntry reads the full cache entry for slug, or nil if it is
+// missing or invalid.
+func ReadCacheEntry(cacheDir, slug string) *CacheEntry {
+	data, err := os.ReadFile(filepath.Join(cacheDir, slug+".json"))
+	if err != nil {
+		return nil
+	}
+	var entry CacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil
+	}
+	return &entry
+}
+
+// StaleSlugs returns the slugs whose cached enrichment was generated from
+// different source content, given slug -> current entity.SourceHash. Only
+// entries recorded with SourceHashVersion are compared; a hash other tools
+// wrote, or an older SourceHash, is not reported.
+func StaleSlugs(cacheDir string, sourceHashes map[string]string) []string {
+	var stale []string
+	for slug, hash := range sourceHashes {
+		entry := ReadCacheEntry(cacheDir, slug)
+		if entry == nil || entry.ContentHash == "" || entry.HashVersion != SourceHashVersion {
+			continue
+		}
+		if entry.ContentHash != hash {
+			stale = append(stale, slug)
+		}
+	}
+	sort.Strings(stale)
+	return stale
+}
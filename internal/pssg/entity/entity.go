@@ -5,8 +5,15 @@ type Entity struct {
 	Slug       string
 	SourceFile string
 	Fields     map[string]interface{}
-	Sections   map[string]interface{} // section name -> content ([]string for lists, []FAQ for faqs, string for markdown)
+	Sections   map[string]interface{} // section name -> content ([]string for lists, []FAQ for faqs, []Step for runbooks, string for markdown)
 	Body       string                 // raw markdown body (minus frontmatter)
+
+	// SourceHash covers fields, sections and body; enrichment caches compare
+	// against it to detect stale entries.
+	SourceHash string
+	// ContentHash additionally covers enrichment data and changes whenever
+	// the rendered page may change.
+	ContentHash string
 }
 
 // FAQ represents a question-answer pair extracted from a body section.
@@ -15,6 +22,16 @@ type FAQ struct {
 	Answer   string
 }
 
+// Step is one step of a runbook section. ID is unique within the entity
+// and stays the same when steps are reordered, so links and tooling that
+// track progress keep pointing at the right step.
+type Step struct {
+	ID     string
+	Text   string
+	Detail string // indented lines under the step, if any
+	Done   bool   // written as "- [x]" in the source
+}
+
 // GetString returns a string field value, or empty string if not found/not a string.
 func (e *Entity) GetString(key string) string {
 	v, ok := e.Fields[key]
@@ -132,6 +149,14 @@ func (e *Entity) GetFAQs() []FAQ {
 	return nil
 }
 
+// GetSteps returns a runbook section as []Step.
+func (e *Entity) GetSteps(name string) []Step {
+	if s, ok := e.Sections[name].([]Step); ok {
+		return s
+	}
+	return nil
+}
+
 // HasField checks if a field exists and is non-empty.
 func (e *Entity) HasField(key string) bool {
 	v, ok := e.Fields[key]
@@ -151,3 +176,27 @@ func (e *Entity) HasField(key string) bool {
 		return true
 	}
 }
+
+// NoIndex reports whether the entity opted out of discovery with
+// `noindex: true`. Such pages are still rendered but are left out of the
+// sitemap, feeds, llms.txt and the search index.
+func (e *Entity) NoIndex() bool {
+	return e.GetBool("noindex")
+}
+
+// CanonicalOverride returns the `canonical_url` frontmatter value, set on
+// syndicated content whose canonical copy lives elsewhere.
+func (e *Entity) CanonicalOverride() string {
+	return e.GetString("canonical_url")
+}
+
+// Indexable returns the entities that have not opted out with noindex.
+func Indexable(entities []*Entity) []*Entity {
+	result := make([]*Entity, 0, len(entities))
+	for _, e := range entities {
+		if !e.NoIndex() {
+			result = append(result, e)
+		}
+	}
+	return result
+}
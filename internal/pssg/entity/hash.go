@@ -0,0 +1,48 @@
+package entity
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+)
+
+// hashInput is the canonical form hashed for an entity. encoding/json sorts
+// map keys, so equal content always produces the same bytes.
+type hashInput struct {
+	Fields     map[string]interface{} `json:"fields"`
+	Sections   map[string]interface{} `json:"sections"`
+	Body       string                 `json:"body"`
+	Enrichment map[string]interface{} `json:"enrichment,omitempty"`
+}
+
+func hashOf(in hashInput) string {
+	data, err := json.Marshal(in)
+	if err != nil {
+		// Unmarshalable field values (e.g. maps with non-string keys) fall
+		// back to hashing the raw body alone.
+		data = []byte(in.Body)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// ComputeSourceHash sets SourceHash from the entity's fields, sections and
+// body. It is called by loaders once an entity is fully populated.
+func (e *Entity) ComputeSourceHash() {
+	e.SourceHash = hashOf(hashInput{Fields: e.Fields, Sections: e.Sections, Body: e.Body})
+}
+
+// ComputeContentHash sets ContentHash from the source content plus the
+// entity's enrichment data, which may be nil.
+func (e *Entity) ComputeContentHash(enrichment map[string]interface{}) {
+	e.ContentHash = hashOf(hashInput{Fields: e.Fields, Sections: e.Sections, Body: e.Body, Enrichment: enrichment})
+}
+
+// ShortHash returns an abbreviated ContentHash suitable for cache-busting
+// query strings, or "" if it has not been computed.
+func (e *Entity) ShortHash() string {
+	if len(e.ContentHash) < 12 {
+		return e.ContentHash
+	}
+	return e.ContentHash[:12]
+}
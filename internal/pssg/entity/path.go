@@ -0,0 +1,62 @@
+package entity
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// GetPath returns a nested field value addressed by a dot-separated path,
+// e.g. "nutrition.protein_g" or "steps.0.title". Numeric segments index into
+// lists. It returns nil if any segment is missing.
+func (e *Entity) GetPath(path string) interface{} {
+	return LookupPath(e.Fields, path)
+}
+
+// GetPathString returns a nested field value formatted as a string, or ""
+// if it is missing. Non-string scalars are formatted with fmt.
+func (e *Entity) GetPathString(path string) string {
+	switch v := e.GetPath(path).(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case map[string]interface{}, []interface{}:
+		return ""
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// LookupPath walks maps and lists in v following a dot-separated path.
+func LookupPath(v interface{}, path string) interface{} {
+	if path == "" {
+		return v
+	}
+	cur := v
+	for _, seg := range strings.Split(path, ".") {
+		switch node := cur.(type) {
+		case map[string]interface{}:
+			next, ok := node[seg]
+			if !ok {
+				return nil
+			}
+			cur = next
+		case []interface{}:
+			i, err := strconv.Atoi(seg)
+			if err != nil || i < 0 || i >= len(node) {
+				return nil
+			}
+			cur = node[i]
+		case []string:
+			i, err := strconv.Atoi(seg)
+			if err != nil || i < 0 || i >= len(node) {
+				return nil
+			}
+			cur = node[i]
+		default:
+			return nil
+		}
+	}
+	return cur
+}
@@ -1,17 +1,119 @@
 package entity
 
 import (
-	"regexp"
 	"strings"
+	"sync"
+	"unicode"
 )
 
-var nonAlnum = regexp.MustCompile(`[^a-z0-9]+`)
+// slugStyle is the site-wide slug setting. Entity slugs, taxonomy terms
+// and template links must all agree on it, so it is set once per build
+// (see SetSlugStyle) rather than passed to every caller.
+var slugStyle struct {
+	sync.RWMutex
+	lang    string
+	unicode bool
+}
+
+// SetSlugStyle configures ToSlug for a site language. With keepUnicode,
+// letters and digits of any script are kept as-is; otherwise they are
+// transliterated to ASCII using lang's conventions where they differ from
+// the generic table (German "ü" becomes "ue", not "u").
+func SetSlugStyle(lang string, keepUnicode bool) {
+	slugStyle.Lock()
+	defer slugStyle.Unlock()
+	slugStyle.lang, _, _ = strings.Cut(strings.ToLower(lang), "-")
+	slugStyle.unicode = keepUnicode
+}
 
 // ToSlug converts a string to a URL-safe slug.
-// Lowercase, replace non-alphanumeric with hyphens, trim leading/trailing hyphens.
+// Lowercase, transliterate or keep non-ASCII letters (see SetSlugStyle),
+// replace everything else with hyphens, trim leading/trailing hyphens.
 func ToSlug(s string) string {
-	s = strings.ToLower(s)
-	s = nonAlnum.ReplaceAllString(s, "-")
-	s = strings.Trim(s, "-")
-	return s
+	slugStyle.RLock()
+	lang, keep := slugStyle.lang, slugStyle.unicode
+	slugStyle.RUnlock()
+
+	var b strings.Builder
+	dash := false
+	write := func(t string) {
+		if dash && b.Len() > 0 {
+			b.WriteByte('-')
+		}
+		dash = false
+		b.WriteString(t)
+	}
+	for _, r := range strings.ToLower(s) {
+		switch {
+		case r >= 'a' && r <= 'z' || r >= '0' && r <= '9':
+			write(string(r))
+		case r < 0x80:
+			dash = true
+		case keep && (unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r)):
+			write(string(r))
+		case unicode.IsMark(r):
+			// accents of decomposed text ("e" + U+0301) are dropped
+		default:
+			if t, ok := transliterate(lang, r); ok {
+				write(t)
+			} else {
+				dash = true
+			}
+		}
+	}
+	return b.String()
+}
+
+func transliterate(lang string, r rune) (string, bool) {
+	if t, ok := langTranslit[lang][r]; ok {
+		return t, true
+	}
+	t, ok := translit[r]
+	return t, ok
+}
+
+// langTranslit holds the per-language exceptions to translit.
+var langTranslit = map[string]map[rune]string{
+	"de": {'ä': "ae", 'ö': "oe", 'ü': "ue"},
+	"da": {'å': "aa", 'ø': "oe", 'æ': "ae"},
+	"nb": {'å': "aa", 'ø': "oe", 'æ': "ae"},
+	"no": {'å': "aa", 'ø': "oe", 'æ': "ae"},
+	"uk": {'г': "h", 'и': "y", 'і': "i", 'ї': "yi", 'є': "ye"},
+}
+
+// translit maps lowercase letters to ASCII: Latin with diacritics, Greek
+// and Cyrillic.
+var translit = map[rune]string{
+	// Latin-1 Supplement and Latin Extended-A
+	'à': "a", 'á': "a", 'â': "a", 'ã': "a", 'ä': "a", 'å': "a", 'æ': "ae",
+	'ç': "c", 'è': "e", 'é': "e", 'ê': "e", 'ë': "e", 'ì': "i", 'í': "i",
+	'î': "i", 'ï': "i", 'ð': "d", 'ñ': "n", 'ò': "o", 'ó': "o", 'ô': "o",
+	'õ': "o", 'ö': "o", 'ø': "o", 'ù': "u", 'ú': "u", 'û': "u", 'ü': "u",
+	'ý': "y", 'þ': "th", 'ÿ': "y", 'ß': "ss",
+	'ā': "a", 'ă': "a", 'ą': "a", 'ć': "c", 'ĉ': "c", 'ċ': "c", 'č': "c",
+	'ď': "d", 'đ': "d", 'ē': "e", 'ĕ': "e", 'ė': "e", 'ę': "e", 'ě': "e",
+	'ĝ': "g", 'ğ': "g", 'ġ': "g", 'ģ': "g", 'ĥ': "h", 'ħ': "h", 'ĩ': "i",
+	'ī': "i", 'ĭ': "i", 'į': "i", 'ı': "i", 'ĳ': "ij", 'ĵ': "j", 'ķ': "k",
+	'ĺ': "l", 'ļ': "l", 'ľ': "l", 'ŀ': "l", 'ł': "l", 'ń': "n", 'ņ': "n",
+	'ň': "n", 'ŋ': "ng", 'ō': "o", 'ŏ': "o", 'ő': "o", 'œ': "oe", 'ŕ': "r",
+	'ŗ': "r", 'ř': "r", 'ś': "s", 'ŝ': "s", 'ş': "s", 'š': "s", 'ţ': "t",
+	'ť': "t", 'ŧ': "t", 'ũ': "u", 'ū': "u", 'ŭ': "u", 'ů': "u", 'ű': "u",
+	'ų': "u", 'ŵ': "w", 'ŷ': "y", 'ź': "z", 'ż': "z", 'ž': "z", 'ș': "s",
+	'ț': "t",
+
+	// Greek
+	'α': "a", 'ά': "a", 'β': "v", 'γ': "g", 'δ': "d", 'ε': "e", 'έ': "e",
+	'ζ': "z", 'η': "i", 'ή': "i", 'θ': "th", 'ι': "i", 'ί': "i", 'ϊ': "i",
+	'ΐ': "i", 'κ': "k", 'λ': "l", 'μ': "m", 'ν': "n", 'ξ': "x", 'ο': "o",
+	'ό': "o", 'π': "p", 'ρ': "r", 'σ': "s", 'ς': "s", 'τ': "t", 'υ': "y",
+	'ύ': "y", 'ϋ': "y", 'ΰ': "y", 'φ': "f", 'χ': "ch", 'ψ': "ps", 'ω': "o",
+	'ώ': "o",
+
+	// Cyrillic
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "yo",
+	'ж': "zh", 'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m",
+	'н': "n", 'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u",
+	'ф': "f", 'х': "kh", 'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "shch",
+	'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu", 'я': "ya", 'і': "i",
+	'ї': "yi", 'є': "ye", 'ґ': "g", 'ў': "u",
 }
@@ -0,0 +1,118 @@
+package entity
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultTimeLayouts are tried by GetTime after any caller-supplied layouts.
+var DefaultTimeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"2006/01/02",
+	"January 2, 2006",
+	"Jan 2, 2006",
+	"2 January 2006",
+	time.RFC1123Z,
+	time.RFC1123,
+}
+
+// GetTime returns a date/time field value, or the zero time if the field is
+// missing or unparseable. YAML timestamps are used as-is; strings are parsed
+// with layouts first, then DefaultTimeLayouts.
+func (e *Entity) GetTime(key string, layouts ...string) time.Time {
+	v, ok := e.Fields[key]
+	if !ok {
+		return time.Time{}
+	}
+	switch val := v.(type) {
+	case time.Time:
+		return val
+	case string:
+		t, _ := ParseTime(val, layouts...)
+		return t
+	}
+	return time.Time{}
+}
+
+// ParseTime parses s with the given layouts, then DefaultTimeLayouts.
+func ParseTime(s string, layouts ...string) (time.Time, bool) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, false
+	}
+	for _, list := range [][]string{layouts, DefaultTimeLayouts} {
+		for _, layout := range list {
+			if t, err := time.Parse(layout, s); err == nil {
+				return t, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+// GetDuration returns a duration field value, or 0 if the field is missing
+// or unparseable. Bare numbers are treated as minutes.
+func (e *Entity) GetDuration(key string) time.Duration {
+	v, ok := e.Fields[key]
+	if !ok {
+		return 0
+	}
+	switch val := v.(type) {
+	case string:
+		d, _ := ParseDuration(val)
+		return d
+	case int:
+		return time.Duration(val) * time.Minute
+	case int64:
+		return time.Duration(val) * time.Minute
+	case float64:
+		return time.Duration(val * float64(time.Minute))
+	}
+	return 0
+}
+
+var isoDuration = regexp.MustCompile(`(?i)^P(?:(\d+(?:\.\d+)?)W)?(?:(\d+(?:\.\d+)?)D)?(?:T(?:(\d+(?:\.\d+)?)H)?(?:(\d+(?:\.\d+)?)M)?(?:(\d+(?:\.\d+)?)S)?)?$`)
+
+// ParseDuration parses an ISO 8601 duration ("PT1H30M", "P1DT2H"), a Go
+// duration ("1h30m") or a bare number of minutes ("45").
+func ParseDuration(s string) (time.Duration, bool) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, false
+	}
+	if d, ok := ParseISODuration(s); ok {
+		return d, true
+	}
+	if d, err := time.ParseDuration(s); err == nil {
+		return d, true
+	}
+	if n, err := strconv.ParseFloat(s, 64); err == nil {
+		return time.Duration(n * float64(time.Minute)), true
+	}
+	return 0, false
+}
+
+// ParseISODuration parses an ISO 8601 duration such as "PT1H30M" or
+// "P1DT2H", the only form schema.org accepts.
+func ParseISODuration(s string) (time.Duration, bool) {
+	m := isoDuration.FindStringSubmatch(s)
+	if m == nil || len(s) == 1 || strings.EqualFold(s, "PT") {
+		return 0, false
+	}
+	units := []time.Duration{7 * 24 * time.Hour, 24 * time.Hour, time.Hour, time.Minute, time.Second}
+	var d time.Duration
+	for i, unit := range units {
+		if m[i+1] == "" {
+			continue
+		}
+		n, _ := strconv.ParseFloat(m[i+1], 64)
+		d += time.Duration(n * float64(unit))
+	}
+	return d, true
+}
@@ -0,0 +1,60 @@
+// Package featured picks entities to feature, in an order that looks
+// random but only changes with a seed, so a homepage can rotate its
+// content while a pinned seed keeps builds reproducible.
+package featured
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/supermodeltools/arch-docs/internal/pssg/config"
+	"github.com/supermodeltools/arch-docs/internal/pssg/entity"
+)
+
+// Seed returns the seed a build at now features entities by: featured.seed
+// when it is set, or else one that changes every day, every ISO week or
+// every build, as featured.rotate says.
+func Seed(cfg config.FeaturedConfig, now time.Time) string {
+	if cfg.Seed != "" {
+		return cfg.Seed
+	}
+	now = now.UTC()
+	switch cfg.Rotate {
+	case "week":
+		year, week := now.ISOWeek()
+		return fmt.Sprintf("%d-W%02d", year, week)
+	case "build":
+		return strconv.FormatInt(now.UnixNano(), 10)
+	default:
+		return now.Format("2006-01-02")
+	}
+}
+
+// Shuffle orders the entities by a hash of the seed and each slug, so the
+// order is stable from build to build until the seed changes.
+func Shuffle(entities []*entity.Entity, seed string) []*entity.Entity {
+	keys := make(map[string]uint64, len(entities))
+	for _, e := range entities {
+		h := fnv.New64a()
+		h.Write([]byte(seed + "\x00" + e.Slug))
+		keys[e.Slug] = h.Sum64()
+	}
+	shuffled := append([]*entity.Entity(nil), entities...)
+	sort.Slice(shuffled, func(i, j int) bool {
+		return keys[shuffled[i].Slug] < keys[shuffled[j].Slug]
+	})
+	return shuffled
+}
+
+// Pick returns n of the entities, shuffled by seed, or all of them when
+// n is 0 or more than there are.
+func Pick(entities []*entity.Entity, seed string, n int) []*entity.Entity {
+	shuffled := Shuffle(entities, seed)
+	if n > 0 && n < len(shuffled) {
+		return shuffled[:n]
+	}
+	return shuffled
+}
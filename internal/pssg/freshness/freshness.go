@@ -0,0 +1,115 @@
+// Package freshness compares when entities were last edited with when
+// the code they document last changed, to find documentation that has
+// fallen behind.
+package freshness
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/supermodeltools/arch-docs/internal/pssg/config"
+	"github.com/supermodeltools/arch-docs/internal/pssg/entity"
+	"github.com/supermodeltools/arch-docs/internal/pssg/history"
+)
+
+// Item is one entity with the code it documents.
+type Item struct {
+	Entity       *entity.Entity `json:"-"`
+	Slug         string         `json:"slug"`
+	Title        string         `json:"title"`
+	Source       string         `json:"source"` // path relative to paths.source_dir
+	DocModified  time.Time      `json:"doc_modified"`
+	CodeModified time.Time      `json:"code_modified"`
+	LagDays      int            `json:"lag_days"` // days the code changed after the docs; 0 if the docs are newer
+	Stale        bool           `json:"stale"`
+}
+
+// Report is the freshness of every entity that documents code.
+type Report struct {
+	Generated     time.Time `json:"generated"`
+	ThresholdDays int       `json:"threshold_days"`
+	Checked       int       `json:"checked"`
+	Stale         int       `json:"stale"`
+	Items         []Item    `json:"items"` // stale first, then by lag, longest first
+	bySlug        map[string]int
+}
+
+// Item returns the entry for an entity, or nil if its code could not be
+// dated or it documents none.
+func (r *Report) Item(slug string) *Item {
+	if i, ok := r.bySlug[slug]; ok {
+		return &r.Items[i]
+	}
+	return nil
+}
+
+// Build checks every entity naming a path in one of cfg.Freshness's path
+// fields. An entity was last edited at its date field when it sets one,
+// otherwise at the last commit to its file, otherwise at the file's
+// modification time; code is dated the same way from sourceDir. Entities
+// whose code cannot be dated are left out.
+func Build(entities []*entity.Entity, cfg *config.Config, now time.Time) *Report {
+	fc := cfg.Freshness
+	r := &Report{Generated: now, ThresholdDays: fc.ThresholdDays, Items: []Item{}}
+	threshold := time.Duration(fc.ThresholdDays) * 24 * time.Hour
+	for _, e := range entities {
+		src := ""
+		for _, f := range fc.PathFields {
+			if src = e.GetString(f); src != "" {
+				break
+			}
+		}
+		// Paths outside the source tree document nothing the site knows.
+		if src == "" || !filepath.IsLocal(filepath.FromSlash(src)) && src != "." {
+			continue
+		}
+		code, ok := modified(cfg.Paths.SourceDir, filepath.FromSlash(src))
+		if !ok {
+			continue
+		}
+		doc := e.GetTime(fc.DateField)
+		if doc.IsZero() {
+			doc, _ = modified(filepath.Dir(e.SourceFile), filepath.Base(e.SourceFile))
+		}
+		it := Item{Entity: e, Slug: e.Slug, Title: e.GetString("title"), Source: src, DocModified: doc, CodeModified: code}
+		if lag := code.Sub(doc); lag > 0 {
+			it.LagDays = int(lag / (24 * time.Hour))
+			it.Stale = lag > threshold
+		}
+		if it.Stale {
+			r.Stale++
+		}
+		r.Items = append(r.Items, it)
+	}
+	r.Checked = len(r.Items)
+	sort.SliceStable(r.Items, func(i, j int) bool {
+		a, b := r.Items[i], r.Items[j]
+		if a.Stale != b.Stale {
+			return a.Stale
+		}
+		if a.LagDays != b.LagDays {
+			return a.LagDays > b.LagDays
+		}
+		return a.Slug < b.Slug
+	})
+	r.bySlug = make(map[string]int, len(r.Items))
+	for i, it := range r.Items {
+		r.bySlug[it.Slug] = i
+	}
+	return r
+}
+
+// modified dates a path inside dir by its last commit, falling back to
+// its modification time outside git.
+func modified(dir, path string) (time.Time, bool) {
+	if t, ok := history.LastChange(dir, path); ok {
+		return t, true
+	}
+	info, err := os.Stat(filepath.Join(dir, path))
+	if err != nil {
+		return time.Time{}, false
+	}
+	return info.ModTime(), true
+}
@@ -0,0 +1,102 @@
+// Package history reads the git history of entity files, so pages can
+// show what changed recently and feeds can announce it.
+package history
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// Commit is one change to a file.
+type Commit struct {
+	Hash    string
+	Author  string
+	Email   string
+	Date    time.Time
+	Subject string
+}
+
+// Short is the abbreviated hash git prints.
+func (c Commit) Short() string {
+	if len(c.Hash) > 7 {
+		return c.Hash[:7]
+	}
+	return c.Hash
+}
+
+// Field separators in the log format; neither appears in names or
+// subjects.
+const (
+	recordSep = "\x1e"
+	fieldSep  = "\x1f"
+)
+
+// Log returns the commits that touched each file under dir, newest
+// first and at most limit per file, keyed by slash-separated path
+// relative to dir. Merges are left out. One git log covers the whole
+// directory, however many files it holds; a file keeps its history from
+// its latest rename on. It fails when dir is not in a git work tree.
+func Log(dir string, limit int) (map[string][]Commit, error) {
+	format := recordSep + strings.Join([]string{"%H", "%an", "%ae", "%aI", "%s"}, fieldSep)
+	cmd := exec.Command("git", "-C", dir, "-c", "core.quotepath=false", "log", "--no-merges", "--relative", "--name-only", "--format="+format, "--", ".")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("git log in %s: %s", dir, msg)
+		}
+		return nil, fmt.Errorf("git log in %s: %w", dir, err)
+	}
+	return parse(string(out), limit), nil
+}
+
+// parse reads the records Log's format prints: the commit fields on one
+// line, then the names of the files it changed.
+func parse(out string, limit int) map[string][]Commit {
+	files := make(map[string][]Commit)
+	for _, record := range strings.Split(out, recordSep) {
+		lines := strings.Split(strings.TrimSpace(record), "\n")
+		fields := strings.Split(lines[0], fieldSep)
+		if len(fields) != 5 {
+			continue
+		}
+		date, _ := time.Parse(time.RFC3339, fields[3])
+		c := Commit{Hash: fields[0], Author: fields[1], Email: fields[2], Date: date, Subject: fields[4]}
+		for _, name := range lines[1:] {
+			name = strings.TrimSpace(name)
+			if name == "" || limit > 0 && len(files[name]) >= limit {
+				continue
+			}
+			files[name] = append(files[name], c)
+		}
+	}
+	return files
+}
+
+// LastChange returns the committer date of the latest commit to path,
+// a file or directory inside the work tree at dir. It reports false when
+// git cannot tell, as for untracked paths or outside a work tree.
+func LastChange(dir, path string) (time.Time, bool) {
+	out, err := exec.Command("git", "-C", dir, "log", "-1", "--format=%cI", "--", path).Output()
+	if err != nil {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(time.RFC3339, strings.TrimSpace(string(out)))
+	return t, err == nil
+}
+
+// Head returns the commit checked out in the work tree containing dir,
+// and whether dir holds uncommitted changes. It reports false outside a
+// work tree.
+func Head(dir string) (hash string, dirty, ok bool) {
+	out, err := exec.Command("git", "-C", dir, "rev-parse", "HEAD").Output()
+	if err != nil {
+		return "", false, false
+	}
+	status, err := exec.Command("git", "-C", dir, "status", "--porcelain", "--", ".").Output()
+	return strings.TrimSpace(string(out)), err == nil && len(bytes.TrimSpace(status)) > 0, true
+}
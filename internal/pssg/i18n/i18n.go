@@ -0,0 +1,222 @@
+// Package i18n provides the translated strings templates use through the
+// T function.
+//
+// Messages live in <paths.i18n>/<lang>.yaml, with a theme's i18n/ files as
+// a base layer. A message is either a string or a map of CLDR plural forms,
+// and nested maps group keys under a dotted prefix:
+//
+//	browse_all: "Browse all"
+//	page_of: "Page %d of %d"
+//	recipes:
+//	  one: "%d recipe"
+//	  other: "%d recipes"
+//	nav:
+//	  home: "Home"   # T "nav.home"
+package i18n
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/supermodeltools/arch-docs/internal/pssg/config"
+	"github.com/supermodeltools/arch-docs/internal/pssg/logging"
+	"github.com/supermodeltools/arch-docs/internal/pssg/theme"
+)
+
+// Catalog holds the messages for one language.
+type Catalog struct {
+	lang     string
+	plural   func(n int) string
+	messages map[string]message
+
+	mu     sync.Mutex
+	warned map[string]bool
+}
+
+// message is a plain string (stored under "other") or a set of plural forms.
+type message map[string]string
+
+// Load builds the catalog for site.language. Keys missing from its file
+// fall back to the base language ("pt" for "pt-BR") and then to the
+// default edition's language, so a partial translation still renders.
+func Load(cfg *config.Config) (*Catalog, error) {
+	lang := cfg.Site.Language
+	c := &Catalog{
+		lang:     lang,
+		plural:   pluralRule(lang),
+		messages: make(map[string]message),
+		warned:   make(map[string]bool),
+	}
+
+	chain := []string{lang}
+	if base, _, ok := strings.Cut(lang, "-"); ok {
+		chain = append(chain, base)
+	}
+	if d := cfg.I18n.Default; d != "" && d != lang {
+		chain = append(chain, d)
+	}
+
+	t, err := theme.Open(cfg)
+	if err != nil {
+		return nil, err
+	}
+	// Lowest priority first, so later files overwrite
+	for i := len(chain) - 1; i >= 0; i-- {
+		name := chain[i] + ".yaml"
+		if t != nil {
+			if data, err := fs.ReadFile(t, "i18n/"+name); err == nil {
+				if err := c.add(data, "theme i18n/"+name); err != nil {
+					return nil, err
+				}
+			}
+		}
+		if cfg.Paths.I18n == "" {
+			continue
+		}
+		path := filepath.Join(cfg.Paths.I18n, name)
+		data, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if err := c.add(data, path); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+func (c *Catalog) add(data []byte, source string) error {
+	var raw map[string]interface{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("parsing %s: %w", source, err)
+	}
+	return c.addMap("", raw, source)
+}
+
+func (c *Catalog) addMap(prefix string, raw map[string]interface{}, source string) error {
+	for k, v := range raw {
+		key := prefix + k
+		switch v := v.(type) {
+		case map[string]interface{}:
+			if isPluralForms(v) {
+				m := make(message, len(v))
+				for form, s := range v {
+					m[form] = fmt.Sprint(s)
+				}
+				c.messages[key] = m
+				continue
+			}
+			if err := c.addMap(key+".", v, source); err != nil {
+				return err
+			}
+		case nil:
+			return fmt.Errorf("%s: %s has no value", source, key)
+		default:
+			c.messages[key] = message{"other": fmt.Sprint(v)}
+		}
+	}
+	return nil
+}
+
+// Has reports whether key has a message.
+func (c *Catalog) Has(key string) bool {
+	_, ok := c.messages[key]
+	return ok
+}
+
+// Keys returns every message key, sorted.
+func (c *Catalog) Keys() []string {
+	keys := make([]string, 0, len(c.messages))
+	for k := range c.messages {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// T returns the message for key formatted with args. If the message has
+// plural forms, the first integer argument selects one; a "zero" form, if
+// given, is used for 0 in every language. An unknown key is returned
+// as-is, and is reported once if the language has any messages at all.
+func (c *Catalog) T(key string, args ...interface{}) string {
+	m, ok := c.messages[key]
+	if !ok {
+		c.warnMissing(key)
+		return format(key, args)
+	}
+	s := m["other"]
+	if len(m) > 1 {
+		if n, ok := count(args); ok {
+			if z, ok := m["zero"]; ok && n == 0 {
+				s = z
+			} else if f, ok := m[c.plural(n)]; ok {
+				s = f
+			}
+		}
+	}
+	return format(s, args)
+}
+
+func (c *Catalog) warnMissing(key string) {
+	if len(c.messages) == 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.warned[key] {
+		c.warned[key] = true
+		logging.Stage("render").Warn("Missing translation", "language", c.lang, "key", key)
+	}
+}
+
+// format applies args only if the message has verbs, so a translation may
+// leave out a count its English original shows.
+func format(s string, args []interface{}) string {
+	if len(args) == 0 || !strings.Contains(s, "%") {
+		return s
+	}
+	return fmt.Sprintf(s, args...)
+}
+
+func count(args []interface{}) (int, bool) {
+	for _, a := range args {
+		switch v := a.(type) {
+		case int:
+			return v, true
+		case int64:
+			return int(v), true
+		case float64:
+			if v == float64(int(v)) {
+				return int(v), true
+			}
+		}
+	}
+	return 0, false
+}
+
+func isPluralForms(m map[string]interface{}) bool {
+	if _, ok := m["other"]; !ok {
+		return false
+	}
+	for k, v := range m {
+		if _, nested := v.(map[string]interface{}); nested {
+			return false
+		}
+		switch k {
+		case "zero", "one", "two", "few", "many", "other":
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,173 @@
+package i18n
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Locale formats numbers and dates for a BCP 47 locale such as "fr-FR".
+// Languages it has no data for format like en-US.
+type Locale struct {
+	Tag    string
+	lang   string
+	region string
+}
+
+// NewLocale returns the locale for tag.
+func NewLocale(tag string) Locale {
+	lang, region, _ := strings.Cut(tag, "-")
+	if i := strings.IndexByte(region, '-'); i >= 0 {
+		region = region[:i]
+	}
+	return Locale{Tag: tag, lang: strings.ToLower(lang), region: strings.ToUpper(region)}
+}
+
+// Imperial reports whether the locale measures in US customary units:
+// the United States, Liberia and Myanmar.
+func (l Locale) Imperial() bool {
+	switch l.region {
+	case "US", "LR", "MM":
+		return true
+	case "":
+		return l.lang == "en" || l.lang == "my"
+	}
+	return false
+}
+
+// separators returns the decimal and grouping separators. Languages that
+// group with a space use a no-break space so numbers never wrap.
+func (l Locale) separators() (string, string) {
+	switch l.lang {
+	case "de":
+		if l.region == "CH" || l.region == "LI" {
+			return ".", "\u2019"
+		}
+		return ",", "."
+	case "es", "it", "nl", "id", "tr", "el", "da", "ro", "hr", "sl", "sr", "vi":
+		return ",", "."
+	case "pt":
+		if l.region == "PT" {
+			return ",", "\u00a0"
+		}
+		return ",", "."
+	case "fr":
+		return ",", "\u202f"
+	case "ru", "uk", "be", "pl", "cs", "sk", "sv", "nb", "no", "fi", "hu", "bg", "lt", "lv", "et":
+		return ",", "\u00a0"
+	}
+	return ".", ","
+}
+
+// FormatNumber formats f with the given number of decimals, or with as
+// many as it needs (up to three) if decimals is negative.
+func (l Locale) FormatNumber(f float64, decimals int) string {
+	var s string
+	if decimals < 0 {
+		s = strconv.FormatFloat(math.Round(f*1000)/1000, 'f', -1, 64)
+	} else {
+		s = strconv.FormatFloat(f, 'f', decimals, 64)
+	}
+	neg := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(s, "-")
+	intPart, frac, _ := strings.Cut(s, ".")
+
+	dec, group := l.separators()
+	var b strings.Builder
+	if neg {
+		b.WriteString("-")
+	}
+	for i, c := range intPart {
+		if i > 0 && (len(intPart)-i)%3 == 0 {
+			b.WriteString(group)
+		}
+		b.WriteRune(c)
+	}
+	if frac != "" {
+		b.WriteString(dec)
+		b.WriteString(frac)
+	}
+	return b.String()
+}
+
+// FormatDate formats t in the "long" style (2 January 2006, with month
+// names in the locale's language) or the numeric "short" style.
+func (l Locale) FormatDate(t time.Time, style string) string {
+	if style == "short" {
+		switch {
+		case l.lang == "en" && (l.region == "US" || l.region == ""):
+			return t.Format("1/2/2006")
+		case l.lang == "ja" || l.lang == "zh" || l.lang == "ko" || l.lang == "hu" || l.lang == "sv" || l.lang == "lt":
+			return t.Format("2006-01-02")
+		case l.lang == "de" || l.lang == "ru" || l.lang == "pl" || l.lang == "cs" || l.lang == "fi" || l.lang == "nb" || l.lang == "da" || l.lang == "uk" || l.lang == "tr":
+			return t.Format("02.01.2006")
+		case l.lang == "nl":
+			return t.Format("02-01-2006")
+		}
+		return t.Format("02/01/2006")
+	}
+
+	d, y := t.Day(), t.Year()
+	switch l.lang {
+	case "ja", "zh":
+		return fmt.Sprintf("%d年%d月%d日", y, int(t.Month()), d)
+	case "ko":
+		return fmt.Sprintf("%d년 %d월 %d일", y, int(t.Month()), d)
+	case "hu":
+		return fmt.Sprintf("%d. %s %d.", y, l.month(t.Month()), d)
+	}
+	m := l.month(t.Month())
+	switch l.lang {
+	case "en":
+		if l.region == "US" || l.region == "" {
+			return fmt.Sprintf("%s %d, %d", m, d, y)
+		}
+	case "de", "da", "nb", "no", "cs", "fi":
+		return fmt.Sprintf("%d. %s %d", d, m, y)
+	case "es", "pt":
+		return fmt.Sprintf("%d de %s de %d", d, m, y)
+	case "ru", "uk":
+		return fmt.Sprintf("%d %s %d г.", d, m, y)
+	}
+	return fmt.Sprintf("%d %s %d", d, m, y)
+}
+
+func (l Locale) month(m time.Month) string {
+	if names, ok := monthNames[l.lang]; ok {
+		return names[m-1]
+	}
+	return m.String()
+}
+
+// monthNames are in the form used after a day number, which for Slavic
+// languages is the genitive.
+var monthNames = map[string][12]string{
+	"fr": {"janvier", "février", "mars", "avril", "mai", "juin", "juillet", "août", "septembre", "octobre", "novembre", "décembre"},
+	"de": {"Januar", "Februar", "März", "April", "Mai", "Juni", "Juli", "August", "September", "Oktober", "November", "Dezember"},
+	"es": {"enero", "febrero", "marzo", "abril", "mayo", "junio", "julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"},
+	"it": {"gennaio", "febbraio", "marzo", "aprile", "maggio", "giugno", "luglio", "agosto", "settembre", "ottobre", "novembre", "dicembre"},
+	"pt": {"janeiro", "fevereiro", "março", "abril", "maio", "junho", "julho", "agosto", "setembro", "outubro", "novembro", "dezembro"},
+	"nl": {"januari", "februari", "maart", "april", "mei", "juni", "juli", "augustus", "september", "oktober", "november", "december"},
+	"sv": {"januari", "februari", "mars", "april", "maj", "juni", "juli", "augusti", "september", "oktober", "november", "december"},
+	"da": {"januar", "februar", "marts", "april", "maj", "juni", "juli", "august", "september", "oktober", "november", "december"},
+	"nb": {"januar", "februar", "mars", "april", "mai", "juni", "juli", "august", "september", "oktober", "november", "desember"},
+	"pl": {"stycznia", "lutego", "marca", "kwietnia", "maja", "czerwca", "lipca", "sierpnia", "września", "października", "listopada", "grudnia"},
+	"ru": {"января", "февраля", "марта", "апреля", "мая", "июня", "июля", "августа", "сентября", "октября", "ноября", "декабря"},
+	"uk": {"січня", "лютого", "березня", "квітня", "травня", "червня", "липня", "серпня", "вересня", "жовтня", "листопада", "грудня"},
+	"cs": {"ledna", "února", "března", "dubna", "května", "června", "července", "srpna", "září", "října", "listopadu", "prosince"},
+	"tr": {"Ocak", "Şubat", "Mart", "Nisan", "Mayıs", "Haziran", "Temmuz", "Ağustos", "Eylül", "Ekim", "Kasım", "Aralık"},
+	"hu": {"január", "február", "március", "április", "május", "június", "július", "augusztus", "szeptember", "október", "november", "december"},
+	"fi": {"tammikuuta", "helmikuuta", "maaliskuuta", "huhtikuuta", "toukokuuta", "kesäkuuta", "heinäkuuta", "elokuuta", "syyskuuta", "lokakuuta", "marraskuuta", "joulukuuta"},
+}
+
+// IsRTL reports whether lang is written right to left.
+func IsRTL(lang string) bool {
+	base, _, _ := strings.Cut(strings.ToLower(lang), "-")
+	switch base {
+	case "ar", "he", "fa", "ur", "ps", "yi", "dv", "ckb", "sd", "ug":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,82 @@
+package i18n
+
+import "strings"
+
+// pluralRule returns the CLDR plural category function for integer counts
+// in lang. Languages not listed use the English rule.
+func pluralRule(lang string) func(n int) string {
+	base, _, _ := strings.Cut(strings.ToLower(lang), "-")
+	switch base {
+	case "ja", "zh", "ko", "vi", "th", "id", "ms", "tr", "fa":
+		return func(int) string { return "other" }
+	case "fr", "pt", "hi", "bn":
+		return func(n int) string {
+			if n == 0 || n == 1 {
+				return "one"
+			}
+			return "other"
+		}
+	case "ru", "uk", "be", "sr", "hr", "bs":
+		return func(n int) string {
+			switch {
+			case n%10 == 1 && n%100 != 11:
+				return "one"
+			case n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14):
+				return "few"
+			}
+			return "many"
+		}
+	case "pl":
+		return func(n int) string {
+			switch {
+			case n == 1:
+				return "one"
+			case n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14):
+				return "few"
+			}
+			return "many"
+		}
+	case "cs", "sk":
+		return func(n int) string {
+			switch {
+			case n == 1:
+				return "one"
+			case n >= 2 && n <= 4:
+				return "few"
+			}
+			return "other"
+		}
+	case "ar":
+		return func(n int) string {
+			switch {
+			case n == 0:
+				return "zero"
+			case n == 1:
+				return "one"
+			case n == 2:
+				return "two"
+			case n%100 >= 3 && n%100 <= 10:
+				return "few"
+			case n%100 >= 11:
+				return "many"
+			}
+			return "other"
+		}
+	case "he":
+		return func(n int) string {
+			switch n {
+			case 1:
+				return "one"
+			case 2:
+				return "two"
+			}
+			return "other"
+		}
+	}
+	return func(n int) string {
+		if n == 1 {
+			return "one"
+		}
+		return "other"
+	}
+}
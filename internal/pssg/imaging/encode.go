@@ -0,0 +1,75 @@
+package imaging
+
+import (
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"os/exec"
+	"strconv"
+)
+
+// encoders name the command-line tool that writes each extra format. The
+// standard library only encodes JPEG and PNG.
+var encoders = map[string]string{
+	"webp": "cwebp",
+	"avif": "avifenc",
+}
+
+var lookPath = exec.LookPath
+
+// extension returns the file extension for a format name.
+func extension(format string) string {
+	if format == "jpeg" {
+		return "jpg"
+	}
+	return format
+}
+
+// encode writes img to path in the given format. WebP and AVIF go through
+// a lossless PNG handed to the external encoder.
+func encode(img image.Image, format string, quality int, path string) error {
+	tmp := path + ".tmp"
+	defer os.Remove(tmp)
+
+	switch format {
+	case "jpeg", "png":
+		f, err := os.Create(tmp)
+		if err != nil {
+			return err
+		}
+		if format == "jpeg" {
+			err = jpeg.Encode(f, img, &jpeg.Options{Quality: quality})
+		} else {
+			err = (&png.Encoder{CompressionLevel: png.BestCompression}).Encode(f, img)
+		}
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return fmt.Errorf("encoding %s: %w", format, err)
+		}
+	case "webp", "avif":
+		in := path + ".png"
+		defer os.Remove(in)
+		if err := encode(img, "png", quality, in); err != nil {
+			return err
+		}
+		q := strconv.Itoa(quality)
+		var cmd *exec.Cmd
+		if format == "webp" {
+			cmd = exec.Command(encoders[format], "-quiet", "-q", q, in, "-o", tmp)
+		} else {
+			cmd = exec.Command(encoders[format], "-q", q, in, tmp)
+		}
+		if out, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("%s: %w: %s", encoders[format], err, out)
+		}
+	default:
+		return fmt.Errorf("unknown image format %q", format)
+	}
+	// Renamed into place so an interrupted build never leaves a partial
+	// file in the cache.
+	return os.Rename(tmp, path)
+}
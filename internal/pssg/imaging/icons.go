@@ -0,0 +1,186 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/supermodeltools/arch-docs/internal/pssg/config"
+)
+
+// Icon is one generated favicon or app icon.
+type Icon struct {
+	Href    string
+	Size    int
+	Type    string
+	Rel     string // <link rel> for the page head; empty for manifest-only icons
+	Purpose string // manifest purpose: "any" or "maskable"
+}
+
+// Sizes is the icon's size attribute, e.g. "32x32", or "any" for the
+// multi-size favicon.ico.
+func (i Icon) Sizes() string {
+	if i.Size == 0 {
+		return "any"
+	}
+	return fmt.Sprintf("%dx%d", i.Size, i.Size)
+}
+
+// iconSpec describes one PNG in the icon set.
+type iconSpec struct {
+	name     string
+	size     int
+	rel      string
+	purpose  string
+	opaque   bool // flattened onto the background
+	maskable bool // inset to the maskable safe zone
+}
+
+var iconSpecs = []iconSpec{
+	{name: "favicon-16x16.png", size: 16, rel: "icon"},
+	{name: "favicon-32x32.png", size: 32, rel: "icon"},
+	{name: "apple-touch-icon.png", size: 180, rel: "apple-touch-icon", opaque: true},
+	{name: "icon-192.png", size: 192, purpose: "any"},
+	{name: "icon-512.png", size: 512, purpose: "any"},
+	{name: "icon-maskable-192.png", size: 192, purpose: "maskable", opaque: true, maskable: true},
+	{name: "icon-maskable-512.png", size: 512, purpose: "maskable", opaque: true, maskable: true},
+}
+
+// icoSizes are the PNG frames packed into favicon.ico.
+var icoSizes = []int{16, 32, 48}
+
+// SiteIcons lists the icon set WriteIcons produces for cfg, or nil if no
+// icons.source is configured. Icons live at the root of the default
+// edition, so other languages link to the same files.
+func SiteIcons(cfg *config.Config) []Icon {
+	if cfg.Icons.Source == "" {
+		return nil
+	}
+	baseURL := cfg.I18n.RootURL
+	if baseURL == "" {
+		baseURL = cfg.Site.BaseURL
+	}
+	icons := []Icon{{Href: baseURL + "/favicon.ico", Type: "image/x-icon", Rel: "icon"}}
+	for _, s := range iconSpecs {
+		icons = append(icons, Icon{
+			Href:    baseURL + "/" + s.name,
+			Size:    s.size,
+			Type:    "image/png",
+			Rel:     s.rel,
+			Purpose: s.purpose,
+		})
+	}
+	return icons
+}
+
+// WriteIcons renders the icon set from source into outDir. background is
+// a "#rrggbb" color for the icons that cannot be transparent.
+func WriteIcons(source, background, outDir string) error {
+	f, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	src, _, err := image.Decode(f)
+	if err != nil {
+		return fmt.Errorf("decoding %s: %w", source, err)
+	}
+	b := src.Bounds()
+	if b.Dx() != b.Dy() {
+		src = cropTo(src, min(b.Dx(), b.Dy()), min(b.Dx(), b.Dy()))
+	}
+	bg, err := parseHexColor(background)
+	if err != nil {
+		return err
+	}
+
+	for _, s := range iconSpecs {
+		var img image.Image = resize(src, s.size, s.size)
+		if s.maskable {
+			// The maskable safe zone is a circle of 80% of the icon's
+			// width; anything outside it may be cropped by the launcher.
+			inner := s.size * 4 / 5
+			img = onBackground(resize(src, inner, inner), s.size, bg)
+		} else if s.opaque {
+			img = onBackground(img, s.size, bg)
+		}
+		if err := writePNG(img, filepath.Join(outDir, s.name)); err != nil {
+			return err
+		}
+	}
+
+	ico, err := encodeICO(src, icoSizes)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(outDir, "favicon.ico"), ico, 0644)
+}
+
+// onBackground centers img on a size×size square of bg.
+func onBackground(img image.Image, size int, bg color.RGBA) *image.RGBA {
+	out := image.NewRGBA(image.Rect(0, 0, size, size))
+	draw.Draw(out, out.Bounds(), image.NewUniform(bg), image.Point{}, draw.Src)
+	b := img.Bounds()
+	off := image.Pt((size-b.Dx())/2, (size-b.Dy())/2)
+	draw.Draw(out, b.Sub(b.Min).Add(off), img, b.Min, draw.Over)
+	return out
+}
+
+func writePNG(img image.Image, path string) error {
+	var buf bytes.Buffer
+	if err := (&png.Encoder{CompressionLevel: png.BestCompression}).Encode(&buf, img); err != nil {
+		return err
+	}
+	return os.WriteFile(path, buf.Bytes(), 0644)
+}
+
+// encodeICO packs PNG frames of src at each size into an ICO file, which
+// every current browser accepts in place of the old bitmap frames.
+func encodeICO(src image.Image, sizes []int) ([]byte, error) {
+	frames := make([][]byte, len(sizes))
+	for i, size := range sizes {
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, resize(src, size, size)); err != nil {
+			return nil, err
+		}
+		frames[i] = buf.Bytes()
+	}
+
+	var out bytes.Buffer
+	binary.Write(&out, binary.LittleEndian, [3]uint16{0, 1, uint16(len(sizes))})
+	offset := 6 + 16*len(sizes)
+	for i, size := range sizes {
+		dim := uint8(size)
+		if size >= 256 {
+			dim = 0 // 0 means 256
+		}
+		binary.Write(&out, binary.LittleEndian, struct {
+			Width, Height, Colors, Reserved uint8
+			Planes, BitCount                uint16
+			Size, Offset                    uint32
+		}{dim, dim, 0, 0, 1, 32, uint32(len(frames[i])), uint32(offset)})
+		offset += len(frames[i])
+	}
+	for _, f := range frames {
+		out.Write(f)
+	}
+	return out.Bytes(), nil
+}
+
+func parseHexColor(s string) (color.RGBA, error) {
+	if len(s) != 7 || s[0] != '#' {
+		return color.RGBA{}, fmt.Errorf("invalid color %q", s)
+	}
+	v, err := strconv.ParseUint(s[1:], 16, 32)
+	if err != nil {
+		return color.RGBA{}, fmt.Errorf("invalid color %q", s)
+	}
+	return color.RGBA{uint8(v >> 16), uint8(v >> 8), uint8(v), 255}, nil
+}
@@ -0,0 +1,333 @@
+// Package imaging turns entity hero images into resized, re-encoded
+// variants. Results are cached in paths.cache by source content, so an
+// unchanged image costs one hash per build.
+package imaging
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"image"
+	_ "image/gif" // register decoders for image.Decode
+	_ "image/jpeg"
+	_ "image/png"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/supermodeltools/arch-docs/internal/pssg/config"
+	"github.com/supermodeltools/arch-docs/internal/pssg/entity"
+	"github.com/supermodeltools/arch-docs/internal/pssg/logging"
+)
+
+// shareWidth and shareHeight are the share image canvas the photo is
+// cropped to.
+const (
+	shareWidth  = 1200
+	shareHeight = 630
+)
+
+// missingEncoders records the tools already reported missing, so each is
+// warned about once per run rather than once per image or edition.
+var missingEncoders sync.Map
+
+// Variant is one encoded rendition of an image.
+type Variant struct {
+	URL    string
+	Width  int
+	Height int
+	Type   string // MIME type, e.g. "image/webp"
+}
+
+// Set is every rendition generated for one source image.
+type Set struct {
+	Source string // the frontmatter value
+	Width  int    // of the source
+	Height int
+	// Variants are ordered by format, the fallback format (JPEG or PNG)
+	// last, then by ascending width.
+	Variants   []Variant
+	Thumbnails []Variant // one per format, in the same order
+
+	share string // cached JPEG cropped to the share image canvas
+}
+
+// ShareDataURI returns the photo cropped to the 1200×630 share image
+// canvas as a data: URI, for embedding in the share image SVG.
+func (s *Set) ShareDataURI() (string, error) {
+	data, err := os.ReadFile(s.share)
+	if err != nil {
+		return "", err
+	}
+	return "data:image/jpeg;base64," + base64.StdEncoding.EncodeToString(data), nil
+}
+
+// ShareKey identifies the share image crop by its source and encoding,
+// without reading it.
+func (s *Set) ShareKey() string {
+	return filepath.Base(s.share)
+}
+
+// Types lists the MIME types of the variants, the fallback type last.
+func (s *Set) Types() []string {
+	var types []string
+	for _, v := range s.Variants {
+		if len(types) == 0 || types[len(types)-1] != v.Type {
+			types = append(types, v.Type)
+		}
+	}
+	return types
+}
+
+// ByType returns the variants of one MIME type, narrowest first.
+func (s *Set) ByType(mime string) []Variant {
+	var out []Variant
+	for _, v := range s.Variants {
+		if v.Type == mime {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// Fallback returns the variants every browser can display.
+func (s *Set) Fallback() []Variant {
+	types := s.Types()
+	if len(types) == 0 {
+		return nil
+	}
+	return s.ByType(types[len(types)-1])
+}
+
+// Largest returns the widest fallback variant, for og:image and JSON-LD.
+func (s *Set) Largest() Variant {
+	fb := s.Fallback()
+	if len(fb) == 0 {
+		return Variant{}
+	}
+	return fb[len(fb)-1]
+}
+
+// Thumbnail returns the fallback-format thumbnail.
+func (s *Set) Thumbnail() Variant {
+	if len(s.Thumbnails) == 0 {
+		return Variant{}
+	}
+	return s.Thumbnails[len(s.Thumbnails)-1]
+}
+
+// Processor generates image sets for entities.
+type Processor struct {
+	cfg      config.ImagesConfig
+	baseURL  string
+	static   string
+	cacheDir string
+
+	mu      sync.Mutex
+	encoded map[string]*sync.Mutex // cache file -> lock, so shared sources are encoded once
+	remote  *remoteCache
+}
+
+// New returns a processor for cfg's images settings.
+func New(cfg *config.Config) *Processor {
+	return &Processor{
+		cfg:      cfg.Images,
+		baseURL:  cfg.Site.BaseURL,
+		static:   cfg.Paths.Static,
+		cacheDir: filepath.Join(cfg.Paths.Cache, "images"),
+		encoded:  make(map[string]*sync.Mutex),
+		remote:   loadRemoteCache(filepath.Join(cfg.Paths.Cache, "images", "remote")),
+	}
+}
+
+// Save records the checksums of downloaded images for later builds.
+func (p *Processor) Save() error {
+	return p.remote.save()
+}
+
+// Process generates the variants of e's image into outDir and returns
+// them, or nil if e has no image.
+func (p *Processor) Process(e *entity.Entity, outDir string) (*Set, error) {
+	src := strings.TrimSpace(e.GetString(p.cfg.Field))
+	if src == "" {
+		return nil, nil
+	}
+	data, err := p.read(src, e.SourceFile)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:8])
+
+	conf, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("decoding %s: %w", src, err)
+	}
+	set := &Set{Source: src, Width: conf.Width, Height: conf.Height}
+
+	// Decoded lazily: a fully cached set never needs the pixels.
+	var img image.Image
+	decode := func() (image.Image, error) {
+		if img == nil {
+			decoded, _, err := image.Decode(bytes.NewReader(data))
+			if err != nil {
+				return nil, fmt.Errorf("decoding %s: %w", src, err)
+			}
+			img = decoded
+		}
+		return img, nil
+	}
+
+	fallback := "jpeg"
+	if format == "png" {
+		fallback = "png"
+	}
+	formats := append(p.available(), fallback)
+	widths := p.widths(conf.Width)
+	dir := filepath.Join(outDir, filepath.FromSlash(p.cfg.Dir))
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	for _, f := range formats {
+		for _, w := range widths {
+			h := max(1, conf.Height*w/conf.Width)
+			name := fmt.Sprintf("%s-%d.%s", e.Slug, w, extension(f))
+			err := p.variant(hash, f, w, h, false, filepath.Join(dir, name), decode)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", src, err)
+			}
+			set.Variants = append(set.Variants, p.entry(name, hash, f, w, h))
+		}
+		tw, th := p.cfg.Thumbnail.Width, p.cfg.Thumbnail.Height
+		name := fmt.Sprintf("%s-thumb.%s", e.Slug, extension(f))
+		if err := p.variant(hash, f, tw, th, true, filepath.Join(dir, name), decode); err != nil {
+			return nil, fmt.Errorf("%s: %w", src, err)
+		}
+		set.Thumbnails = append(set.Thumbnails, p.entry(name, hash, f, tw, th))
+	}
+	if set.share, err = p.cache(hash, "jpeg", shareWidth, shareHeight, true, decode); err != nil {
+		return nil, fmt.Errorf("%s: %w", src, err)
+	}
+	return set, nil
+}
+
+// widths are the configured widths narrower than the source, or the
+// source width alone if it is narrower than all of them.
+func (p *Processor) widths(source int) []int {
+	var out []int
+	for _, w := range p.cfg.Widths {
+		if w < source {
+			out = append(out, w)
+		}
+	}
+	if len(out) < len(p.cfg.Widths) {
+		out = append(out, source)
+	}
+	return out
+}
+
+func (p *Processor) entry(name, hash, format string, w, h int) Variant {
+	return Variant{
+		URL:    p.baseURL + "/" + path.Join(p.cfg.Dir, name) + "?v=" + hash[:8],
+		Width:  w,
+		Height: h,
+		Type:   "image/" + format,
+	}
+}
+
+// variant writes one rendition to dst.
+func (p *Processor) variant(hash, format string, w, h int, crop bool, dst string, decode func() (image.Image, error)) error {
+	cached, err := p.cache(hash, format, w, h, crop, decode)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(cached)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(dst, data, 0644)
+}
+
+// cache returns the cache file of one rendition, encoding it first if this
+// source, format and size have not been seen before.
+func (p *Processor) cache(hash, format string, w, h int, crop bool, decode func() (image.Image, error)) (string, error) {
+	kind := "w"
+	if crop {
+		kind = "c"
+	}
+	cached := filepath.Join(p.cacheDir, fmt.Sprintf("%s-%s%dx%d-q%d.%s", hash, kind, w, h, p.cfg.Quality, extension(format)))
+
+	lock := p.lock(cached)
+	lock.Lock()
+	defer lock.Unlock()
+
+	if _, err := os.Stat(cached); os.IsNotExist(err) {
+		img, err := decode()
+		if err != nil {
+			return "", err
+		}
+		var out image.Image
+		if crop {
+			out = cropTo(img, w, h)
+		} else {
+			out = resize(img, w, h)
+		}
+		if err := os.MkdirAll(p.cacheDir, 0755); err != nil {
+			return "", err
+		}
+		if err := encode(out, format, p.cfg.Quality, cached); err != nil {
+			return "", err
+		}
+	}
+	return cached, nil
+}
+
+func (p *Processor) lock(key string) *sync.Mutex {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	l, ok := p.encoded[key]
+	if !ok {
+		l = new(sync.Mutex)
+		p.encoded[key] = l
+	}
+	return l
+}
+
+// available returns the configured extra formats whose encoder is
+// installed, warning once about each that is not.
+func (p *Processor) available() []string {
+	var out []string
+	for _, f := range p.cfg.Formats {
+		tool := encoders[f]
+		if _, err := lookPath(tool); err != nil {
+			if _, seen := missingEncoders.LoadOrStore(tool, true); !seen {
+				logging.Stage("images").Warn("Encoder not found, skipping format", "format", f, "tool", tool)
+			}
+			continue
+		}
+		out = append(out, f)
+	}
+	return out
+}
+
+// read returns the bytes of a local or remote image.
+func (p *Processor) read(src, entityFile string) ([]byte, error) {
+	if IsRemote(src) {
+		return p.remote.fetch(src)
+	}
+	var file string
+	switch {
+	case strings.HasPrefix(src, "/") && p.static != "":
+		file = filepath.Join(p.static, filepath.FromSlash(src))
+	case filepath.IsAbs(src):
+		file = src
+	default:
+		file = filepath.Join(filepath.Dir(entityFile), filepath.FromSlash(src))
+	}
+	return os.ReadFile(file)
+}
@@ -0,0 +1,235 @@
+package imaging
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/supermodeltools/arch-docs/internal/pssg/entity"
+	"github.com/supermodeltools/arch-docs/internal/pssg/logging"
+)
+
+// maxRemoteImageSize caps downloads of remote images.
+const maxRemoteImageSize = 32 << 20
+
+var remoteClient = &http.Client{Timeout: 60 * time.Second}
+
+// IsRemote reports whether an image reference is an http(s) URL.
+func IsRemote(src string) bool {
+	return strings.HasPrefix(src, "https://") || strings.HasPrefix(src, "http://")
+}
+
+// remoteEntry records one downloaded image.
+type remoteEntry struct {
+	File    string    `json:"file"`
+	SHA256  string    `json:"sha256"`
+	Type    string    `json:"type"`
+	Fetched time.Time `json:"fetched"`
+}
+
+// remoteCache keeps downloaded images in paths.cache with the checksum
+// each had when it was fetched. A cached copy that no longer matches is
+// fetched again, and a URL pinned with #sha256=<hex>, as remote configs
+// are, must match its pin.
+type remoteCache struct {
+	dir string
+
+	mu      sync.Mutex
+	entries map[string]remoteEntry
+	locks   map[string]*sync.Mutex
+	dirty   bool
+}
+
+func loadRemoteCache(dir string) *remoteCache {
+	c := &remoteCache{dir: dir, entries: make(map[string]remoteEntry), locks: make(map[string]*sync.Mutex)}
+	if data, err := os.ReadFile(filepath.Join(dir, "index.json")); err == nil {
+		if err := json.Unmarshal(data, &c.entries); err != nil {
+			logging.Stage("images").Warn("Ignoring unreadable remote image index", "error", err)
+		}
+	}
+	return c
+}
+
+func (c *remoteCache) save() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.dirty {
+		return nil
+	}
+	data, err := json.MarshalIndent(c.entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(c.dir, 0755); err != nil {
+		return err
+	}
+	c.dirty = false
+	return os.WriteFile(filepath.Join(c.dir, "index.json"), data, 0644)
+}
+
+// lock serializes fetches of one URL, so entities sharing an image
+// download it once.
+func (c *remoteCache) lock(u string) *sync.Mutex {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	l, ok := c.locks[u]
+	if !ok {
+		l = new(sync.Mutex)
+		c.locks[u] = l
+	}
+	return l
+}
+
+// fetch returns a remote image, from the cache when the cached copy is
+// intact.
+func (c *remoteCache) fetch(raw string) ([]byte, error) {
+	data, _, err := c.get(raw)
+	return data, err
+}
+
+// get is fetch that also returns the image's MIME type.
+func (c *remoteCache) get(raw string) ([]byte, string, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, "", err
+	}
+	pin, _ := strings.CutPrefix(u.Fragment, "sha256=")
+	pin = strings.ToLower(pin)
+	u.Fragment = ""
+	key := u.String()
+
+	l := c.lock(key)
+	l.Lock()
+	defer l.Unlock()
+
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	c.mu.Unlock()
+	if ok && (pin == "" || pin == entry.SHA256) {
+		data, err := os.ReadFile(filepath.Join(c.dir, entry.File))
+		if err == nil && checksum(data) == entry.SHA256 {
+			return data, entry.Type, nil
+		}
+		logging.Stage("images").Warn("Cached image is missing or changed, fetching again", "url", key)
+	}
+
+	resp, err := remoteClient.Get(key)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("GET %s: %s", key, resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxRemoteImageSize+1))
+	if err != nil {
+		return nil, "", err
+	}
+	if len(data) > maxRemoteImageSize {
+		return nil, "", fmt.Errorf("GET %s: larger than %d bytes", key, maxRemoteImageSize)
+	}
+	typ := http.DetectContentType(data)
+	if !strings.HasPrefix(typ, "image/") {
+		// Sniffing misses some formats, such as SVG and AVIF.
+		typ, _, _ = mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	}
+	if !strings.HasPrefix(typ, "image/") {
+		return nil, "", fmt.Errorf("GET %s: not an image (%s)", key, typ)
+	}
+	sum := checksum(data)
+	if pin != "" && sum != pin {
+		return nil, "", fmt.Errorf("GET %s: checksum mismatch: pinned sha256 %s, got %s", key, pin, sum)
+	}
+	logging.Stage("images").Debug("Downloaded image", "url", key, "kb", len(data)/1024)
+
+	entry = remoteEntry{File: checksum([]byte(key))[:32], SHA256: sum, Type: typ, Fetched: time.Now().UTC()}
+	if err := os.MkdirAll(c.dir, 0755); err != nil {
+		return nil, "", err
+	}
+	if err := os.WriteFile(filepath.Join(c.dir, entry.File), data, 0644); err != nil {
+		return nil, "", err
+	}
+	c.mu.Lock()
+	c.entries[key] = entry
+	c.dirty = true
+	c.mu.Unlock()
+	return data, typ, nil
+}
+
+// Localize downloads the remote images named by images.remote_fields on e
+// and copies them into <outDir>/<images.dir>/remote/, rewriting the fields
+// to the local URLs so pages never hot-link third-party images. Fields may
+// hold one URL or a list. Local values are left as they are.
+func (p *Processor) Localize(e *entity.Entity, outDir string) error {
+	var errs []error
+	local := func(src string) string {
+		if !IsRemote(src) {
+			return src
+		}
+		data, typ, err := p.remote.get(src)
+		if err != nil {
+			errs = append(errs, err)
+			return src
+		}
+		name := checksum(data)[:16] + imageExtension(typ, src)
+		dir := filepath.Join(outDir, filepath.FromSlash(p.cfg.Dir), "remote")
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			errs = append(errs, err)
+			return src
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0644); err != nil {
+			errs = append(errs, err)
+			return src
+		}
+		return p.baseURL + "/" + path.Join(p.cfg.Dir, "remote", name)
+	}
+
+	for _, field := range p.cfg.RemoteFields {
+		switch v := e.Fields[field].(type) {
+		case string:
+			e.Fields[field] = local(v)
+		case []interface{}:
+			for i, item := range v {
+				if s, ok := item.(string); ok {
+					v[i] = local(s)
+				}
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// imageExtension picks a file extension for a downloaded image from its
+// MIME type, falling back to the URL's own.
+func imageExtension(typ, src string) string {
+	switch typ {
+	case "image/jpeg":
+		return ".jpg"
+	case "image/svg+xml":
+		return ".svg"
+	}
+	if sub, ok := strings.CutPrefix(typ, "image/"); ok && !strings.ContainsAny(sub, "+.-") {
+		return "." + sub
+	}
+	if u, err := url.Parse(src); err == nil {
+		return path.Ext(u.Path)
+	}
+	return ""
+}
+
+func checksum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,131 @@
+package imaging
+
+import (
+	"image"
+	"image/draw"
+	"math"
+)
+
+// resize scales img to w×h with a tent filter widened by the scale factor,
+// so downscaling averages every source pixel instead of skipping rows.
+func resize(img image.Image, w, h int) *image.RGBA {
+	src := toRGBA(img)
+	sw, sh := src.Bounds().Dx(), src.Bounds().Dy()
+
+	pix := make([]float32, len(src.Pix))
+	for i, v := range src.Pix {
+		pix[i] = float32(v)
+	}
+	pix = resampleRows(pix, sw, sh, w)
+	pix = resampleColumns(pix, w, sh, h)
+
+	out := image.NewRGBA(image.Rect(0, 0, w, h))
+	for i, v := range pix {
+		out.Pix[i] = uint8(math.Round(math.Max(0, math.Min(255, float64(v)))))
+	}
+	return out
+}
+
+// cropTo scales img to cover w×h and cuts the overflow evenly from both
+// sides.
+func cropTo(img image.Image, w, h int) *image.RGBA {
+	b := img.Bounds()
+	cw, ch := b.Dx(), b.Dy()
+	if cw*h > ch*w {
+		cw = max(1, ch*w/h)
+	} else {
+		ch = max(1, cw*h/w)
+	}
+	x := b.Min.X + (b.Dx()-cw)/2
+	y := b.Min.Y + (b.Dy()-ch)/2
+
+	crop := image.NewRGBA(image.Rect(0, 0, cw, ch))
+	draw.Draw(crop, crop.Bounds(), img, image.Pt(x, y), draw.Src)
+	return resize(crop, w, h)
+}
+
+func toRGBA(img image.Image) *image.RGBA {
+	if rgba, ok := img.(*image.RGBA); ok && rgba.Rect.Min == (image.Point{}) {
+		return rgba
+	}
+	b := img.Bounds()
+	rgba := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(rgba, rgba.Bounds(), img, b.Min, draw.Src)
+	return rgba
+}
+
+// contribution is the run of source pixels averaged into one output pixel.
+type contribution struct {
+	start   int
+	weights []float32
+}
+
+func contributions(in, out int) []contribution {
+	scale := float64(in) / float64(out)
+	radius := math.Max(scale, 1)
+	cs := make([]contribution, out)
+	for i := range cs {
+		center := (float64(i) + 0.5) * scale
+		lo := max(0, int(math.Floor(center-radius)))
+		hi := min(in, int(math.Ceil(center+radius)))
+
+		var sum float64
+		ws := make([]float32, 0, hi-lo)
+		for j := lo; j < hi; j++ {
+			wt := math.Max(0, 1-math.Abs(float64(j)+0.5-center)/radius)
+			ws = append(ws, float32(wt))
+			sum += wt
+		}
+		if sum == 0 {
+			nearest := min(in-1, int(center))
+			cs[i] = contribution{start: nearest, weights: []float32{1}}
+			continue
+		}
+		for k := range ws {
+			ws[k] /= float32(sum)
+		}
+		cs[i] = contribution{start: lo, weights: ws}
+	}
+	return cs
+}
+
+// resampleRows scales each row of a w×h RGBA buffer to nw pixels.
+func resampleRows(pix []float32, w, h, nw int) []float32 {
+	cs := contributions(w, nw)
+	out := make([]float32, nw*h*4)
+	for y := 0; y < h; y++ {
+		row := pix[y*w*4:]
+		for x, c := range cs {
+			var px [4]float32
+			for k, wt := range c.weights {
+				s := (c.start + k) * 4
+				px[0] += row[s] * wt
+				px[1] += row[s+1] * wt
+				px[2] += row[s+2] * wt
+				px[3] += row[s+3] * wt
+			}
+			copy(out[(y*nw+x)*4:], px[:])
+		}
+	}
+	return out
+}
+
+// resampleColumns scales each column of a w×h RGBA buffer to nh pixels.
+func resampleColumns(pix []float32, w, h, nh int) []float32 {
+	cs := contributions(h, nh)
+	out := make([]float32, w*nh*4)
+	for y, c := range cs {
+		for x := 0; x < w; x++ {
+			var px [4]float32
+			for k, wt := range c.weights {
+				s := ((c.start+k)*w + x) * 4
+				px[0] += pix[s] * wt
+				px[1] += pix[s+1] * wt
+				px[2] += pix[s+2] * wt
+				px[3] += pix[s+3] * wt
+			}
+			copy(out[(y*w+x)*4:], px[:])
+		}
+	}
+	return out
+}
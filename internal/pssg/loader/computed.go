@@ -0,0 +1,83 @@
+package loader
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/supermodeltools/arch-docs/internal/pssg/config"
+	"github.com/supermodeltools/arch-docs/internal/pssg/entity"
+	"github.com/supermodeltools/arch-docs/internal/pssg/render"
+)
+
+// computedField is a parsed data.computed_fields entry.
+type computedField struct {
+	cfg  config.ComputedField
+	tmpl *template.Template
+}
+
+// compileComputedFields parses every computed field expression with the
+// same helper functions available to page templates.
+func compileComputedFields(fields []config.ComputedField) ([]computedField, error) {
+	funcs := template.FuncMap(render.BuildFuncMap())
+	compiled := make([]computedField, 0, len(fields))
+	for _, cf := range fields {
+		t, err := template.New(cf.Name).Funcs(funcs).Option("missingkey=zero").Parse(cf.Expr)
+		if err != nil {
+			return nil, fmt.Errorf("parsing computed field %s: %w", cf.Name, err)
+		}
+		compiled = append(compiled, computedField{cfg: cf, tmpl: t})
+	}
+	return compiled, nil
+}
+
+// applyComputedFields evaluates each field against e and stores the result in
+// e.Fields. Empty results leave the field unset.
+func applyComputedFields(e *entity.Entity, fields []computedField) error {
+	if len(fields) > 0 && e.Fields == nil {
+		e.Fields = make(map[string]interface{})
+	}
+	for _, cf := range fields {
+		var buf bytes.Buffer
+		if err := cf.tmpl.Execute(&buf, e); err != nil {
+			return fmt.Errorf("computing %s: %w", cf.cfg.Name, err)
+		}
+		raw := strings.TrimSpace(buf.String())
+		if raw == "" || raw == "<no value>" {
+			continue
+		}
+		v, err := convertComputed(raw, cf.cfg.Type)
+		if err != nil {
+			return fmt.Errorf("computing %s: %w", cf.cfg.Name, err)
+		}
+		e.Fields[cf.cfg.Name] = v
+	}
+	return nil
+}
+
+func convertComputed(raw, typ string) (interface{}, error) {
+	switch typ {
+	case "int":
+		f, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a number", raw)
+		}
+		return int(f), nil
+	case "float":
+		f, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a number", raw)
+		}
+		return f, nil
+	case "bool":
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a boolean", raw)
+		}
+		return b, nil
+	default:
+		return raw, nil
+	}
+}
@@ -0,0 +1,100 @@
+package loader
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/supermodeltools/arch-docs/internal/pssg/config"
+	"github.com/supermodeltools/arch-docs/internal/pssg/entity"
+)
+
+// localize turns the default-language entities into those of the edition
+// named by site.language. Files under <data>/<lang>/ are full translations
+// and replace the entity with the same slug; other entities take any
+// `field.<lang>` values in place of `field`, and are kept only if they had
+// one, unless i18n.include_untranslated is set. Suffixed fields for every
+// configured language are removed, so the root edition never sees them.
+func (l *MarkdownLoader) localize(base []*entity.Entity) ([]*entity.Entity, error) {
+	i18n := l.Config.I18n
+	if len(i18n.Languages) < 2 {
+		return base, nil
+	}
+	lang := l.Config.Site.Language
+
+	translated := make(map[string]*entity.Entity)
+	if lang != i18n.Default {
+		dir := filepath.Join(l.Config.Paths.Data, lang)
+		if _, err := os.Stat(dir); err == nil {
+			sub := *l.Config
+			sub.Paths.Data = dir
+			sub.I18n.Languages = nil // the files are already translated
+			list, err := (&MarkdownLoader{Config: &sub}).Load()
+			if err != nil {
+				return nil, err
+			}
+			for _, e := range list {
+				translated[e.Slug] = e
+			}
+		}
+	}
+
+	var out []*entity.Entity
+	for _, e := range base {
+		if t, ok := translated[e.Slug]; ok {
+			out = append(out, t)
+			delete(translated, e.Slug)
+			continue
+		}
+		le, found := localizeFields(e, lang, i18n.Languages)
+		if lang != i18n.Default && !found {
+			if !i18n.IncludeUntranslated {
+				continue
+			}
+			// The page repeats the default edition's, which stays canonical
+			// and keeps it out of this edition's sitemap and feeds
+			if le.CanonicalOverride() == "" && i18n.RootURL != "" {
+				le.Fields["canonical_url"] = i18n.RootURL + "/" + le.Slug + ".html"
+			}
+		}
+		out = append(out, le)
+	}
+	// Entities that exist only in this edition
+	for _, e := range translated {
+		out = append(out, e)
+	}
+	return out, nil
+}
+
+// localizeFields returns a copy of e with `key.<lang>` values moved onto
+// `key` and all language-suffixed keys dropped. found reports whether any
+// field was translated.
+func localizeFields(e *entity.Entity, lang string, languages []config.LanguageConfig) (*entity.Entity, bool) {
+	suffixed := func(key string) (string, string, bool) {
+		for _, l := range languages {
+			if base, ok := strings.CutSuffix(key, "."+l.Code); ok && base != "" {
+				return base, l.Code, true
+			}
+		}
+		return "", "", false
+	}
+
+	fields := make(map[string]interface{}, len(e.Fields))
+	found := false
+	for k, v := range e.Fields {
+		if _, _, ok := suffixed(k); !ok {
+			fields[k] = v
+		}
+	}
+	for k, v := range e.Fields {
+		if base, code, ok := suffixed(k); ok && code == lang {
+			fields[base] = v
+			found = true
+		}
+	}
+
+	c := *e
+	c.Fields = fields
+	c.ComputeSourceHash()
+	return &c, found
+}
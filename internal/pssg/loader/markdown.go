@@ -10,6 +10,7 @@ import (
 
 	"github.com/supermodeltools/arch-docs/internal/pssg/config"
 	"github.com/supermodeltools/arch-docs/internal/pssg/entity"
+	"github.com/supermodeltools/arch-docs/internal/pssg/logging"
 )
 
 // MarkdownLoader loads entities from markdown files with YAML frontmatter.
@@ -25,6 +26,11 @@ func (l *MarkdownLoader) Load() ([]*entity.Entity, error) {
 		return nil, fmt.Errorf("reading data dir %s: %w", dataDir, err)
 	}
 
+	computed, err := compileComputedFields(l.Config.Data.ComputedFields)
+	if err != nil {
+		return nil, err
+	}
+
 	var entities []*entity.Entity
 	for _, entry := range entries {
 		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".md") {
@@ -34,13 +40,17 @@ func (l *MarkdownLoader) Load() ([]*entity.Entity, error) {
 		path := filepath.Join(dataDir, entry.Name())
 		e, err := l.parseFile(path)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Warning: skipping %s: %v\n", entry.Name(), err)
+			logging.Stage("load").Warn("Skipping entity file", "file", entry.Name(), "error", err)
 			continue
 		}
+		if err := applyComputedFields(e, computed); err != nil {
+			logging.Stage("load").Warn("Computing fields failed", "file", entry.Name(), "error", err)
+		}
+		e.ComputeSourceHash()
 		entities = append(entities, e)
 	}
 
-	return entities, nil
+	return l.localize(entities)
 }
 
 func (l *MarkdownLoader) parseFile(path string) (*entity.Entity, error) {
@@ -109,11 +119,16 @@ func (l *MarkdownLoader) deriveSlug(path string, fields map[string]interface{})
 	}
 	// Default: derive from filename
 	base := filepath.Base(path)
-	return strings.TrimSuffix(base, filepath.Ext(base))
+	slug := strings.TrimSuffix(base, filepath.Ext(base))
+	if l.Config.URLs.Lowercase {
+		slug = strings.ToLower(slug)
+	}
+	return slug
 }
 
 func (l *MarkdownLoader) parseSections(body string) map[string]interface{} {
 	sections := make(map[string]interface{})
+	stepIDs := make(map[string]bool) // shared so ids stay unique across runbooks
 
 	for _, sectionCfg := range l.Config.Data.BodySections {
 		content := extractSection(body, sectionCfg.Header)
@@ -128,6 +143,8 @@ func (l *MarkdownLoader) parseSections(body string) map[string]interface{} {
 			sections[sectionCfg.Name] = parseOrderedList(content)
 		case "faq":
 			sections[sectionCfg.Name] = parseFAQs(content)
+		case "runbook":
+			sections[sectionCfg.Name] = parseRunbook(content, stepIDs)
 		case "markdown":
 			sections[sectionCfg.Name] = content
 		default:
@@ -228,3 +245,64 @@ func parseFAQs(content string) []entity.FAQ {
 
 	return faqs
 }
+
+// parseRunbook extracts steps from a markdown list, ordered or not, whose
+// items may be task list items ("- [ ]", "- [x]"). Indented lines under an
+// item are its detail. A step's ID comes from a trailing "{#id}" or else
+// from its text; seen holds the IDs already taken and gets the new ones.
+func parseRunbook(content string, seen map[string]bool) []entity.Step {
+	var steps []entity.Step
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		text, ok := listItem(trimmed)
+		if !ok || line != strings.TrimLeft(line, " \t") && len(steps) > 0 {
+			if len(steps) > 0 && trimmed != "" {
+				s := &steps[len(steps)-1]
+				s.Detail = strings.TrimSpace(s.Detail + "\n" + trimmed)
+			}
+			continue
+		}
+		step := entity.Step{Text: text}
+		switch {
+		case strings.HasPrefix(text, "[ ] "):
+			step.Text = text[4:]
+		case strings.HasPrefix(text, "[x] "), strings.HasPrefix(text, "[X] "):
+			step.Text, step.Done = text[4:], true
+		}
+		id := ""
+		if i := strings.LastIndex(step.Text, "{#"); i >= 0 && strings.HasSuffix(step.Text, "}") {
+			id = entity.ToSlug(step.Text[i+2 : len(step.Text)-1])
+			step.Text = strings.TrimSpace(step.Text[:i])
+		}
+		if id == "" {
+			id = entity.ToSlug(step.Text)
+		}
+		if id == "" {
+			id = fmt.Sprintf("step-%d", len(steps)+1)
+		}
+		step.ID = id
+		for n := 2; seen[step.ID]; n++ {
+			step.ID = fmt.Sprintf("%s-%d", id, n)
+		}
+		seen[step.ID] = true
+		steps = append(steps, step)
+	}
+	return steps
+}
+
+// listItem returns the text of a "- ", "* " or "1. " list item.
+func listItem(line string) (string, bool) {
+	if strings.HasPrefix(line, "- ") || strings.HasPrefix(line, "* ") {
+		return strings.TrimSpace(line[2:]), true
+	}
+	dot := strings.Index(line, ". ")
+	if dot <= 0 || dot > 4 {
+		return "", false
+	}
+	for _, c := range line[:dot] {
+		if c < '0' || c > '9' {
+			return "", false
+		}
+	}
+	return strings.TrimSpace(line[dot+2:]), true
+}
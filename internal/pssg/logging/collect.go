@@ -0,0 +1,100 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+)
+
+// Warning is a warning or error logged during a build.
+type Warning struct {
+	Level   string            `json:"level"` // "warning" or "error"
+	Stage   string            `json:"stage,omitempty"`
+	Slug    string            `json:"slug,omitempty"`
+	Message string            `json:"message"`
+	Attrs   map[string]string `json:"attrs,omitempty"`
+}
+
+// Collector is a slog.Handler that passes records on to another handler
+// and keeps those at warning level and above, so a build can report them
+// together at the end.
+type Collector struct {
+	next  slog.Handler
+	attrs []slog.Attr
+	list  *warningList
+}
+
+type warningList struct {
+	mu    sync.Mutex
+	items []Warning
+}
+
+// Collect installs a Collector in front of the default logger's handler.
+// The returned function puts the previous logger back.
+func Collect() (*Collector, func()) {
+	prev := slog.Default()
+	c := &Collector{next: prev.Handler(), list: &warningList{}}
+	slog.SetDefault(slog.New(c))
+	return c, func() { slog.SetDefault(prev) }
+}
+
+// Warnings returns what has been collected, in the order it was logged.
+func (c *Collector) Warnings() []Warning {
+	c.list.mu.Lock()
+	defer c.list.mu.Unlock()
+	return append([]Warning{}, c.list.items...)
+}
+
+func (c *Collector) Enabled(ctx context.Context, l slog.Level) bool {
+	return l >= slog.LevelWarn || c.next.Enabled(ctx, l)
+}
+
+func (c *Collector) Handle(ctx context.Context, r slog.Record) error {
+	if r.Level >= slog.LevelWarn {
+		w := Warning{Level: "warning", Message: r.Message}
+		if r.Level >= slog.LevelError {
+			w.Level = "error"
+		}
+		add := func(a slog.Attr) {
+			switch {
+			case a.Equal(slog.Attr{}):
+			case a.Key == StageKey:
+				w.Stage = a.Value.Resolve().String()
+			case a.Key == "slug":
+				w.Slug = a.Value.Resolve().String()
+			default:
+				if w.Attrs == nil {
+					w.Attrs = make(map[string]string)
+				}
+				w.Attrs[a.Key] = a.Value.Resolve().String()
+			}
+		}
+		for _, a := range c.attrs {
+			add(a)
+		}
+		r.Attrs(func(a slog.Attr) bool {
+			add(a)
+			return true
+		})
+		c.list.mu.Lock()
+		c.list.items = append(c.list.items, w)
+		c.list.mu.Unlock()
+	}
+	if !c.next.Enabled(ctx, r.Level) {
+		return nil
+	}
+	return c.next.Handle(ctx, r)
+}
+
+func (c *Collector) WithAttrs(attrs []slog.Attr) slog.Handler {
+	c2 := *c
+	c2.next = c.next.WithAttrs(attrs)
+	c2.attrs = append(append([]slog.Attr(nil), c.attrs...), attrs...)
+	return &c2
+}
+
+func (c *Collector) WithGroup(name string) slog.Handler {
+	c2 := *c
+	c2.next = c.next.WithGroup(name)
+	return &c2
+}
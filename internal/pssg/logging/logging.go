@@ -0,0 +1,121 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+)
+
+// StageKey is the attribute naming the build stage a record comes from,
+// e.g. "load" or "render". The text format leaves it out; JSON keeps it so
+// CI systems can group output.
+const StageKey = "stage"
+
+// Setup installs the default slog logger writing to w. format is "text"
+// (the default) or "json".
+func Setup(w io.Writer, level slog.Level, format string) error {
+	var h slog.Handler
+	switch format {
+	case "", "text":
+		h = NewTextHandler(w, level)
+	case "json":
+		h = slog.NewJSONHandler(w, &slog.HandlerOptions{Level: level})
+	default:
+		return fmt.Errorf("unknown log format %q (want text or json)", format)
+	}
+	slog.SetDefault(slog.New(h))
+	return nil
+}
+
+// Stage returns the default logger tagged with a build stage.
+func Stage(name string) *slog.Logger {
+	return slog.Default().With(StageKey, name)
+}
+
+// TextHandler writes one human-readable line per record:
+//
+//	2025/01/02 15:04:05 Warning: relation target not found from=a target=b
+//
+// Lines are written whole under a lock, so records from concurrent
+// goroutines never interleave.
+type TextHandler struct {
+	mu    *sync.Mutex
+	w     io.Writer
+	level slog.Leveler
+	attrs []slog.Attr
+	group string
+}
+
+// NewTextHandler returns a TextHandler for records at or above level.
+func NewTextHandler(w io.Writer, level slog.Leveler) *TextHandler {
+	return &TextHandler{mu: &sync.Mutex{}, w: w, level: level}
+}
+
+func (h *TextHandler) Enabled(_ context.Context, l slog.Level) bool {
+	return l >= h.level.Level()
+}
+
+func (h *TextHandler) Handle(_ context.Context, r slog.Record) error {
+	var b strings.Builder
+	t := r.Time
+	if t.IsZero() {
+		t = time.Now()
+	}
+	b.WriteString(t.Format("2006/01/02 15:04:05 "))
+	switch {
+	case r.Level >= slog.LevelError:
+		b.WriteString("Error: ")
+	case r.Level >= slog.LevelWarn:
+		b.WriteString("Warning: ")
+	case r.Level < slog.LevelInfo:
+		b.WriteString("  ")
+	}
+	b.WriteString(r.Message)
+
+	write := func(a slog.Attr) {
+		if a.Key == StageKey || a.Equal(slog.Attr{}) {
+			return
+		}
+		key := a.Key
+		if h.group != "" {
+			key = h.group + "." + key
+		}
+		v := a.Value.Resolve().String()
+		if strings.ContainsAny(v, " \t\"=") || v == "" {
+			v = fmt.Sprintf("%q", v)
+		}
+		b.WriteString(" " + key + "=" + v)
+	}
+	for _, a := range h.attrs {
+		write(a)
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		write(a)
+		return true
+	})
+	b.WriteByte('\n')
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := io.WriteString(h.w, b.String())
+	return err
+}
+
+func (h *TextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	h2 := *h
+	h2.attrs = append(append([]slog.Attr(nil), h.attrs...), attrs...)
+	return &h2
+}
+
+func (h *TextHandler) WithGroup(name string) slog.Handler {
+	h2 := *h
+	if h2.group != "" {
+		name = h2.group + "." + name
+	}
+	h2.group = name
+	return &h2
+}
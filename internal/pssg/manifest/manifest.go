@@ -0,0 +1,158 @@
+package manifest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// Manifest names inside paths.cache. Each build moves the previous manifest
+// to PrevFileName so the last two builds can be compared.
+const (
+	FileName     = "output-manifest.json"
+	PrevFileName = "output-manifest.prev.json"
+)
+
+// File is one generated file.
+type File struct {
+	Hash string `json:"hash"` // hex SHA-256 of the contents
+	Size int64  `json:"size"`
+}
+
+// Manifest records the files a build wrote to the output directory, so
+// later commands can tell generated files from ones placed there by hand.
+type Manifest struct {
+	Output string          `json:"output"`
+	Built  time.Time       `json:"built"`
+	Files  map[string]File `json:"files"` // slash-separated paths relative to Output
+	// Stale lists files generated by earlier builds that this build did not
+	// write again, e.g. pages of deleted entities.
+	Stale []string `json:"stale,omitempty"`
+}
+
+// Scan hashes every file under dir. A missing dir yields an empty map.
+func Scan(dir string) (map[string]File, error) {
+	files := make(map[string]File)
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && path == dir {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		f, err := HashFile(path)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(rel)] = f
+		return nil
+	})
+	return files, err
+}
+
+// HashFile returns the hash and size of one file.
+func HashFile(path string) (File, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return File{}, err
+	}
+	defer f.Close()
+	h := sha256.New()
+	n, err := io.Copy(h, f)
+	if err != nil {
+		return File{}, err
+	}
+	return File{Hash: hex.EncodeToString(h.Sum(nil)), Size: n}, nil
+}
+
+// Load reads a manifest. It returns nil and no error if none exists yet.
+func Load(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("parsing manifest %s: %w", path, err)
+	}
+	return &m, nil
+}
+
+// Save writes the manifest, creating its directory if needed.
+func (m *Manifest) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// Changes lists the differences between two sets of files, each sorted.
+type Changes struct {
+	Added   []string
+	Removed []string
+	Changed []string
+}
+
+// Empty reports whether nothing changed.
+func (c Changes) Empty() bool {
+	return len(c.Added) == 0 && len(c.Removed) == 0 && len(c.Changed) == 0
+}
+
+// Compare returns what changed going from old to new.
+func Compare(old, new map[string]File) Changes {
+	var c Changes
+	for path, f := range new {
+		prev, ok := old[path]
+		switch {
+		case !ok:
+			c.Added = append(c.Added, path)
+		case prev.Hash != f.Hash:
+			c.Changed = append(c.Changed, path)
+		}
+	}
+	for path := range old {
+		if _, ok := new[path]; !ok {
+			c.Removed = append(c.Removed, path)
+		}
+	}
+	sort.Strings(c.Added)
+	sort.Strings(c.Removed)
+	sort.Strings(c.Changed)
+	return c
+}
+
+// Written returns the files under dir modified at or after since, i.e. the
+// ones a build starting at since wrote. Files placed by hand (a CNAME,
+// say) keep their older modification time and are left out. On filesystems
+// with coarse timestamps a file may be missed, never wrongly claimed.
+func Written(dir string, files map[string]File, since time.Time) map[string]File {
+	written := make(map[string]File, len(files))
+	for rel, f := range files {
+		info, err := os.Stat(filepath.Join(dir, filepath.FromSlash(rel)))
+		if err == nil && !info.ModTime().Before(since) {
+			written[rel] = f
+		}
+	}
+	return written
+}
@@ -0,0 +1,119 @@
+// Package notify posts build results to a webhook, such as a Slack
+// incoming webhook or a deploy pipeline's endpoint.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/supermodeltools/arch-docs/internal/pssg/config"
+)
+
+// Timeout bounds a notification, so a slow endpoint cannot hold up the
+// build that reports to it.
+const Timeout = 10 * time.Second
+
+// Result is what a notification reports about a build. The JSON names
+// are the default payload's.
+type Result struct {
+	Site     string `json:"site"`
+	URL      string `json:"url"`
+	Status   string `json:"status"` // "success" or "failure"
+	Error    string `json:"error,omitempty"`
+	Pages    int    `json:"pages"`
+	Changed  int    `json:"changed"`
+	Entities int    `json:"entities"`
+	Duration string `json:"duration"`
+}
+
+// URL returns the webhook the config names, or "" when there is none.
+func URL(cfg config.NotifyConfig) string {
+	if cfg.URL != "" {
+		return cfg.URL
+	}
+	if cfg.EnvVar != "" {
+		return os.Getenv(cfg.EnvVar)
+	}
+	return ""
+}
+
+// Wants reports whether the config asks to be told about a result.
+func Wants(cfg config.NotifyConfig, r Result) bool {
+	return URL(cfg) != "" && slices.Contains(cfg.On, r.Status)
+}
+
+// Payload renders the request body for a result.
+func Payload(cfg config.NotifyConfig, r Result) ([]byte, error) {
+	if cfg.Payload == "" {
+		if slack(URL(cfg)) {
+			return json.Marshal(map[string]string{"text": Message(r)})
+		}
+		return json.Marshal(r)
+	}
+	tmpl, err := template.New("payload").Funcs(template.FuncMap{
+		"json": func(v interface{}) (string, error) {
+			data, err := json.Marshal(v)
+			return string(data), err
+		},
+	}).Parse(cfg.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("hooks.notify.payload: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, r); err != nil {
+		return nil, fmt.Errorf("hooks.notify.payload: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// Message describes a result in one line.
+func Message(r Result) string {
+	if r.Status == "failure" {
+		return fmt.Sprintf("%s build failed after %s: %s", r.Site, r.Duration, r.Error)
+	}
+	return fmt.Sprintf("%s built in %s: %d pages, %d changed. %s", r.Site, r.Duration, r.Pages, r.Changed, r.URL)
+}
+
+// Send posts a result to the webhook. A payload that is a JSON object or
+// array is sent as application/json, anything else as plain text.
+func Send(ctx context.Context, cfg config.NotifyConfig, r Result) error {
+	body, err := Payload(cfg, r)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, Timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, URL(cfg), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	if trimmed := bytes.TrimSpace(body); json.Valid(trimmed) && len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[') {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("webhook answered %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// slack reports whether a URL is a Slack incoming webhook, which expects
+// a message rather than arbitrary JSON.
+func slack(url string) bool {
+	return strings.HasPrefix(url, "https://hooks.slack.com/")
+}
@@ -0,0 +1,71 @@
+package output
+
+import (
+	"encoding/xml"
+	"time"
+
+	"github.com/supermodeltools/arch-docs/internal/pssg/history"
+)
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title   string      `xml:"title"`
+	ID      string      `xml:"id"`
+	Updated string      `xml:"updated"`
+	Links   []atomLink  `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomEntry struct {
+	Title   string     `xml:"title"`
+	ID      string     `xml:"id"`
+	Updated string     `xml:"updated"`
+	Author  atomAuthor `xml:"author"`
+	Link    *atomLink  `xml:"link,omitempty"`
+}
+
+type atomAuthor struct {
+	Name  string `xml:"name"`
+	Email string `xml:"email,omitempty"`
+}
+
+// GenerateChangeFeed returns an Atom feed of the commits to one entity's
+// file, newest first. Entries link to the commit when commitURL (the
+// repository URL followed by "/commit/") is set, otherwise to the page.
+func GenerateChangeFeed(title, pageURL, feedURL, commitURL string, commits []history.Commit) string {
+	feed := atomFeed{
+		Title: title,
+		ID:    feedURL,
+		Links: []atomLink{
+			{Href: feedURL, Rel: "self", Type: "application/atom+xml"},
+			{Href: pageURL, Rel: "alternate", Type: "text/html"},
+		},
+	}
+	if len(commits) > 0 {
+		feed.Updated = commits[0].Date.UTC().Format(time.RFC3339)
+	}
+	for _, c := range commits {
+		entry := atomEntry{
+			Title:   c.Subject,
+			ID:      pageURL + "#commit-" + c.Hash,
+			Updated: c.Date.UTC().Format(time.RFC3339),
+			Author:  atomAuthor{Name: c.Author, Email: c.Email},
+			Link:    &atomLink{Href: pageURL, Rel: "alternate"},
+		}
+		if commitURL != "" {
+			entry.Link.Href = commitURL + c.Hash
+		}
+		feed.Entries = append(feed.Entries, entry)
+	}
+	data, err := xml.MarshalIndent(feed, "", "  ")
+	if err != nil {
+		return ""
+	}
+	return xml.Header + string(data)
+}
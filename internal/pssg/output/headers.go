@@ -0,0 +1,59 @@
+package output
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/supermodeltools/arch-docs/internal/pssg/config"
+)
+
+// GenerateHeaders generates a _headers file (Netlify / Cloudflare Pages
+// format) from the configured header rules followed by extra, adding a
+// rule that serves policy as the Content-Security-Policy when it is set.
+func GenerateHeaders(cfg *config.Config, policy string, extra []config.HeaderRule) string {
+	var b strings.Builder
+	if policy != "" {
+		b.WriteString(cfg.Headers.CSP.Path + "\n")
+		b.WriteString("  Content-Security-Policy: " + policy + "\n")
+	}
+	for _, rule := range append(cfg.Headers.Rules[:len(cfg.Headers.Rules):len(cfg.Headers.Rules)], extra...) {
+		b.WriteString(rule.Path + "\n")
+		names := make([]string, 0, len(rule.Values))
+		for name := range rule.Values {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			b.WriteString("  " + name + ": " + rule.Values[name] + "\n")
+		}
+	}
+	return b.String()
+}
+
+// HeaderValue returns the value of the named header for a URL path such as
+// "/index.html", taken from the first rule whose pattern matches and sets
+// it. Patterns match the whole path, with * matching any run of characters.
+func HeaderValue(rules []config.HeaderRule, urlPath, name string) string {
+	for _, rule := range rules {
+		if !MatchPath(rule.Path, urlPath) {
+			continue
+		}
+		for k, v := range rule.Values {
+			if strings.EqualFold(k, name) {
+				return v
+			}
+		}
+	}
+	return ""
+}
+
+// MatchPath reports whether a header rule pattern matches a URL path.
+func MatchPath(pattern, urlPath string) bool {
+	parts := strings.Split(pattern, "*")
+	for i, p := range parts {
+		parts[i] = regexp.QuoteMeta(p)
+	}
+	re, err := regexp.Compile("^" + strings.Join(parts, ".*") + "$")
+	return err == nil && re.MatchString(urlPath)
+}
@@ -41,7 +41,7 @@ func GenerateLlmsTxt(cfg *config.Config, entities []*entity.Entity, taxonomies [
 	for _, e := range sorted {
 		title := e.GetString("title")
 		desc := e.GetString("description")
-		url := fmt.Sprintf("%s/%s.html", cfg.Site.BaseURL, e.Slug)
+		url := cfg.Site.BaseURL + CanonicalPath(cfg.URLs, "/"+e.Slug+".html")
 		lines = append(lines, fmt.Sprintf("- [%s](%s): %s", title, url, desc))
 	}
 	lines = append(lines, "")
@@ -52,7 +52,7 @@ func GenerateLlmsTxt(cfg *config.Config, entities []*entity.Entity, taxonomies [
 			if tax.Name == taxName {
 				lines = append(lines, fmt.Sprintf("## %s", tax.Label))
 				for _, entry := range tax.Entries {
-					url := fmt.Sprintf("%s/%s/%s.html", cfg.Site.BaseURL, tax.Name, entry.Slug)
+					url := cfg.Site.BaseURL + CanonicalPath(cfg.URLs, "/"+tax.Name+"/"+entry.Slug+".html")
 					lines = append(lines, fmt.Sprintf("- [%s](%s)", entry.Name, url))
 				}
 				lines = append(lines, "")
@@ -61,5 +61,25 @@ func GenerateLlmsTxt(cfg *config.Config, entities []*entity.Entity, taxonomies [
 		}
 	}
 
+	// Other language editions, each with its own llms.txt
+	if langs := cfg.I18n.Languages; len(langs) > 1 {
+		root := cfg.I18n.RootURL
+		if root == "" {
+			root = cfg.Site.BaseURL
+		}
+		lines = append(lines, "## Other Languages")
+		for _, l := range langs {
+			if l.Code == cfg.Site.Language {
+				continue
+			}
+			url := root + "/llms.txt"
+			if l.Code != cfg.I18n.Default {
+				url = root + "/" + l.Code + "/llms.txt"
+			}
+			lines = append(lines, fmt.Sprintf("- [%s](%s)", l.Name, url))
+		}
+		lines = append(lines, "")
+	}
+
 	return strings.Join(lines, "\n")
 }
@@ -6,17 +6,28 @@ import (
 	"github.com/supermodeltools/arch-docs/internal/pssg/config"
 )
 
+// ManifestIcon is one entry of the manifest's icons list.
+type ManifestIcon struct {
+	Src     string `json:"src"`
+	Sizes   string `json:"sizes"`
+	Type    string `json:"type"`
+	Purpose string `json:"purpose,omitempty"`
+}
+
 // GenerateManifest generates a PWA manifest.json.
-func GenerateManifest(cfg *config.Config) string {
+func GenerateManifest(cfg *config.Config, icons []ManifestIcon) string {
 	manifest := map[string]interface{}{
 		"name":             cfg.Site.Name,
 		"short_name":       cfg.Site.Name,
 		"description":      cfg.Site.Description,
-		"start_url":        "/",
+		"start_url":        cfg.Site.BasePath + "/",
 		"display":          "standalone",
 		"background_color": "#FAFAF7",
 		"theme_color":      "#5B7B5E",
 	}
+	if len(icons) > 0 {
+		manifest["icons"] = icons
+	}
 
 	data, err := json.MarshalIndent(manifest, "", "  ")
 	if err != nil {
@@ -11,10 +11,18 @@ import (
 func GenerateRobotsTxt(cfg *config.Config) string {
 	var lines []string
 
+	// Internal pages are disallowed in every group: a crawler follows only
+	// the most specific group that names it.
+	var disallow []string
+	if cfg.Previews.Enabled {
+		disallow = append(disallow, fmt.Sprintf("Disallow: /%s/", cfg.Previews.Dir))
+	}
+
 	lines = append(lines, "User-agent: *")
 	if cfg.Robots.AllowAll {
 		lines = append(lines, "Allow: /")
 	}
+	lines = append(lines, disallow...)
 	lines = append(lines, "")
 
 	// Standard bots
@@ -22,6 +30,7 @@ func GenerateRobotsTxt(cfg *config.Config) string {
 	for _, bot := range standardBots {
 		lines = append(lines, fmt.Sprintf("User-agent: %s", bot))
 		lines = append(lines, "Allow: /")
+		lines = append(lines, disallow...)
 		lines = append(lines, "")
 	}
 
@@ -29,6 +38,7 @@ func GenerateRobotsTxt(cfg *config.Config) string {
 	for _, bot := range cfg.Robots.ExtraBots {
 		lines = append(lines, fmt.Sprintf("User-agent: %s", bot))
 		lines = append(lines, "Allow: /")
+		lines = append(lines, disallow...)
 		lines = append(lines, "")
 	}
 
@@ -6,6 +6,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/supermodeltools/arch-docs/internal/pssg/collection"
 	"github.com/supermodeltools/arch-docs/internal/pssg/config"
 	"github.com/supermodeltools/arch-docs/internal/pssg/entity"
 )
@@ -91,6 +92,32 @@ func GenerateRSSFeeds(entities []*entity.Entity, cfg *config.Config, taxonomyEnt
 	return feeds
 }
 
+// GenerateCollectionFeeds generates a feed for each curated collection at
+// <collections.dir>/<slug>/feed.xml, with items in the collection's order.
+func GenerateCollectionFeeds(collections []*collection.Collection, cfg *config.Config) []RSSFeed {
+	if !cfg.RSS.Enabled {
+		return nil
+	}
+
+	buildDate := time.Now().UTC().Format(time.RFC1123Z)
+	var feeds []RSSFeed
+	for _, c := range collections {
+		feeds = append(feeds, RSSFeed{
+			RelativePath: fmt.Sprintf("%s/%s/feed.xml", cfg.Collections.Dir, c.Slug),
+			Content: generateFeed(
+				fmt.Sprintf("%s — %s", cfg.Site.Name, c.Title),
+				c.URL,
+				c.Description,
+				cfg.Site.Language,
+				buildDate,
+				c.Entities,
+				cfg.Site.BaseURL,
+			),
+		})
+	}
+	return feeds
+}
+
 func generateFeed(title, link, description, language, buildDate string, entities []*entity.Entity, baseURL string) string {
 	channel := rssChannel{
 		Title:         xmlEscape(title),
@@ -3,7 +3,6 @@ package output
 import (
 	"encoding/xml"
 	"fmt"
-	"strings"
 )
 
 // SitemapEntry represents a single URL in the sitemap.
@@ -81,6 +80,37 @@ func GenerateSitemapFiles(entries []SitemapEntry, baseURL string, maxPerFile int
 	return files
 }
 
+// GenerateSitemapChunks writes entries to <name>.xml, or to <name>-1.xml,
+// <name>-2.xml and so on past maxPerFile URLs, without an index of its own.
+// It is for sitemaps that share one index, such as those of language
+// editions.
+func GenerateSitemapChunks(entries []SitemapEntry, name string, maxPerFile int) []SitemapFile {
+	if maxPerFile <= 0 {
+		maxPerFile = 50000
+	}
+	chunks := chunkEntries(entries, maxPerFile)
+	if len(chunks) <= 1 {
+		return []SitemapFile{{Filename: name + ".xml", Content: generateSitemap(entries)}}
+	}
+	var files []SitemapFile
+	for i, chunk := range chunks {
+		files = append(files, SitemapFile{
+			Filename: fmt.Sprintf("%s-%d.xml", name, i+1),
+			Content:  generateSitemap(chunk),
+		})
+	}
+	return files
+}
+
+// GenerateSitemapIndex returns a sitemap index listing the given sitemap URLs.
+func GenerateSitemapIndex(locs []string, lastmod string) string {
+	var entries []sitemapEntry
+	for _, loc := range locs {
+		entries = append(entries, sitemapEntry{Loc: loc, Lastmod: lastmod})
+	}
+	return generateSitemapIndex(entries)
+}
+
 // SitemapFile is a filename + content pair.
 type SitemapFile struct {
 	Filename string
@@ -134,13 +164,8 @@ func chunkEntries(entries []SitemapEntry, size int) [][]SitemapEntry {
 
 // NewSitemapEntry creates a sitemap entry with the given base URL.
 func NewSitemapEntry(baseURL, path, lastmod, priority, changefreq string) SitemapEntry {
-	loc := baseURL + path
-	loc = strings.TrimRight(loc, "/")
-	if path == "/" {
-		loc = baseURL + "/"
-	}
 	return SitemapEntry{
-		Loc:        loc,
+		Loc:        baseURL + path,
 		Lastmod:    lastmod,
 		Priority:   priority,
 		ChangeFreq: changefreq,
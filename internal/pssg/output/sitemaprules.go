@@ -0,0 +1,35 @@
+package output
+
+import (
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/supermodeltools/arch-docs/internal/pssg/config"
+)
+
+// SitemapIncludes reports whether a page of the given type at the URL
+// path p (e.g. "/all/page-2.html") belongs in the sitemap.
+func SitemapIncludes(sc config.SitemapConfig, kind, p string) bool {
+	if slices.Contains(sc.ExcludeTypes, kind) {
+		return false
+	}
+	if len(sc.Include) > 0 && !matchPath(sc.Include, p) {
+		return false
+	}
+	return !matchPath(sc.Exclude, p)
+}
+
+// matchPath reports whether p matches one of the patterns: a glob over
+// the whole path, or a prefix ending in "/".
+func matchPath(patterns []string, p string) bool {
+	for _, pat := range patterns {
+		if strings.HasSuffix(pat, "/") && strings.HasPrefix(p, pat) {
+			return true
+		}
+		if ok, _ := path.Match(pat, p); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,28 @@
+package output
+
+import (
+	"strings"
+
+	"github.com/supermodeltools/arch-docs/internal/pssg/config"
+)
+
+// CanonicalPath returns the form urls gives the root-relative path of a
+// page: an index.html page by its directory, other pages without .html
+// under the clean style, and all of it lowercased with urls.lowercase.
+// A query or fragment is kept as it is.
+func CanonicalPath(urls config.URLsConfig, p string) string {
+	rest := ""
+	if i := strings.IndexAny(p, "?#"); i >= 0 {
+		p, rest = p[:i], p[i:]
+	}
+	switch {
+	case p == "/index.html" || strings.HasSuffix(p, "/index.html"):
+		p = strings.TrimSuffix(p, "index.html")
+	case urls.Style == "clean":
+		p = strings.TrimSuffix(p, ".html")
+	}
+	if urls.Lowercase {
+		p = strings.ToLower(p)
+	}
+	return p + rest
+}
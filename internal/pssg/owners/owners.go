@@ -0,0 +1,374 @@
+// Package owners reads code ownership from a CODEOWNERS file or an
+// ownership YAML file and attaches the owning teams to entities.
+package owners
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/supermodeltools/arch-docs/internal/pssg/config"
+	"github.com/supermodeltools/arch-docs/internal/pssg/entity"
+)
+
+// Locations are where GitHub looks for a CODEOWNERS file, in the order it
+// looks.
+var Locations = []string{".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS"}
+
+// Owner is a team or person that owns code.
+type Owner struct {
+	Name    string // e.g. "@org/team", "@user" or an email address
+	Contact string // from the ownership file; "" for CODEOWNERS
+}
+
+// URL links to the owner: the contact when it is a URL or an email
+// address, otherwise the GitHub team or user page, or a mailto link for
+// an email name. It is "" when there is nothing to link to.
+func (o Owner) URL() string {
+	for _, s := range []string{o.Contact, o.Name} {
+		switch {
+		case strings.HasPrefix(s, "https://"), strings.HasPrefix(s, "http://"), strings.HasPrefix(s, "mailto:"):
+			return s
+		case strings.HasPrefix(s, "@"):
+			if org, team, ok := strings.Cut(s[1:], "/"); ok {
+				return "https://github.com/orgs/" + org + "/teams/" + team
+			}
+			return "https://github.com/" + s[1:]
+		case strings.Contains(s, "@") && !strings.ContainsAny(s, " \t"):
+			return "mailto:" + s
+		}
+	}
+	return ""
+}
+
+// rule assigns owners to the paths a pattern matches.
+type rule struct {
+	pattern string
+	re      *regexp.Regexp
+	owners  []string
+}
+
+// Set is a parsed ownership file. A nil Set owns nothing.
+type Set struct {
+	rules    []rule
+	contacts map[string]string   // owner name -> contact
+	entities map[string][]string // entity slug -> owner names
+}
+
+// Find returns the CODEOWNERS file GitHub would use for the repository at
+// root, or "" if it has none.
+func Find(root string) string {
+	for _, name := range Locations {
+		p := filepath.Join(root, filepath.FromSlash(name))
+		if _, err := os.Stat(p); err == nil {
+			return p
+		}
+	}
+	return ""
+}
+
+// ForConfig loads the ownership file a site's owners section names, or
+// the CODEOWNERS of paths.source_dir (or of the config directory) when it
+// names none.
+func ForConfig(cfg *config.Config) (*Set, error) {
+	file := cfg.Owners.File
+	if file == "" {
+		root := cfg.Paths.SourceDir
+		if root == "" {
+			root = cfg.ConfigDir
+		}
+		if file = Find(root); file == "" {
+			return nil, fmt.Errorf("no CODEOWNERS file in %s; set owners.file", root)
+		}
+	}
+	return Load(file)
+}
+
+// Load reads an ownership file: YAML when it ends in .yaml or .yml,
+// CODEOWNERS syntax otherwise.
+func Load(file string) (*Set, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".yaml", ".yml":
+		s, err := ParseYAML(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		return s, nil
+	}
+	return ParseCODEOWNERS(data), nil
+}
+
+// ParseCODEOWNERS reads CODEOWNERS lines: a pattern followed by its
+// owners. Comments and lines without owners are skipped.
+func ParseCODEOWNERS(data []byte) *Set {
+	s := &Set{}
+	for _, line := range strings.Split(string(data), "\n") {
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		s.add(fields[0], fields[1:])
+	}
+	return s
+}
+
+// ownershipFile is the YAML format: a list of owners, each with the path
+// patterns (CODEOWNERS syntax) and entity slugs it owns.
+//
+//	owners:
+//	  - name: "@acme/platform"
+//	    contact: platform@acme.dev
+//	    paths: [/internal/, "*.proto"]
+//	    entities: [api-gateway]
+type ownershipFile struct {
+	Owners []struct {
+		Name     string   `yaml:"name"`
+		Contact  string   `yaml:"contact"`
+		Paths    []string `yaml:"paths"`
+		Entities []string `yaml:"entities"`
+	} `yaml:"owners"`
+}
+
+// ParseYAML reads an ownership YAML file. As in CODEOWNERS, owners listed
+// later win for paths several patterns match; an entity listed by slug
+// belongs to every owner that lists it.
+func ParseYAML(data []byte) (*Set, error) {
+	var f ownershipFile
+	if err := yaml.Unmarshal(data, &f); err != nil {
+		return nil, err
+	}
+	s := &Set{contacts: make(map[string]string), entities: make(map[string][]string)}
+	for i, o := range f.Owners {
+		if o.Name == "" {
+			return nil, fmt.Errorf("owners[%d]: name is required", i)
+		}
+		if o.Contact != "" {
+			s.contacts[o.Name] = o.Contact
+		}
+		for _, p := range o.Paths {
+			s.add(p, []string{o.Name})
+		}
+		for _, slug := range o.Entities {
+			s.entities[slug] = append(s.entities[slug], o.Name)
+		}
+	}
+	return s, nil
+}
+
+func (s *Set) add(pattern string, owners []string) {
+	s.rules = append(s.rules, rule{pattern: pattern, re: compile(pattern), owners: owners})
+}
+
+// Owner returns an owner with its contact.
+func (s *Set) Owner(name string) Owner {
+	o := Owner{Name: name}
+	if s != nil {
+		o.Contact = s.contacts[name]
+	}
+	return o
+}
+
+// Entity returns the owners an ownership file lists an entity under by
+// slug.
+func (s *Set) Entity(slug string) []string {
+	if s == nil {
+		return nil
+	}
+	return s.entities[slug]
+}
+
+// Match returns the owners of a file, given as a slash-separated path
+// relative to the repository root. The last matching rule wins.
+func (s *Set) Match(file string) []string {
+	if s == nil {
+		return nil
+	}
+	file = strings.TrimPrefix(path.Clean("/"+file), "/")
+	var owners []string
+	for _, r := range s.rules {
+		if r.matches(file) {
+			owners = r.owners
+		}
+	}
+	return owners
+}
+
+// matches reports whether the rule covers file: the pattern matches the
+// file itself or, as patterns name whole trees, one of its directories.
+// A pattern ending in "/" matches directories only.
+func (r rule) matches(file string) bool {
+	if r.re == nil {
+		return false
+	}
+	if !strings.HasSuffix(r.pattern, "/") && r.re.MatchString(file) {
+		return true
+	}
+	for d := path.Dir(file); d != "." && d != "/"; d = path.Dir(d) {
+		if r.re.MatchString(d) {
+			return true
+		}
+	}
+	return false
+}
+
+// compile turns a CODEOWNERS pattern into a regular expression over
+// root-relative paths. A pattern with a leading or inner "/" is anchored
+// at the root; others match at any depth. "*" and "?" stay within one
+// path segment and "**" spans any number.
+func compile(pattern string) *regexp.Regexp {
+	p := strings.TrimSuffix(pattern, "/")
+	anchored := strings.HasPrefix(p, "/") || strings.Contains(p, "/")
+	p = strings.TrimPrefix(p, "/")
+	if p == "" {
+		p = "**"
+	}
+	var b strings.Builder
+	b.WriteString("^")
+	if !anchored {
+		b.WriteString("(?:.*/)?")
+	}
+	for i := 0; i < len(p); i++ {
+		switch {
+		case strings.HasPrefix(p[i:], "**/"):
+			b.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(p[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case p[i] == '*':
+			b.WriteString("[^/]*")
+		case p[i] == '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(p[i : i+1]))
+		}
+	}
+	b.WriteString("$")
+	re, err := regexp.Compile(b.String())
+	if err != nil {
+		return nil
+	}
+	return re
+}
+
+// MatchDir returns the owners of the files directly in a directory, as
+// the owners of a Go package. The last matching rule wins.
+func (s *Set) MatchDir(dir string) []string {
+	if s == nil {
+		return nil
+	}
+	var owners []string
+	for _, r := range s.rules {
+		if ownsDir(r.pattern, dir) {
+			owners = r.owners
+		}
+	}
+	return owners
+}
+
+// ownsDir reports whether a CODEOWNERS pattern covers the files directly
+// in dir. It handles the common forms: "*", "*.go", "/dir/", "dir/" and
+// "/dir/*"; patterns for single files never own a whole package.
+func ownsDir(pattern, dir string) bool {
+	if pattern == "*" || pattern == "/*" || pattern == "*.go" {
+		return true
+	}
+	anchored := strings.HasPrefix(pattern, "/") || strings.Contains(strings.TrimSuffix(pattern, "/"), "/")
+	direct := strings.HasSuffix(pattern, "/*") // files in dir, not below it
+	p := strings.TrimPrefix(pattern, "/")
+	p = strings.TrimSuffix(strings.TrimSuffix(p, "*"), "/")
+	if p == "" {
+		return true
+	}
+	if dir == "." {
+		return false
+	}
+	if anchored && direct {
+		ok, _ := path.Match(p, dir)
+		return ok
+	}
+	if anchored {
+		return matchPrefix(p, dir)
+	}
+	// An unanchored name matches a directory of that name at any depth.
+	parts := strings.Split(dir, "/")
+	for i := range parts {
+		if matchPrefix(p, strings.Join(parts[i:], "/")) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchPrefix reports whether the glob p matches dir or one of its
+// ancestors.
+func matchPrefix(p, dir string) bool {
+	for d := dir; d != "." && d != "/"; d = path.Dir(d) {
+		if ok, _ := path.Match(p, d); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Assign sets the owners field of every entity that does not set it
+// itself: to the owners the file lists its slug under, else the owners of
+// its file field, else of its directory field. The contact field gets the
+// first owner's contact or link. It returns the slugs of entities left
+// without an owner.
+func Assign(entities []*entity.Entity, s *Set, cfg config.OwnersConfig) []string {
+	var unowned []string
+	for _, e := range entities {
+		names := e.GetStringSlice(cfg.Field)
+		if len(names) == 0 {
+			if name := e.GetString(cfg.Field); name != "" {
+				names = []string{name}
+			}
+		}
+		if len(names) == 0 {
+			names = s.Entity(e.Slug)
+		}
+		if len(names) == 0 {
+			if file := e.GetString(cfg.FileField); file != "" {
+				names = s.Match(file)
+			}
+		}
+		if len(names) == 0 {
+			if dir := e.GetString(cfg.DirField); dir != "" {
+				names = s.MatchDir(path.Clean(dir))
+			}
+		}
+		if len(names) == 0 {
+			unowned = append(unowned, e.Slug)
+			continue
+		}
+		e.Fields[cfg.Field] = names
+		if e.GetString(cfg.ContactField) != "" {
+			continue
+		}
+		for _, name := range names {
+			o := s.Owner(name)
+			contact := o.Contact
+			if contact == "" {
+				contact = o.URL()
+			}
+			if contact != "" {
+				e.Fields[cfg.ContactField] = contact
+				break
+			}
+		}
+	}
+	return unowned
+}
@@ -0,0 +1,56 @@
+package postprocess
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+
+	"github.com/supermodeltools/arch-docs/internal/pssg/config"
+	"github.com/supermodeltools/arch-docs/internal/pssg/entity"
+)
+
+var (
+	headingRe = regexp.MustCompile(`(?is)<h([2-6])(\s[^>]*)?>(.*?)</h([2-6])\s*>`)
+	idRe      = regexp.MustCompile(`(?i)\sid\s*=\s*"([^"]*)"`)
+	tagRe     = regexp.MustCompile(`<[^>]*>`)
+)
+
+func anchorsProcessor(cfg *config.Config) Func {
+	return func(urlPath, page string) string {
+		return Anchors(page)
+	}
+}
+
+// Anchors links h2-h6 headings to themselves, so readers can share a link
+// to a section. A heading without an id gets one from its text, made
+// unique on the page; headings that already hold a link are left alone.
+func Anchors(page string) string {
+	used := make(map[string]bool)
+	for _, m := range idRe.FindAllStringSubmatch(page, -1) {
+		used[m[1]] = true
+	}
+	return headingRe.ReplaceAllStringFunc(page, func(h string) string {
+		m := headingRe.FindStringSubmatch(h)
+		level, attrs, inner := m[1], m[2], m[3]
+		if m[4] != level || strings.Contains(strings.ToLower(inner), "<a ") {
+			return h
+		}
+		id := ""
+		if im := idRe.FindStringSubmatch(attrs); im != nil {
+			id = im[1]
+		} else {
+			base := entity.ToSlug(html.UnescapeString(tagRe.ReplaceAllString(inner, "")))
+			if base == "" {
+				return h
+			}
+			id = base
+			for n := 2; used[id]; n++ {
+				id = fmt.Sprintf("%s-%d", base, n)
+			}
+			used[id] = true
+			attrs = ` id="` + id + `"` + attrs
+		}
+		return fmt.Sprintf(`<h%s%s>%s <a class="heading-anchor" href="#%s" aria-label="Link to this section">#</a></h%s>`, level, attrs, inner, id, level)
+	})
+}
@@ -0,0 +1,49 @@
+package postprocess
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/supermodeltools/arch-docs/internal/pssg/config"
+)
+
+// urlAttrRe matches the attributes holding a URL, and srcset.
+var urlAttrRe = regexp.MustCompile(`(?i)(\s(href|src|action|poster|srcset)\s*=\s*")([^"]*)"`)
+
+// basePathProcessor prefixes root-relative URLs with site.base_path, for
+// sites served from a subdirectory such as https://example.github.io/docs.
+func basePathProcessor(cfg *config.Config) Func {
+	base := cfg.Site.BasePath
+	if base == "" {
+		return nil
+	}
+	return func(urlPath, page string) string {
+		return BasePath(page, base)
+	}
+}
+
+// BasePath prefixes the root-relative URLs in a page's href, src, action,
+// poster and srcset attributes with base, such as "/docs". URLs that
+// already start with base, protocol-relative URLs and relative ones are
+// left alone.
+func BasePath(page, base string) string {
+	return urlAttrRe.ReplaceAllStringFunc(page, func(attr string) string {
+		m := urlAttrRe.FindStringSubmatch(attr)
+		if strings.EqualFold(m[2], "srcset") {
+			candidates := strings.Split(m[3], ",")
+			for i, c := range candidates {
+				trimmed := strings.TrimLeft(c, " ")
+				candidates[i] = c[:len(c)-len(trimmed)] + prefix(trimmed, base)
+			}
+			return m[1] + strings.Join(candidates, ",") + `"`
+		}
+		return m[1] + prefix(m[3], base) + `"`
+	})
+}
+
+func prefix(u, base string) string {
+	if !strings.HasPrefix(u, "/") || strings.HasPrefix(u, "//") || u == base || strings.HasPrefix(u, base+"/") {
+		return u
+	}
+	return base + u
+}
@@ -0,0 +1,317 @@
+package postprocess
+
+import (
+	"html"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/supermodeltools/arch-docs/internal/pssg/config"
+	"github.com/supermodeltools/arch-docs/internal/pssg/logging"
+)
+
+var (
+	openTagRe   = regexp.MustCompile(`<([a-zA-Z][a-zA-Z0-9-]*)`)
+	classAttrRe = regexp.MustCompile(`(?i)\sclass\s*=\s*(?:"([^"]*)"|'([^']*)')`)
+	idAttrRe    = regexp.MustCompile(`(?i)\sid\s*=\s*(?:"([^"]*)"|'([^']*)')`)
+	linkTagRe   = regexp.MustCompile(`(?i)<link\b[^>]*>`)
+	styleRe     = regexp.MustCompile(`(?is)<style>(.*?)</style>`)
+	// pseudoRe and attrSelRe match the parts of a selector critical CSS
+	// ignores; leaving them out can only keep more rules.
+	pseudoRe     = regexp.MustCompile(`::?[a-zA-Z-]+(\([^)]*\))?`)
+	attrSelRe    = regexp.MustCompile(`\[[^\]]*\]`)
+	combinatorRe = regexp.MustCompile(`\s*[>+~]\s*|\s+`)
+	simpleSelRe  = regexp.MustCompile(`[.#]?[^.#]+`)
+)
+
+// criticalCSSProcessor inlines the rules of output.extract_css each page
+// uses and loads the rest of the stylesheet without blocking rendering.
+func criticalCSSProcessor(cfg *config.Config) Func {
+	if cfg.Output.ExtractCSS == "" {
+		return nil
+	}
+	href := "/" + strings.TrimPrefix(filepath.ToSlash(cfg.Output.ExtractCSS), "/")
+	var (
+		once sync.Once
+		c    *Critical
+	)
+	return func(urlPath, page string) string {
+		once.Do(func() {
+			data, err := os.ReadFile(filepath.Join(cfg.Paths.Output, cfg.Output.ExtractCSS))
+			if err != nil {
+				logging.Stage("postprocess").Warn("Failed to read stylesheet for critical CSS", "error", err)
+				return
+			}
+			c = NewCritical(string(data))
+		})
+		if c == nil {
+			return page
+		}
+		return c.Inline(page, href)
+	}
+}
+
+// Critical computes the critical CSS of pages from one stylesheet. Pages
+// using the same tags, classes and ids, which pages of one type mostly
+// do, share the result.
+type Critical struct {
+	rules []cssRule
+	// sheet is the stylesheet with comments and runs of whitespace
+	// squeezed out, to spot it inlined in a page: html/template drops the
+	// comments of a <style> element.
+	sheet string
+	mu    sync.Mutex
+	cache map[string]string
+}
+
+// NewCritical parses a stylesheet for critical CSS.
+func NewCritical(sheet string) *Critical {
+	return &Critical{rules: parseCSS(sheet), sheet: squeezeCSS(sheet), cache: map[string]string{}}
+}
+
+// Inline replaces the stylesheet in a page, as a <style> element holding
+// it or a <link rel="stylesheet"> to href, with the rules the page's
+// markup uses and a preload link that a small script applies without
+// blocking rendering. Pages holding neither are returned as they are.
+func (c *Critical) Inline(page, href string) string {
+	start, end := -1, -1
+	for _, m := range styleRe.FindAllStringSubmatchIndex(page, -1) {
+		if squeezeCSS(page[m[2]:m[3]]) == c.sheet {
+			start, end = m[0], m[1]
+			break
+		}
+	}
+	if start < 0 {
+		for _, m := range linkTagRe.FindAllStringIndex(page, -1) {
+			tag := strings.ToLower(page[m[0]:m[1]])
+			if strings.Contains(tag, `rel="stylesheet"`) && strings.Contains(tag, `href="`+strings.ToLower(href)+`"`) {
+				start, end = m[0], m[1]
+				break
+			}
+		}
+	}
+	if start < 0 {
+		return page
+	}
+	critical := c.For(page[:start] + page[end:])
+	escaped := html.EscapeString(href)
+	deferred := `<link rel="preload" href="` + escaped + `" as="style" data-critical-css>` + applySheet +
+		`<noscript><link rel="stylesheet" href="` + escaped + `"></noscript>`
+	return page[:start] + "<style>" + critical + "</style>\n" + deferred + page[end:]
+}
+
+// applySheet turns the preloaded stylesheet into a stylesheet. A link a
+// script adds does not block rendering, and unlike an onload attribute an
+// inline script is the same on every page, so headers.csp can allow it by
+// one hash or by the nonce.
+const applySheet = `<script>document.querySelectorAll("link[data-critical-css]").forEach(function(l){l.rel="stylesheet"})</script>`
+
+// For returns the rules of the stylesheet the page's markup can match.
+func (c *Critical) For(page string) string {
+	used := pageSelectors(page)
+	key := used.key()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if css, ok := c.cache[key]; ok {
+		return css
+	}
+	css := renderRules(c.rules, used)
+	c.cache[key] = css
+	return css
+}
+
+// cssRule is a rule of a stylesheet: a style rule with its selectors, a
+// grouping at-rule such as @media holding its own rules, or another
+// at-rule kept or dropped whole.
+type cssRule struct {
+	prelude   string
+	selectors []string
+	body      string
+	children  []cssRule
+	group     bool
+	keep      bool
+}
+
+// parseCSS splits a stylesheet into rules. Comments are dropped.
+func parseCSS(css string) []cssRule {
+	var rules []cssRule
+	css = stripComments(css)
+	for i := 0; i < len(css); {
+		open := strings.IndexAny(css[i:], "{;")
+		if open < 0 {
+			break
+		}
+		open += i
+		prelude := strings.TrimSpace(css[i:open])
+		if css[open] == ';' {
+			// A statement at-rule; only @import still matters inline
+			if strings.HasPrefix(prelude, "@import") {
+				rules = append(rules, cssRule{prelude: prelude + ";", keep: true})
+			}
+			i = open + 1
+			continue
+		}
+		close := matchBrace(css, open)
+		body := css[open+1 : close]
+		i = close + 1
+		switch {
+		case strings.HasPrefix(prelude, "@media"), strings.HasPrefix(prelude, "@supports"):
+			rules = append(rules, cssRule{prelude: prelude, children: parseCSS(body), group: true})
+		case strings.HasPrefix(prelude, "@font-face"):
+			rules = append(rules, cssRule{prelude: prelude, body: body, keep: true})
+		case strings.HasPrefix(prelude, "@"):
+			// @keyframes and the like wait for the full stylesheet
+		default:
+			var sels []string
+			for _, s := range strings.Split(prelude, ",") {
+				if s = strings.TrimSpace(s); s != "" {
+					sels = append(sels, s)
+				}
+			}
+			rules = append(rules, cssRule{prelude: prelude, selectors: sels, body: body})
+		}
+	}
+	return rules
+}
+
+// squeezeCSS drops a stylesheet's comments and whitespace.
+func squeezeCSS(css string) string {
+	return strings.Join(strings.Fields(stripComments(css)), "")
+}
+
+func stripComments(css string) string {
+	var b strings.Builder
+	for {
+		i := strings.Index(css, "/*")
+		if i < 0 {
+			b.WriteString(css)
+			return b.String()
+		}
+		b.WriteString(css[:i])
+		j := strings.Index(css[i+2:], "*/")
+		if j < 0 {
+			return b.String()
+		}
+		css = css[i+2+j+2:]
+	}
+}
+
+// matchBrace returns the index of the brace closing the one at open, or
+// the end of css when it is never closed.
+func matchBrace(css string, open int) int {
+	depth := 0
+	var quote byte
+	for i := open; i < len(css); i++ {
+		ch := css[i]
+		switch {
+		case quote != 0:
+			if ch == '\\' {
+				i++
+			} else if ch == quote {
+				quote = 0
+			}
+		case ch == '"' || ch == '\'':
+			quote = ch
+		case ch == '{':
+			depth++
+		case ch == '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return len(css)
+}
+
+func renderRules(rules []cssRule, used selectorSet) string {
+	var b strings.Builder
+	for _, r := range rules {
+		switch {
+		case r.group:
+			if inner := renderRules(r.children, used); inner != "" {
+				b.WriteString(r.prelude + "{" + inner + "}\n")
+			}
+		case r.keep && r.body == "":
+			b.WriteString(r.prelude + "\n")
+		case r.keep:
+			b.WriteString(r.prelude + "{" + r.body + "}\n")
+		default:
+			var sels []string
+			for _, s := range r.selectors {
+				if used.matches(s) {
+					sels = append(sels, s)
+				}
+			}
+			if len(sels) > 0 {
+				b.WriteString(strings.Join(sels, ",") + "{" + strings.TrimSpace(r.body) + "}\n")
+			}
+		}
+	}
+	return b.String()
+}
+
+// selectorSet is the tags, classes and ids a page's markup uses.
+type selectorSet struct {
+	tags, classes, ids map[string]bool
+}
+
+func pageSelectors(page string) selectorSet {
+	s := selectorSet{tags: map[string]bool{}, classes: map[string]bool{}, ids: map[string]bool{}}
+	for _, m := range openTagRe.FindAllStringSubmatch(page, -1) {
+		s.tags[strings.ToLower(m[1])] = true
+	}
+	for _, m := range classAttrRe.FindAllStringSubmatch(page, -1) {
+		for _, c := range strings.Fields(m[1] + " " + m[2]) {
+			s.classes[c] = true
+		}
+	}
+	for _, m := range idAttrRe.FindAllStringSubmatch(page, -1) {
+		if id := strings.TrimSpace(m[1] + m[2]); id != "" {
+			s.ids[id] = true
+		}
+	}
+	return s
+}
+
+func (s selectorSet) key() string {
+	var parts []string
+	for _, set := range []map[string]bool{s.tags, s.classes, s.ids} {
+		names := make([]string, 0, len(set))
+		for n := range set {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		parts = append(parts, strings.Join(names, " "))
+	}
+	return strings.Join(parts, "|")
+}
+
+// matches reports whether every compound of a selector names tags,
+// classes and ids the page has. Pseudo-classes, pseudo-elements and
+// attribute selectors are ignored, so it errs on the side of a match.
+func (s selectorSet) matches(sel string) bool {
+	sel = attrSelRe.ReplaceAllString(pseudoRe.ReplaceAllString(sel, ""), "")
+	for _, compound := range combinatorRe.Split(strings.TrimSpace(sel), -1) {
+		for _, part := range simpleSelRe.FindAllString(compound, -1) {
+			switch part[0] {
+			case '.':
+				if !s.classes[part[1:]] {
+					return false
+				}
+			case '#':
+				if !s.ids[part[1:]] {
+					return false
+				}
+			default:
+				if part != "*" && !s.tags[strings.ToLower(part)] {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
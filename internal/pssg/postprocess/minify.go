@@ -0,0 +1,53 @@
+package postprocess
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/supermodeltools/arch-docs/internal/pssg/config"
+)
+
+var (
+	// rawRe matches the elements whose content minify leaves alone.
+	rawRe     = regexp.MustCompile(`(?is)<(pre|textarea|script|style)\b.*?</(?:pre|textarea|script|style)\s*>`)
+	commentRe = regexp.MustCompile(`(?s)<!--.*?-->`)
+	spaceRe   = regexp.MustCompile(`[ \t\r\n\f]+`)
+)
+
+func minifyProcessor(cfg *config.Config) Func {
+	return func(urlPath, page string) string {
+		return Minify(page)
+	}
+}
+
+// Minify shrinks a page without changing how it renders: comments go,
+// except conditional ones, and each run of whitespace becomes a single
+// space, or a newline if it held one. The content of pre, textarea,
+// script and style elements is kept as it is.
+func Minify(page string) string {
+	var b strings.Builder
+	b.Grow(len(page))
+	last := 0
+	for _, m := range rawRe.FindAllStringIndex(page, -1) {
+		b.WriteString(minifyText(page[last:m[0]]))
+		b.WriteString(page[m[0]:m[1]])
+		last = m[1]
+	}
+	b.WriteString(minifyText(page[last:]))
+	return b.String()
+}
+
+func minifyText(s string) string {
+	s = commentRe.ReplaceAllStringFunc(s, func(c string) string {
+		if strings.HasPrefix(c, "<!--[if") {
+			return c
+		}
+		return ""
+	})
+	return spaceRe.ReplaceAllStringFunc(s, func(ws string) string {
+		if strings.ContainsAny(ws, "\r\n") {
+			return "\n"
+		}
+		return " "
+	})
+}
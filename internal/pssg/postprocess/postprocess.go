@@ -0,0 +1,84 @@
+// Package postprocess rewrites rendered pages after the templates have
+// run, for changes that cut across every page, such as adding an
+// analytics snippet or minifying the HTML. output.postprocess lists the
+// processors a site uses, in the order they run.
+package postprocess
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/supermodeltools/arch-docs/internal/pssg/analytics"
+	"github.com/supermodeltools/arch-docs/internal/pssg/config"
+)
+
+// Func rewrites one page; urlPath is the page's path on the site, such
+// as "/about.html".
+type Func func(urlPath, page string) string
+
+// Processor makes the Func a site runs for a processor. It returns nil
+// when the site's config leaves the processor nothing to do.
+type Processor func(cfg *config.Config) Func
+
+var processors = map[string]Processor{
+	"analytics":    analyticsProcessor,
+	"anchors":      anchorsProcessor,
+	"base_path":    basePathProcessor,
+	"critical_css": criticalCSSProcessor,
+	"minify":       minifyProcessor,
+}
+
+// Register adds a processor that output.postprocess can name, or
+// replaces one.
+func Register(name string, p Processor) {
+	processors[name] = p
+}
+
+// Names lists the processors output.postprocess can name.
+func Names() []string {
+	names := make([]string, 0, len(processors))
+	for name := range processors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Pipeline chains the processors output.postprocess lists. It returns
+// nil when none of them has anything to do.
+func Pipeline(cfg *config.Config) (Func, error) {
+	var funcs []Func
+	for _, name := range cfg.Output.Postprocess {
+		p, ok := processors[name]
+		if !ok {
+			return nil, fmt.Errorf("output.postprocess: unknown processor %q", name)
+		}
+		if f := p(cfg); f != nil {
+			funcs = append(funcs, f)
+		}
+	}
+	if len(funcs) == 0 {
+		return nil, nil
+	}
+	return func(urlPath, page string) string {
+		for _, f := range funcs {
+			page = f(urlPath, page)
+		}
+		return page
+	}, nil
+}
+
+// analyticsProcessor adds the analytics snippet to the pages
+// analytics.include and analytics.exclude select.
+func analyticsProcessor(cfg *config.Config) Func {
+	snippet := analytics.Snippet(cfg)
+	if snippet == "" {
+		return nil
+	}
+	return func(urlPath, page string) string {
+		if !analytics.Tracked(cfg.Analytics, urlPath) {
+			return page
+		}
+		return analytics.Inject(page, snippet)
+	}
+}
@@ -0,0 +1,167 @@
+package relation
+
+import (
+	"sort"
+
+	"github.com/supermodeltools/arch-docs/internal/pssg/entity"
+)
+
+// Node is an entity in exported graph data.
+type Node struct {
+	ID    string `json:"id"`
+	Slug  string `json:"slug"`
+	Label string `json:"label"`
+	Type  string `json:"type"`
+	Count int    `json:"count"` // relations in and out
+}
+
+// Edge is one forward relation in exported graph data.
+type Edge struct {
+	Source string `json:"source"`
+	Target string `json:"target"`
+	Type   string `json:"type"` // relation name
+}
+
+// Data is the nodes/edges JSON the graph scripts draw.
+type Data struct {
+	Nodes []Node `json:"nodes"`
+	Edges []Edge `json:"edges"`
+}
+
+// Edges returns every forward relation, in entity order and then config
+// order.
+func (g *Graph) Edges(entities []*entity.Entity) []Edge {
+	edges := []Edge{}
+	for _, e := range entities {
+		for _, rc := range g.configs {
+			for _, t := range g.forward[e.Slug][rc.Name] {
+				edges = append(edges, Edge{Source: e.Slug, Target: t.Slug, Type: rc.Name})
+			}
+		}
+	}
+	return edges
+}
+
+// Data returns the entities that take part in any relation, with every
+// relation between them. Entities without relations are left out.
+func (g *Graph) Data(entities []*entity.Entity) Data {
+	return data(entities, g.Edges(entities))
+}
+
+// Top trims d to the limit best-connected nodes and the edges between
+// them, for overviews that cannot show a whole site.
+func (d Data) Top(limit int) Data {
+	if len(d.Nodes) <= limit {
+		return d
+	}
+	nodes := append([]Node(nil), d.Nodes...)
+	sort.SliceStable(nodes, func(i, j int) bool { return nodes[i].Count > nodes[j].Count })
+	nodes = nodes[:limit]
+	keep := make(map[string]bool, limit)
+	for _, n := range nodes {
+		keep[n.ID] = true
+	}
+	out := Data{Edges: []Edge{}}
+	for _, n := range d.Nodes {
+		if keep[n.ID] {
+			out.Nodes = append(out.Nodes, n)
+		}
+	}
+	for _, e := range d.Edges {
+		if keep[e.Source] && keep[e.Target] {
+			out.Edges = append(out.Edges, e)
+		}
+	}
+	return out
+}
+
+// Subgraphs returns each node's neighborhood, keyed by slug: the nodes
+// within depth relations of it in either direction and the relations
+// among them. Counts stay those of the whole graph.
+func (d Data) Subgraphs(depth int) map[string]Data {
+	index := make(map[string]int, len(d.Nodes))
+	for i, n := range d.Nodes {
+		index[n.ID] = i
+	}
+	incident := make([][]int, len(d.Nodes)) // node -> edge indexes
+	for i, e := range d.Edges {
+		s, t := index[e.Source], index[e.Target]
+		incident[s] = append(incident[s], i)
+		incident[t] = append(incident[t], i)
+	}
+	other := func(edge, node int) int {
+		if s := index[d.Edges[edge].Source]; s != node {
+			return s
+		}
+		return index[d.Edges[edge].Target]
+	}
+
+	subgraphs := make(map[string]Data, len(d.Nodes))
+	for start := range d.Nodes {
+		in := map[int]bool{start: true}
+		frontier := []int{start}
+		for step := 0; step < depth && len(frontier) > 0; step++ {
+			var next []int
+			for _, n := range frontier {
+				for _, edge := range incident[n] {
+					if o := other(edge, n); !in[o] {
+						in[o] = true
+						next = append(next, o)
+					}
+				}
+			}
+			frontier = next
+		}
+
+		members := make([]int, 0, len(in))
+		edgeSet := make(map[int]bool)
+		for n := range in {
+			members = append(members, n)
+			for _, edge := range incident[n] {
+				if in[other(edge, n)] {
+					edgeSet[edge] = true
+				}
+			}
+		}
+		sort.Ints(members)
+		edges := make([]int, 0, len(edgeSet))
+		for edge := range edgeSet {
+			edges = append(edges, edge)
+		}
+		sort.Ints(edges)
+
+		sub := Data{Nodes: make([]Node, len(members)), Edges: make([]Edge, len(edges))}
+		for i, n := range members {
+			sub.Nodes[i] = d.Nodes[n]
+		}
+		for i, edge := range edges {
+			sub.Edges[i] = d.Edges[edge]
+		}
+		subgraphs[d.Nodes[start].ID] = sub
+	}
+	return subgraphs
+}
+
+// data builds the nodes for the entities that appear in edges, counting
+// each entity's relations.
+func data(entities []*entity.Entity, edges []Edge) Data {
+	count := make(map[string]int)
+	for _, e := range edges {
+		count[e.Source]++
+		count[e.Target]++
+	}
+	d := Data{Nodes: []Node{}, Edges: edges}
+	for _, e := range entities {
+		if count[e.Slug] == 0 {
+			continue
+		}
+		d.Nodes = append(d.Nodes, Node{
+			ID:    e.Slug,
+			Slug:  e.Slug,
+			Label: e.GetString("title"),
+			Type:  e.GetString("node_type"),
+			Count: count[e.Slug],
+		})
+	}
+	return d
+}
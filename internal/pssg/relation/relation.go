@@ -0,0 +1,167 @@
+package relation
+
+import (
+	"sort"
+
+	"github.com/supermodeltools/arch-docs/internal/pssg/config"
+	"github.com/supermodeltools/arch-docs/internal/pssg/entity"
+)
+
+// Group is a named set of related entities shown on an entity page,
+// e.g. "Depends on" or the automatic reverse "Used by".
+type Group struct {
+	Name     string
+	Label    string
+	Reverse  bool
+	Entities []*entity.Entity
+	Links    []Link // targets on sibling sites
+}
+
+// Link is a relation target on another site.
+type Link struct {
+	Site  string // the sibling site's name
+	Slug  string
+	Title string
+	URL   string
+}
+
+// Dangling is a relation target that did not resolve to any entity.
+type Dangling struct {
+	From     string
+	Relation string
+	Target   string
+}
+
+// Graph holds resolved forward and reverse relations for all entities.
+type Graph struct {
+	configs  []config.RelationConfig
+	forward  map[string]map[string][]*entity.Entity // slug -> relation -> targets
+	reverse  map[string]map[string][]*entity.Entity // slug -> reverse relation -> sources
+	external map[string]map[string][]Link           // slug -> relation -> sibling targets
+	Dangling []Dangling
+}
+
+// Build resolves every configured relation field through slugMap. Targets are
+// matched by exact slug first, then by slugifying the raw value, so titles
+// work as well as slugs.
+func Build(entities []*entity.Entity, slugMap map[string]*entity.Entity, configs []config.RelationConfig) *Graph {
+	g := &Graph{
+		configs: configs,
+		forward: make(map[string]map[string][]*entity.Entity),
+		reverse: make(map[string]map[string][]*entity.Entity),
+	}
+
+	for _, e := range entities {
+		for _, rc := range configs {
+			for _, target := range e.GetStringSlice(rc.Field) {
+				t, ok := Resolve(slugMap, target)
+				if !ok {
+					g.Dangling = append(g.Dangling, Dangling{From: e.Slug, Relation: rc.Name, Target: target})
+					continue
+				}
+				if t == e {
+					continue
+				}
+				add(g.forward, e.Slug, rc.Name, t)
+				if rc.Reverse != "" {
+					add(g.reverse, t.Slug, rc.Reverse, e)
+				}
+			}
+		}
+	}
+
+	// Reverse lists are built in load order; sort them for stable output.
+	for _, rels := range g.reverse {
+		for _, list := range rels {
+			sort.SliceStable(list, func(i, j int) bool { return list[i].Slug < list[j].Slug })
+		}
+	}
+	return g
+}
+
+// Resolve looks up a relation target by slug, falling back to its slugified form.
+func Resolve(slugMap map[string]*entity.Entity, target string) (*entity.Entity, bool) {
+	if e, ok := slugMap[target]; ok {
+		return e, true
+	}
+	e, ok := slugMap[entity.ToSlug(target)]
+	return e, ok
+}
+
+func add(m map[string]map[string][]*entity.Entity, slug, rel string, e *entity.Entity) {
+	if m[slug] == nil {
+		m[slug] = make(map[string][]*entity.Entity)
+	}
+	for _, existing := range m[slug][rel] {
+		if existing == e {
+			return
+		}
+	}
+	m[slug][rel] = append(m[slug][rel], e)
+}
+
+// LinkExternal looks up dangling targets with resolve, which finds them
+// on other sites. The targets it finds become links in the entity's
+// relation groups and leave Dangling.
+func (g *Graph) LinkExternal(resolve func(target string) (Link, bool)) {
+	var dangling []Dangling
+	for _, d := range g.Dangling {
+		link, ok := resolve(d.Target)
+		if !ok {
+			dangling = append(dangling, d)
+			continue
+		}
+		if g.external == nil {
+			g.external = make(map[string]map[string][]Link)
+		}
+		if g.external[d.From] == nil {
+			g.external[d.From] = make(map[string][]Link)
+		}
+		g.external[d.From][d.Relation] = append(g.external[d.From][d.Relation], link)
+	}
+	g.Dangling = dangling
+}
+
+// Related returns the entities linked from slug by the named relation,
+// which may be a forward or reverse relation name.
+func (g *Graph) Related(slug, name string) []*entity.Entity {
+	if list, ok := g.forward[slug][name]; ok {
+		return list
+	}
+	return g.reverse[slug][name]
+}
+
+// Groups returns all non-empty relation groups for an entity, forward
+// relations first, in config order. Forward groups include links to
+// sibling sites.
+func (g *Graph) Groups(slug string) []Group {
+	var groups []Group
+	for _, rc := range g.configs {
+		list, links := g.forward[slug][rc.Name], g.external[slug][rc.Name]
+		if len(list) > 0 || len(links) > 0 {
+			groups = append(groups, Group{Name: rc.Name, Label: rc.Label, Entities: list, Links: links})
+		}
+	}
+	for _, rc := range g.configs {
+		if rc.Reverse == "" {
+			continue
+		}
+		if list := g.reverse[slug][rc.Reverse]; len(list) > 0 {
+			groups = append(groups, Group{Name: rc.Reverse, Label: rc.ReverseLabel, Reverse: true, Entities: list})
+		}
+	}
+	return groups
+}
+
+// Map returns relation name -> entities for an entity, covering both forward
+// and reverse relations, for direct lookup in templates.
+func (g *Graph) Map(slug string) map[string][]*entity.Entity {
+	m := make(map[string][]*entity.Entity)
+	for name, list := range g.forward[slug] {
+		m[name] = list
+	}
+	for name, list := range g.reverse[slug] {
+		m[name] = list
+	}
+	return m
+}
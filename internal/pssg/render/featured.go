@@ -0,0 +1,25 @@
+package render
+
+import (
+	"github.com/supermodeltools/arch-docs/internal/pssg/entity"
+	"github.com/supermodeltools/arch-docs/internal/pssg/featured"
+)
+
+// featuredSeed backs the featured template function. It starts with the
+// seed featured sets for the engine's creation; the builder replaces it
+// with the build's own.
+type featuredSeed struct {
+	seed string
+}
+
+// SetFeaturedSeed sets the seed the featured template function picks by,
+// so it picks the same entities as the homepage's .Featured.
+func (e *Engine) SetFeaturedSeed(seed string) {
+	e.featured.seed = seed
+}
+
+// pick returns n of the entities, chosen by the featured seed, e.g.
+// {{range featured .Entities 3}}. n of 0 returns all of them, shuffled.
+func (s *featuredSeed) pick(entities []*entity.Entity, n int) []*entity.Entity {
+	return featured.Pick(entities, s.seed, n)
+}
@@ -6,11 +6,14 @@ import (
 	"html/template"
 	"math"
 	"net/url"
+	"reflect"
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/supermodeltools/arch-docs/internal/pssg/entity"
+	"github.com/supermodeltools/arch-docs/internal/pssg/text"
 )
 
 // BuildFuncMap creates the template FuncMap with all helper functions.
@@ -29,6 +32,7 @@ func BuildFuncMap() template.FuncMap {
 		"hasSuffix": strings.HasSuffix,
 		"trimSpace": strings.TrimSpace,
 		"urlencode": url.QueryEscape,
+		"truncate":  func(max int, s string) string { return text.Truncate(s, max) },
 
 		// Number functions
 		"formatNumber": formatNumber,
@@ -64,6 +68,9 @@ func BuildFuncMap() template.FuncMap {
 		"hasField":       hasField,
 		"getInt":         getInt,
 		"getFloat":       getFloat,
+		"getTime":        getTime,
+		"getDuration":    getDuration,
+		"path":           pathAccess,
 
 		// JSON/HTML functions
 		"jsonMarshal": jsonMarshal,
@@ -96,6 +103,10 @@ func BuildFuncMap() template.FuncMap {
 		// Misc
 		"toJSON": toJSON,
 		"noescape": func(s string) template.HTML { return template.HTML(s) },
+
+		// Links that open an AI chat with a prompt
+		"chatGPTURL": ChatGPTURL,
+		"claudeURL":  ClaudeURL,
 	}
 }
 
@@ -128,17 +139,10 @@ func formatNumber(n interface{}) string {
 	return string(result)
 }
 
-var isoDuration = regexp.MustCompile(`PT(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?`)
-
 // durationMinutes converts an ISO 8601 duration to minutes.
 func durationMinutes(d string) int {
-	matches := isoDuration.FindStringSubmatch(d)
-	if matches == nil {
-		return 0
-	}
-	hours, _ := strconv.Atoi(matches[1])
-	minutes, _ := strconv.Atoi(matches[2])
-	return hours*60 + minutes
+	dur, _ := entity.ParseDuration(d)
+	return int(dur / time.Minute)
 }
 
 // totalTime adds two ISO 8601 durations.
@@ -271,6 +275,10 @@ func length(v interface{}) int {
 	case []map[string]interface{}:
 		return len(val)
 	}
+	// Other slices and maps, such as the C4 and ADR lists
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Slice || rv.Kind() == reflect.Map || rv.Kind() == reflect.Array {
+		return rv.Len()
+	}
 	return 0
 }
 
@@ -344,6 +352,32 @@ func getFloat(e *entity.Entity, key string) float64 {
 	return e.GetFloat(key)
 }
 
+// pathAccess looks up a dot-separated path in an entity's fields, or in any
+// map/list value, e.g. {{path .Entity "nutrition.protein_g"}}.
+func pathAccess(v interface{}, path string) interface{} {
+	if e, ok := v.(*entity.Entity); ok {
+		if e == nil {
+			return nil
+		}
+		return e.GetPath(path)
+	}
+	return entity.LookupPath(v, path)
+}
+
+func getTime(e *entity.Entity, key string) time.Time {
+	if e == nil {
+		return time.Time{}
+	}
+	return e.GetTime(key)
+}
+
+func getDuration(e *entity.Entity, key string) time.Duration {
+	if e == nil {
+		return 0
+	}
+	return e.GetDuration(key)
+}
+
 func jsonMarshal(v interface{}) template.JS {
 	data, err := json.Marshal(v)
 	if err != nil {
@@ -609,3 +643,13 @@ func scaleQty(baseQty float64, baseServings, newServings int) string {
 	scaled := baseQty * float64(newServings) / float64(baseServings)
 	return fractionDisplay(scaled)
 }
+
+// ChatGPTURL links to a new ChatGPT conversation with prompt filled in.
+func ChatGPTURL(prompt string) string {
+	return "https://chatgpt.com/?q=" + url.QueryEscape(prompt)
+}
+
+// ClaudeURL links to a new Claude conversation with prompt filled in.
+func ClaudeURL(prompt string) string {
+	return "https://claude.ai/new?q=" + url.QueryEscape(prompt)
+}
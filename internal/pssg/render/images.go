@@ -0,0 +1,119 @@
+package render
+
+import (
+	"fmt"
+	"html/template"
+	"strings"
+
+	"github.com/supermodeltools/arch-docs/internal/pssg/entity"
+	"github.com/supermodeltools/arch-docs/internal/pssg/imaging"
+)
+
+// imageIndex backs imgSrcset. The builder fills it once images are
+// processed, before any page renders.
+type imageIndex struct {
+	sets map[string]*imaging.Set
+}
+
+// SetImages makes the processed hero images, by entity slug, available to
+// imgSrcset.
+func (e *Engine) SetImages(sets map[string]*imaging.Set) {
+	e.images.sets = sets
+}
+
+// imgSrcset renders responsive markup for a processed image: an <img> with
+// srcset, sizes and intrinsic dimensions, wrapped in a <picture> with one
+// <source> per extra format when WebP or AVIF variants exist. The image is
+// a set, an entity or an entity slug; the rest are key-value options:
+//
+//	{{imgSrcset .Image "sizes" "(min-width: 960px) 960px, 100vw" "alt" "Soup"}}
+//	{{imgSrcset . "thumb" true "class" "card-img"}}
+//
+// Options are alt, class, sizes (default: full width up to the widest
+// variant), thumb (use the cropped thumbnail) and loading (default
+// "lazy"; pass "eager" for images above the fold). Unknown images render
+// nothing.
+func (ix *imageIndex) imgSrcset(img interface{}, opts ...interface{}) (template.HTML, error) {
+	var set *imaging.Set
+	switch v := img.(type) {
+	case *imaging.Set:
+		set = v
+	case *entity.Entity:
+		if v != nil {
+			set = ix.sets[v.Slug]
+		}
+	case string:
+		set = ix.sets[v]
+	}
+	if set == nil || len(set.Variants) == 0 {
+		return "", nil
+	}
+	if len(opts)%2 != 0 {
+		return "", fmt.Errorf("imgSrcset: options must be key-value pairs")
+	}
+
+	attrs := map[string]string{"loading": "lazy"}
+	thumb := false
+	for i := 0; i < len(opts); i += 2 {
+		key, ok := opts[i].(string)
+		if !ok {
+			return "", fmt.Errorf("imgSrcset: option key %v is not a string", opts[i])
+		}
+		switch key {
+		case "thumb":
+			thumb, _ = opts[i+1].(bool)
+		case "alt", "class", "sizes", "loading":
+			attrs[key] = fmt.Sprint(opts[i+1])
+		default:
+			return "", fmt.Errorf("imgSrcset: unknown option %q", key)
+		}
+	}
+
+	// Each format becomes one srcset; the last is the fallback.
+	var groups [][]imaging.Variant
+	if thumb {
+		for _, v := range set.Thumbnails {
+			groups = append(groups, []imaging.Variant{v})
+		}
+	} else {
+		for _, t := range set.Types() {
+			groups = append(groups, set.ByType(t))
+		}
+	}
+	if len(groups) == 0 {
+		return "", nil
+	}
+	fallback := groups[len(groups)-1]
+	largest := fallback[len(fallback)-1]
+	if _, ok := attrs["sizes"]; !ok {
+		attrs["sizes"] = fmt.Sprintf("(min-width: %dpx) %dpx, 100vw", largest.Width, largest.Width)
+	}
+
+	esc := template.HTMLEscapeString
+	var b strings.Builder
+	if len(groups) > 1 {
+		b.WriteString("<picture>")
+		for _, g := range groups[:len(groups)-1] {
+			fmt.Fprintf(&b, `<source type="%s" srcset="%s" sizes="%s">`, g[0].Type, esc(srcset(g)), esc(attrs["sizes"]))
+		}
+	}
+	fmt.Fprintf(&b, `<img src="%s" srcset="%s" sizes="%s" width="%d" height="%d" alt="%s"`,
+		esc(largest.URL), esc(srcset(fallback)), esc(attrs["sizes"]), largest.Width, largest.Height, esc(attrs["alt"]))
+	if c := attrs["class"]; c != "" {
+		fmt.Fprintf(&b, ` class="%s"`, esc(c))
+	}
+	fmt.Fprintf(&b, ` loading="%s" decoding="async">`, esc(attrs["loading"]))
+	if len(groups) > 1 {
+		b.WriteString("</picture>")
+	}
+	return template.HTML(b.String()), nil
+}
+
+// srcset lists variants as "url 480w, url 960w".
+func srcset(variants []imaging.Variant) string {
+	parts := make([]string, len(variants))
+	for i, v := range variants {
+		parts[i] = fmt.Sprintf("%s %dw", v.URL, v.Width)
+	}
+	return strings.Join(parts, ", ")
+}
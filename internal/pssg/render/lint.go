@@ -0,0 +1,56 @@
+package render
+
+import (
+	"fmt"
+	"sort"
+	"text/template/parse"
+)
+
+// Lint reports {{template}} calls naming templates that are not defined.
+// html/template only notices these when the calling page is executed, so
+// a typo in a rarely rendered page would otherwise surface mid-build.
+func (e *Engine) Lint() []string {
+	var problems []string
+	for _, t := range e.tmpl.Templates() {
+		if t.Tree == nil || t.Name() == "" {
+			continue
+		}
+		for _, name := range templateCalls(t.Tree.Root) {
+			if e.tmpl.Lookup(name) == nil {
+				problems = append(problems, fmt.Sprintf("%s: calls undefined template %q", t.Name(), name))
+			}
+		}
+	}
+	sort.Strings(problems)
+	return problems
+}
+
+// templateCalls returns the names used in {{template}} actions under n.
+func templateCalls(n parse.Node) []string {
+	var names []string
+	var walk func(parse.Node)
+	walk = func(n parse.Node) {
+		switch n := n.(type) {
+		case *parse.ListNode:
+			if n == nil {
+				return
+			}
+			for _, c := range n.Nodes {
+				walk(c)
+			}
+		case *parse.TemplateNode:
+			names = append(names, n.Name)
+		case *parse.IfNode:
+			walk(n.List)
+			walk(n.ElseList)
+		case *parse.RangeNode:
+			walk(n.List)
+			walk(n.ElseList)
+		case *parse.WithNode:
+			walk(n.List)
+			walk(n.ElseList)
+		}
+	}
+	walk(n)
+	return names
+}
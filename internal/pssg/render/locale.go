@@ -0,0 +1,207 @@
+package render
+
+import (
+	"fmt"
+	"html/template"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/supermodeltools/arch-docs/internal/pssg/config"
+	"github.com/supermodeltools/arch-docs/internal/pssg/entity"
+	"github.com/supermodeltools/arch-docs/internal/pssg/i18n"
+)
+
+// Canonical units (see unitAliases) in millilitres and grams.
+var (
+	unitML = map[string]float64{
+		"teaspoon": 4.92892, "tablespoon": 14.7868, "cup": 236.588, "pint": 473.176,
+		"quart": 946.353, "gallon": 3785.41, "ml": 1, "liter": 1000,
+	}
+	unitG = map[string]float64{
+		"ounce": 28.3495, "pound": 453.592, "gram": 1, "kilogram": 1000,
+	}
+)
+
+// unitSymbols are the default labels for converted quantities; a
+// `units.<unit>` message in the i18n files replaces them.
+var unitSymbols = map[string][2]string{
+	"teaspoon": {"tsp", "tsp"}, "tablespoon": {"tbsp", "tbsp"}, "cup": {"cup", "cups"},
+	"pint": {"pt", "pt"}, "quart": {"qt", "qt"}, "gallon": {"gal", "gal"},
+	"ml": {"ml", "ml"}, "liter": {"l", "l"}, "ounce": {"oz", "oz"}, "pound": {"lb", "lb"},
+	"gram": {"g", "g"}, "kilogram": {"kg", "kg"},
+}
+
+// localizer backs the locale-dependent template functions.
+type localizer struct {
+	locale   i18n.Locale
+	imperial bool
+	catalog  *i18n.Catalog
+}
+
+// localeFuncs returns the template functions that follow site.locale and
+// site.units.
+func localeFuncs(cfg *config.Config, catalog *i18n.Catalog) template.FuncMap {
+	l := &localizer{locale: i18n.NewLocale(cfg.Site.Locale), catalog: catalog}
+	l.imperial = l.locale.Imperial()
+	if cfg.Site.Units != "" {
+		l.imperial = cfg.Site.Units == "imperial"
+	}
+	return template.FuncMap{
+		"localizeNumber":   l.number,
+		"localizeDate":     l.date,
+		"localizeQuantity": func(qty float64, unit string) string { return l.quantity(qty, unit, l.imperial) },
+		"toMetric":         func(qty float64, unit string) string { return l.quantity(qty, unit, false) },
+		"toImperial":       func(qty float64, unit string) string { return l.quantity(qty, unit, true) },
+		"convertUnit":      convertUnit,
+		"textDir": func() string {
+			if i18n.IsRTL(cfg.Site.Language) {
+				return "rtl"
+			}
+			return "ltr"
+		},
+	}
+}
+
+// number formats n with the locale's separators: {{localizeNumber 1234.5}}
+// gives "1,234.5" in en and "1 234,5" in fr. An optional argument fixes the
+// number of decimals.
+func (l *localizer) number(n interface{}, decimals ...int) string {
+	d := -1
+	if len(decimals) > 0 {
+		d = decimals[0]
+	}
+	switch v := n.(type) {
+	case int:
+		return l.locale.FormatNumber(float64(v), d)
+	case int64:
+		return l.locale.FormatNumber(float64(v), d)
+	case float64:
+		return l.locale.FormatNumber(v, d)
+	}
+	return fmt.Sprintf("%v", n)
+}
+
+// date formats a time.Time or date string in the "long" (default) or
+// "short" style. Unparseable strings are returned unchanged.
+func (l *localizer) date(v interface{}, style ...string) string {
+	var t time.Time
+	switch d := v.(type) {
+	case time.Time:
+		t = d
+	case string:
+		parsed, ok := entity.ParseTime(d)
+		if !ok {
+			return d
+		}
+		t = parsed
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+	if t.IsZero() {
+		return ""
+	}
+	s := "long"
+	if len(style) > 0 {
+		s = style[0]
+	}
+	return l.locale.FormatDate(t, s)
+}
+
+// quantity converts qty of unit to the metric or US customary system and
+// formats it with a suitable unit: 2 cups becomes "475 ml" and 500 g
+// becomes "1⅛ lb". Spoon measures stay spoons in metric, as metric recipes
+// use them too. Units without a conversion, such as cloves, are formatted
+// as given.
+func (l *localizer) quantity(qty float64, unit string, imperial bool) string {
+	given := strings.TrimSpace(unit)
+	unit = canonicalUnit(unit)
+	if unitML[unit] == 0 && unitG[unit] == 0 {
+		return strings.TrimSpace(fractionDisplay(qty) + " " + given)
+	}
+	v, to := qty, unit
+	switch {
+	case unitML[unit] > 0 && imperial:
+		ml := qty * unitML[unit]
+		switch {
+		case ml < 14:
+			v, to = ml/unitML["teaspoon"], "teaspoon"
+		case ml < 59:
+			v, to = ml/unitML["tablespoon"], "tablespoon"
+		default:
+			v, to = ml/unitML["cup"], "cup"
+		}
+	case unitML[unit] > 0 && unit != "teaspoon" && unit != "tablespoon":
+		ml := qty * unitML[unit]
+		if ml >= 1000 {
+			v, to = ml/1000, "liter"
+		} else {
+			v, to = roundMetric(ml), "ml"
+		}
+	case unitG[unit] > 0 && imperial:
+		g := qty * unitG[unit]
+		if g >= unitG["pound"] {
+			v, to = g/unitG["pound"], "pound"
+		} else {
+			v, to = g/unitG["ounce"], "ounce"
+		}
+	case unitG[unit] > 0:
+		g := qty * unitG[unit]
+		if g >= 1000 {
+			v, to = g/1000, "kilogram"
+		} else {
+			v, to = roundMetric(g), "gram"
+		}
+	}
+
+	num := l.locale.FormatNumber(math.Round(v*100)/100, -1)
+	if imperial || to == "teaspoon" || to == "tablespoon" {
+		v = math.Round(v*8) / 8 // kitchen measures come in eighths
+		num = fractionDisplay(v)
+	}
+	return num + " " + l.unitLabel(to, v)
+}
+
+func (l *localizer) unitLabel(unit string, v float64) string {
+	n := 2
+	if v <= 1 {
+		n = 1
+	}
+	if key := "units." + unit; l.catalog.Has(key) {
+		return l.catalog.T(key, n)
+	}
+	return unitSymbols[unit][n-1]
+}
+
+// roundMetric rounds gram and millilitre amounts the way recipes write
+// them: tenths below 10, units below 100, then to the nearest 5.
+func roundMetric(v float64) float64 {
+	switch {
+	case v < 10:
+		return math.Round(v*10) / 10
+	case v < 100:
+		return math.Round(v)
+	}
+	return math.Round(v/5) * 5
+}
+
+// convertUnit converts qty between two units of volume or of mass, for
+// templates that do their own formatting: {{convertUnit 2 "cups" "ml"}}.
+func convertUnit(qty float64, from, to string) (float64, error) {
+	f, t := canonicalUnit(from), canonicalUnit(to)
+	if unitML[f] > 0 && unitML[t] > 0 {
+		return qty * unitML[f] / unitML[t], nil
+	}
+	if unitG[f] > 0 && unitG[t] > 0 {
+		return qty * unitG[f] / unitG[t], nil
+	}
+	return 0, fmt.Errorf("convertUnit: cannot convert %s to %s", from, to)
+}
+
+func canonicalUnit(u string) string {
+	u = strings.ToLower(strings.TrimSpace(u))
+	if c, ok := unitAliases[u]; ok {
+		return c
+	}
+	return u
+}
@@ -0,0 +1,39 @@
+package render
+
+import (
+	"strings"
+
+	"github.com/supermodeltools/arch-docs/internal/pssg/config"
+	"github.com/supermodeltools/arch-docs/internal/pssg/text"
+)
+
+// ogDescriptionLimit is about what share previews show of a description.
+const ogDescriptionLimit = 200
+
+// withOGDefaults fills what a page's OG meta leaves empty from the og
+// section, for the og template function: {{$og := og .OG}}.
+func withOGDefaults(og OGMeta, cfg *config.Config) OGMeta {
+	if og.ImageURL == "" && cfg.OG.Image != "" {
+		og.ImageURL = cfg.OG.Image
+		if strings.HasPrefix(og.ImageURL, "/") && !strings.HasPrefix(og.ImageURL, "//") {
+			og.ImageURL = cfg.Site.BaseURL + og.ImageURL
+		}
+		if og.ImageAlt == "" {
+			og.ImageAlt = cfg.OG.ImageAlt
+		}
+	}
+	if og.ImageAlt == "" {
+		og.ImageAlt = og.Title
+	}
+	og.Description = text.Truncate(og.Description, ogDescriptionLimit)
+	if og.Card == "" {
+		og.Card = cfg.OG.TwitterCard
+	}
+	if og.TwitterSite == "" {
+		og.TwitterSite = cfg.OG.TwitterSite
+	}
+	if og.TwitterCreator == "" {
+		og.TwitterCreator = cfg.OG.TwitterCreator
+	}
+	return og
+}
@@ -7,51 +7,200 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	texttemplate "text/template"
+	"time"
 
+	"github.com/supermodeltools/arch-docs/internal/pssg/adr"
 	"github.com/supermodeltools/arch-docs/internal/pssg/affiliate"
+	"github.com/supermodeltools/arch-docs/internal/pssg/apispec"
+	"github.com/supermodeltools/arch-docs/internal/pssg/c4"
+	"github.com/supermodeltools/arch-docs/internal/pssg/collection"
 	"github.com/supermodeltools/arch-docs/internal/pssg/config"
+	"github.com/supermodeltools/arch-docs/internal/pssg/contributors"
+	"github.com/supermodeltools/arch-docs/internal/pssg/engagement"
 	"github.com/supermodeltools/arch-docs/internal/pssg/entity"
+	"github.com/supermodeltools/arch-docs/internal/pssg/featured"
+	"github.com/supermodeltools/arch-docs/internal/pssg/freshness"
+	"github.com/supermodeltools/arch-docs/internal/pssg/history"
+	"github.com/supermodeltools/arch-docs/internal/pssg/i18n"
+	"github.com/supermodeltools/arch-docs/internal/pssg/imaging"
+	"github.com/supermodeltools/arch-docs/internal/pssg/output"
+	"github.com/supermodeltools/arch-docs/internal/pssg/owners"
+	"github.com/supermodeltools/arch-docs/internal/pssg/relation"
+	"github.com/supermodeltools/arch-docs/internal/pssg/series"
+	"github.com/supermodeltools/arch-docs/internal/pssg/similar"
+	"github.com/supermodeltools/arch-docs/internal/pssg/source"
 	"github.com/supermodeltools/arch-docs/internal/pssg/taxonomy"
+	"github.com/supermodeltools/arch-docs/internal/pssg/theme"
 )
 
 // Engine is the template rendering engine.
 type Engine struct {
 	tmpl    *template.Template
 	cfg     *config.Config
+	images  *imageIndex
+	// featured backs the featured template function.
+	featured *featuredSeed
+	// prompt is the prompts.template; nil when the built-in wording is used.
+	prompt  *texttemplate.Template
 }
 
 // EntityPageContext is the template context for entity (recipe) pages.
 type EntityPageContext struct {
 	Site            config.SiteConfig
+	Languages       []LanguageLink // every edition of this page; nil unless i18n is configured
 	Entity          *entity.Entity
+	// Image holds the resized variants of the entity's hero image; nil
+	// unless images are enabled and the entity names one.
+	Image           *imaging.Set
 	Slug            string
 	URL             string
 	CanonicalURL    string
 	Breadcrumbs     []Breadcrumb
 	Pairings        []*entity.Entity
+	Relations       map[string][]*entity.Entity // relation name (forward or reverse) -> entities
+	RelationGroups  []relation.Group            // non-empty relations in display order
+	Series          *series.Nav                 // nil unless the entity belongs to a series
 	Enrichment      map[string]interface{}
 	AffiliateLinks  []affiliate.Link
+	// AffiliateDisclosure is set only when AffiliateLinks is non-empty.
+	AffiliateDisclosure template.HTML
 	CookModePrompt  string
+	// PromptURL is the published copy of CookModePrompt; "" unless
+	// prompts.enabled is set and the entity has a prompt.
+	PromptURL string
+	// PrintURL is the entity's printer-friendly copy; "" unless
+	// print.enabled is set.
+	PrintURL string
 	JsonLD          template.HTML
 	Taxonomies      []taxonomy.Taxonomy
 	AllTaxonomies   []taxonomy.Taxonomy
 	ValidSlugs      map[string]map[string]bool
+	// NoIndex is set for entities with `noindex: true`; _head.html switches
+	// the robots meta tag to noindex.
+	NoIndex         bool
+	// Robots is the robots directive robots.pages sets for the page type,
+	// or "" for "index, follow". NoIndex wins over it.
+	Robots          string
+	// Contributors is the raw contributors file.
+	//
+	// Deprecated: use Author, or Profiles on the contributors page.
 	Contributors    map[string]interface{}
+	// Author is the contributor profile of the entity's author; nil when
+	// the contributors file has none.
+	Author          *contributors.Profile
 	OG              OGMeta
 	ChartData       template.HTML
 	CTA             config.CTAConfig
-	SourceCode      string
+	// SourceCode is the highlighted excerpt the entity cites, set when
+	// paths.source_dir is set; SourceURL links to its source page.
+	SourceCode      template.HTML
 	SourceLang      string
+	SourceURL       string
+	// GraphURL is the entity's relation subgraph JSON, set when
+	// graph.enabled is and the entity has relations.
+	GraphURL        string
+	// C4 places the entity in the C4 model; nil unless c4.enabled is set
+	// and the entity has a level.
+	C4              *c4.Element
+	C4URL           string // the system context page
+	// ADR is the entity's decision record; nil unless adr.enabled is set
+	// and the entity is an ADR.
+	ADR             *adr.Record
+	ADRURL          string // the ADR index page
+	// API is the OpenAPI or protobuf spec the entity references, or nil.
+	API             *apispec.Spec
+	// Owners are the entity's code owners; nil unless owners.enabled is
+	// set. OwnerDir is the owner taxonomy's directory.
+	Owners          []owners.Owner
+	OwnerDir        string
+	// Changes are the recent commits to the entity's file, newest first;
+	// nil unless history.enabled is set. ChangesFeed is their Atom feed
+	// and CommitURL, when set, prefixes a hash to link a commit.
+	Changes         []history.Commit
+	ChangesFeed     string
+	CommitURL       string
+	// Stale is set when the code the entity documents changed more than
+	// freshness.threshold_days after the entity did.
+	Stale           *freshness.Item
+	// Runbooks are the entity's runbook body sections; StepsURL is their
+	// steps as JSON, "" when it has none.
+	Runbooks        []Runbook
+	StepsURL        string
+	// Comments configures the page's discussion thread; nil when comments
+	// are off for the site or the entity.
+	Comments        *Comments
+	// Similar are the entities with the most similar text, most similar
+	// first; nil unless similar.enabled is set.
+	Similar         []similar.Match
+	// Engagement holds the entity's comment, reaction and rating counts
+	// from extra.engagement; nil when the file has none for it.
+	Engagement      *engagement.Counts
+}
+
+// Comments is what a template needs to embed a giscus or utterances
+// thread. Mapping and Term are in the provider's terms: a slug mapping
+// becomes "specific" (giscus) with the slug as Term.
+type Comments struct {
+	Provider   string
+	Repo       string
+	RepoID     string
+	Category   string
+	CategoryID string
+	Mapping    string
+	Term       string
+	Label      string
+	Theme      string
+	Lang       string
+}
+
+// Runbook is a body section of type runbook.
+type Runbook struct {
+	Name   string
+	Header string
+	Steps  []entity.Step
+}
+
+// RecentEntity is an entity with the date it was added or last updated.
+type RecentEntity struct {
+	Entity *entity.Entity
+	Date   time.Time
+}
+
+// HomepageSection is a homepage section the build computed from
+// homepage.sections.
+type HomepageSection struct {
+	Type     string
+	Title    string
+	URL      string           // the collection page or taxonomy index; "" for other types
+	Entities []*entity.Entity // for all types but top_terms
+	Taxonomy string           // for top_terms: the taxonomy name, for term URLs
+	Terms    []taxonomy.Entry // for top_terms
 }
 
 // HomepageContext is the template context for the homepage.
 type HomepageContext struct {
 	Site          config.SiteConfig
+	Languages     []LanguageLink
 	Entities      []*entity.Entity
 	Taxonomies    []taxonomy.Taxonomy
 	Favorites     []*entity.Entity
+	// Featured are featured.limit entities picked by the featured seed.
+	Featured      []*entity.Entity
+	// Collections are the curated collections marked for the homepage.
+	Collections   []*collection.Collection
+	// Sections are the sections homepage.sections composes, in order.
+	Sections      []HomepageSection
+	// RecentlyAdded and RecentlyUpdated are the entities builds first saw
+	// and saw change most recently, newest first; nil unless
+	// recent.enabled is set.
+	RecentlyAdded   []RecentEntity
+	RecentlyUpdated []RecentEntity
 	JsonLD        template.HTML
 	EntityCount   int
+	// Contributors is the raw contributors file.
+	//
+	// Deprecated: use the contributors page's Profiles.
 	Contributors  map[string]interface{}
 	OG            OGMeta
 	ChartData     template.HTML
@@ -62,6 +211,7 @@ type HomepageContext struct {
 // HubPageContext is the template context for taxonomy hub (category) pages.
 type HubPageContext struct {
 	Site           config.SiteConfig
+	Languages      []LanguageLink
 	Taxonomy       taxonomy.Taxonomy
 	Entry          taxonomy.Entry
 	Entities       []*entity.Entity
@@ -69,17 +219,36 @@ type HubPageContext struct {
 	JsonLD         template.HTML
 	Breadcrumbs    []Breadcrumb
 	AllTaxonomies  []taxonomy.Taxonomy
+	// Contributors and ContributorProfile are the raw contributors file
+	// and, on author hubs, the author's entry in it.
+	//
+	// Deprecated: use Contributor.
 	Contributors   map[string]interface{}
 	ContributorProfile map[string]interface{}
+	// Contributor is the author's profile on author hub pages; nil
+	// elsewhere or when the contributors file has none.
+	Contributor    *contributors.Profile
 	OG             OGMeta
 	ChartData      template.HTML
 	CTA            config.CTAConfig
+	JSONURL        string // the page's JSON twin; "" unless output.json_twins is set
+	// ZeroState is set on the hub of an entry with fewer than the
+	// taxonomy's min_entities entities, rendered because its sparse
+	// setting is "render". Such hubs are noindex and not in the sitemap.
+	ZeroState      bool
+	Robots         string // as on EntityPageContext
+	// RecentlyAdded and RecentlyUpdated are as on HomepageContext, among
+	// the entry's entities.
+	RecentlyAdded   []RecentEntity
+	RecentlyUpdated []RecentEntity
 }
 
 // TaxonomyIndexContext is the template context for taxonomy index pages.
 type TaxonomyIndexContext struct {
 	Site          config.SiteConfig
+	Languages     []LanguageLink
 	Taxonomy      taxonomy.Taxonomy
+	Description   string // index_description, rendered
 	Entries       []taxonomy.Entry
 	TopEntries    []taxonomy.Entry
 	LetterGroups  []taxonomy.LetterGroup
@@ -91,11 +260,13 @@ type TaxonomyIndexContext struct {
 	OG            OGMeta
 	ChartData     template.HTML
 	CTA           config.CTAConfig
+	Robots        string // as on EntityPageContext
 }
 
 // LetterPageContext is the template context for A-Z letter pages.
 type LetterPageContext struct {
 	Site          config.SiteConfig
+	Languages     []LanguageLink
 	Taxonomy      taxonomy.Taxonomy
 	Letter        string
 	Entries       []taxonomy.Entry
@@ -106,11 +277,14 @@ type LetterPageContext struct {
 	OG            OGMeta
 	ChartData     template.HTML
 	CTA           config.CTAConfig
+	JSONURL       string // the page's JSON twin; "" unless output.json_twins is set
+	Robots        string // as on EntityPageContext
 }
 
 // AllEntitiesPageContext is the template context for the all-entities listing pages.
 type AllEntitiesPageContext struct {
 	Site          config.SiteConfig
+	Languages     []LanguageLink
 	Entities      []*entity.Entity
 	Pagination    taxonomy.PaginationInfo
 	JsonLD        template.HTML
@@ -121,11 +295,153 @@ type AllEntitiesPageContext struct {
 	OG             OGMeta
 	ChartData      template.HTML
 	CTA            config.CTAConfig
+	// Letters are the first letters with a page of their own, when
+	// all_entities.letters is set; Letter is the one a letter page lists.
+	Letters        []string
+	Letter         string
+	Robots         string // as on EntityPageContext
+}
+
+// SeriesPageContext is the template context for series index pages.
+type SeriesPageContext struct {
+	Site          config.SiteConfig
+	Languages     []LanguageLink
+	Series        *series.Series
+	Entities      []*entity.Entity
+	JsonLD        template.HTML
+	Breadcrumbs   []Breadcrumb
+	AllTaxonomies []taxonomy.Taxonomy
+	OG            OGMeta
+	CTA           config.CTAConfig
+}
+
+// ADRIndexContext is the template context for the ADR index page.
+type ADRIndexContext struct {
+	Site          config.SiteConfig
+	Languages     []LanguageLink
+	Records       []*adr.Record
+	Statuses      []ADRStatusCount
+	JsonLD        template.HTML
+	Breadcrumbs   []Breadcrumb
+	AllTaxonomies []taxonomy.Taxonomy
+	OG            OGMeta
+	CTA           config.CTAConfig
+}
+
+// FreshnessContext is the template context for the stale docs report.
+type FreshnessContext struct {
+	Site          config.SiteConfig
+	Languages     []LanguageLink
+	Report        *freshness.Report
+	JSONURL       string // the report as JSON
+	JsonLD        template.HTML
+	Breadcrumbs   []Breadcrumb
+	AllTaxonomies []taxonomy.Taxonomy
+	NoIndex       bool
+	OG            OGMeta
+}
+
+// ContributorsContext is the template context for the contributors index.
+type ContributorsContext struct {
+	Site          config.SiteConfig
+	Languages     []LanguageLink
+	Profiles      []*contributors.Profile
+	JsonLD        template.HTML
+	Breadcrumbs   []Breadcrumb
+	AllTaxonomies []taxonomy.Taxonomy
+	OG            OGMeta
+}
+
+// CollectionPageContext is the template context for a curated collection.
+type CollectionPageContext struct {
+	Site          config.SiteConfig
+	Languages     []LanguageLink
+	Collection    *collection.Collection
+	JsonLD        template.HTML
+	Breadcrumbs   []Breadcrumb
+	AllTaxonomies []taxonomy.Taxonomy
+	FeedURL       string // "" unless RSS is enabled
+	OG            OGMeta
+	CTA           config.CTAConfig
+}
+
+// PreviewsContext is the template context for the share card audit page.
+type PreviewsContext struct {
+	Site          config.SiteConfig
+	Cards         []PreviewCard
+	Problems      int // cards with at least one problem
+	Breadcrumbs   []Breadcrumb
+	AllTaxonomies []taxonomy.Taxonomy
+	NoIndex       bool
+	OG            OGMeta
+}
+
+// PreviewCard is the share card of one generated page.
+type PreviewCard struct {
+	Page        string // path of the page, e.g. "/about.html"
+	Title       string
+	Description string
+	Image       string   // og:image as written
+	ImageSrc    string   // the image as this build serves it
+	Problems    []string // e.g. "no image", "title over 60 characters"
+}
+
+// ADRStatusCount is the number of records in one status.
+type ADRStatusCount struct {
+	Status string
+	Count  int
+}
+
+// C4PageContext is the template context for C4 model pages: the system
+// context page, where View.Scope is nil, and one page per element with
+// children.
+type C4PageContext struct {
+	Site          config.SiteConfig
+	Languages     []LanguageLink
+	View          *c4.View
+	Diagram       string // Mermaid source
+	JsonLD        template.HTML
+	Breadcrumbs   []Breadcrumb
+	AllTaxonomies []taxonomy.Taxonomy
+	OG            OGMeta
+	CTA           config.CTAConfig
+}
+
+// GraphPageContext is the template context for the relation graph page.
+type GraphPageContext struct {
+	Site          config.SiteConfig
+	Languages     []LanguageLink
+	DataURL       string // graph.json
+	NodeCount     int
+	EdgeCount     int
+	Relations     []config.RelationConfig
+	JsonLD        template.HTML
+	Breadcrumbs   []Breadcrumb
+	AllTaxonomies []taxonomy.Taxonomy
+	OG            OGMeta
+	CTA           config.CTAConfig
+}
+
+// SourcePageContext is the template context for source file and
+// directory pages. File is nil on directory pages, Dir on file pages.
+type SourcePageContext struct {
+	Site          config.SiteConfig
+	Languages     []LanguageLink
+	File          *source.File
+	Lines         []source.Line // nil when the file is over source.max_bytes
+	Dir           *source.Dir
+	Entities      []*entity.Entity // entities citing the file or directory
+	JsonLD        template.HTML
+	Breadcrumbs   []Breadcrumb
+	AllTaxonomies []taxonomy.Taxonomy
+	OG            OGMeta
+	CTA           config.CTAConfig
 }
 
 // StaticPageContext is the template context for static pages.
 type StaticPageContext struct {
 	Site          config.SiteConfig
+	Languages     []LanguageLink
 	Title         string
 	Content       template.HTML
 	JsonLD        template.HTML
@@ -133,6 +449,15 @@ type StaticPageContext struct {
 	AllTaxonomies []taxonomy.Taxonomy
 }
 
+// LanguageLink points to one language edition of the current page, or to
+// that edition's homepage if it has no counterpart.
+type LanguageLink struct {
+	Code    string // BCP 47 code, for hreflang
+	Name    string
+	URL     string
+	Current bool
+}
+
 // Breadcrumb is a single breadcrumb entry.
 type Breadcrumb struct {
 	Name string
@@ -147,6 +472,12 @@ type OGMeta struct {
 	ImageURL    string
 	Type        string // "website" for homepage, "article" for all others
 	SiteName    string
+	// ImageAlt describes the image; the title stands in without one.
+	ImageAlt    string
+	// Card, TwitterSite and TwitterCreator default to the og section.
+	Card           string
+	TwitterSite    string
+	TwitterCreator string
 }
 
 // NameCount is a generic name+count pair used for chart data and share images.
@@ -158,30 +489,67 @@ type NameCount struct {
 // NewEngine creates a render engine loading templates from the given directory.
 func NewEngine(cfg *config.Config) (*Engine, error) {
 	funcMap := BuildFuncMap()
+	catalog, err := i18n.Load(cfg)
+	if err != nil {
+		return nil, err
+	}
+	funcMap["T"] = catalog.T
+	for name, fn := range localeFuncs(cfg, catalog) {
+		funcMap[name] = fn
+	}
+	images := &imageIndex{}
+	funcMap["imgSrcset"] = images.imgSrcset
+	icons := imaging.SiteIcons(cfg)
+	funcMap["icons"] = func() []imaging.Icon { return icons }
+	funcMap["og"] = func(og OGMeta) OGMeta { return withOGDefaults(og, cfg) }
+	funcMap["basePath"] = func() string { return cfg.Site.BasePath }
+	// link joins its arguments into a root-relative page path and writes
+	// it as urls says, e.g. {{link "/" .Slug ".html"}}.
+	funcMap["link"] = func(parts ...string) string {
+		return output.CanonicalPath(cfg.URLs, strings.Join(parts, ""))
+	}
+	seed := &featuredSeed{seed: featured.Seed(cfg.Featured, time.Now())}
+	funcMap["featured"] = seed.pick
+
+	// Theme templates form the base layer; site templates with the same
+	// name replace them.
+	files := make(map[string][]byte)
+	t, err := theme.Open(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if t != nil {
+		if files, err = theme.Templates(t); err != nil {
+			return nil, err
+		}
+	}
 
 	tmplDir := cfg.Paths.Templates
 	entries, err := os.ReadDir(tmplDir)
-	if err != nil {
+	if err != nil && (t == nil || !os.IsNotExist(err)) {
 		return nil, fmt.Errorf("reading template dir %s: %w", tmplDir, err)
 	}
 
-	tmpl := template.New("").Funcs(funcMap)
-
 	for _, entry := range entries {
 		if entry.IsDir() {
 			continue
 		}
 		name := entry.Name()
-		ext := filepath.Ext(name)
-		if ext != ".html" && ext != ".css" && ext != ".js" {
-			continue
-		}
-
 		path := filepath.Join(tmplDir, name)
 		data, err := os.ReadFile(path)
 		if err != nil {
 			return nil, fmt.Errorf("reading template %s: %w", name, err)
 		}
+		files[name] = data
+	}
+
+	tmpl := template.New("").Funcs(funcMap)
+
+	for name, data := range files {
+		ext := filepath.Ext(name)
+		if ext != ".html" && ext != ".css" && ext != ".js" {
+			continue
+		}
 
 		_, err = tmpl.New(name).Parse(string(data))
 		if err != nil {
@@ -189,7 +557,18 @@ func NewEngine(cfg *config.Config) (*Engine, error) {
 		}
 	}
 
-	return &Engine{tmpl: tmpl, cfg: cfg}, nil
+	var prompt *texttemplate.Template
+	if name := cfg.Prompts.Template; name != "" {
+		data, ok := files[name]
+		if !ok {
+			return nil, fmt.Errorf("prompt template %s not found in %s", name, tmplDir)
+		}
+		if prompt, err = texttemplate.New(name).Funcs(texttemplate.FuncMap(funcMap)).Parse(string(data)); err != nil {
+			return nil, fmt.Errorf("parsing template %s: %w", name, err)
+		}
+	}
+
+	return &Engine{tmpl: tmpl, cfg: cfg, images: images, featured: seed, prompt: prompt}, nil
 }
 
 // RenderEntity renders an entity page.
@@ -197,6 +576,11 @@ func (e *Engine) RenderEntity(ctx EntityPageContext) (string, error) {
 	return e.render(e.cfg.Templates.Entity, ctx)
 }
 
+// RenderPrint renders the printer-friendly copy of an entity page.
+func (e *Engine) RenderPrint(ctx EntityPageContext) (string, error) {
+	return e.render(e.cfg.Print.Template, ctx)
+}
+
 // RenderHomepage renders the homepage.
 func (e *Engine) RenderHomepage(ctx HomepageContext) (string, error) {
 	return e.render(e.cfg.Templates.Homepage, ctx)
@@ -225,6 +609,51 @@ func (e *Engine) RenderAllEntities(ctx AllEntitiesPageContext) (string, error) {
 	return e.render("all_entities.html", ctx)
 }
 
+// RenderSeries renders a series index page.
+func (e *Engine) RenderSeries(ctx SeriesPageContext) (string, error) {
+	return e.render(e.cfg.Series.Template, ctx)
+}
+
+// RenderADRIndex renders the ADR index page.
+func (e *Engine) RenderADRIndex(ctx ADRIndexContext) (string, error) {
+	return e.render(e.cfg.ADR.Template, ctx)
+}
+
+// RenderFreshness renders the stale docs report.
+func (e *Engine) RenderFreshness(ctx FreshnessContext) (string, error) {
+	return e.render(e.cfg.Freshness.Template, ctx)
+}
+
+// RenderContributors renders the contributors index.
+func (e *Engine) RenderContributors(ctx ContributorsContext) (string, error) {
+	return e.render(e.cfg.Contributors.Template, ctx)
+}
+
+// RenderCollection renders a curated collection page.
+func (e *Engine) RenderCollection(ctx CollectionPageContext) (string, error) {
+	return e.render(e.cfg.Collections.Template, ctx)
+}
+
+// RenderPreviews renders the share card audit page.
+func (e *Engine) RenderPreviews(ctx PreviewsContext) (string, error) {
+	return e.render(e.cfg.Previews.Template, ctx)
+}
+
+// RenderC4 renders a C4 model page.
+func (e *Engine) RenderC4(ctx C4PageContext) (string, error) {
+	return e.render(e.cfg.C4.Template, ctx)
+}
+
+// RenderGraph renders the relation graph page.
+func (e *Engine) RenderGraph(ctx GraphPageContext) (string, error) {
+	return e.render(e.cfg.Graph.Template, ctx)
+}
+
+// RenderSource renders a source file or directory page.
+func (e *Engine) RenderSource(ctx SourcePageContext) (string, error) {
+	return e.render(e.cfg.Source.Template, ctx)
+}
+
 // RenderStatic renders a static page.
 func (e *Engine) RenderStatic(templateName string, ctx StaticPageContext) (string, error) {
 	return e.render(templateName, ctx)
@@ -244,6 +673,31 @@ func (e *Engine) render(name string, data interface{}) (string, error) {
 	return buf.String(), nil
 }
 
+// RenderPartial renders a named template fragment as trusted HTML.
+func (e *Engine) RenderPartial(name string, data interface{}) (template.HTML, error) {
+	html, err := e.render(name, data)
+	if err != nil {
+		return "", err
+	}
+	return template.HTML(html), nil
+}
+
+// AffiliateDisclosure returns the configured disclosure for a page with the
+// given links, or "" when there are none.
+func (e *Engine) AffiliateDisclosure(links []affiliate.Link) (template.HTML, error) {
+	if len(links) == 0 {
+		return "", nil
+	}
+	d := e.cfg.Affiliates.Disclosure
+	if d.Template != "" {
+		return e.RenderPartial(d.Template, map[string]interface{}{
+			"Site":  e.cfg.Site,
+			"Links": links,
+		})
+	}
+	return template.HTML(template.HTMLEscapeString(d.Text)), nil
+}
+
 // RenderCSS reads and returns the CSS template content.
 func (e *Engine) RenderCSS() (string, error) {
 	t := e.tmpl.Lookup("_styles.css")
@@ -270,6 +724,30 @@ func (e *Engine) RenderJS() (string, error) {
 	return buf.String(), nil
 }
 
+// PromptContext is the data a prompts.template is executed with.
+type PromptContext struct {
+	Site           config.SiteConfig
+	Entity         *entity.Entity
+	URL            string
+	Enrichment     map[string]interface{}
+	AffiliateLinks []affiliate.Link
+}
+
+// CookModePrompt builds an entity's assistant prompt with the configured
+// prompts.template, or with GenerateCookModePrompt when there is none.
+// Surrounding whitespace is trimmed, so a template that renders nothing
+// yields no prompt.
+func (e *Engine) CookModePrompt(ctx PromptContext) (string, error) {
+	if e.prompt == nil {
+		return GenerateCookModePrompt(ctx.Entity, ctx.Enrichment, ctx.AffiliateLinks), nil
+	}
+	var buf bytes.Buffer
+	if err := e.prompt.Execute(&buf, ctx); err != nil {
+		return "", fmt.Errorf("rendering template %s: %w", e.prompt.Name(), err)
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
+
 // GenerateCookModePrompt builds a cook-with-AI prompt for a recipe.
 func GenerateCookModePrompt(e *entity.Entity, enrichment map[string]interface{}, affiliateLinks []affiliate.Link) string {
 	if enrichment == nil {
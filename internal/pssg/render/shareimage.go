@@ -3,6 +3,10 @@ package render
 import (
 	"fmt"
 	"strings"
+	"unicode/utf8"
+
+	"github.com/supermodeltools/arch-docs/internal/pssg/i18n"
+	"github.com/supermodeltools/arch-docs/internal/pssg/text"
 )
 
 // Share image constants
@@ -25,20 +29,46 @@ func svgEscape(s string) string {
 	return s
 }
 
-// truncate limits string length with ellipsis.
-func truncate(s string, max int) string {
-	if len(s) <= max {
-		return s
+// ShareImages renders the SVG share images for one site language. For
+// right-to-left languages the layout is mirrored: text starts at the right
+// edge and bars grow leftwards.
+type ShareImages struct {
+	RTL bool
+}
+
+// NewShareImages returns the share image renderer for a site language.
+func NewShareImages(lang string) ShareImages {
+	return ShareImages{RTL: i18n.IsRTL(lang)}
+}
+
+// x mirrors a text anchor or point across the image for RTL layouts.
+func (s ShareImages) x(x int) int {
+	if s.RTL {
+		return svgWidth - x
 	}
-	return s[:max-1] + "\u2026"
+	return x
+}
+
+// rectX returns the left edge of a rect of width w that starts at x in
+// reading order.
+func (s ShareImages) rectX(x, w int) int {
+	if s.RTL {
+		return svgWidth - x - w
+	}
+	return x
 }
 
 // svgScaffold wraps content in the standard share image scaffold.
-func svgScaffold(siteName, pageTitle, content string) string {
-	return fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">
+func (s ShareImages) svgScaffold(siteName, pageTitle, content string) string {
+	dir := ""
+	if s.RTL {
+		// With direction="rtl" text-anchor start is the right end of the text
+		dir = ` direction="rtl"`
+	}
+	return fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d"%s>
   <rect width="%d" height="%d" fill="%s"/>
-  <text x="60" y="56" font-family="system-ui,sans-serif" font-size="18" font-weight="600" fill="%s">%s</text>
-  <text x="60" y="110" font-family="system-ui,sans-serif" font-size="36" font-weight="700" fill="%s">%s</text>
+  <text x="%d" y="56" font-family="system-ui,sans-serif" font-size="18" font-weight="600" fill="%s">%s</text>
+  <text x="%d" y="110" font-family="system-ui,sans-serif" font-size="36" font-weight="700" fill="%s">%s</text>
   %s
   <rect x="0" y="%d" width="%d" height="8" fill="url(#accent-grad)"/>
   <defs>
@@ -48,10 +78,10 @@ func svgScaffold(siteName, pageTitle, content string) string {
     </linearGradient>
   </defs>
 </svg>`,
-		svgWidth, svgHeight, svgWidth, svgHeight,
+		svgWidth, svgHeight, svgWidth, svgHeight, dir,
 		svgWidth, svgHeight, svgBG,
-		svgMuted, svgEscape(siteName),
-		svgText, svgEscape(truncate(pageTitle, 60)),
+		s.x(60), svgMuted, svgEscape(siteName),
+		s.x(60), svgText, svgEscape(text.Truncate(pageTitle, 60)),
 		content,
 		svgHeight-8, svgWidth,
 		svgAccent, svgAccent2,
@@ -59,7 +89,7 @@ func svgScaffold(siteName, pageTitle, content string) string {
 }
 
 // renderBarsSVG renders horizontal bars as SVG elements.
-func renderBarsSVG(bars []NameCount, x, y, maxW, barH, gap int) string {
+func (s ShareImages) renderBarsSVG(bars []NameCount, x, y, maxW, barH, gap int) string {
 	if len(bars) == 0 {
 		return ""
 	}
@@ -82,22 +112,22 @@ func renderBarsSVG(bars []NameCount, x, y, maxW, barH, gap int) string {
 		}
 		cy := y + i*(barH+gap)
 		color := colors[i%len(colors)]
-		sb.WriteString(fmt.Sprintf(`  <rect x="%d" y="%d" width="%d" height="%d" rx="4" fill="%s" opacity="0.85"/>`, x, cy, w, barH, color))
+		sb.WriteString(fmt.Sprintf(`  <rect x="%d" y="%d" width="%d" height="%d" rx="4" fill="%s" opacity="0.85"/>`, s.rectX(x, w), cy, w, barH, color))
 		sb.WriteString("\n")
-		sb.WriteString(fmt.Sprintf(`  <text x="%d" y="%d" font-family="system-ui,sans-serif" font-size="14" fill="%s">%s</text>`, x, cy-4, svgText, svgEscape(truncate(b.Name, 30))))
+		sb.WriteString(fmt.Sprintf(`  <text x="%d" y="%d" font-family="system-ui,sans-serif" font-size="14" fill="%s">%s</text>`, s.x(x), cy-4, svgText, svgEscape(text.Truncate(b.Name, 30))))
 		sb.WriteString("\n")
-		sb.WriteString(fmt.Sprintf(`  <text x="%d" y="%d" font-family="system-ui,sans-serif" font-size="13" fill="%s">%d</text>`, x+w+8, cy+barH-4, svgMuted, b.Count))
+		sb.WriteString(fmt.Sprintf(`  <text x="%d" y="%d" font-family="system-ui,sans-serif" font-size="13" fill="%s">%d</text>`, s.x(x+w+8), cy+barH-4, svgMuted, b.Count))
 		sb.WriteString("\n")
 	}
 	return sb.String()
 }
 
-// GenerateHomepageShareSVG generates the homepage share image SVG.
-func GenerateHomepageShareSVG(siteName, description string, taxStats []NameCount, totalEntities int) string {
+// Homepage generates the homepage share image SVG.
+func (s ShareImages) Homepage(siteName, description string, taxStats []NameCount, totalEntities int) string {
 	var content strings.Builder
-	content.WriteString(fmt.Sprintf(`  <text x="60" y="160" font-family="system-ui,sans-serif" font-size="18" fill="%s">%s</text>`, svgMuted, svgEscape(truncate(description, 80))))
+	content.WriteString(fmt.Sprintf(`  <text x="%d" y="160" font-family="system-ui,sans-serif" font-size="18" fill="%s">%s</text>`, s.x(60), svgMuted, svgEscape(text.Truncate(description, 80))))
 	content.WriteString("\n")
-	content.WriteString(fmt.Sprintf(`  <text x="60" y="200" font-family="system-ui,sans-serif" font-size="22" font-weight="600" fill="%s">%d total recipes</text>`, svgAccent, totalEntities))
+	content.WriteString(fmt.Sprintf(`  <text x="%d" y="200" font-family="system-ui,sans-serif" font-size="22" font-weight="600" fill="%s">%d total recipes</text>`, s.x(60), svgAccent, totalEntities))
 	content.WriteString("\n")
 
 	// Show taxonomy bars (max 8)
@@ -106,46 +136,92 @@ func GenerateHomepageShareSVG(siteName, description string, taxStats []NameCount
 		limit = 8
 	}
 	bars := taxStats[:limit]
-	content.WriteString(renderBarsSVG(bars, 60, 250, 900, 28, 14))
+	content.WriteString(s.renderBarsSVG(bars, 60, 250, 900, 28, 14))
 
-	return svgScaffold(siteName, siteName+" \u2014 Recipe Collection", content.String())
+	return s.svgScaffold(siteName, siteName+" \u2014 Recipe Collection", content.String())
 }
 
-// GenerateEntityShareSVG generates the entity share image SVG.
-func GenerateEntityShareSVG(siteName, title, category, cuisine, skillLevel string) string {
+// Entity generates the entity share image SVG.
+func (s ShareImages) Entity(siteName, title, category, cuisine, skillLevel string) string {
 	var content strings.Builder
 
 	// Pills for metadata
-	pillX := 60
-	pillY := 170
-	pills := []struct{ label, color string }{
-		{category, svgAccent},
-		{cuisine, svgAccent2},
-		{skillLevel, "#4A7B9B"},
+	content.WriteString(s.pillsSVG(170, 0.2, "", category, cuisine, skillLevel))
+
+	// Large decorative title
+	content.WriteString(fmt.Sprintf(`  <text x="600" y="380" text-anchor="middle" font-family="Georgia,serif" font-size="48" font-weight="700" fill="%s" opacity="0.15">%s</text>`, svgText, svgEscape(text.Truncate(title, 40))))
+	content.WriteString("\n")
+
+	return s.svgScaffold(siteName, text.Truncate(title, 55), content.String())
+}
+
+// EntityPhoto generates the entity share image SVG over the entity's own
+// photo, given as a data: URI. A gradient darkens the lower half so the
+// title and pills stay legible on any picture.
+func (s ShareImages) EntityPhoto(siteName, title, photo, category, cuisine, skillLevel string) string {
+	dir := ""
+	if s.RTL {
+		dir = ` direction="rtl"`
 	}
-	for _, p := range pills {
-		if p.label == "" {
+	return fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d"%s>
+  <image href="%s" width="%d" height="%d" preserveAspectRatio="xMidYMid slice"/>
+  <rect width="%d" height="%d" fill="url(#shade)"/>
+  <text x="%d" y="56" font-family="system-ui,sans-serif" font-size="18" font-weight="600" fill="%s">%s</text>
+%s  <text x="%d" y="%d" font-family="system-ui,sans-serif" font-size="48" font-weight="700" fill="#ffffff">%s</text>
+  <rect x="0" y="%d" width="%d" height="8" fill="url(#accent-grad)"/>
+  <defs>
+    <linearGradient id="shade" x1="0" y1="0" x2="0" y2="1">
+      <stop offset="0" stop-color="#000000" stop-opacity="0.35"/>
+      <stop offset="0.45" stop-color="#000000" stop-opacity="0.1"/>
+      <stop offset="1" stop-color="#000000" stop-opacity="0.8"/>
+    </linearGradient>
+    <linearGradient id="accent-grad" x1="0" y1="0" x2="1" y2="0">
+      <stop offset="0" stop-color="%s"/>
+      <stop offset="1" stop-color="%s"/>
+    </linearGradient>
+  </defs>
+</svg>`,
+		svgWidth, svgHeight, svgWidth, svgHeight, dir,
+		photo, svgWidth, svgHeight,
+		svgWidth, svgHeight,
+		s.x(60), svgText, svgEscape(siteName),
+		s.pillsSVG(470, 0.9, "#ffffff", category, cuisine, skillLevel),
+		s.x(60), svgHeight-60, svgEscape(text.Truncate(title, 40)),
+		svgHeight-8, svgWidth,
+		svgAccent, svgAccent2,
+	)
+}
+
+// pillsSVG renders the non-empty labels as a row of rounded pills at y.
+// Each pill is tinted with its own color; text takes textColor, or the
+// pill color when textColor is empty.
+func (s ShareImages) pillsSVG(y int, opacity float64, textColor string, labels ...string) string {
+	var out strings.Builder
+	colors := []string{svgAccent, svgAccent2, "#4A7B9B"}
+	pillX := 60
+	for i, label := range labels {
+		if label == "" {
 			continue
 		}
-		w := len(p.label)*10 + 24
-		content.WriteString(fmt.Sprintf(`  <rect x="%d" y="%d" width="%d" height="32" rx="16" fill="%s" opacity="0.2"/>`, pillX, pillY, w, p.color))
-		content.WriteString("\n")
-		content.WriteString(fmt.Sprintf(`  <text x="%d" y="%d" font-family="system-ui,sans-serif" font-size="14" font-weight="600" fill="%s">%s</text>`, pillX+12, pillY+21, p.color, svgEscape(p.label)))
-		content.WriteString("\n")
+		color := colors[i%len(colors)]
+		fg := textColor
+		if fg == "" {
+			fg = color
+		}
+		w := utf8.RuneCountInString(label)*10 + 24
+		out.WriteString(fmt.Sprintf(`  <rect x="%d" y="%d" width="%d" height="32" rx="16" fill="%s" opacity="%g"/>`, s.rectX(pillX, w), y, w, color, opacity))
+		out.WriteString("\n")
+		out.WriteString(fmt.Sprintf(`  <text x="%d" y="%d" font-family="system-ui,sans-serif" font-size="14" font-weight="600" fill="%s">%s</text>`, s.x(pillX+12), y+21, fg, svgEscape(label)))
+		out.WriteString("\n")
 		pillX += w + 12
 	}
-
-	// Large decorative title
-	content.WriteString(fmt.Sprintf(`  <text x="600" y="380" text-anchor="middle" font-family="Georgia,serif" font-size="48" font-weight="700" fill="%s" opacity="0.15">%s</text>`, svgText, svgEscape(truncate(title, 40))))
-	content.WriteString("\n")
-
-	return svgScaffold(siteName, truncate(title, 55), content.String())
+	return out.String()
 }
 
-// GenerateHubShareSVG generates the hub page share image SVG.
-func GenerateHubShareSVG(siteName, entryName, taxLabel string, count int, topTypes []NameCount) string {
+// Hub generates the hub page share image SVG.
+func (s ShareImages) Hub(siteName, entryName, taxLabel string, count int, topTypes []NameCount) string {
 	var content strings.Builder
-	content.WriteString(fmt.Sprintf(`  <text x="60" y="160" font-family="system-ui,sans-serif" font-size="18" fill="%s">%s · %d recipes</text>`, svgMuted, svgEscape(taxLabel), count))
+	content.WriteString(fmt.Sprintf(`  <text x="%d" y="160" font-family="system-ui,sans-serif" font-size="18" fill="%s">%s · %d recipes</text>`, s.x(60), svgMuted, svgEscape(taxLabel), count))
 	content.WriteString("\n")
 
 	limit := len(topTypes)
@@ -153,15 +229,15 @@ func GenerateHubShareSVG(siteName, entryName, taxLabel string, count int, topTyp
 		limit = 6
 	}
 	bars := topTypes[:limit]
-	content.WriteString(renderBarsSVG(bars, 60, 220, 900, 32, 16))
+	content.WriteString(s.renderBarsSVG(bars, 60, 220, 900, 32, 16))
 
-	return svgScaffold(siteName, entryName, content.String())
+	return s.svgScaffold(siteName, entryName, content.String())
 }
 
-// GenerateTaxIndexShareSVG generates the taxonomy index share image SVG.
-func GenerateTaxIndexShareSVG(siteName, taxLabel string, topEntries []NameCount) string {
+// TaxIndex generates the taxonomy index share image SVG.
+func (s ShareImages) TaxIndex(siteName, taxLabel string, topEntries []NameCount) string {
 	var content strings.Builder
-	content.WriteString(fmt.Sprintf(`  <text x="60" y="160" font-family="system-ui,sans-serif" font-size="18" fill="%s">Browse all %s</text>`, svgMuted, svgEscape(taxLabel)))
+	content.WriteString(fmt.Sprintf(`  <text x="%d" y="160" font-family="system-ui,sans-serif" font-size="18" fill="%s">Browse all %s</text>`, s.x(60), svgMuted, svgEscape(taxLabel)))
 	content.WriteString("\n")
 
 	limit := len(topEntries)
@@ -169,15 +245,15 @@ func GenerateTaxIndexShareSVG(siteName, taxLabel string, topEntries []NameCount)
 		limit = 10
 	}
 	bars := topEntries[:limit]
-	content.WriteString(renderBarsSVG(bars, 60, 210, 900, 26, 12))
+	content.WriteString(s.renderBarsSVG(bars, 60, 210, 900, 26, 12))
 
-	return svgScaffold(siteName, taxLabel, content.String())
+	return s.svgScaffold(siteName, taxLabel, content.String())
 }
 
-// GenerateAllEntitiesShareSVG generates the all-entities share image SVG.
-func GenerateAllEntitiesShareSVG(siteName string, totalCount int, typeDist []NameCount) string {
+// AllEntities generates the all-entities share image SVG.
+func (s ShareImages) AllEntities(siteName string, totalCount int, typeDist []NameCount) string {
 	var content strings.Builder
-	content.WriteString(fmt.Sprintf(`  <text x="60" y="160" font-family="system-ui,sans-serif" font-size="22" font-weight="600" fill="%s">%d recipes</text>`, svgAccent, totalCount))
+	content.WriteString(fmt.Sprintf(`  <text x="%d" y="160" font-family="system-ui,sans-serif" font-size="22" font-weight="600" fill="%s">%d recipes</text>`, s.x(60), svgAccent, totalCount))
 	content.WriteString("\n")
 
 	// Proportional bar segments
@@ -205,7 +281,7 @@ func GenerateAllEntitiesShareSVG(siteName string, totalCount int, typeDist []Nam
 				w = 2
 			}
 			color := colors[i%len(colors)]
-			content.WriteString(fmt.Sprintf(`  <rect x="%d" y="%d" width="%d" height="%d" fill="%s"/>`, cx, barY, w, barH, color))
+			content.WriteString(fmt.Sprintf(`  <rect x="%d" y="%d" width="%d" height="%d" fill="%s"/>`, s.rectX(cx, w), barY, w, barH, color))
 			content.WriteString("\n")
 			cx += w
 		}
@@ -218,19 +294,19 @@ func GenerateAllEntitiesShareSVG(siteName string, totalCount int, typeDist []Nam
 				ly += 30
 			}
 			color := colors[i%len(colors)]
-			content.WriteString(fmt.Sprintf(`  <rect x="%d" y="%d" width="12" height="12" rx="2" fill="%s"/>`, lx, ly, color))
-			content.WriteString(fmt.Sprintf(`  <text x="%d" y="%d" font-family="system-ui,sans-serif" font-size="13" fill="%s">%s (%d)</text>`, lx+18, ly+11, svgMuted, svgEscape(truncate(typeDist[i].Name, 25)), typeDist[i].Count))
+			content.WriteString(fmt.Sprintf(`  <rect x="%d" y="%d" width="12" height="12" rx="2" fill="%s"/>`, s.rectX(lx, 12), ly, color))
+			content.WriteString(fmt.Sprintf(`  <text x="%d" y="%d" font-family="system-ui,sans-serif" font-size="13" fill="%s">%s (%d)</text>`, s.x(lx+18), ly+11, svgMuted, svgEscape(text.Truncate(typeDist[i].Name, 25)), typeDist[i].Count))
 			content.WriteString("\n")
 		}
 	}
 
-	return svgScaffold(siteName, "All Recipes", content.String())
+	return s.svgScaffold(siteName, "All Recipes", content.String())
 }
 
-// GenerateLetterShareSVG generates the letter page share image SVG.
-func GenerateLetterShareSVG(siteName, taxLabel, letter string, entryCount int) string {
+// Letter generates the letter page share image SVG.
+func (s ShareImages) Letter(siteName, taxLabel, letter string, entryCount int) string {
 	var content strings.Builder
-	content.WriteString(fmt.Sprintf(`  <text x="60" y="160" font-family="system-ui,sans-serif" font-size="18" fill="%s">%s · %d entries</text>`, svgMuted, svgEscape(taxLabel), entryCount))
+	content.WriteString(fmt.Sprintf(`  <text x="%d" y="160" font-family="system-ui,sans-serif" font-size="18" fill="%s">%s · %d entries</text>`, s.x(60), svgMuted, svgEscape(taxLabel), entryCount))
 	content.WriteString("\n")
 
 	// Large decorative letter
@@ -239,5 +315,5 @@ func GenerateLetterShareSVG(siteName, taxLabel, letter string, entryCount int) s
 	content.WriteString(fmt.Sprintf(`  <text x="600" y="440" text-anchor="middle" font-family="Georgia,serif" font-size="120" font-weight="700" fill="%s" opacity="0.25">%s</text>`, svgAccent, svgEscape(letter)))
 	content.WriteString("\n")
 
-	return svgScaffold(siteName, fmt.Sprintf("%s \u2014 %s", taxLabel, letter), content.String())
+	return s.svgScaffold(siteName, fmt.Sprintf("%s \u2014 %s", taxLabel, letter), content.String())
 }
@@ -0,0 +1,39 @@
+package render
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	svgBetweenTags = regexp.MustCompile(`>\s+<`)
+	svgDefs        = regexp.MustCompile(`(?s)<defs>(.*?)</defs>`)
+	// svgDef matches one top-level definition: a self-closing element or an
+	// element with its closing tag.
+	svgDef = regexp.MustCompile(`(?s)<(\w+)\s[^>]*?id="([^"]+)"[^>]*?(?:/>|>.*?</(\w+)>)`)
+)
+
+// MinifySVG shrinks a generated SVG: whitespace between tags goes, as do
+// definitions nothing refers to and the <defs> block if that leaves it
+// empty. Text content is left alone.
+func MinifySVG(svg string) string {
+	svg = svgBetweenTags.ReplaceAllString(strings.TrimSpace(svg), "><")
+	return svgDefs.ReplaceAllStringFunc(svg, func(block string) string {
+		rest := strings.Replace(svg, block, "", 1)
+		inner := svgDefs.FindStringSubmatch(block)[1]
+		kept := svgDef.ReplaceAllStringFunc(inner, func(def string) string {
+			m := svgDef.FindStringSubmatch(def)
+			if m[3] != "" && m[3] != m[1] {
+				return def // nested elements we cannot match safely
+			}
+			if strings.Contains(rest, "#"+m[2]+")") || strings.Contains(rest, `"#`+m[2]+`"`) {
+				return def
+			}
+			return ""
+		})
+		if strings.TrimSpace(kept) == "" {
+			return ""
+		}
+		return "<defs>" + kept + "</defs>"
+	})
+}
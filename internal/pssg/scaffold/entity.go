@@ -0,0 +1,222 @@
+package scaffold
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/supermodeltools/arch-docs/internal/pssg/config"
+	"github.com/supermodeltools/arch-docs/internal/pssg/entity"
+)
+
+// EntityOptions describe a new entity file.
+type EntityOptions struct {
+	Slug  string
+	Title string // defaults to the slug in title case
+	Type  string // node type; selects the archetype and type-specific fields
+	Force bool
+}
+
+// archetypeData is the context archetype templates are executed with.
+type archetypeData struct {
+	Slug  string
+	Title string
+	Type  string
+	Date  string
+}
+
+// NewEntity writes <paths.data>/<slug>.md and returns its path.
+//
+// If the archetypes directory has <type>.md (or default.md) it is executed as
+// a text/template with .Slug, .Title, .Type and .Date. Otherwise the
+// frontmatter is generated from data.fields plus any fields the validation
+// profiles require, and the body gets a heading per configured body section.
+func NewEntity(cfg *config.Config, opts EntityOptions) (string, error) {
+	if opts.Slug == "" || entity.ToSlug(opts.Slug) != opts.Slug {
+		return "", fmt.Errorf("invalid slug %q (try %q)", opts.Slug, entity.ToSlug(opts.Slug))
+	}
+	if opts.Title == "" {
+		opts.Title = titleFromSlug(opts.Slug)
+	}
+
+	path := filepath.Join(cfg.Paths.Data, opts.Slug+".md")
+	if _, err := os.Stat(path); err == nil && !opts.Force {
+		return "", fmt.Errorf("%s already exists (use --force to overwrite)", path)
+	}
+
+	data := archetypeData{
+		Slug:  opts.Slug,
+		Title: opts.Title,
+		Type:  opts.Type,
+		Date:  time.Now().Format("2006-01-02"),
+	}
+	content, err := fromArchetype(cfg.Paths.Archetypes, data)
+	if err != nil {
+		return "", err
+	}
+	if content == nil {
+		if content, err = fromFields(cfg, data); err != nil {
+			return "", err
+		}
+	}
+
+	if err := os.MkdirAll(cfg.Paths.Data, 0755); err != nil {
+		return "", fmt.Errorf("creating %s: %w", cfg.Paths.Data, err)
+	}
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		return "", fmt.Errorf("writing %s: %w", path, err)
+	}
+	return path, nil
+}
+
+// fromArchetype renders the archetype for data.Type, falling back to
+// default.md. It returns nil if neither exists.
+func fromArchetype(dir string, data archetypeData) ([]byte, error) {
+	var names []string
+	if data.Type != "" {
+		names = append(names, data.Type+".md")
+	}
+	names = append(names, "default.md")
+
+	for _, name := range names {
+		src, err := os.ReadFile(filepath.Join(dir, name))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		t, err := template.New(name).Parse(string(src))
+		if err != nil {
+			return nil, fmt.Errorf("parsing archetype %s: %w", name, err)
+		}
+		var buf bytes.Buffer
+		if err := t.Execute(&buf, data); err != nil {
+			return nil, fmt.Errorf("executing archetype %s: %w", name, err)
+		}
+		return buf.Bytes(), nil
+	}
+	return nil, nil
+}
+
+// fromFields builds frontmatter from the declared fields. Fields with enum
+// rules get their allowed values as a trailing comment.
+func fromFields(cfg *config.Config, data archetypeData) ([]byte, error) {
+	fm := &yaml.Node{Kind: yaml.MappingNode}
+	seen := make(map[string]bool)
+	add := func(name string, value *yaml.Node) {
+		if seen[name] {
+			return
+		}
+		seen[name] = true
+		fm.Content = append(fm.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: name}, value)
+	}
+
+	add("title", scalar(data.Title))
+	add("description", scalar(""))
+	typeField := cfg.Data.Validation.TypeField
+	if data.Type != "" {
+		add(typeField, scalar(data.Type))
+	}
+
+	for _, f := range cfg.Data.Fields {
+		if len(f.Types) > 0 && !containsFold(f.Types, data.Type) {
+			continue
+		}
+		v, err := fieldValue(f, data.Date)
+		if err != nil {
+			return nil, fmt.Errorf("data.fields %s: %w", f.Name, err)
+		}
+		add(f.Name, v)
+	}
+
+	profiles := []config.ValidationProfile{cfg.Data.Validation.Profiles["*"]}
+	for name, p := range cfg.Data.Validation.Profiles {
+		if data.Type != "" && name != "*" && strings.EqualFold(name, data.Type) {
+			profiles = append(profiles, p)
+		}
+	}
+	for _, p := range profiles {
+		for _, name := range p.Required {
+			add(name, scalar(""))
+		}
+	}
+	for _, p := range profiles {
+		for i := 0; i+1 < len(fm.Content); i += 2 {
+			if allowed, ok := p.Enum[fm.Content[i].Value]; ok {
+				fm.Content[i+1].LineComment = "one of: " + strings.Join(allowed, ", ")
+			}
+		}
+	}
+
+	out, err := yaml.Marshal(fm)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("---\n")
+	buf.Write(out)
+	buf.WriteString("---\n")
+	for _, s := range cfg.Data.BodySections {
+		header := s.Header
+		if header == "" {
+			header = s.Name
+		}
+		fmt.Fprintf(&buf, "\n## %s\n\n", header)
+	}
+	return buf.Bytes(), nil
+}
+
+// fieldValue returns the YAML node for a declared field's default.
+func fieldValue(f config.FieldConfig, today string) (*yaml.Node, error) {
+	if f.Default != nil {
+		var n yaml.Node
+		if err := n.Encode(f.Default); err != nil {
+			return nil, err
+		}
+		return &n, nil
+	}
+	switch f.Type {
+	case "list":
+		return &yaml.Node{Kind: yaml.SequenceNode, Style: yaml.FlowStyle}, nil
+	case "int":
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: "0"}, nil
+	case "float":
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!float", Value: "0.0"}, nil
+	case "bool":
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: "false"}, nil
+	case "date":
+		return scalar(today), nil
+	}
+	return scalar(""), nil
+}
+
+func scalar(v string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: v, Style: yaml.DoubleQuotedStyle}
+}
+
+func titleFromSlug(slug string) string {
+	words := strings.Split(slug, "-")
+	for i, w := range words {
+		if w != "" {
+			words[i] = strings.ToUpper(w[:1]) + w[1:]
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+func containsFold(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,116 @@
+package scaffold
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// The `all:` prefix is needed so partials such as _head.html are embedded.
+//
+//go:embed all:starter
+var starter embed.FS
+
+// Options configure a new site.
+type Options struct {
+	Name    string
+	BaseURL string
+	// Force overwrites files that already exist instead of failing.
+	Force bool
+}
+
+// Files returns the embedded starter site, rooted at the site directory.
+func Files() fs.FS {
+	sub, err := fs.Sub(starter, "starter")
+	if err != nil {
+		panic(err) // the directory is embedded at compile time
+	}
+	return sub
+}
+
+// Init writes the starter site into dir: a pssg.yaml, a minimal template set,
+// its i18n message files, an example entity and a static directory. Files ending in .tmpl are
+// executed with opts and written without the suffix. It returns the paths
+// written, relative to dir.
+//
+// Existing files are never overwritten unless opts.Force is set; the check
+// runs before anything is written so a refused init leaves dir untouched.
+func Init(dir string, opts Options) ([]string, error) {
+	if opts.Name == "" {
+		opts.Name = "My Site"
+	}
+	if opts.BaseURL == "" {
+		opts.BaseURL = "http://localhost:8080"
+	}
+	opts.BaseURL = strings.TrimSuffix(opts.BaseURL, "/")
+
+	src := Files()
+	var names []string
+	err := fs.WalkDir(src, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		names = append(names, path)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if !opts.Force {
+		var existing []string
+		for _, name := range names {
+			if _, err := os.Stat(filepath.Join(dir, outputName(name))); err == nil {
+				existing = append(existing, outputName(name))
+			}
+		}
+		if len(existing) > 0 {
+			return nil, fmt.Errorf("%s already contains %s (use --force to overwrite)", dir, strings.Join(existing, ", "))
+		}
+	}
+
+	written := make([]string, 0, len(names))
+	for _, name := range names {
+		data, err := fs.ReadFile(src, name)
+		if err != nil {
+			return written, err
+		}
+		if strings.HasSuffix(name, ".tmpl") {
+			if data, err = execute(name, data, opts); err != nil {
+				return written, err
+			}
+		}
+
+		out := outputName(name)
+		dst := filepath.Join(dir, out)
+		if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+			return written, fmt.Errorf("creating %s: %w", filepath.Dir(dst), err)
+		}
+		if err := os.WriteFile(dst, data, 0644); err != nil {
+			return written, fmt.Errorf("writing %s: %w", dst, err)
+		}
+		written = append(written, out)
+	}
+	return written, nil
+}
+
+func outputName(name string) string {
+	return filepath.FromSlash(strings.TrimSuffix(name, ".tmpl"))
+}
+
+func execute(name string, data []byte, opts Options) ([]byte, error) {
+	t, err := template.New(name).Parse(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", name, err)
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, opts); err != nil {
+		return nil, fmt.Errorf("executing %s: %w", name, err)
+	}
+	return buf.Bytes(), nil
+}
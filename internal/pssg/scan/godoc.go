@@ -0,0 +1,273 @@
+package scan
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/doc"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Doc is a package's documentation as go doc presents it: the package
+// comment, the exported API with each declaration's comment, and the
+// examples from its tests. It is stored in the enrichment cache, so the
+// field names are those templates read.
+type Doc struct {
+	ImportPath string    `json:"import_path"`
+	Name       string    `json:"name"`
+	Synopsis   string    `json:"synopsis,omitempty"`
+	Doc        string    `json:"doc,omitempty"`
+	Consts     []Value   `json:"consts,omitempty"`
+	Vars       []Value   `json:"vars,omitempty"`
+	Funcs      []Func    `json:"funcs,omitempty"`
+	Types      []Type    `json:"types,omitempty"`
+	Examples   []Example `json:"examples,omitempty"`
+}
+
+// Value is a const or var declaration, possibly a group.
+type Value struct {
+	Names []string `json:"names"`
+	Doc   string   `json:"doc,omitempty"`
+	Decl  string   `json:"decl"`
+}
+
+// Func is a function or method; Decl is its signature.
+type Func struct {
+	Name string `json:"name"`
+	Doc  string `json:"doc,omitempty"`
+	Decl string `json:"decl"`
+}
+
+// Type is a type declaration with the constants, variables, constructors
+// and methods go doc groups under it.
+type Type struct {
+	Name    string  `json:"name"`
+	Doc     string  `json:"doc,omitempty"`
+	Decl    string  `json:"decl"`
+	Consts  []Value `json:"consts,omitempty"`
+	Vars    []Value `json:"vars,omitempty"`
+	Funcs   []Func  `json:"funcs,omitempty"`
+	Methods []Func  `json:"methods,omitempty"`
+}
+
+// Example is a testable example; Name is what it documents, such as
+// "Client.Do", or "" for the package.
+type Example struct {
+	Name   string `json:"name"`
+	Suffix string `json:"suffix,omitempty"`
+	Doc    string `json:"doc,omitempty"`
+	Code   string `json:"code"`
+	Output string `json:"output,omitempty"`
+}
+
+// Docs extracts the documentation of every package in the Go module at
+// root, skipping the directories Scan skips. Packages without exported
+// API or a package comment are left out.
+func Docs(root string) ([]*Doc, error) {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+	module := modulePath(root)
+	if module == "" {
+		return nil, fmt.Errorf("%s has no go.mod; only Go modules can be documented", root)
+	}
+	var docs []*Doc
+	err = walkPackages(root, func(dir, rel string) error {
+		importPath := module
+		if rel != "." {
+			importPath = module + "/" + rel
+		}
+		d, err := docDir(dir, importPath)
+		if err != nil {
+			return err
+		}
+		if d != nil {
+			docs = append(docs, d)
+		}
+		return nil
+	})
+	return docs, err
+}
+
+// docDir documents the package in dir, returning nil if it has no Go
+// files or nothing to document.
+func docDir(dir, importPath string) (*Doc, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	fset := token.NewFileSet()
+	var files, tests []*ast.File
+	name := ""
+	for _, e := range entries {
+		file := e.Name()
+		if e.IsDir() || !strings.HasSuffix(file, ".go") {
+			continue
+		}
+		f, err := parser.ParseFile(fset, filepath.Join(dir, file), nil, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		if ignored(f) {
+			continue
+		}
+		if strings.HasSuffix(file, "_test.go") {
+			tests = append(tests, f)
+			continue
+		}
+		// Like scanDir, prefer the library package when a directory also
+		// holds a stray main.
+		if name == "" || name == "main" && f.Name.Name != "main" {
+			name = f.Name.Name
+		}
+		files = append(files, f)
+	}
+	var pkgFiles []*ast.File
+	for _, f := range files {
+		if f.Name.Name == name {
+			pkgFiles = append(pkgFiles, f)
+		}
+	}
+	if len(pkgFiles) == 0 {
+		return nil, nil
+	}
+	for _, f := range tests {
+		if f.Name.Name == name || f.Name.Name == name+"_test" {
+			pkgFiles = append(pkgFiles, f)
+		}
+	}
+
+	p, err := doc.NewFromFiles(fset, pkgFiles, importPath)
+	if err != nil {
+		return nil, err
+	}
+	d := &Doc{
+		ImportPath: importPath,
+		Name:       p.Name,
+		Doc:        strings.TrimSpace(p.Doc),
+		Synopsis:   p.Synopsis(p.Doc),
+		Consts:     values(fset, p.Consts),
+		Vars:       values(fset, p.Vars),
+		Funcs:      funcs(fset, p.Funcs),
+		Examples:   examples(fset, p.Examples, ""),
+	}
+	for _, t := range p.Types {
+		d.Types = append(d.Types, Type{
+			Name:    t.Name,
+			Doc:     strings.TrimSpace(t.Doc),
+			Decl:    node(fset, withoutDoc(t.Decl)),
+			Consts:  values(fset, t.Consts),
+			Vars:    values(fset, t.Vars),
+			Funcs:   funcs(fset, t.Funcs),
+			Methods: funcs(fset, t.Methods),
+		})
+		d.Examples = append(d.Examples, examples(fset, t.Examples, t.Name)...)
+		for _, m := range t.Methods {
+			d.Examples = append(d.Examples, examples(fset, m.Examples, t.Name+"."+m.Name)...)
+		}
+	}
+	for _, f := range p.Funcs {
+		d.Examples = append(d.Examples, examples(fset, f.Examples, f.Name)...)
+	}
+	if d.Doc == "" && len(d.Consts)+len(d.Vars)+len(d.Funcs)+len(d.Types) == 0 {
+		return nil, nil
+	}
+	return d, nil
+}
+
+// ignored reports whether a file is excluded from every build by a
+// "//go:build ignore" line, as generators usually are.
+func ignored(f *ast.File) bool {
+	for _, cg := range f.Comments {
+		if cg.Pos() > f.Package {
+			break
+		}
+		for _, c := range cg.List {
+			if c.Text == "//go:build ignore" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func values(fset *token.FileSet, list []*doc.Value) []Value {
+	var out []Value
+	for _, v := range list {
+		out = append(out, Value{Names: v.Names, Doc: strings.TrimSpace(v.Doc), Decl: node(fset, withoutDoc(v.Decl))})
+	}
+	return out
+}
+
+func funcs(fset *token.FileSet, list []*doc.Func) []Func {
+	var out []Func
+	for _, f := range list {
+		name := f.Name
+		if f.Recv != "" {
+			name = strings.TrimPrefix(f.Recv, "*") + "." + f.Name
+		}
+		sig := *f.Decl
+		sig.Doc, sig.Body = nil, nil
+		out = append(out, Func{Name: name, Doc: strings.TrimSpace(f.Doc), Decl: node(fset, &sig)})
+	}
+	return out
+}
+
+func examples(fset *token.FileSet, list []*doc.Example, name string) []Example {
+	var out []Example
+	for _, ex := range list {
+		code := node(fset, ex.Code)
+		if _, ok := ex.Code.(*ast.BlockStmt); ok {
+			code = unblock(code)
+		}
+		out = append(out, Example{
+			Name:   name,
+			Suffix: ex.Suffix,
+			Doc:    strings.TrimSpace(ex.Doc),
+			Code:   code,
+			Output: strings.TrimSpace(ex.Output),
+		})
+	}
+	return out
+}
+
+// withoutDoc copies a declaration without its doc comment, which Doc
+// carries separately.
+func withoutDoc(decl *ast.GenDecl) ast.Node {
+	if decl == nil {
+		return nil
+	}
+	d := *decl
+	d.Doc = nil
+	return &d
+}
+
+// node prints an AST node as gofmt would.
+func node(fset *token.FileSet, n ast.Node) string {
+	if n == nil {
+		return ""
+	}
+	var buf bytes.Buffer
+	if err := format.Node(&buf, fset, n); err != nil {
+		return ""
+	}
+	return buf.String()
+}
+
+// unblock turns a printed block into its statements: the braces go and
+// each line loses one level of indentation.
+func unblock(s string) string {
+	s = strings.TrimSpace(s)
+	s = strings.TrimSuffix(strings.TrimPrefix(s, "{"), "}")
+	lines := strings.Split(strings.Trim(s, "\n"), "\n")
+	for i, l := range lines {
+		lines[i] = strings.TrimPrefix(l, "\t")
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,136 @@
+package scan
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// GeneratedBy marks entity files written by scan. Only files carrying it
+// are overwritten by a later scan, so hand-written entities are safe.
+const GeneratedBy = "pssg scan"
+
+// frontmatter is the entity written for each package. Field names follow
+// the graph2md entities, so templates can serve both.
+type frontmatter struct {
+	Title        string   `yaml:"title"`
+	Description  string   `yaml:"description"`
+	NodeType     string   `yaml:"node_type"`
+	Language     string   `yaml:"language"`
+	PackageName  string   `yaml:"package_name"`
+	ImportPath   string   `yaml:"import_path"`
+	DirPath      string   `yaml:"dir_path"`
+	Repo         string   `yaml:"repo"`
+	SourceURL    string   `yaml:"source_url,omitempty"`
+	FileCount    int      `yaml:"file_count"`
+	LOC          int      `yaml:"loc"`
+	TestLOC      int      `yaml:"test_loc,omitempty"`
+	Imports      []string `yaml:"imports,flow"`
+	ImportedBy   []string `yaml:"imported_by,flow"`
+	External     []string `yaml:"external_imports,flow"`
+	Owners       []string `yaml:"owners,flow,omitempty"`
+	TopDirectory string   `yaml:"top_directory"`
+	GeneratedBy  string   `yaml:"generated_by"`
+}
+
+// Markdown renders a package as an entity file for the markdown loader.
+func Markdown(res *Result, p *Package) ([]byte, error) {
+	desc := p.Synopsis
+	if desc == "" {
+		desc = fmt.Sprintf("Package %s in the %s codebase: %d files, %d lines of Go.", p.Name, res.RepoName, p.Files, p.LOC)
+	}
+	fm := frontmatter{
+		Title:        fmt.Sprintf("%s — %s Package", p.ImportPath, res.RepoName),
+		Description:  desc,
+		NodeType:     "Package",
+		Language:     p.Language,
+		PackageName:  p.Name,
+		ImportPath:   p.ImportPath,
+		DirPath:      p.Dir,
+		Repo:         res.RepoName,
+		SourceURL:    p.SourceURL,
+		FileCount:    p.Files,
+		LOC:          p.LOC,
+		TestLOC:      p.TestLOC,
+		Imports:      nonNil(p.Imports),
+		ImportedBy:   nonNil(p.ImportedBy),
+		External:     nonNil(p.External),
+		Owners:       p.Owners,
+		TopDirectory: strings.Split(p.Dir, "/")[0],
+		GeneratedBy:  GeneratedBy,
+	}
+	out, err := yaml.Marshal(fm)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("---\n")
+	buf.Write(out)
+	buf.WriteString("---\n")
+	if p.Doc != "" {
+		fmt.Fprintf(&buf, "\n## Overview\n\n%s\n", p.Doc)
+	}
+	if len(p.Exported) > 0 {
+		buf.WriteString("\n## Exported API\n\n")
+		for _, name := range p.Exported {
+			fmt.Fprintf(&buf, "- `%s`\n", name)
+		}
+	}
+	if len(p.Imports) > 0 {
+		buf.WriteString("\n## Depends On\n\n")
+		for _, slug := range p.Imports {
+			fmt.Fprintf(&buf, "- [%s](/%s.html)\n", slug, slug)
+		}
+	}
+	if len(p.ImportedBy) > 0 {
+		buf.WriteString("\n## Used By\n\n")
+		for _, slug := range p.ImportedBy {
+			fmt.Fprintf(&buf, "- [%s](/%s.html)\n", slug, slug)
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// WriteResult reports what WriteMarkdown did.
+type WriteResult struct {
+	Written int
+	Skipped []string // existing files scan did not write
+}
+
+// WriteMarkdown writes one entity file per package into dir. Existing
+// files are replaced only if an earlier scan wrote them, unless force is
+// set.
+func WriteMarkdown(res *Result, dir string, force bool) (WriteResult, error) {
+	var wr WriteResult
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return wr, err
+	}
+	for _, p := range res.Packages {
+		path := filepath.Join(dir, p.Slug+".md")
+		if existing, err := os.ReadFile(path); err == nil && !force && !bytes.Contains(existing, []byte("generated_by: "+GeneratedBy)) {
+			wr.Skipped = append(wr.Skipped, path)
+			continue
+		}
+		data, err := Markdown(res, p)
+		if err != nil {
+			return wr, fmt.Errorf("%s: %w", p.ImportPath, err)
+		}
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			return wr, err
+		}
+		wr.Written++
+	}
+	return wr, nil
+}
+
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
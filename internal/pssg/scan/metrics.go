@@ -0,0 +1,117 @@
+package scan
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// Metrics measure a package's size, tests and dependencies. They are
+// stored in the enrichment cache, so the field names are those templates
+// and bucket taxonomies read.
+type Metrics struct {
+	ImportPath      string   `json:"import_path"`
+	Files           int      `json:"files"`
+	LOC             int      `json:"loc"`
+	TestLOC         int      `json:"test_loc"`
+	Imports         int      `json:"imports"`
+	ImportedBy      int      `json:"imported_by"`
+	ExternalImports int      `json:"external_imports"`
+	Exported        int      `json:"exported"`
+	Statements      int      `json:"statements,omitempty"`
+	Covered         int      `json:"covered_statements,omitempty"`
+	Coverage        *float64 `json:"coverage,omitempty"` // percentage of statements covered; nil if the profile has no statements for the package
+}
+
+// Coverage counts a package's statements and those tests ran.
+type Coverage struct {
+	Statements int
+	Covered    int
+}
+
+// PackageMetrics measures every package of a scan. coverage, keyed by
+// import path, may be nil; packages it does not list get no coverage.
+func PackageMetrics(res *Result, coverage map[string]Coverage) []*Metrics {
+	var out []*Metrics
+	for _, p := range res.Packages {
+		m := &Metrics{
+			ImportPath:      p.ImportPath,
+			Files:           p.Files,
+			LOC:             p.LOC,
+			TestLOC:         p.TestLOC,
+			Imports:         len(p.Imports),
+			ImportedBy:      len(p.ImportedBy),
+			ExternalImports: len(p.External),
+			Exported:        len(p.Exported),
+		}
+		if c, ok := coverage[p.ImportPath]; ok && c.Statements > 0 {
+			m.Statements, m.Covered = c.Statements, c.Covered
+			pct := float64(c.Covered) * 100 / float64(c.Statements)
+			pct = float64(int(pct*10+0.5)) / 10
+			m.Coverage = &pct
+		}
+		out = append(out, m)
+	}
+	return out
+}
+
+// ReadCoverProfile sums a `go test -coverprofile` file by package. A
+// block listed more than once, as with -coverpkg across several test
+// binaries, counts as covered if any run covered it.
+func ReadCoverProfile(file string) (map[string]Coverage, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	type block struct {
+		pkg   string
+		stmts int
+		hit   bool
+	}
+	blocks := make(map[string]*block)
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "mode:") {
+			continue
+		}
+		// name.go:line.col,line.col statements count
+		fields := strings.Fields(line)
+		colon := strings.LastIndex(fields[0], ":")
+		if len(fields) != 3 || colon < 0 {
+			return nil, fmt.Errorf("%s:%d: not a coverage profile line", file, n)
+		}
+		stmts, err1 := strconv.Atoi(fields[1])
+		count, err2 := strconv.Atoi(fields[2])
+		if err1 != nil || err2 != nil {
+			return nil, fmt.Errorf("%s:%d: not a coverage profile line", file, n)
+		}
+		key := fields[0]
+		b, ok := blocks[key]
+		if !ok {
+			b = &block{pkg: path.Dir(fields[0][:colon]), stmts: stmts}
+			blocks[key] = b
+		}
+		b.hit = b.hit || count > 0
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+
+	coverage := make(map[string]Coverage)
+	for _, b := range blocks {
+		c := coverage[b.pkg]
+		c.Statements += b.stmts
+		if b.hit {
+			c.Covered += b.stmts
+		}
+		coverage[b.pkg] = c
+	}
+	return coverage, nil
+}
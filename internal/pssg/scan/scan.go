@@ -0,0 +1,295 @@
+// Package scan walks a source repository and describes its packages as
+// entities, so an architecture site can be built from the code itself.
+// Go is supported today; other languages plug in as further walkers
+// producing the same Package records.
+package scan
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/doc"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/supermodeltools/arch-docs/internal/pssg/entity"
+	"github.com/supermodeltools/arch-docs/internal/pssg/owners"
+)
+
+// Options control a scan.
+type Options struct {
+	Root     string // repository root
+	RepoName string // default: the module path's last element
+	RepoURL  string // links package pages to their source, e.g. https://github.com/org/repo
+	Branch   string // for source links, default "main"
+	// Tests also counts _test.go files towards a package's files and lines.
+	// Test-only imports are never part of the import graph.
+	Tests bool
+}
+
+// Package describes one package of the repository.
+type Package struct {
+	Slug       string   `json:"slug"`
+	Name       string   `json:"name"`
+	ImportPath string   `json:"import_path"`
+	Dir        string   `json:"dir"` // slash-separated, relative to the root; "." for the root
+	Language   string   `json:"language"`
+	Synopsis   string   `json:"synopsis"`
+	Doc        string   `json:"doc"`
+	Files      int      `json:"files"`
+	LOC        int      `json:"loc"`      // non-blank lines
+	TestLOC    int      `json:"test_loc"` // non-blank lines in tests
+	Imports    []string `json:"imports"`  // slugs of packages in this repository
+	ImportedBy []string `json:"imported_by"`
+	External   []string `json:"external_imports"` // import paths outside the repository
+	Exported   []string `json:"exported"`         // exported top-level identifiers
+	Owners     []string `json:"owners"`           // from CODEOWNERS
+	SourceURL  string   `json:"source_url,omitempty"`
+}
+
+// Result is a scanned repository.
+type Result struct {
+	Module   string     `json:"module"`
+	RepoName string     `json:"repo"`
+	Packages []*Package `json:"packages"`
+}
+
+// Scan walks opts.Root and returns its Go packages, sorted by directory.
+// Vendored code, testdata and hidden or underscore-prefixed directories
+// are skipped, as the go tool skips them.
+func Scan(opts Options) (*Result, error) {
+	root, err := filepath.Abs(opts.Root)
+	if err != nil {
+		return nil, err
+	}
+	module := modulePath(root)
+	if module == "" {
+		return nil, fmt.Errorf("%s has no go.mod; only Go modules can be scanned", opts.Root)
+	}
+	res := &Result{Module: module, RepoName: opts.RepoName}
+	if res.RepoName == "" {
+		res.RepoName = path.Base(module)
+	}
+	var codeOwners *owners.Set
+	if file := owners.Find(root); file != "" {
+		codeOwners, _ = owners.Load(file) // unreadable ownership just leaves packages unowned
+	}
+	if opts.Branch == "" {
+		opts.Branch = "main"
+	}
+
+	err = walkPackages(root, func(p, rel string) error {
+		pkg, err := scanDir(p, rel, module, opts.Tests)
+		if err != nil {
+			return err
+		}
+		if pkg != nil {
+			pkg.Owners = codeOwners.MatchDir(pkg.Dir)
+			if opts.RepoURL != "" {
+				pkg.SourceURL = fmt.Sprintf("%s/tree/%s/%s", strings.TrimSuffix(opts.RepoURL, "/"), opts.Branch, pkg.Dir)
+				pkg.SourceURL = strings.TrimSuffix(pkg.SourceURL, "/.")
+			}
+			res.Packages = append(res.Packages, pkg)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	link(res)
+	return res, nil
+}
+
+// walkPackages calls fn for every directory of the module at root that
+// the go tool would consider: vendored code, testdata, nested modules and
+// hidden or underscore-prefixed directories are skipped. rel is the
+// slash-separated path from root, "." for root itself.
+func walkPackages(root string, fn func(dir, rel string) error) error {
+	return filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		name := d.Name()
+		if p != root && (name == "vendor" || name == "testdata" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")) {
+			return filepath.SkipDir
+		}
+		if p != root {
+			if _, err := os.Stat(filepath.Join(p, "go.mod")); err == nil {
+				return filepath.SkipDir // a nested module
+			}
+		}
+		rel, _ := filepath.Rel(root, p)
+		return fn(p, filepath.ToSlash(rel))
+	})
+}
+
+// scanDir parses the Go files of one directory, returning nil if there
+// are none.
+func scanDir(dir, rel, module string, tests bool) (*Package, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	fset := token.NewFileSet()
+	var files []*ast.File
+	imports := make(map[string]bool)
+	pkg := &Package{Dir: rel, Language: "Go", ImportPath: module}
+	if rel != "." {
+		pkg.ImportPath = module + "/" + rel
+	}
+
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, ".go") {
+			continue
+		}
+		isTest := strings.HasSuffix(name, "_test.go")
+		if isTest && !tests {
+			continue
+		}
+		src, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+		f, err := parser.ParseFile(fset, filepath.Join(dir, name), src, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		if isTest {
+			pkg.TestLOC += countLines(src)
+			pkg.Files++
+			continue
+		}
+		if pkg.Name == "" || pkg.Name == "main" && f.Name.Name != "main" {
+			pkg.Name = f.Name.Name
+		}
+		pkg.LOC += countLines(src)
+		pkg.Files++
+		files = append(files, f)
+		for _, imp := range f.Imports {
+			imports[strings.Trim(imp.Path.Value, `"`)] = true
+		}
+	}
+	if len(files) == 0 {
+		return nil, nil
+	}
+
+	for imp := range imports {
+		if imp == module || strings.HasPrefix(imp, module+"/") {
+			pkg.Imports = append(pkg.Imports, imp) // resolved to slugs by link
+		} else {
+			pkg.External = append(pkg.External, imp)
+		}
+	}
+	sort.Strings(pkg.External)
+
+	for _, f := range files {
+		if f.Doc != nil && pkg.Doc == "" {
+			pkg.Doc = strings.TrimSpace(f.Doc.Text())
+		}
+		for _, decl := range f.Decls {
+			pkg.Exported = append(pkg.Exported, exportedNames(decl)...)
+		}
+	}
+	sort.Strings(pkg.Exported)
+	if pkg.Doc != "" {
+		pkg.Synopsis = new(doc.Package).Synopsis(pkg.Doc)
+	}
+	pkg.Slug = packageSlug(module, rel)
+	return pkg, nil
+}
+
+// link turns import paths into slugs and fills in ImportedBy.
+func link(res *Result) {
+	byPath := make(map[string]*Package)
+	for _, p := range res.Packages {
+		byPath[p.ImportPath] = p
+	}
+	for _, p := range res.Packages {
+		var slugs []string
+		for _, imp := range p.Imports {
+			if dep, ok := byPath[imp]; ok {
+				slugs = append(slugs, dep.Slug)
+				dep.ImportedBy = append(dep.ImportedBy, p.Slug)
+			}
+		}
+		sort.Strings(slugs)
+		p.Imports = slugs
+	}
+	for _, p := range res.Packages {
+		sort.Strings(p.ImportedBy)
+	}
+}
+
+// packageSlug names a package's entity after its directory, or the
+// module for the root package.
+func packageSlug(module, rel string) string {
+	if rel == "." {
+		return entity.ToSlug(path.Base(module))
+	}
+	return entity.ToSlug(strings.ReplaceAll(rel, "/", "-"))
+}
+
+func exportedNames(decl ast.Decl) []string {
+	var names []string
+	switch d := decl.(type) {
+	case *ast.FuncDecl:
+		if d.Recv == nil && d.Name.IsExported() {
+			names = append(names, d.Name.Name)
+		}
+	case *ast.GenDecl:
+		for _, spec := range d.Specs {
+			switch s := spec.(type) {
+			case *ast.TypeSpec:
+				if s.Name.IsExported() {
+					names = append(names, s.Name.Name)
+				}
+			case *ast.ValueSpec:
+				for _, n := range s.Names {
+					if n.IsExported() {
+						names = append(names, n.Name)
+					}
+				}
+			}
+		}
+	}
+	return names
+}
+
+// countLines counts non-blank lines.
+func countLines(src []byte) int {
+	n := 0
+	sc := bufio.NewScanner(bytes.NewReader(src))
+	sc.Buffer(make([]byte, 0, 64*1024), 1<<20)
+	for sc.Scan() {
+		if len(bytes.TrimSpace(sc.Bytes())) > 0 {
+			n++
+		}
+	}
+	return n
+}
+
+// modulePath reads the module path from root/go.mod.
+func modulePath(root string) string {
+	data, err := os.ReadFile(filepath.Join(root, "go.mod"))
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if rest, ok := strings.CutPrefix(strings.TrimSpace(line), "module "); ok {
+			return strings.Trim(strings.TrimSpace(rest), `"`)
+		}
+	}
+	return ""
+}
@@ -3,12 +3,14 @@ package schema
 import (
 	"encoding/json"
 	"fmt"
-	"regexp"
-	"strconv"
 	"strings"
+	"time"
 
 	"github.com/supermodeltools/arch-docs/internal/pssg/config"
+	"github.com/supermodeltools/arch-docs/internal/pssg/contributors"
+	"github.com/supermodeltools/arch-docs/internal/pssg/engagement"
 	"github.com/supermodeltools/arch-docs/internal/pssg/entity"
+	"github.com/supermodeltools/arch-docs/internal/pssg/text"
 )
 
 // Generator creates JSON-LD structured data.
@@ -59,7 +61,7 @@ func (g *Generator) GenerateRecipeSchema(e *entity.Entity, entityURL string) map
 		schema["cookTime"] = cookTime
 	}
 	if prepTime != "" && cookTime != "" {
-		schema["totalTime"] = computeTotalTime(prepTime, cookTime)
+		schema["totalTime"] = isoMinutes(e.GetDuration("prep_time") + e.GetDuration("cook_time"))
 	}
 
 	// Servings
@@ -77,7 +79,7 @@ func (g *Generator) GenerateRecipeSchema(e *entity.Entity, entityURL string) map
 
 	// Image
 	if img := e.GetString("image"); img != "" {
-		schema["image"] = []string{img}
+		schema["image"] = []interface{}{ImageObject(contributors.AbsoluteURL(g.SiteConfig.BaseURL, img), 0, 0, e.GetString("image_caption"))}
 	}
 
 	// Nutrition
@@ -131,6 +133,55 @@ func (g *Generator) GenerateRecipeSchema(e *entity.Entity, entityURL string) map
 	return schema
 }
 
+// Decision describes an architecture decision record for
+// GenerateDecisionSchema.
+type Decision struct {
+	ID         string // e.g. "ADR-0007"
+	Status     string
+	Date       string // ISO 8601, or ""
+	Supersedes []ItemListEntry
+}
+
+// GenerateDecisionSchema generates TechArticle JSON-LD for an architecture
+// decision record. The status becomes creativeWorkStatus, and the records
+// it replaces are the works it is based on.
+func (g *Generator) GenerateDecisionSchema(e *entity.Entity, entityURL string, d Decision) map[string]interface{} {
+	s := map[string]interface{}{
+		"@context":           "https://schema.org",
+		"@type":              "TechArticle",
+		"headline":           e.GetString("title"),
+		"name":               e.GetString("title"),
+		"description":        e.GetString("description"),
+		"url":                entityURL,
+		"genre":              "Architecture Decision Record",
+		"creativeWorkStatus": d.Status,
+		"isPartOf": map[string]interface{}{
+			"@type": "WebSite",
+			"name":  g.SiteConfig.Name,
+			"url":   g.SiteConfig.BaseURL,
+		},
+	}
+	if d.ID != "" {
+		s["identifier"] = d.ID
+	}
+	if d.Date != "" {
+		s["datePublished"] = d.Date
+	} else if g.Schema.DatePublished != "" {
+		s["datePublished"] = g.Schema.DatePublished
+	}
+	if authorName := e.GetString("author"); authorName != "" {
+		s["author"] = map[string]interface{}{"@type": "Person", "name": authorName}
+	}
+	if len(d.Supersedes) > 0 {
+		var based []map[string]interface{}
+		for _, item := range d.Supersedes {
+			based = append(based, map[string]interface{}{"@type": "TechArticle", "name": item.Name, "url": item.URL})
+		}
+		s["isBasedOn"] = based
+	}
+	return s
+}
+
 // GenerateBreadcrumbSchema generates BreadcrumbList JSON-LD.
 func (g *Generator) GenerateBreadcrumbSchema(items []BreadcrumbItem) map[string]interface{} {
 	var listItems []map[string]interface{}
@@ -199,7 +250,7 @@ func (g *Generator) GenerateWebSiteSchema(imageURL string) map[string]interface{
 		},
 	}
 	if imageURL != "" {
-		s["image"] = imageURL
+		s["image"] = ShareImageObject(imageURL, g.SiteConfig.Name)
 	}
 	return s
 }
@@ -225,7 +276,7 @@ func (g *Generator) GenerateItemListSchema(name, description string, items []Ite
 		"itemListElement": listItems,
 	}
 	if imageURL != "" {
-		s["image"] = imageURL
+		s["image"] = ShareImageObject(imageURL, name)
 	}
 	return s
 }
@@ -261,11 +312,41 @@ func (g *Generator) GenerateCollectionPageSchema(name, description, pageURL stri
 		},
 	}
 	if imageURL != "" {
-		s["image"] = imageURL
+		s["image"] = ShareImageObject(imageURL, name)
 	}
 	return s
 }
 
+// Share images are drawn at this size.
+const (
+	shareImageWidth  = 1200
+	shareImageHeight = 630
+)
+
+// ImageObject describes an image with its dimensions and caption, which
+// rich results prefer to a bare URL. Unknown dimensions (0) and an empty
+// caption are left out.
+func ImageObject(url string, width, height int, caption string) map[string]interface{} {
+	img := map[string]interface{}{
+		"@type":      "ImageObject",
+		"url":        url,
+		"contentUrl": url,
+	}
+	if width > 0 && height > 0 {
+		img["width"] = width
+		img["height"] = height
+	}
+	if caption != "" {
+		img["caption"] = caption
+	}
+	return img
+}
+
+// ShareImageObject describes a generated share image.
+func ShareImageObject(url, caption string) map[string]interface{} {
+	return ImageObject(url, shareImageWidth, shareImageHeight, caption)
+}
+
 // MarshalSchemas encodes one or more schemas as a JSON-LD script block.
 func MarshalSchemas(schemas ...map[string]interface{}) string {
 	var parts []string
@@ -290,29 +371,12 @@ func stepName(step string) string {
 			return step[:idx+1]
 		}
 	}
-	// Truncate if too long
-	if len(step) > 80 {
-		return step[:77] + "..."
-	}
-	return step
-}
-
-var durationRegex = regexp.MustCompile(`PT(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?`)
-
-// parseDurationMinutes parses an ISO 8601 duration to minutes.
-func parseDurationMinutes(d string) int {
-	matches := durationRegex.FindStringSubmatch(d)
-	if matches == nil {
-		return 0
-	}
-	hours, _ := strconv.Atoi(matches[1])
-	minutes, _ := strconv.Atoi(matches[2])
-	return hours*60 + minutes
+	return text.Truncate(step, 80)
 }
 
-// computeTotalTime adds two ISO 8601 durations and returns the result.
-func computeTotalTime(d1, d2 string) string {
-	total := parseDurationMinutes(d1) + parseDurationMinutes(d2)
+// isoMinutes formats a duration as an ISO 8601 duration in whole minutes.
+func isoMinutes(d time.Duration) string {
+	total := int(d / time.Minute)
 	hours := total / 60
 	minutes := total % 60
 	if hours > 0 && minutes > 0 {
@@ -323,3 +387,65 @@ func computeTotalTime(d1, d2 string) string {
 	}
 	return fmt.Sprintf("PT%dM", minutes)
 }
+
+// AddEngagement adds counts collected outside the build to an entity's
+// JSON-LD: an aggregateRating when there are ratings, and comment and
+// reaction totals as interactionStatistic.
+func AddEngagement(s map[string]interface{}, c *engagement.Counts) {
+	if c == nil {
+		return
+	}
+	if c.Rated() {
+		s["aggregateRating"] = map[string]interface{}{
+			"@type":       "AggregateRating",
+			"ratingValue": c.Rating,
+			"ratingCount": c.RatingCount,
+			"bestRating":  5,
+			"worstRating": 1,
+		}
+	}
+	var stats []map[string]interface{}
+	for _, st := range []struct {
+		action string
+		count  int
+	}{{"CommentAction", c.Comments}, {"LikeAction", c.TotalReactions()}} {
+		if st.count > 0 {
+			stats = append(stats, map[string]interface{}{
+				"@type":                "InteractionCounter",
+				"interactionType":      "https://schema.org/" + st.action,
+				"userInteractionCount": st.count,
+			})
+		}
+	}
+	if len(stats) > 0 {
+		s["interactionStatistic"] = stats
+	}
+}
+
+// GeneratePersonSchema generates Person JSON-LD for a contributor; id is
+// the URL of their entry on the contributors page.
+func (g *Generator) GeneratePersonSchema(p *contributors.Profile, id string) map[string]interface{} {
+	s := map[string]interface{}{
+		"@context": "https://schema.org",
+		"@type":    "Person",
+		"@id":      id,
+		"name":     p.Name,
+	}
+	if p.Avatar != "" {
+		s["image"] = contributors.AbsoluteURL(g.SiteConfig.BaseURL, p.Avatar)
+	}
+	if p.Role != "" {
+		s["jobTitle"] = p.Role
+	}
+	if p.Bio != "" {
+		s["description"] = p.Bio
+	}
+	var sameAs []string
+	for _, l := range p.Links {
+		sameAs = append(sameAs, contributors.AbsoluteURL(g.SiteConfig.BaseURL, l.URL))
+	}
+	if len(sameAs) > 0 {
+		s["sameAs"] = sameAs
+	}
+	return s
+}
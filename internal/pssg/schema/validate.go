@@ -0,0 +1,109 @@
+package schema
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/supermodeltools/arch-docs/internal/pssg/entity"
+)
+
+// Properties holding URLs and dates, checked wherever they appear.
+var (
+	urlProps      = []string{"url", "@id", "item", "logo"}
+	dateProps     = []string{"datePublished", "dateModified", "uploadDate"}
+	durationProps = []string{"prepTime", "cookTime", "totalTime", "duration"}
+)
+
+// Validate checks a generated JSON-LD object for problems search engines
+// reject: a missing @context or @type, an empty name, relative URLs,
+// malformed dates and durations, and list positions out of sequence.
+// Nested objects are checked too. Problems are prefixed with their path.
+func Validate(s map[string]interface{}) []string {
+	var problems []string
+	if ctx, _ := s["@context"].(string); ctx != "https://schema.org" {
+		problems = append(problems, fmt.Sprintf("@context is %q, want \"https://schema.org\"", ctx))
+	}
+	validateNode(s, "", &problems)
+	return problems
+}
+
+func validateNode(s map[string]interface{}, path string, problems *[]string) {
+	typ, _ := s["@type"].(string)
+	if typ == "" {
+		*problems = append(*problems, prefix(path, "missing @type"))
+	} else {
+		path = strings.TrimPrefix(path+"."+typ, ".")
+	}
+	if name, ok := s["name"]; ok && strings.TrimSpace(fmt.Sprint(name)) == "" {
+		*problems = append(*problems, prefix(path, "name is empty"))
+	}
+	for _, key := range urlProps {
+		if v, ok := s[key].(string); ok && !strings.HasPrefix(v, "https://") && !strings.HasPrefix(v, "http://") {
+			*problems = append(*problems, prefix(path, fmt.Sprintf("%s %q is not an absolute URL", key, v)))
+		}
+	}
+	for _, key := range dateProps {
+		if v, ok := s[key].(string); ok && !validDate(v) {
+			*problems = append(*problems, prefix(path, fmt.Sprintf("%s %q is not an ISO 8601 date", key, v)))
+		}
+	}
+	for _, key := range durationProps {
+		v, ok := s[key].(string)
+		if !ok {
+			continue
+		}
+		if _, iso := entity.ParseISODuration(v); !iso {
+			*problems = append(*problems, prefix(path, fmt.Sprintf("%s %q is not an ISO 8601 duration", key, v)))
+		}
+	}
+
+	keys := make([]string, 0, len(s))
+	for k := range s {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		switch v := s[k].(type) {
+		case map[string]interface{}:
+			validateNode(v, path+"."+k, problems)
+		case []map[string]interface{}:
+			validateList(v, path+"."+k, problems)
+		case []interface{}:
+			var items []map[string]interface{}
+			for _, item := range v {
+				if m, ok := item.(map[string]interface{}); ok {
+					items = append(items, m)
+				}
+			}
+			validateList(items, path+"."+k, problems)
+		}
+	}
+}
+
+func validateList(items []map[string]interface{}, path string, problems *[]string) {
+	for i, item := range items {
+		itemPath := fmt.Sprintf("%s[%d]", path, i)
+		if pos, ok := item["position"]; ok && fmt.Sprint(pos) != fmt.Sprint(i+1) {
+			*problems = append(*problems, prefix(itemPath, fmt.Sprintf("position is %v, want %d", pos, i+1)))
+		}
+		validateNode(item, itemPath, problems)
+	}
+}
+
+func validDate(v string) bool {
+	for _, layout := range []string{"2006-01-02", time.RFC3339, "2006-01-02T15:04:05"} {
+		if _, err := time.Parse(layout, v); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+func prefix(path, msg string) string {
+	if path == "" {
+		return msg
+	}
+	return path + ": " + msg
+}
@@ -0,0 +1,100 @@
+package series
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/supermodeltools/arch-docs/internal/pssg/config"
+	"github.com/supermodeltools/arch-docs/internal/pssg/entity"
+)
+
+// Series is an ordered sequence of entities sharing a series name, such as
+// the parts of a multi-part guide.
+type Series struct {
+	Name     string
+	Slug     string
+	URL      string // root-relative URL of the series index page
+	Entities []*entity.Entity
+}
+
+// Nav is an entity's position within its series.
+type Nav struct {
+	Series   *Series
+	Position int // 1-based
+	Total    int
+	Prev     *entity.Entity
+	Next     *entity.Entity
+}
+
+// Index holds every series and the navigation for each member entity.
+type Index struct {
+	All []*Series
+	nav map[string]*Nav
+}
+
+// Build groups entities by their series field. Members are ordered by the
+// order field (entities without one come last), then by title.
+func Build(entities []*entity.Entity, cfg config.SeriesConfig) *Index {
+	bySlug := make(map[string]*Series)
+	for _, e := range entities {
+		name := strings.TrimSpace(e.GetString(cfg.Field))
+		if name == "" {
+			continue
+		}
+		slug := entity.ToSlug(name)
+		s, ok := bySlug[slug]
+		if !ok {
+			s = &Series{
+				Name: name,
+				Slug: slug,
+				URL:  fmt.Sprintf("/%s/%s.html", cfg.Dir, slug),
+			}
+			bySlug[slug] = s
+		}
+		s.Entities = append(s.Entities, e)
+	}
+
+	idx := &Index{nav: make(map[string]*Nav)}
+	for _, s := range bySlug {
+		sortMembers(s.Entities, cfg.OrderField)
+		for i, e := range s.Entities {
+			n := &Nav{Series: s, Position: i + 1, Total: len(s.Entities)}
+			if i > 0 {
+				n.Prev = s.Entities[i-1]
+			}
+			if i < len(s.Entities)-1 {
+				n.Next = s.Entities[i+1]
+			}
+			idx.nav[e.Slug] = n
+		}
+		idx.All = append(idx.All, s)
+	}
+	sort.Slice(idx.All, func(i, j int) bool { return idx.All[i].Slug < idx.All[j].Slug })
+	return idx
+}
+
+func sortMembers(list []*entity.Entity, orderField string) {
+	sort.SliceStable(list, func(i, j int) bool {
+		a, b := list[i], list[j]
+		aHas, bHas := a.HasField(orderField), b.HasField(orderField)
+		if aHas != bHas {
+			return aHas
+		}
+		if aHas {
+			if ao, bo := a.GetFloat(orderField), b.GetFloat(orderField); ao != bo {
+				return ao < bo
+			}
+		}
+		if at, bt := a.GetString("title"), b.GetString("title"); at != bt {
+			return at < bt
+		}
+		return a.Slug < b.Slug
+	})
+}
+
+// Nav returns the series navigation for an entity, or nil if it is not
+// part of a series.
+func (idx *Index) Nav(slug string) *Nav {
+	return idx.nav[slug]
+}
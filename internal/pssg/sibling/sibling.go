@@ -0,0 +1,170 @@
+// Package sibling links pssg sites to each other. A site publishes a
+// manifest of its entities; its siblings read it to resolve relation
+// targets they do not have themselves.
+package sibling
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/supermodeltools/arch-docs/internal/pssg/config"
+	"github.com/supermodeltools/arch-docs/internal/pssg/entity"
+	"github.com/supermodeltools/arch-docs/internal/pssg/output"
+	"github.com/supermodeltools/arch-docs/internal/pssg/relation"
+)
+
+// ManifestFile is where a site publishes its manifest.
+const ManifestFile = "entities.json"
+
+// maxManifestSize caps manifest downloads.
+const maxManifestSize = 32 << 20
+
+var client = &http.Client{Timeout: 30 * time.Second}
+
+// Manifest lists a site's entities.
+type Manifest struct {
+	Site     string  `json:"site"`
+	BaseURL  string  `json:"base_url"`
+	Entities []Entry `json:"entities"`
+}
+
+// Entry is one entity in a manifest.
+type Entry struct {
+	Slug  string `json:"slug"`
+	Title string `json:"title"`
+	Type  string `json:"type,omitempty"`
+	URL   string `json:"url"`
+}
+
+// NewManifest describes entities for siblings; typeField names the field
+// holding each entity's type.
+func NewManifest(cfg *config.Config, entities []*entity.Entity, typeField string) Manifest {
+	m := Manifest{Site: cfg.Site.Name, BaseURL: cfg.Site.BaseURL, Entities: make([]Entry, 0, len(entities))}
+	for _, e := range entities {
+		m.Entities = append(m.Entities, Entry{
+			Slug:  e.Slug,
+			Title: e.GetString("title"),
+			Type:  e.GetString(typeField),
+			URL:   cfg.Site.BaseURL + output.CanonicalPath(cfg.URLs, "/"+e.Slug+".html"),
+		})
+	}
+	return m
+}
+
+// site is a loaded sibling.
+type site struct {
+	name    string
+	baseURL string
+	bySlug  map[string]Entry
+}
+
+// Index resolves relation targets against sibling manifests.
+type Index struct {
+	sites []site
+}
+
+// Load reads the manifest of every sibling. A sibling whose manifest
+// cannot be read is reported in the returned errors and left out.
+func Load(siblings []config.SiblingConfig) (*Index, []error) {
+	idx := &Index{}
+	var errs []error
+	for _, sib := range siblings {
+		m, err := read(sib.Manifest)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("sibling %s: %w", sib.Name, err))
+			continue
+		}
+		s := site{name: sib.Name, baseURL: sib.BaseURL, bySlug: make(map[string]Entry, len(m.Entities))}
+		for _, e := range m.Entities {
+			if e.Slug != "" {
+				s.bySlug[e.Slug] = e
+			}
+		}
+		idx.sites = append(idx.sites, s)
+	}
+	return idx, errs
+}
+
+// read fetches a manifest over HTTP(S) or reads it from a file.
+func read(src string) (*Manifest, error) {
+	var data []byte
+	if strings.HasPrefix(src, "https://") || strings.HasPrefix(src, "http://") {
+		resp, err := client.Get(src)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("GET %s: %s", src, resp.Status)
+		}
+		if data, err = io.ReadAll(io.LimitReader(resp.Body, maxManifestSize+1)); err != nil {
+			return nil, err
+		}
+		if len(data) > maxManifestSize {
+			return nil, fmt.Errorf("GET %s: larger than %d bytes", src, maxManifestSize)
+		}
+	} else {
+		var err error
+		if data, err = os.ReadFile(src); err != nil {
+			return nil, err
+		}
+	}
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("%s: %w", src, err)
+	}
+	return &m, nil
+}
+
+// Resolve finds a relation target on a sibling: by slug, then by its
+// slugified form, in each sibling in turn. A "<name>:" prefix naming a
+// sibling looks in that one only.
+func (idx *Index) Resolve(target string) (relation.Link, bool) {
+	sites := idx.sites
+	if name, rest, ok := strings.Cut(target, ":"); ok {
+		for _, s := range idx.sites {
+			if s.name == name {
+				sites, target = []site{s}, rest
+				break
+			}
+		}
+	}
+	for _, s := range sites {
+		e, ok := s.bySlug[target]
+		if !ok {
+			e, ok = s.bySlug[entity.ToSlug(target)]
+		}
+		if !ok {
+			continue
+		}
+		link := relation.Link{Site: s.name, Slug: e.Slug, Title: e.Title, URL: e.URL}
+		if link.Title == "" {
+			link.Title = e.Slug
+		}
+		if !strings.HasPrefix(link.URL, "https://") && !strings.HasPrefix(link.URL, "http://") {
+			link.URL = s.baseURL + "/" + e.Slug + ".html"
+		}
+		return link, true
+	}
+	return relation.Link{}, false
+}
+
+// Qualified reports whether a relation target names a sibling with a
+// "<name>:" prefix.
+func Qualified(target string, siblings []config.SiblingConfig) bool {
+	name, _, ok := strings.Cut(target, ":")
+	if !ok {
+		return false
+	}
+	for _, sib := range siblings {
+		if sib.Name == name {
+			return true
+		}
+	}
+	return false
+}
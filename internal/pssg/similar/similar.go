@@ -0,0 +1,181 @@
+// Package similar finds entities with similar text, by cosine similarity
+// of TF-IDF vectors over their fields and body sections.
+package similar
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/supermodeltools/arch-docs/internal/pssg/config"
+	"github.com/supermodeltools/arch-docs/internal/pssg/entity"
+)
+
+// Match is a similar entity and how similar it is, from 0 to 1.
+type Match struct {
+	Entity *entity.Entity
+	Score  float64
+}
+
+// doc is an entity's weighted terms, normalized to unit length.
+type doc struct {
+	terms map[string]float64
+}
+
+// Compute returns up to cfg.Limit matches per entity slug, most similar
+// first, leaving out matches scoring below cfg.MinScore. Each entity keeps
+// only its cfg.MaxTerms heaviest terms, and terms found in more than half
+// the entities are skipped as too common to tell them apart; together
+// these bound the work per entity.
+func Compute(entities []*entity.Entity, cfg config.SimilarConfig) map[string][]Match {
+	n := len(entities)
+	if n < 2 {
+		return nil
+	}
+	counts := make([]map[string]int, n)
+	df := make(map[string]int)
+	for i, e := range entities {
+		counts[i] = termCounts(text(e, cfg.Fields))
+		for t := range counts[i] {
+			df[t]++
+		}
+	}
+	common := max(2, n/2)
+
+	docs := make([]doc, n)
+	postings := make(map[string][]int)
+	for i, c := range counts {
+		w := make(map[string]float64, len(c))
+		for t, tf := range c {
+			if df[t] > common && n > 2 {
+				continue
+			}
+			w[t] = (1 + math.Log(float64(tf))) * math.Log(float64(n)/float64(df[t]))
+		}
+		w = heaviest(w, cfg.MaxTerms)
+		var norm float64
+		for _, v := range w {
+			norm += v * v
+		}
+		if norm == 0 {
+			continue
+		}
+		norm = math.Sqrt(norm)
+		for t := range w {
+			w[t] /= norm
+			postings[t] = append(postings[t], i)
+		}
+		docs[i] = doc{terms: w}
+	}
+
+	out := make(map[string][]Match)
+	for i := range entities {
+		scores := make(map[int]float64)
+		for t, v := range docs[i].terms {
+			for _, j := range postings[t] {
+				if j != i {
+					scores[j] += v * docs[j].terms[t]
+				}
+			}
+		}
+		var matches []Match
+		for j, s := range scores {
+			if s >= cfg.MinScore {
+				matches = append(matches, Match{Entity: entities[j], Score: s})
+			}
+		}
+		sort.Slice(matches, func(a, b int) bool {
+			if matches[a].Score != matches[b].Score {
+				return matches[a].Score > matches[b].Score
+			}
+			return matches[a].Entity.Slug < matches[b].Entity.Slug
+		})
+		if len(matches) > cfg.Limit {
+			matches = matches[:cfg.Limit]
+		}
+		if len(matches) > 0 {
+			out[entities[i].Slug] = matches
+		}
+	}
+	return out
+}
+
+// heaviest keeps the limit highest weights; a limit of 0 keeps them all.
+func heaviest(w map[string]float64, limit int) map[string]float64 {
+	if limit <= 0 || len(w) <= limit {
+		return w
+	}
+	terms := make([]string, 0, len(w))
+	for t := range w {
+		terms = append(terms, t)
+	}
+	sort.Slice(terms, func(a, b int) bool {
+		if w[terms[a]] != w[terms[b]] {
+			return w[terms[a]] > w[terms[b]]
+		}
+		return terms[a] < terms[b]
+	})
+	kept := make(map[string]float64, limit)
+	for _, t := range terms[:limit] {
+		kept[t] = w[t]
+	}
+	return kept
+}
+
+// text joins an entity's fields and every body section into one string.
+func text(e *entity.Entity, fields []string) string {
+	var b strings.Builder
+	for _, f := range fields {
+		b.WriteString(e.GetString(f))
+		b.WriteByte(' ')
+	}
+	for _, v := range e.Sections {
+		switch v := v.(type) {
+		case string:
+			b.WriteString(v)
+		case []string:
+			b.WriteString(strings.Join(v, " "))
+		case []entity.FAQ:
+			for _, f := range v {
+				b.WriteString(f.Question + " " + f.Answer + " ")
+			}
+		case []entity.Step:
+			for _, s := range v {
+				b.WriteString(s.Text + " " + s.Detail + " ")
+			}
+		default:
+			fmt.Fprint(&b, v)
+		}
+		b.WriteByte(' ')
+	}
+	return b.String()
+}
+
+// termCounts splits text into lower-case words of three or more letters
+// or digits, leaving out stop words.
+func termCounts(s string) map[string]int {
+	counts := make(map[string]int)
+	for _, w := range strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if len([]rune(w)) < 3 || stopWords[w] {
+			continue
+		}
+		counts[w]++
+	}
+	return counts
+}
+
+var stopWords = map[string]bool{
+	"the": true, "and": true, "for": true, "are": true, "but": true, "not": true,
+	"you": true, "all": true, "any": true, "can": true, "has": true, "have": true,
+	"its": true, "into": true, "from": true, "that": true, "this": true, "with": true,
+	"was": true, "were": true, "will": true, "which": true, "when": true, "what": true,
+	"their": true, "there": true, "these": true, "those": true, "than": true, "then": true,
+	"them": true, "they": true, "been": true, "being": true, "also": true, "each": true,
+	"such": true, "only": true, "other": true, "more": true, "most": true, "some": true,
+	"how": true, "our": true, "out": true, "use": true, "used": true, "uses": true,
+	"may": true, "does": true, "one": true, "two": true, "about": true, "over": true,
+}
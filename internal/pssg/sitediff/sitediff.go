@@ -0,0 +1,163 @@
+package sitediff
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/supermodeltools/arch-docs/internal/pssg/manifest"
+)
+
+// maxDiffLines bounds the line-by-line comparison of a single page; larger
+// pages are reported as changed without a text diff.
+const maxDiffLines = 4000
+
+// PageDiff is the visible-text difference of one changed page.
+type PageDiff struct {
+	Path string
+	// Lines are "-old" and "+new" lines of visible text. Empty means only
+	// markup changed (attributes, scripts, cache-busting query strings).
+	Lines []string
+	// TooLarge is set when the page exceeded maxDiffLines.
+	TooLarge bool
+}
+
+// Significant reports whether the page's visible text changed.
+func (d PageDiff) Significant() bool {
+	return d.TooLarge || len(d.Lines) > 0
+}
+
+// Dirs compares two build output directories.
+func Dirs(a, b string) (manifest.Changes, error) {
+	fa, err := manifest.Scan(a)
+	if err != nil {
+		return manifest.Changes{}, err
+	}
+	fb, err := manifest.Scan(b)
+	if err != nil {
+		return manifest.Changes{}, err
+	}
+	return manifest.Compare(fa, fb), nil
+}
+
+// Page diffs the visible text of one page present in both directories.
+func Page(a, b, rel string) (PageDiff, error) {
+	da, err := os.ReadFile(filepath.Join(a, filepath.FromSlash(rel)))
+	if err != nil {
+		return PageDiff{}, err
+	}
+	db, err := os.ReadFile(filepath.Join(b, filepath.FromSlash(rel)))
+	if err != nil {
+		return PageDiff{}, err
+	}
+	la, lb := TextLines(da), TextLines(db)
+	if len(la) > maxDiffLines || len(lb) > maxDiffLines {
+		return PageDiff{Path: rel, TooLarge: true}, nil
+	}
+	return PageDiff{Path: rel, Lines: diffLines(la, lb)}, nil
+}
+
+// IsPage reports whether a path is an HTML page.
+func IsPage(rel string) bool {
+	return strings.HasSuffix(rel, ".html")
+}
+
+var (
+	reInvisible = regexp.MustCompile(`(?is)<(script|style)\b.*?</(script|style)>|<!--.*?-->`)
+	reTitle     = regexp.MustCompile(`(?is)<title>(.*?)</title>`)
+	reMeta      = regexp.MustCompile(`(?is)<meta\s+name="description"\s+content="([^"]*)"`)
+	reTag       = regexp.MustCompile(`(?s)<[^>]*>`)
+)
+
+// TextLines reduces an HTML page to what a reader or search engine sees:
+// the title, the meta description and the visible text, one block per
+// line with whitespace collapsed.
+func TextLines(html []byte) []string {
+	var lines []string
+	if m := reTitle.FindSubmatch(html); m != nil {
+		lines = append(lines, "title: "+collapse(string(m[1])))
+	}
+	if m := reMeta.FindSubmatch(html); m != nil {
+		lines = append(lines, "description: "+collapse(string(m[1])))
+	}
+	body := html
+	if i := bytes.Index(bytes.ToLower(html), []byte("<body")); i >= 0 {
+		body = html[i:]
+	}
+	body = reInvisible.ReplaceAll(body, nil)
+	for _, block := range reTag.Split(string(body), -1) {
+		if s := collapse(block); s != "" {
+			lines = append(lines, s)
+		}
+	}
+	return lines
+}
+
+func collapse(s string) string {
+	return strings.Join(strings.FieldsFunc(s, unicode.IsSpace), " ")
+}
+
+// diffLines returns a minimal edit script between a and b as "-"/"+" lines,
+// using the longest common subsequence.
+func diffLines(a, b []string) []string {
+	n, m := len(a), len(b)
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var out []string
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case a[i] == b[j]:
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			out = append(out, "-"+a[i])
+			i++
+		default:
+			out = append(out, "+"+b[j])
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		out = append(out, "-"+a[i])
+	}
+	for ; j < m; j++ {
+		out = append(out, "+"+b[j])
+	}
+	return out
+}
+
+// Summary is a one-line count of the changes.
+func Summary(c manifest.Changes) string {
+	pages := func(list []string) (p, other int) {
+		for _, rel := range list {
+			if IsPage(rel) {
+				p++
+			} else {
+				other++
+			}
+		}
+		return
+	}
+	ap, ao := pages(c.Added)
+	rp, ro := pages(c.Removed)
+	cp, co := pages(c.Changed)
+	return fmt.Sprintf("pages: %d added, %d removed, %d changed; other files: %d added, %d removed, %d changed",
+		ap, rp, cp, ao, ro, co)
+}
@@ -0,0 +1,280 @@
+package source
+
+import (
+	"html/template"
+	"path"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// syntax describes just enough of a language to color it: comments,
+// strings, numbers, keywords and calls. It is a lexer, not a parser, so
+// the odd construct may be colored wrongly, but text is never lost.
+type syntax struct {
+	lineComments []string
+	block        [2]string // block comment delimiters, if any
+	quotes       string    // string delimiters
+	raw          byte      // a quote without escapes that may span lines
+	multiline    byte      // a quote with escapes that may span lines
+	triple       bool      // """ and ''' strings
+	keywords     map[string]bool
+}
+
+func words(s string) map[string]bool {
+	m := make(map[string]bool)
+	for _, w := range strings.Fields(s) {
+		m[w] = true
+	}
+	return m
+}
+
+var (
+	cKeywords = "auto break case char const continue default do double else enum extern float for goto if inline int long register return short signed sizeof static struct switch typedef union unsigned void volatile while NULL true false bool"
+
+	syntaxes = map[string]*syntax{
+		"go": {
+			lineComments: []string{"//"}, block: [2]string{"/*", "*/"}, quotes: "\"'`", raw: '`',
+			keywords: words("break case chan const continue default defer else fallthrough for func go goto if import interface map package range return select struct switch type var nil true false iota any error string bool byte rune int int8 int16 int32 int64 uint uint8 uint16 uint32 uint64 uintptr float32 float64 complex64 complex128 make new len cap append copy delete panic recover"),
+		},
+		"python": {
+			lineComments: []string{"#"}, quotes: `"'`, triple: true,
+			keywords: words("and as assert async await break class continue def del elif else except finally for from global if import in is lambda nonlocal not or pass raise return try while with yield None True False self"),
+		},
+		"javascript": {
+			lineComments: []string{"//"}, block: [2]string{"/*", "*/"}, quotes: "\"'`", multiline: '`',
+			keywords: words("async await break case catch class const continue debugger default delete do else export extends finally for from function if import in instanceof let new of return static super switch this throw try typeof var void while with yield null undefined true false"),
+		},
+		"typescript": {
+			lineComments: []string{"//"}, block: [2]string{"/*", "*/"}, quotes: "\"'`", multiline: '`',
+			keywords: words("abstract any as async await boolean break case catch class const constructor continue declare default delete do else enum export extends false finally for from function if implements import in infer instanceof interface is keyof let namespace never new null number of private protected public readonly return static string super switch this throw true try type typeof undefined unknown var void while yield"),
+		},
+		"java": {
+			lineComments: []string{"//"}, block: [2]string{"/*", "*/"}, quotes: `"'`,
+			keywords: words("abstract assert boolean break byte case catch char class const continue default do double else enum extends final finally float for if implements import instanceof int interface long native new package private protected public return short static super switch synchronized this throw throws transient try var void volatile while null true false record"),
+		},
+		"kotlin": {
+			lineComments: []string{"//"}, block: [2]string{"/*", "*/"}, quotes: `"'`,
+			keywords: words("as break class continue do else false for fun if in interface is null object package return super this throw true try typealias val var when while by companion data enum import internal open override private protected public sealed suspend"),
+		},
+		"c": {
+			lineComments: []string{"//"}, block: [2]string{"/*", "*/"}, quotes: `"'`,
+			keywords: words(cKeywords + " #include #define #ifdef #ifndef #endif #if #else #pragma"),
+		},
+		"cpp": {
+			lineComments: []string{"//"}, block: [2]string{"/*", "*/"}, quotes: `"'`,
+			keywords: words(cKeywords + " class namespace template typename public private protected virtual override new delete this using nullptr constexpr auto try catch throw noexcept #include #define #ifdef #ifndef #endif #if #else #pragma"),
+		},
+		"csharp": {
+			lineComments: []string{"//"}, block: [2]string{"/*", "*/"}, quotes: `"'`,
+			keywords: words("abstract as async await base bool break byte case catch char class const continue decimal default delegate do double else enum event explicit false finally float for foreach get if implicit in int interface internal is lock long namespace new null object override params private protected public readonly record ref return sealed set short static string struct switch this throw true try typeof uint ulong using var virtual void while"),
+		},
+		"rust": {
+			lineComments: []string{"//"}, block: [2]string{"/*", "*/"}, quotes: `"`,
+			keywords: words("as async await break const continue crate dyn else enum extern false fn for if impl in let loop match mod move mut pub ref return self Self static struct super trait true type unsafe use where while Some None Ok Err Option Result String Vec Box i8 i16 i32 i64 i128 isize u8 u16 u32 u64 u128 usize f32 f64 bool char str"),
+		},
+		"ruby": {
+			lineComments: []string{"#"}, quotes: `"'`,
+			keywords: words("alias and begin break case class def do else elsif end ensure false for if in module next nil not or redo rescue retry return self super then true undef unless until when while yield require attr_reader attr_accessor"),
+		},
+		"php": {
+			lineComments: []string{"//", "#"}, block: [2]string{"/*", "*/"}, quotes: `"'`,
+			keywords: words("abstract and array as break case catch class clone const continue declare default do echo else elseif empty extends final finally fn for foreach function global if implements include interface isset list match namespace new null or private protected public require return static switch throw trait true false try use var while yield"),
+		},
+		"swift": {
+			lineComments: []string{"//"}, block: [2]string{"/*", "*/"}, quotes: `"`,
+			keywords: words("as associatedtype break case catch class continue default defer do else enum extension false fileprivate for func guard if import in init inout internal is let nil open operator private protocol public repeat rethrows return self Self static struct subscript super switch throw throws true try typealias var where while async await"),
+		},
+		"bash": {
+			lineComments: []string{"#"}, quotes: `"'`,
+			keywords: words("if then else elif fi case esac for while until do done in function return local export readonly set unset shift exit echo source"),
+		},
+		"sql": {
+			lineComments: []string{"--"}, block: [2]string{"/*", "*/"}, quotes: `'"`,
+			keywords: words("select from where and or not insert into values update set delete create table index view drop alter add primary key foreign references join left right inner outer on group by order having limit offset as distinct null is in like between case when then else end union all exists default unique constraint SELECT FROM WHERE AND OR NOT INSERT INTO VALUES UPDATE SET DELETE CREATE TABLE INDEX VIEW DROP ALTER ADD PRIMARY KEY FOREIGN REFERENCES JOIN LEFT RIGHT INNER OUTER ON GROUP BY ORDER HAVING LIMIT OFFSET AS DISTINCT NULL IS IN LIKE BETWEEN CASE WHEN THEN ELSE END UNION ALL EXISTS DEFAULT UNIQUE CONSTRAINT"),
+		},
+		"yaml": {
+			lineComments: []string{"#"}, quotes: `"'`,
+			keywords: words("true false null yes no on off"),
+		},
+		"toml": {
+			lineComments: []string{"#"}, quotes: `"'`, triple: true,
+			keywords: words("true false"),
+		},
+		"json": {
+			quotes:   `"`,
+			keywords: words("true false null"),
+		},
+		"css": {
+			block: [2]string{"/*", "*/"}, quotes: `"'`,
+			keywords: words("@media @import @keyframes @supports !important"),
+		},
+	}
+
+	extLanguages = map[string]string{
+		".go": "go", ".py": "python", ".pyi": "python",
+		".js": "javascript", ".mjs": "javascript", ".cjs": "javascript", ".jsx": "javascript",
+		".ts": "typescript", ".tsx": "typescript", ".mts": "typescript",
+		".java": "java", ".kt": "kotlin", ".kts": "kotlin",
+		".c": "c", ".h": "c", ".cc": "cpp", ".cpp": "cpp", ".cxx": "cpp", ".hpp": "cpp", ".hh": "cpp",
+		".cs": "csharp", ".rs": "rust", ".rb": "ruby", ".php": "php", ".swift": "swift",
+		".sh": "bash", ".bash": "bash", ".zsh": "bash",
+		".sql": "sql", ".yaml": "yaml", ".yml": "yaml", ".toml": "toml", ".json": "json",
+		".css": "css", ".scss": "css",
+		".md": "markdown", ".html": "html", ".xml": "xml", ".proto": "protobuf",
+	}
+
+	// languageAliases maps the language names entities use, e.g. graph2md's
+	// "TypeScript" or "C++", to the highlighter's.
+	languageAliases = map[string]string{
+		"golang": "go", "py": "python", "js": "javascript", "ts": "typescript",
+		"c++": "cpp", "c#": "csharp", "shell": "bash", "sh": "bash", "yml": "yaml",
+	}
+)
+
+// Language names the language of a file for highlighting and for the
+// language-* class on its code block, from its extension or, failing
+// that, the entity's declared language. It returns "text" when neither
+// is known.
+func Language(file, declared string) string {
+	if lang, ok := extLanguages[strings.ToLower(path.Ext(file))]; ok {
+		return lang
+	}
+	switch base := path.Base(file); {
+	case base == "Dockerfile" || base == "Makefile":
+		return "bash"
+	}
+	lang := strings.ToLower(strings.TrimSpace(declared))
+	if alias, ok := languageAliases[lang]; ok {
+		return alias
+	}
+	if _, ok := syntaxes[lang]; ok {
+		return lang
+	}
+	return "text"
+}
+
+// Highlight escapes src and wraps its tokens in <span class="tok-…">
+// elements (kw, str, com, num, fn), one template.HTML per line so callers
+// can number lines or cut excerpts. Spans never cross lines. Languages
+// the highlighter does not know are only escaped.
+func Highlight(src, lang string) []template.HTML {
+	src = strings.ReplaceAll(src, "\r\n", "\n")
+	src = strings.TrimSuffix(src, "\n")
+	var lines []template.HTML
+	var line strings.Builder
+	emit := func(class, text string) {
+		for {
+			seg, rest, more := strings.Cut(text, "\n")
+			if seg != "" {
+				if class != "" {
+					line.WriteString(`<span class="tok-` + class + `">`)
+					line.WriteString(template.HTMLEscapeString(seg))
+					line.WriteString(`</span>`)
+				} else {
+					line.WriteString(template.HTMLEscapeString(seg))
+				}
+			}
+			if !more {
+				return
+			}
+			lines = append(lines, template.HTML(line.String()))
+			line.Reset()
+			text = rest
+		}
+	}
+
+	syn := syntaxes[lang]
+	if syn == nil {
+		emit("", src)
+		return append(lines, template.HTML(line.String()))
+	}
+	for i := 0; i < len(src); {
+		class, n := syn.token(src[i:])
+		emit(class, src[i:i+n])
+		i += n
+	}
+	return append(lines, template.HTML(line.String()))
+}
+
+// token returns the class and length of the token at the start of s.
+func (syn *syntax) token(s string) (string, int) {
+	if open := syn.block[0]; open != "" && strings.HasPrefix(s, open) {
+		if end := strings.Index(s[len(open):], syn.block[1]); end >= 0 {
+			return "com", len(open) + end + len(syn.block[1])
+		}
+		return "com", len(s)
+	}
+	for _, lc := range syn.lineComments {
+		if strings.HasPrefix(s, lc) {
+			if end := strings.IndexByte(s, '\n'); end >= 0 {
+				return "com", end
+			}
+			return "com", len(s)
+		}
+	}
+	if syn.triple && (strings.HasPrefix(s, `"""`) || strings.HasPrefix(s, "'''")) {
+		if end := strings.Index(s[3:], s[:3]); end >= 0 {
+			return "str", end + 6
+		}
+		return "str", len(s)
+	}
+
+	c := s[0]
+	if strings.IndexByte(syn.quotes, c) >= 0 {
+		return "str", syn.stringLen(s)
+	}
+	if c >= '0' && c <= '9' {
+		n := 1
+		for n < len(s) && (isIdent(s[n]) || s[n] == '.' && n+1 < len(s) && s[n+1] >= '0' && s[n+1] <= '9') {
+			n++
+		}
+		return "num", n
+	}
+	r, size := utf8.DecodeRuneInString(s)
+	if r == '_' || unicode.IsLetter(r) || (c == '#' || c == '@' || c == '!') && len(syn.keywords) > 0 {
+		n := size
+		for n < len(s) {
+			r, size := utf8.DecodeRuneInString(s[n:])
+			if r != '_' && !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+				break
+			}
+			n += size
+		}
+		word := s[:n]
+		if syn.keywords[word] {
+			return "kw", n
+		}
+		if word[0] == '#' || word[0] == '@' || word[0] == '!' {
+			return "", 1
+		}
+		if rest := strings.TrimLeft(s[n:], " "); strings.HasPrefix(rest, "(") {
+			return "fn", n
+		}
+		return "", n
+	}
+	return "", size
+}
+
+// stringLen measures the string literal at the start of s. Unterminated
+// strings end at the end of the line.
+func (syn *syntax) stringLen(s string) int {
+	q := s[0]
+	escapes := q != syn.raw
+	multi := q == syn.raw || q == syn.multiline
+	for i := 1; i < len(s); i++ {
+		switch {
+		case escapes && s[i] == '\\':
+			i++
+		case s[i] == q:
+			return i + 1
+		case s[i] == '\n' && !multi:
+			return i
+		}
+	}
+	return len(s)
+}
+
+func isIdent(c byte) bool {
+	return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
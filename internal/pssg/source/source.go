@@ -0,0 +1,264 @@
+// Package source publishes the code entities describe: highlighted
+// excerpts for entity pages, and a page per referenced file or directory
+// that links back to the entities citing it.
+package source
+
+import (
+	"fmt"
+	"html/template"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/supermodeltools/arch-docs/internal/pssg/config"
+	"github.com/supermodeltools/arch-docs/internal/pssg/entity"
+	"github.com/supermodeltools/arch-docs/internal/pssg/logging"
+)
+
+// Line is one highlighted source line.
+type Line struct {
+	Number int
+	HTML   template.HTML
+}
+
+// File is a source file with a page of its own.
+type File struct {
+	Path     string // slash-separated, relative to paths.source_dir
+	Page     string // site-relative page path, e.g. /source/cmd/main.go.html
+	URL      string
+	Lang     string
+	Size     int64
+	TooLarge bool             // over source.max_bytes: listed, not rendered
+	Entities []*entity.Entity // entities naming the file, by slug
+
+	full  string
+	out   string // page path under the output directory
+	once  sync.Once
+	lines []Line
+	err   error
+}
+
+// Name is the file's base name.
+func (f *File) Name() string { return path.Base(f.Path) }
+
+// Lines reads and highlights the file once, however many pages use it.
+func (f *File) Lines() ([]Line, error) {
+	f.once.Do(func() {
+		if f.TooLarge {
+			return
+		}
+		data, err := os.ReadFile(f.full)
+		if err != nil {
+			f.err = err
+			return
+		}
+		for i, html := range Highlight(string(data), f.Lang) {
+			f.lines = append(f.lines, Line{Number: i + 1, HTML: html})
+		}
+	})
+	return f.lines, f.err
+}
+
+// Dir is a source directory with a listing page.
+type Dir struct {
+	Path     string
+	Page     string
+	URL      string
+	Files    []*File
+	Entities []*entity.Entity
+
+	out string
+}
+
+// OutputPath is where the file's page is written under outDir.
+func (f *File) OutputPath(outDir string) string {
+	return filepath.Join(outDir, filepath.FromSlash(f.out))
+}
+
+// OutputPath is where the directory's page is written under outDir.
+func (d *Dir) OutputPath(outDir string) string {
+	return filepath.Join(outDir, filepath.FromSlash(d.out))
+}
+
+// Name is the directory's base name, or "/" for paths.source_dir itself.
+func (d *Dir) Name() string {
+	if d.Path == "." {
+		return "/"
+	}
+	return path.Base(d.Path)
+}
+
+// Ref is what an entity's source field points at.
+type Ref struct {
+	File       *File // nil for directories
+	Dir        *Dir
+	Start, End int // cited lines, 0 when the whole file is meant
+}
+
+// URL links to the file or directory page, at the cited lines if any.
+func (r Ref) URL() string {
+	if r.File == nil {
+		return r.Dir.URL
+	}
+	if r.Start > 0 {
+		return fmt.Sprintf("%s#L%d", r.File.URL, r.Start)
+	}
+	return r.File.URL
+}
+
+// Index maps entities to the source they name.
+type Index struct {
+	Files []*File // sorted by path
+	Dirs  []*Dir
+
+	cfg     config.SourceConfig
+	root    string
+	baseURL string
+	refs    map[string]Ref
+}
+
+// Build resolves each entity's source field against paths.source_dir. Values
+// outside the root, and paths that do not exist, are logged and skipped.
+// Directory pages list the files directly inside, so a directory entity
+// publishes those files too.
+func Build(entities []*entity.Entity, cfg *config.Config) *Index {
+	idx := &Index{cfg: cfg.Source, root: cfg.Paths.SourceDir, baseURL: cfg.Site.BaseURL, refs: make(map[string]Ref)}
+	if cfg.I18n.RootURL != "" {
+		// Editions link to the root edition's source pages.
+		idx.baseURL = cfg.I18n.RootURL
+	}
+	log := logging.Stage("source")
+	files := make(map[string]*File)
+	dirs := make(map[string]*Dir)
+
+	file := func(rel string, info os.FileInfo, declared string) *File {
+		f, ok := files[rel]
+		if !ok {
+			out := path.Join(idx.cfg.Dir, rel+".html")
+			f = &File{
+				Path:     rel,
+				Page:     pagePath(out),
+				URL:      idx.baseURL + pagePath(out),
+				out:      out,
+				Lang:     Language(rel, declared),
+				Size:     info.Size(),
+				TooLarge: info.Size() > int64(idx.cfg.MaxBytes),
+				full:     filepath.Join(idx.root, filepath.FromSlash(rel)),
+			}
+			files[rel] = f
+		}
+		return f
+	}
+
+	for _, e := range entities {
+		value := e.GetString(idx.cfg.Field)
+		if value == "" {
+			continue
+		}
+		rel := strings.TrimPrefix(path.Clean(filepath.ToSlash(value)), "/")
+		if rel == "" {
+			rel = "."
+		}
+		if rel == ".." || strings.HasPrefix(rel, "../") {
+			log.Warn("Source path leaves paths.source_dir", "slug", e.Slug, "path", value)
+			continue
+		}
+		info, err := os.Stat(filepath.Join(idx.root, filepath.FromSlash(rel)))
+		if err != nil {
+			log.Warn("Source path not found", "slug", e.Slug, "path", value)
+			continue
+		}
+
+		if !info.IsDir() {
+			f := file(rel, info, e.GetString("language"))
+			f.Entities = append(f.Entities, e)
+			ref := Ref{File: f, Start: e.GetInt(idx.cfg.StartField), End: e.GetInt(idx.cfg.EndField)}
+			if ref.Start < 0 || ref.End < 0 {
+				log.Warn("Ignoring negative source line", "slug", e.Slug, "start", ref.Start, "end", ref.End)
+				ref.Start, ref.End = max(ref.Start, 0), max(ref.End, 0)
+			}
+			if ref.End < ref.Start {
+				ref.End = ref.Start
+			}
+			idx.refs[e.Slug] = ref
+			continue
+		}
+
+		d, ok := dirs[rel]
+		if !ok {
+			out := path.Join(idx.cfg.Dir, rel, "index.html")
+			d = &Dir{Path: rel, Page: pagePath(out), URL: idx.baseURL + pagePath(out), out: out}
+			entries, err := os.ReadDir(filepath.Join(idx.root, filepath.FromSlash(rel)))
+			if err != nil {
+				log.Warn("Failed to list source directory", "path", value, "error", err)
+			}
+			for _, entry := range entries {
+				if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") || !entry.Type().IsRegular() {
+					continue
+				}
+				info, err := entry.Info()
+				if err != nil {
+					continue
+				}
+				d.Files = append(d.Files, file(path.Join(rel, entry.Name()), info, e.GetString("language")))
+			}
+			dirs[rel] = d
+		}
+		d.Entities = append(d.Entities, e)
+		idx.refs[e.Slug] = Ref{Dir: d}
+	}
+
+	for _, f := range files {
+		sortBySlug(f.Entities)
+		idx.Files = append(idx.Files, f)
+	}
+	for _, d := range dirs {
+		sortBySlug(d.Entities)
+		idx.Dirs = append(idx.Dirs, d)
+	}
+	sort.Slice(idx.Files, func(i, j int) bool { return idx.Files[i].Path < idx.Files[j].Path })
+	sort.Slice(idx.Dirs, func(i, j int) bool { return idx.Dirs[i].Path < idx.Dirs[j].Path })
+	return idx
+}
+
+// Ref returns what an entity's source field points at.
+func (idx *Index) Ref(slug string) (Ref, bool) {
+	r, ok := idx.refs[slug]
+	return r, ok
+}
+
+// Excerpt returns the highlighted lines an entity cites, joined for a
+// <pre> block, or "" when it cites a whole file or a directory.
+func (idx *Index) Excerpt(slug string) (template.HTML, string, error) {
+	r, ok := idx.refs[slug]
+	if !ok || r.File == nil || r.Start == 0 {
+		return "", "", nil
+	}
+	lines, err := r.File.Lines()
+	if err != nil || r.Start > len(lines) {
+		return "", "", err
+	}
+	end := min(r.End, len(lines))
+	parts := make([]string, 0, end-r.Start+1)
+	for _, l := range lines[r.Start-1 : end] {
+		parts = append(parts, string(l.HTML))
+	}
+	return template.HTML(strings.Join(parts, "\n")), r.File.Lang, nil
+}
+
+// pagePath escapes each segment of an output path for use in URLs.
+func pagePath(out string) string {
+	segs := strings.Split(out, "/")
+	for i, s := range segs {
+		segs[i] = url.PathEscape(s)
+	}
+	return "/" + strings.Join(segs, "/")
+}
+
+func sortBySlug(es []*entity.Entity) {
+	sort.Slice(es, func(i, j int) bool { return es[i].Slug < es[j].Slug })
+}
@@ -0,0 +1,227 @@
+package stats
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"unicode/utf8"
+
+	"github.com/supermodeltools/arch-docs/internal/pssg/config"
+	"github.com/supermodeltools/arch-docs/internal/pssg/entity"
+	"github.com/supermodeltools/arch-docs/internal/pssg/taxonomy"
+)
+
+// Report summarizes a site's content.
+type Report struct {
+	Entities           int                 `json:"entities"`
+	ByType             []Count             `json:"by_type"`
+	Taxonomies         []TaxonomyStats     `json:"taxonomies"`
+	FieldFill          []FieldFill         `json:"field_fill"`
+	Orphans            []string            `json:"orphans"` // slugs that appear in no taxonomy entry
+	AvgDescriptionLen  float64             `json:"avg_description_length"`
+	MissingDescription int                 `json:"missing_description"`
+	Enrichment         *EnrichmentCoverage `json:"enrichment,omitempty"`
+	Ownership          *OwnershipCoverage  `json:"ownership,omitempty"`
+}
+
+// Count is a name with a number of entities.
+type Count struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// TaxonomyStats lists the terms of one taxonomy, largest first, and the
+// names that share a slug.
+type TaxonomyStats struct {
+	Name       string               `json:"name"`
+	Terms      []Count              `json:"terms"`
+	Collisions []taxonomy.Collision `json:"collisions,omitempty"`
+}
+
+// FieldFill is the share of entities with a non-empty value for a field.
+type FieldFill struct {
+	Field  string  `json:"field"`
+	Filled int     `json:"filled"`
+	Rate   float64 `json:"rate"`
+}
+
+// EnrichmentCoverage counts entities with an enrichment cache entry.
+type EnrichmentCoverage struct {
+	Enriched int      `json:"enriched"`
+	Rate     float64  `json:"rate"`
+	Missing  []string `json:"missing"`
+}
+
+// OwnershipCoverage counts entities with a code owner, when owners are
+// enabled.
+type OwnershipCoverage struct {
+	Owned   int      `json:"owned"`
+	Rate    float64  `json:"rate"`
+	Unowned []string `json:"unowned"`
+}
+
+// Compute builds the report. enrichmentData may be nil when the site has no
+// enrichment cache, in which case coverage is omitted.
+func Compute(cfg *config.Config, entities []*entity.Entity, taxonomies []taxonomy.Taxonomy, enrichmentData map[string]map[string]interface{}) Report {
+	r := Report{Entities: len(entities)}
+	if len(entities) == 0 {
+		return r
+	}
+	total := float64(len(entities))
+
+	byType := make(map[string]int)
+	filled := make(map[string]int)
+	descLen := 0
+	for _, e := range entities {
+		typ := e.GetString(cfg.Data.Validation.TypeField)
+		if typ == "" {
+			typ = "(none)"
+		}
+		byType[typ]++
+		for field := range e.Fields {
+			if e.HasField(field) {
+				filled[field]++
+			}
+		}
+		if d := e.GetString("description"); d != "" {
+			descLen += utf8.RuneCountInString(d)
+		} else {
+			r.MissingDescription++
+		}
+	}
+	r.ByType = sortedCounts(byType)
+	if n := len(entities) - r.MissingDescription; n > 0 {
+		r.AvgDescriptionLen = float64(descLen) / float64(n)
+	}
+
+	for field, n := range filled {
+		r.FieldFill = append(r.FieldFill, FieldFill{Field: field, Filled: n, Rate: float64(n) / total})
+	}
+	sort.Slice(r.FieldFill, func(i, j int) bool {
+		if r.FieldFill[i].Filled != r.FieldFill[j].Filled {
+			return r.FieldFill[i].Filled > r.FieldFill[j].Filled
+		}
+		return r.FieldFill[i].Field < r.FieldFill[j].Field
+	})
+
+	inTaxonomy := make(map[string]bool)
+	for _, tax := range taxonomies {
+		terms := make(map[string]int, len(tax.Entries))
+		for _, entry := range tax.Entries {
+			terms[entry.Name] = len(entry.Entities)
+			for _, e := range entry.Entities {
+				inTaxonomy[e.Slug] = true
+			}
+		}
+		r.Taxonomies = append(r.Taxonomies, TaxonomyStats{Name: tax.Name, Terms: sortedCounts(terms), Collisions: tax.Collisions})
+	}
+	if len(taxonomies) > 0 {
+		for _, e := range entities {
+			if !inTaxonomy[e.Slug] {
+				r.Orphans = append(r.Orphans, e.Slug)
+			}
+		}
+		sort.Strings(r.Orphans)
+	}
+
+	if enrichmentData != nil {
+		cov := &EnrichmentCoverage{}
+		for _, e := range entities {
+			if _, ok := enrichmentData[e.Slug]; ok {
+				cov.Enriched++
+			} else {
+				cov.Missing = append(cov.Missing, e.Slug)
+			}
+		}
+		sort.Strings(cov.Missing)
+		cov.Rate = float64(cov.Enriched) / total
+		r.Enrichment = cov
+	}
+
+	if cfg.Owners.Enabled {
+		cov := &OwnershipCoverage{}
+		for _, e := range entities {
+			if e.HasField(cfg.Owners.Field) {
+				cov.Owned++
+			} else {
+				cov.Unowned = append(cov.Unowned, e.Slug)
+			}
+		}
+		sort.Strings(cov.Unowned)
+		cov.Rate = float64(cov.Owned) / total
+		r.Ownership = cov
+	}
+	return r
+}
+
+// WriteText prints the report for a terminal. Long lists are truncated to
+// limit items.
+func (r Report) WriteText(w io.Writer, limit int) {
+	fmt.Fprintf(w, "Entities: %d\n", r.Entities)
+	if r.Entities == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "\nBy type:\n")
+	for _, c := range r.ByType {
+		fmt.Fprintf(w, "  %-24s %d\n", c.Name, c.Count)
+	}
+
+	for _, tax := range r.Taxonomies {
+		fmt.Fprintf(w, "\nTaxonomy %s (%d terms):\n", tax.Name, len(tax.Terms))
+		for i, c := range tax.Terms {
+			if i == limit {
+				fmt.Fprintf(w, "  ... and %d more\n", len(tax.Terms)-i)
+				break
+			}
+			fmt.Fprintf(w, "  %-24s %d\n", c.Name, c.Count)
+		}
+		for _, c := range tax.Collisions {
+			fmt.Fprintf(w, "  slug %s is shared by %q\n", c.Slug, c.Names)
+		}
+	}
+
+	fmt.Fprintf(w, "\nField fill rates:\n")
+	for _, f := range r.FieldFill {
+		fmt.Fprintf(w, "  %-24s %5.1f%% (%d)\n", f.Field, f.Rate*100, f.Filled)
+	}
+
+	fmt.Fprintf(w, "\nDescriptions: avg %.0f chars, %d missing\n", r.AvgDescriptionLen, r.MissingDescription)
+
+	if len(r.Taxonomies) > 0 {
+		fmt.Fprintf(w, "Orphans (in no taxonomy): %d\n", len(r.Orphans))
+		printList(w, r.Orphans, limit)
+	}
+	if r.Enrichment != nil {
+		fmt.Fprintf(w, "Enrichment coverage: %.1f%% (%d/%d)\n", r.Enrichment.Rate*100, r.Enrichment.Enriched, r.Entities)
+		printList(w, r.Enrichment.Missing, limit)
+	}
+	if r.Ownership != nil {
+		fmt.Fprintf(w, "Ownership: %.1f%% (%d/%d)\n", r.Ownership.Rate*100, r.Ownership.Owned, r.Entities)
+		printList(w, r.Ownership.Unowned, limit)
+	}
+}
+
+func printList(w io.Writer, items []string, limit int) {
+	for i, s := range items {
+		if i == limit {
+			fmt.Fprintf(w, "  ... and %d more\n", len(items)-i)
+			return
+		}
+		fmt.Fprintf(w, "  %s\n", s)
+	}
+}
+
+func sortedCounts(m map[string]int) []Count {
+	counts := make([]Count, 0, len(m))
+	for name, n := range m {
+		counts = append(counts, Count{Name: name, Count: n})
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		return counts[i].Name < counts[j].Name
+	})
+	return counts
+}
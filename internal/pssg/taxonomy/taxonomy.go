@@ -3,6 +3,7 @@ package taxonomy
 import (
 	"fmt"
 	"sort"
+	"strconv"
 	"strings"
 	"unicode"
 
@@ -24,6 +25,19 @@ type Taxonomy struct {
 	LabelSingular string
 	Config        config.TaxonomyConfig
 	Entries       []Entry
+	// Sparse are the entries with fewer than min_entities entities, which
+	// get no hub unless the taxonomy's sparse setting asks for one.
+	Sparse []Entry
+	// Collisions are the sets of distinct names that share a slug.
+	Collisions []Collision
+}
+
+// Collision is a set of distinct term names that map to the same slug,
+// such as "Stir Fry" and "Stir-Fry".
+type Collision struct {
+	Slug  string   `json:"slug"`
+	Names []string `json:"names"`           // canonical first: the name most entities use
+	Slugs []string `json:"slugs,omitempty"` // with collisions: suffix, the slug each name got
 }
 
 // PaginationInfo holds pagination state for hub pages.
@@ -63,8 +77,10 @@ func BuildAll(entities []*entity.Entity, taxConfigs []config.TaxonomyConfig, enr
 }
 
 func buildOne(entities []*entity.Entity, tc config.TaxonomyConfig, enrichmentData map[string]map[string]interface{}) Taxonomy {
-	// Group entities by field values
+	// Group entities by field values, and each slug's entities by the
+	// name they give it
 	groups := make(map[string]*Entry)
+	named := make(map[string]map[string][]*entity.Entity)
 
 	for _, e := range entities {
 		values := extractValues(e, tc, enrichmentData)
@@ -85,23 +101,34 @@ func buildOne(entities []*entity.Entity, tc config.TaxonomyConfig, enrichmentDat
 					Name: val,
 					Slug: slug,
 				}
+				named[slug] = make(map[string][]*entity.Entity)
+			}
+			if n := len(groups[slug].Entities); n == 0 || groups[slug].Entities[n-1] != e {
+				groups[slug].Entities = append(groups[slug].Entities, e)
 			}
-			groups[slug].Entities = append(groups[slug].Entities, e)
+			named[slug][val] = append(named[slug][val], e)
+		}
+	}
+	collisions := resolveCollisions(groups, named, tc)
+
+	// Buckets nothing falls into are still entries, if empty ones
+	for _, b := range tc.Buckets {
+		if slug := entity.ToSlug(b.Name); slug != "" && groups[slug] == nil {
+			groups[slug] = &Entry{Name: b.Name, Slug: slug}
 		}
 	}
 
 	// Convert to slice and filter by min_entities
-	var entries []Entry
+	var entries, sparse []Entry
 	for _, entry := range groups {
 		if len(entry.Entities) >= tc.MinEntities {
 			entries = append(entries, *entry)
+		} else {
+			sparse = append(sparse, *entry)
 		}
 	}
-
-	// Sort alphabetically by slug
-	sort.Slice(entries, func(i, j int) bool {
-		return entries[i].Slug < entries[j].Slug
-	})
+	sortEntries(entries, tc)
+	sortEntries(sparse, tc)
 
 	return Taxonomy{
 		Name:          tc.Name,
@@ -109,11 +136,86 @@ func buildOne(entities []*entity.Entity, tc config.TaxonomyConfig, enrichmentDat
 		LabelSingular: tc.LabelSingular,
 		Config:        tc,
 		Entries:       entries,
+		Sparse:        sparse,
+		Collisions:    collisions,
+	}
+}
+
+// resolveCollisions settles slugs that several names map to. The
+// canonical name is the one most entities use, or the first
+// alphabetically on a tie, so the result does not depend on load order.
+// With collisions: suffix each other name becomes an entry of its own
+// under the slug plus "-2", "-3" and so on; otherwise the entry takes the
+// canonical name and keeps every entity.
+func resolveCollisions(groups map[string]*Entry, named map[string]map[string][]*entity.Entity, tc config.TaxonomyConfig) []Collision {
+	slugs := make([]string, 0, len(named))
+	for slug, names := range named {
+		if len(names) > 1 {
+			slugs = append(slugs, slug)
+		}
+	}
+	sort.Strings(slugs)
+
+	var collisions []Collision
+	for _, slug := range slugs {
+		byName := named[slug]
+		names := make([]string, 0, len(byName))
+		for name := range byName {
+			names = append(names, name)
+		}
+		sort.Slice(names, func(i, j int) bool {
+			if len(byName[names[i]]) != len(byName[names[j]]) {
+				return len(byName[names[i]]) > len(byName[names[j]])
+			}
+			return names[i] < names[j]
+		})
+		c := Collision{Slug: slug, Names: names}
+		if tc.Collisions != "suffix" {
+			groups[slug].Name = names[0]
+			collisions = append(collisions, c)
+			continue
+		}
+		groups[slug] = &Entry{Name: names[0], Slug: slug, Entities: byName[names[0]]}
+		c.Slugs = []string{slug}
+		n := 2
+		for _, name := range names[1:] {
+			s := fmt.Sprintf("%s-%d", slug, n)
+			for groups[s] != nil {
+				n++
+				s = fmt.Sprintf("%s-%d", slug, n)
+			}
+			n++
+			groups[s] = &Entry{Name: name, Slug: s, Entities: byName[name]}
+			c.Slugs = append(c.Slugs, s)
+		}
+		collisions = append(collisions, c)
+	}
+	return collisions
+}
+
+// sortEntries sorts entries alphabetically by slug, except that buckets
+// keep the order they are configured in, e.g. high to low.
+func sortEntries(entries []Entry, tc config.TaxonomyConfig) {
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Slug < entries[j].Slug
+	})
+	if len(tc.Buckets) > 0 {
+		order := make(map[string]int, len(tc.Buckets))
+		for i, b := range tc.Buckets {
+			order[b.Name] = i
+		}
+		sort.SliceStable(entries, func(i, j int) bool {
+			return order[entries[i].Name] < order[entries[j].Name]
+		})
 	}
 }
 
 // extractValues gets the taxonomy values from an entity's field.
 func extractValues(e *entity.Entity, tc config.TaxonomyConfig, enrichmentData map[string]map[string]interface{}) []string {
+	if len(tc.Buckets) > 0 {
+		return bucketValues(e, tc, enrichmentData)
+	}
+
 	// Check for enrichment overrides
 	if tc.EnrichmentOverrideField != "" && enrichmentData != nil {
 		if ed, ok := enrichmentData[e.Slug]; ok {
@@ -139,6 +241,42 @@ func extractValues(e *entity.Entity, tc config.TaxonomyConfig, enrichmentData ma
 	return nil
 }
 
+// bucketValues returns the name of the bucket an entity's number falls
+// in, or nil if it has none or no bucket holds it.
+func bucketValues(e *entity.Entity, tc config.TaxonomyConfig, enrichmentData map[string]map[string]interface{}) []string {
+	v := e.GetPath(tc.Field)
+	if tc.EnrichmentOverrideField != "" {
+		if ev := entity.LookupPath(map[string]interface{}(enrichmentData[e.Slug]), tc.EnrichmentOverrideField); ev != nil {
+			v = ev
+		}
+	}
+	n, ok := toNumber(v)
+	if !ok {
+		return nil
+	}
+	for _, b := range tc.Buckets {
+		if (b.Min == nil || n >= *b.Min) && (b.Max == nil || n < *b.Max) {
+			return []string{b.Name}
+		}
+	}
+	return nil
+}
+
+func toNumber(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case float64:
+		return n, true
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(n), 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
 // getEnrichmentOverrides extracts override values from enrichment data.
 // Supports paths like "ingredients[].normalizedName"
 func getEnrichmentOverrides(data map[string]interface{}, field string) []string {
@@ -0,0 +1,38 @@
+// Package text shortens strings for output with a limited length, such
+// as share images, search entries and meta descriptions.
+package text
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Ellipsis marks a truncated string.
+const Ellipsis = "…"
+
+// Truncate shortens s to at most max characters (runes, not bytes),
+// ellipsis included. It cuts at the last space when that keeps at least
+// half the text, so words are not split, and otherwise mid-word, as for
+// scripts written without spaces. A string that fits is returned as is.
+func Truncate(s string, max int) string {
+	if utf8.RuneCountInString(s) <= max {
+		return s
+	}
+	if max <= 0 {
+		return ""
+	}
+	r := []rune(s)
+	cut := max - 1
+	if !unicode.IsSpace(r[cut]) {
+		for i := cut - 1; i >= cut/2 && i > 0; i-- {
+			if unicode.IsSpace(r[i]) {
+				cut = i
+				break
+			}
+		}
+	}
+	return strings.TrimRightFunc(string(r[:cut]), func(c rune) bool {
+		return unicode.IsSpace(c) || strings.ContainsRune(",;:-–—", c)
+	}) + Ellipsis
+}
@@ -0,0 +1,87 @@
+package theme
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/supermodeltools/arch-docs/internal/pssg/config"
+	"github.com/supermodeltools/arch-docs/internal/pssg/scaffold"
+)
+
+// A theme is a directory with a templates/ and an optional static/
+// subdirectory. Its files form a base layer: a site template or static file
+// with the same name replaces the theme's copy.
+
+// Open returns the configured theme, or nil if the site has none. The name
+// config.DefaultTheme selects the built-in theme (the `pssg init` starter);
+// anything else is a directory path.
+func Open(cfg *config.Config) (fs.FS, error) {
+	switch cfg.Theme {
+	case "":
+		return nil, nil
+	case config.DefaultTheme:
+		return scaffold.Files(), nil
+	}
+	info, err := os.Stat(cfg.Theme)
+	if err != nil {
+		return nil, fmt.Errorf("opening theme: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("opening theme: %s is not a directory", cfg.Theme)
+	}
+	return os.DirFS(cfg.Theme), nil
+}
+
+// Templates returns the theme's template files keyed by name.
+func Templates(t fs.FS) (map[string][]byte, error) {
+	entries, err := fs.ReadDir(t, "templates")
+	if err != nil {
+		return nil, fmt.Errorf("reading theme templates: %w", err)
+	}
+	files := make(map[string][]byte, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		data, err := fs.ReadFile(t, "templates/"+entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("reading theme template %s: %w", entry.Name(), err)
+		}
+		files[entry.Name()] = data
+	}
+	return files, nil
+}
+
+// HasTemplate reports whether the theme provides the named template.
+func HasTemplate(t fs.FS, name string) bool {
+	if t == nil {
+		return false
+	}
+	_, err := fs.Stat(t, "templates/"+name)
+	return err == nil
+}
+
+// CopyStatic copies the theme's static/ directory into dst. It runs before
+// the site's own static files are copied so those win.
+func CopyStatic(t fs.FS, dst string) error {
+	if _, err := fs.Stat(t, "static"); err != nil {
+		return nil
+	}
+	return fs.WalkDir(t, "static", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel("static", filepath.FromSlash(path))
+		target := filepath.Join(dst, rel)
+		if d.IsDir() {
+			return os.MkdirAll(target, 0755)
+		}
+		data, err := fs.ReadFile(t, path)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(target, data, 0644)
+	})
+}
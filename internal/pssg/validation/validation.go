@@ -0,0 +1,128 @@
+package validation
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/supermodeltools/arch-docs/internal/pssg/config"
+	"github.com/supermodeltools/arch-docs/internal/pssg/entity"
+)
+
+// Issue is a single rule violation on an entity.
+type Issue struct {
+	Slug    string
+	Type    string
+	Field   string
+	Message string
+}
+
+func (i Issue) String() string {
+	return fmt.Sprintf("%s: %s %s", i.Slug, i.Field, i.Message)
+}
+
+// Check applies the configured profiles to every entity and returns all
+// violations, sorted by slug then field.
+func Check(entities []*entity.Entity, cfg config.ValidationConfig) []Issue {
+	if len(cfg.Profiles) == 0 {
+		return nil
+	}
+
+	profiles := make(map[string]config.ValidationProfile, len(cfg.Profiles))
+	for name, p := range cfg.Profiles {
+		profiles[strings.ToLower(name)] = p
+	}
+
+	var issues []Issue
+	for _, e := range entities {
+		typ := e.GetString(cfg.TypeField)
+		if p, ok := profiles["*"]; ok {
+			issues = append(issues, checkProfile(e, typ, p)...)
+		}
+		if p, ok := profiles[strings.ToLower(typ)]; ok && typ != "*" {
+			issues = append(issues, checkProfile(e, typ, p)...)
+		}
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		if issues[i].Slug != issues[j].Slug {
+			return issues[i].Slug < issues[j].Slug
+		}
+		return issues[i].Field < issues[j].Field
+	})
+	return issues
+}
+
+func checkProfile(e *entity.Entity, typ string, p config.ValidationProfile) []Issue {
+	var issues []Issue
+	add := func(field, format string, args ...interface{}) {
+		issues = append(issues, Issue{Slug: e.Slug, Type: typ, Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	for _, field := range p.Required {
+		if !e.HasField(field) {
+			add(field, "is required")
+		}
+	}
+
+	for field, allowed := range p.Enum {
+		for _, v := range values(e.Fields[field]) {
+			if !slices.Contains(allowed, v) {
+				add(field, "value %q is not one of %s", v, strings.Join(allowed, ", "))
+			}
+		}
+	}
+
+	for field, max := range p.MaxLength {
+		if n := utf8.RuneCountInString(e.GetString(field)); n > max {
+			add(field, "is %d characters, max %d", n, max)
+		}
+	}
+	return issues
+}
+
+// values flattens a scalar or list field into strings for enum checks.
+func values(v interface{}) []string {
+	switch val := v.(type) {
+	case nil:
+		return nil
+	case []interface{}:
+		out := make([]string, 0, len(val))
+		for _, item := range val {
+			out = append(out, fmt.Sprint(item))
+		}
+		return out
+	case []string:
+		return val
+	default:
+		return []string{fmt.Sprint(val)}
+	}
+}
+
+// Report groups issues by entity type for logging.
+func Report(issues []Issue) []string {
+	byType := make(map[string][]Issue)
+	var types []string
+	for _, is := range issues {
+		if _, ok := byType[is.Type]; !ok {
+			types = append(types, is.Type)
+		}
+		byType[is.Type] = append(byType[is.Type], is)
+	}
+	sort.Strings(types)
+
+	var lines []string
+	for _, t := range types {
+		label := t
+		if label == "" {
+			label = "(no type)"
+		}
+		lines = append(lines, fmt.Sprintf("%s (%d):", label, len(byType[t])))
+		for _, is := range byType[t] {
+			lines = append(lines, "  "+is.String())
+		}
+	}
+	return lines
+}
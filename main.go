@@ -7,9 +7,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"mime/multipart"
 	"net/http"
-	"net/url"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -19,6 +19,7 @@ import (
 	"github.com/supermodeltools/arch-docs/internal/graph2md"
 	"github.com/supermodeltools/arch-docs/internal/pssg/build"
 	"github.com/supermodeltools/arch-docs/internal/pssg/config"
+	"github.com/supermodeltools/arch-docs/internal/pssg/logging"
 )
 
 const apiBaseURL = "https://api.supermodeltools.com/v1/graphs/supermodel"
@@ -111,7 +112,7 @@ taxonomies:
     field: "node_type"
     multi_value: false
     min_entities: 1
-    index_description: "Browse by entity type"
+    index_description: "Browse by entity type. {{.Count}} categories available."
 
   - name: "language"
     label: "Languages"
@@ -119,7 +120,7 @@ taxonomies:
     field: "language"
     multi_value: false
     min_entities: 1
-    index_description: "Browse by programming language"
+    index_description: "Browse by programming language. {{.Count}} categories available."
 
   - name: "domain"
     label: "Domains"
@@ -127,7 +128,7 @@ taxonomies:
     field: "domain"
     multi_value: false
     min_entities: 1
-    index_description: "Browse by architectural domain"
+    index_description: "Browse by architectural domain. {{.Count}} categories available."
 
   - name: "subdomain"
     label: "Subdomains"
@@ -135,7 +136,7 @@ taxonomies:
     field: "subdomain"
     multi_value: false
     min_entities: 1
-    index_description: "Browse by architectural subdomain"
+    index_description: "Browse by architectural subdomain. {{.Count}} categories available."
 
   - name: "top_directory"
     label: "Top Directories"
@@ -143,7 +144,7 @@ taxonomies:
     field: "top_directory"
     multi_value: false
     min_entities: 1
-    index_description: "Browse by top-level directory"
+    index_description: "Browse by top-level directory. {{.Count}} categories available."
 
   - name: "extension"
     label: "File Extensions"
@@ -151,7 +152,7 @@ taxonomies:
     field: "extension"
     multi_value: false
     min_entities: 1
-    index_description: "Browse by file extension"
+    index_description: "Browse by file extension. {{.Count}} categories available."
 
   - name: "tags"
     label: "Tags"
@@ -159,7 +160,7 @@ taxonomies:
     field: "tags"
     multi_value: true
     min_entities: 1
-    index_description: "Browse by tag"
+    index_description: "Browse by tag. {{.Count}} categories available."
 
 pagination:
   per_page: 48
@@ -209,6 +210,7 @@ templates:
 output:
   clean_before_build: true
   extract_css: "styles.css"
+  critical_css: true
   extract_js: "main.js"
 
 extra:
@@ -377,6 +379,12 @@ func main() {
 	if err != nil {
 		fatal("Failed to load pssg config: %v", err)
 	}
+	// Debug-level build output when the workflow is re-run with debug logging
+	level := slog.LevelInfo
+	if os.Getenv("RUNNER_DEBUG") == "1" {
+		level = slog.LevelDebug
+	}
+	logging.Setup(os.Stderr, level, "text")
 	builder := build.NewBuilder(cfg, false)
 	if err := builder.Build(); err != nil {
 		fatal("pssg build failed: %v", err)
@@ -386,17 +394,6 @@ func main() {
 	fmt.Printf("Built %d HTML pages\n", pageCount)
 	logGroupEnd()
 
-	// Step 8b: Rewrite paths if base URL has a path prefix (e.g. GitHub Pages subdirectory)
-	pathPrefix := extractPathPrefix(baseURL)
-	if pathPrefix != "" {
-		logGroup("Rewriting paths for subdirectory deployment")
-		fmt.Printf("Path prefix: %s\n", pathPrefix)
-		if err := rewritePathPrefix(outputDir, pathPrefix); err != nil {
-			fatal("Failed to rewrite paths: %v", err)
-		}
-		logGroupEnd()
-	}
-
 	// Step 9: Set outputs
 	logGroup("Setting outputs")
 	absOutput, _ := filepath.Abs(outputDir)
@@ -733,65 +730,6 @@ func generateConfig(configPath, siteName, baseURL, repoURL, repoName, contentDir
 	return os.WriteFile(configPath, []byte(config), 0644)
 }
 
-// extractPathPrefix returns the path component of a URL (e.g. "/graph2md" from
-// "https://supermodeltools.github.io/graph2md"). Returns "" if no prefix.
-func extractPathPrefix(baseURL string) string {
-	u, err := url.Parse(baseURL)
-	if err != nil {
-		return ""
-	}
-	p := strings.TrimRight(u.Path, "/")
-	if p == "" || p == "/" {
-		return ""
-	}
-	return p
-}
-
-// rewritePathPrefix rewrites all root-relative paths in HTML and JS files to
-// include the given prefix. This is needed when deploying to a subdirectory
-// (e.g. GitHub Pages project sites at username.github.io/repo/).
-func rewritePathPrefix(dir, prefix string) error {
-	return filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return nil
-		}
-		if info.IsDir() {
-			return nil
-		}
-
-		ext := strings.ToLower(filepath.Ext(path))
-		if ext != ".html" && ext != ".js" {
-			return nil
-		}
-
-		data, err := os.ReadFile(path)
-		if err != nil {
-			return nil
-		}
-
-		content := string(data)
-		original := content
-
-		// Rewrite href="/..." to href="/prefix/..."
-		content = strings.ReplaceAll(content, `href="/`, `href="`+prefix+`/`)
-		// Rewrite src="/..." to src="/prefix/..."
-		content = strings.ReplaceAll(content, `src="/`, `src="`+prefix+`/`)
-		// Rewrite fetch("/..." to fetch("/prefix/..."
-		content = strings.ReplaceAll(content, `fetch("/`, `fetch("`+prefix+`/`)
-		// Rewrite JS navigation: window.location.href = "/" + ...
-		content = strings.ReplaceAll(content, `window.location.href = "/"`, `window.location.href = "`+prefix+`/"`)
-		content = strings.ReplaceAll(content, `window.location.href = "/" + `, `window.location.href = "`+prefix+`/" + `)
-
-		if content != original {
-			if err := os.WriteFile(path, []byte(content), info.Mode()); err != nil {
-				return fmt.Errorf("writing %s: %w", path, err)
-			}
-		}
-
-		return nil
-	})
-}
-
 // fetchOrgCNAME fetches the raw CNAME file from the org's .github.io repo.
 // Returns the custom domain string or "" if not found.
 func fetchOrgCNAME(org string) string {
@@ -1,3 +1,7 @@
+var basePath = "{{basePath}}";
+// pagePattern is an entity page's path as urls.style writes it, "@" for its slug
+var pagePattern = "{{link "/@.html"}}";
+function pageURL(slug) { return basePath + pagePattern.replace("@", slug); }
 function toSlug(s) { return s.toLowerCase().replace(/[^a-z0-9]+/g, "-").replace(/^-+|-+$/g, ""); }
 
 window.addEventListener("load", function() {
@@ -35,7 +39,7 @@ window.addEventListener("load", function() {
             if (label.length > 16) label = label.substring(0, 14) + "..";
 
             if (items[i].slug && !isLast) {
-              svg += '<a href="/' + items[i].slug + '.html">';
+              svg += '<a href="' + pageURL(items[i].slug) + '">';
             }
             svg += '<rect x="' + x + '" y="' + y + '" width="' + boxW + '" height="' + boxH + '" rx="6" fill="' + fill + '" stroke="' + stroke + '" stroke-width="1"/>';
             svg += '<text x="' + (x + boxW / 2) + '" y="' + (y + boxH / 2 + 5) + '" text-anchor="middle" fill="' + textColor + '" font-size="12" font-family="Inter,system-ui,sans-serif">' + label + '</text>';
@@ -63,16 +67,13 @@ window.addEventListener("load", function() {
   // --- Force-Directed Graph (D3) — enriched nodes ---
   var graphDataEl = document.getElementById("graph-data");
   var graphEl = document.getElementById("force-graph");
-  if (graphDataEl && graphEl && typeof d3 !== "undefined") {
+  function drawForceGraph(graphData) {
     try {
-      var rawGraph = graphDataEl.textContent.trim();
-      var graphData = JSON.parse(rawGraph);
-      if (typeof graphData === "string") graphData = JSON.parse(graphData);
       var centerSlug = graphEl.getAttribute("data-center");
 
       if (graphData && graphData.nodes && graphData.nodes.length > 1) {
         var width = graphEl.clientWidth || 600;
-        var height = 420;
+        var height = graphEl.clientHeight || 420;
 
         var typeColors = {
           File: "#3b82f6", Function: "#22c55e", Class: "#f59e0b",
@@ -163,7 +164,7 @@ window.addEventListener("load", function() {
         });
 
         node.on("click", function(event, d) {
-          if (d.slug) window.location.href = "/" + d.slug + ".html";
+          if (d.slug) window.location.href = pageURL(d.slug);
         });
 
         simulation.on("tick", function() {
@@ -180,6 +181,23 @@ window.addEventListener("load", function() {
       console.error("Force graph error:", e);
     }
   }
+  if (graphEl && typeof d3 !== "undefined") {
+    if (graphDataEl) {
+      try {
+        var graphData = JSON.parse(graphDataEl.textContent.trim());
+        if (typeof graphData === "string") graphData = JSON.parse(graphData);
+        drawForceGraph(graphData);
+      } catch (e) {
+        console.error("Force graph error:", e);
+      }
+    } else if (graphEl.getAttribute("data-src")) {
+      // Relation graphs are separate JSON files, fetched when shown
+      fetch(graphEl.getAttribute("data-src"))
+        .then(function(r) { return r.json(); })
+        .then(drawForceGraph)
+        .catch(function(e) { console.error("Force graph error:", e); });
+    }
+  }
 
   // --- Entity Profile Chart (compact format) ---
   var epDataEl = document.getElementById("entity-profile-data");
@@ -291,7 +309,9 @@ window.addEventListener("load", function() {
   if (archOverDataEl && archOverEl && typeof d3 !== "undefined") {
     try {
       var archData = JSON.parse(archOverDataEl.textContent.trim());
+      if (typeof archData === "string") archData = JSON.parse(archData);
       if (archData && archData.nodes && archData.nodes.length > 1) {
+        var archLinks = archData.links || archData.edges || [];
         var aoW = archOverEl.clientWidth || 800;
         var aoH = 420;
         var aoTypeColors = { root: "#6366f1", domain: "#3b82f6", subdomain: "#a855f7" };
@@ -301,14 +321,14 @@ window.addEventListener("load", function() {
         var radiusScale = d3.scaleSqrt().domain([0, maxCount]).range([8, 36]);
 
         var aoSim = d3.forceSimulation(archData.nodes)
-          .force("link", d3.forceLink(archData.links).id(function(d) { return d.id; }).distance(function(d) {
+          .force("link", d3.forceLink(archLinks).id(function(d) { return d.id; }).distance(function(d) {
             return d.source.type === "root" || d.source === "root" ? 140 : 90;
           }))
           .force("charge", d3.forceManyBody().strength(-300))
           .force("center", d3.forceCenter(aoW / 2, aoH / 2))
           .force("collision", d3.forceCollide().radius(function(d) { return radiusScale(d.count) + 12; }));
 
-        var aoLink = aoSvg.append("g").selectAll("line").data(archData.links).enter().append("line")
+        var aoLink = aoSvg.append("g").selectAll("line").data(archLinks).enter().append("line")
           .attr("stroke", "#2a2e3e").attr("stroke-opacity", 0.6).attr("stroke-width", 1.5);
 
         var aoNode = aoSvg.append("g").selectAll("g").data(archData.nodes).enter().append("g")
@@ -333,7 +353,7 @@ window.addEventListener("load", function() {
           .attr("stroke-width", function(d) { return d.type === "root" ? 2 : 0; });
 
         aoNode.append("text")
-          .text(function(d) { var l = d.name; return l.length > 20 ? l.substring(0, 18) + ".." : l; })
+          .text(function(d) { var l = d.name || d.label || ""; return l.length > 20 ? l.substring(0, 18) + ".." : l; })
           .attr("x", 0)
           .attr("y", function(d) { return (d.type === "root" ? 24 : radiusScale(d.count)) + 14; })
           .attr("text-anchor", "middle").attr("fill", "#9ca3af")
@@ -348,11 +368,11 @@ window.addEventListener("load", function() {
           .attr("font-family", "Inter,system-ui,sans-serif");
 
         aoNode.on("click", function(event, d) {
-          if (d.slug) window.location.href = "/" + d.slug + ".html";
+          if (d.slug) window.location.href = pageURL(d.slug);
         });
 
         aoNode.append("title").text(function(d) {
-          return d.name + (d.count ? " (" + d.count + " entities)" : "");
+          return (d.name || d.label) + (d.count ? " (" + d.count + (d.label ? " relations)" : " entities)") : "");
         });
 
         aoSim.on("tick", function() {
@@ -368,224 +388,97 @@ window.addEventListener("load", function() {
     } catch (e) { console.error("Architecture overview error:", e); }
   }
 
-  // --- Homepage Treemap (clickable) ---
-  var hpDataEl = document.getElementById("homepage-chart-data");
-  var hpChartEl = document.getElementById("homepage-chart");
-  if (hpDataEl && hpChartEl && typeof d3 !== "undefined") {
-    try {
-      var hpData = JSON.parse(hpDataEl.textContent.trim());
-      var hpW = hpChartEl.clientWidth || 800;
-      var hpH = 300;
-      var children = (hpData.taxonomies || []).map(function(t) {
-        return { name: t.name, value: t.count, slug: t.slug };
-      });
-      if (children.length > 0) {
-        var root = d3.hierarchy({ name: "root", children: children }).sum(function(d) { return d.value || 0; }).sort(function(a, b) { return b.value - a.value; });
-        d3.treemap().size([hpW, hpH]).padding(3).round(true)(root);
-        var colors = ["#6366f1", "#3b82f6", "#22c55e", "#f59e0b", "#ef4444", "#a855f7", "#ec4899", "#6b7280"];
-        var svg = d3.select(hpChartEl).append("svg").attr("width", hpW).attr("height", hpH);
-        var cell = svg.selectAll("g").data(root.leaves()).enter().append("g")
-          .attr("transform", function(d) { return "translate(" + d.x0 + "," + d.y0 + ")"; })
-          .style("cursor", "pointer")
-          .on("click", function(event, d) { if (d.data.slug) window.location.href = "/" + d.data.slug + "/index.html"; });
-        cell.append("rect").attr("width", function(d) { return d.x1 - d.x0; }).attr("height", function(d) { return d.y1 - d.y0; }).attr("rx", 4).attr("fill", function(d, i) { return colors[i % colors.length]; }).attr("opacity", 0.85);
-        cell.append("text").attr("x", 8).attr("y", 20).attr("fill", "#fff").attr("font-size", "13px").attr("font-weight", "600").attr("font-family", "Inter,system-ui,sans-serif").text(function(d) { var w = d.x1 - d.x0; return w > 60 ? d.data.name : ""; });
-        cell.append("text").attr("x", 8).attr("y", 38).attr("fill", "rgba(255,255,255,0.7)").attr("font-size", "12px").attr("font-family", "Inter,system-ui,sans-serif").text(function(d) { var w = d.x1 - d.x0; return w > 50 ? d.data.value : ""; });
-        cell.append("title").text(function(d) { return d.data.name + ": " + d.data.value + " entries"; });
-      }
-    } catch (e) { console.error("Homepage chart error:", e); }
+  // --- Listing Page Charts ---
+  // Each listing page embeds a chart set: {schema, total, charts: [...]},
+  // where every chart has a type of "bar", "treemap" or "timeline". The
+  // charts go into the page's chart containers in order; any beyond the
+  // last container share it.
+  var chartColors = ["#6366f1", "#3b82f6", "#22c55e", "#f59e0b", "#ef4444", "#a855f7", "#ec4899", "#6b7280"];
+  var chartFont = "Inter,system-ui,sans-serif";
+
+  function visit(url) { if (url) window.location.href = url; }
+
+  function drawBar(el, chart) {
+    var items = chart.items || [];
+    var w = el.clientWidth || 800;
+    var barH = 26, gap = 4, labelW = Math.min(180, w * 0.4);
+    var max = d3.max(items, function(d) { return d.count; }) || 1;
+    var scale = d3.scaleLinear().domain([0, max]).range([0, w - labelW - 60]);
+    var svg = d3.select(el).append("svg").attr("width", w).attr("height", items.length * (barH + gap));
+    items.forEach(function(d, i) {
+      var y = i * (barH + gap);
+      var label = d.name.length > 22 ? d.name.substring(0, 20) + ".." : d.name;
+      var g = svg.append("g").style("cursor", d.url ? "pointer" : "default").on("click", function() { visit(d.url); });
+      g.append("text").attr("x", labelW - 8).attr("y", y + barH / 2 + 4).attr("text-anchor", "end").attr("fill", "#9ca3af").attr("font-size", "13px").attr("font-family", chartFont).text(label);
+      g.append("rect").attr("x", labelW).attr("y", y).attr("width", Math.max(scale(d.count), 4)).attr("height", barH).attr("rx", 3).attr("fill", chartColors[0]).attr("opacity", 0.85);
+      g.append("text").attr("x", labelW + Math.max(scale(d.count), 4) + 8).attr("y", y + barH / 2 + 4).attr("fill", "#9ca3af").attr("font-size", "12px").attr("font-family", chartFont).text(d.count);
+      g.append("title").text(d.name + ": " + d.count);
+    });
   }
 
-  // --- Hub Charts (donut + top entities) ---
-  var hubDataEl = document.getElementById("hub-chart-data");
-  var hubChartEl = document.getElementById("hub-chart");
-  var hubSecEl = document.getElementById("hub-chart-secondary");
-  if (hubDataEl && hubChartEl && typeof d3 !== "undefined") {
-    try {
-      var hubData = JSON.parse(hubDataEl.textContent.trim());
-      var distributions = hubData.distributions || {};
-      var hubColors = ["#6366f1", "#3b82f6", "#22c55e", "#f59e0b", "#ef4444", "#a855f7", "#ec4899", "#6b7280"];
-      var dimLabels = { node_type: "Node Types", language: "Languages", domain: "Domains", extension: "File Extensions" };
-      var dimOrder = ["node_type", "language", "domain", "extension"];
-
-      // Pick the distribution with the most entries (>1 entry preferred)
-      var bestKey = null;
-      var bestLen = 0;
-      dimOrder.forEach(function(key) {
-        var arr = distributions[key] || [];
-        if (arr.length > bestLen) { bestLen = arr.length; bestKey = key; }
-      });
-
-      var dist = bestKey ? (distributions[bestKey] || []) : [];
-
-      // LEFT: Donut or profile bars
-      if (dist.length > 1) {
-        var hubW = hubChartEl.clientWidth || 400;
-        var hubH = 220;
-        var radius = Math.min(hubW * 0.3, hubH * 0.42);
-        var innerR = radius * 0.55;
-        var pie = d3.pie().value(function(d) { return d.count; }).sort(null);
-        var arc = d3.arc().innerRadius(innerR).outerRadius(radius);
-        var svg = d3.select(hubChartEl).append("svg").attr("width", hubW).attr("height", hubH);
-        var cx = Math.min(hubH / 2 + 10, hubW * 0.3);
-        var g = svg.append("g").attr("transform", "translate(" + cx + "," + (hubH / 2) + ")");
-        var arcs = g.selectAll("path").data(pie(dist)).enter().append("path").attr("d", arc).attr("fill", function(d, i) { return hubColors[i % hubColors.length]; }).attr("stroke", "#0f1117").attr("stroke-width", 2).style("cursor", "pointer")
-          .on("click", function(event, d) { window.location.href = "/" + bestKey + "/" + toSlug(d.data.name) + ".html"; });
-        arcs.append("title").text(function(d) { return d.data.name + ": " + d.data.count; });
-        g.append("text").attr("text-anchor", "middle").attr("y", 6).attr("fill", "#e4e4e7").attr("font-size", "20px").attr("font-weight", "700").attr("font-family", "Inter,system-ui,sans-serif").text(hubData.totalEntities || "");
-        svg.append("text").attr("x", cx).attr("y", hubH - 4).attr("text-anchor", "middle").attr("fill", "#6b7280").attr("font-size", "11px").attr("font-family", "Inter,system-ui,sans-serif").text(dimLabels[bestKey] || bestKey);
-        var legendX = cx + radius + 20;
-        dist.forEach(function(d, i) {
-          if (i >= 8) return;
-          var ly = 16 + i * 22;
-          var lg = svg.append("g").style("cursor", "pointer").on("click", function() { window.location.href = "/" + bestKey + "/" + toSlug(d.name) + ".html"; });
-          lg.append("rect").attr("x", legendX).attr("y", ly).attr("width", 10).attr("height", 10).attr("rx", 2).attr("fill", hubColors[i % hubColors.length]);
-          lg.append("text").attr("x", legendX + 16).attr("y", ly + 9).attr("fill", "#9ca3af").attr("font-size", "11px").attr("font-family", "Inter,system-ui,sans-serif").text(d.name + " (" + d.count + ")");
-        });
-      } else {
-        var profileBars = [];
-        dimOrder.forEach(function(key) {
-          var arr = distributions[key] || [];
-          if (arr.length > 0) {
-            profileBars.push({ name: dimLabels[key] || key, count: arr.length, detail: arr.map(function(d) { return d.name; }).join(", ") });
-          }
-        });
-        if (profileBars.length > 0) {
-          var pbW = hubChartEl.clientWidth || 400;
-          var pbBarH = 28;
-          var pbGap = 5;
-          var pbH = profileBars.length * (pbBarH + pbGap) + 30;
-          var pbLabelW = 120;
-          var pbMax = d3.max(profileBars, function(d) { return d.count; }) || 1;
-          var pbScale = d3.scaleLinear().domain([0, pbMax]).range([0, pbW - pbLabelW - 100]);
-          var svg = d3.select(hubChartEl).append("svg").attr("width", pbW).attr("height", pbH);
-          svg.append("text").attr("x", 0).attr("y", 14).attr("fill", "#6b7280").attr("font-size", "11px").attr("font-family", "Inter,system-ui,sans-serif").text(hubData.entryName + " — " + hubData.totalEntities + " entities");
-          profileBars.forEach(function(d, i) {
-            var y = 24 + i * (pbBarH + pbGap);
-            svg.append("text").attr("x", pbLabelW - 6).attr("y", y + pbBarH / 2 + 4).attr("text-anchor", "end").attr("fill", "#9ca3af").attr("font-size", "12px").attr("font-family", "Inter,system-ui,sans-serif").text(d.name);
-            svg.append("rect").attr("x", pbLabelW).attr("y", y).attr("width", Math.max(pbScale(d.count), 4)).attr("height", pbBarH).attr("rx", 3).attr("fill", hubColors[i % hubColors.length]).attr("opacity", 0.85);
-            svg.append("text").attr("x", pbLabelW + Math.max(pbScale(d.count), 4) + 6).attr("y", y + pbBarH / 2 + 4).attr("fill", "#e4e4e7").attr("font-size", "12px").attr("font-weight", "600").attr("font-family", "Inter,system-ui,sans-serif").text(d.detail);
-          });
-        }
-      }
-
-      // RIGHT: Top entities by line count
-      var topEnts = hubData.topEntities || [];
-      if (hubSecEl && topEnts.length > 0) {
-        var teW = hubSecEl.clientWidth || 400;
-        var teBarH = 22;
-        var teGap = 3;
-        var teH = topEnts.length * (teBarH + teGap) + 24;
-        var teLabelW = Math.min(teW * 0.45, 200);
-        var teMax = d3.max(topEnts, function(d) { return d.lines; }) || 1;
-        var teScale = d3.scaleLinear().domain([0, teMax]).range([0, teW - teLabelW - 60]);
-        var typeColors = { Function: "#22c55e", Class: "#f59e0b", File: "#3b82f6", Type: "#ef4444", Domain: "#6366f1", Subdomain: "#a855f7" };
-
-        var teSvg = d3.select(hubSecEl).append("svg").attr("width", teW).attr("height", teH);
-        teSvg.append("text").attr("x", 0).attr("y", 12).attr("fill", "#6b7280").attr("font-size", "11px").attr("font-weight", "600")
-          .attr("text-transform", "uppercase").attr("letter-spacing", "0.04em")
-          .attr("font-family", "Inter,system-ui,sans-serif").text("LARGEST BY LINES OF CODE");
-
-        topEnts.forEach(function(d, i) {
-          var y = 22 + i * (teBarH + teGap);
-          var label = d.name.replace(/ — .*/, "");
-          if (label.length > 26) label = label.substring(0, 24) + "..";
-          var g = teSvg.append("g").style("cursor", "pointer")
-            .on("click", function() { window.location.href = "/" + d.slug + ".html"; });
-          g.append("text").attr("x", teLabelW - 6).attr("y", y + teBarH / 2 + 4).attr("text-anchor", "end")
-            .attr("fill", "#9ca3af").attr("font-size", "11px").attr("font-family", "Inter,system-ui,sans-serif").text(label);
-          g.append("rect").attr("x", teLabelW).attr("y", y).attr("width", Math.max(teScale(d.lines), 3)).attr("height", teBarH)
-            .attr("rx", 3).attr("fill", typeColors[d.type] || "#6366f1").attr("opacity", 0.85);
-          g.append("text").attr("x", teLabelW + Math.max(teScale(d.lines), 3) + 5).attr("y", y + teBarH / 2 + 4)
-            .attr("fill", "#6b7280").attr("font-size", "10px").attr("font-family", "Inter,system-ui,sans-serif").text(d.lines);
-          g.append("title").text(d.name + " (" + d.type + ") — " + d.lines + " lines");
-        });
-      }
-    } catch (e) { console.error("Hub chart error:", e); }
+  function drawTreemap(el, chart) {
+    var w = el.clientWidth || 800, h = 300;
+    var root = d3.hierarchy({ name: "root", children: chart.items || [] }, function(d) { return d.children; })
+      .sum(function(d) { return d.children ? 0 : d.count || 0; })
+      .sort(function(a, b) { return b.value - a.value; });
+    d3.treemap().size([w, h]).padding(3).round(true)(root);
+    var top = root.children || [];
+    var svg = d3.select(el).append("svg").attr("width", w).attr("height", h);
+    var cell = svg.selectAll("g").data(root.leaves()).enter().append("g")
+      .attr("transform", function(d) { return "translate(" + d.x0 + "," + d.y0 + ")"; })
+      .style("cursor", "pointer")
+      .on("click", function(event, d) { visit(d.data.url); });
+    cell.append("rect").attr("width", function(d) { return d.x1 - d.x0; }).attr("height", function(d) { return d.y1 - d.y0; }).attr("rx", 4)
+      .attr("fill", function(d) { var p = d.depth > 1 ? d.parent : d; return chartColors[top.indexOf(p) % chartColors.length]; }).attr("opacity", 0.85);
+    cell.append("text").attr("x", 8).attr("y", 20).attr("fill", "#fff").attr("font-size", "13px").attr("font-weight", "600").attr("font-family", chartFont).text(function(d) { return d.x1 - d.x0 > 60 ? d.data.name : ""; });
+    cell.append("text").attr("x", 8).attr("y", 38).attr("fill", "rgba(255,255,255,0.7)").attr("font-size", "12px").attr("font-family", chartFont).text(function(d) { return d.x1 - d.x0 > 50 ? d.value : ""; });
+    cell.append("title").text(function(d) { return (d.depth > 1 ? d.parent.data.name + " / " : "") + d.data.name + ": " + d.value; });
   }
 
-  // --- Taxonomy Index Bar Chart ---
-  var taxDataEl = document.getElementById("taxonomy-chart-data");
-  var taxChartEl = document.getElementById("taxonomy-chart");
-  if (taxDataEl && taxChartEl && typeof d3 !== "undefined") {
-    try {
-      var taxData = JSON.parse(taxDataEl.textContent.trim());
-      var entries = (taxData.entries || []).slice(0, 20);
-      var taxKey = taxData.taxonomyKey || "";
-      if (entries.length > 0) {
-        var taxW = taxChartEl.clientWidth || 800;
-        var barH = 26;
-        var gap = 4;
-        var taxH = entries.length * (barH + gap);
-        var labelW = 180;
-        var maxCount = d3.max(entries, function(d) { return d.count; }) || 1;
-        var barScale = d3.scaleLinear().domain([0, maxCount]).range([0, taxW - labelW - 80]);
-        var svg = d3.select(taxChartEl).append("svg").attr("width", taxW).attr("height", taxH);
-        entries.forEach(function(d, i) {
-          var y = i * (barH + gap);
-          var label = d.name.length > 22 ? d.name.substring(0, 20) + ".." : d.name;
-          var g = svg.append("g").style("cursor", "pointer").on("click", function() { if (taxKey) window.location.href = "/" + taxKey + "/" + toSlug(d.name) + ".html"; });
-          g.append("text").attr("x", labelW - 8).attr("y", y + barH / 2 + 4).attr("text-anchor", "end").attr("fill", "#9ca3af").attr("font-size", "13px").attr("font-family", "Inter,system-ui,sans-serif").text(label);
-          g.append("rect").attr("x", labelW).attr("y", y).attr("width", Math.max(barScale(d.count), 4)).attr("height", barH).attr("rx", 3).attr("fill", "#6366f1").attr("opacity", 0.85);
-          g.append("text").attr("x", labelW + Math.max(barScale(d.count), 4) + 8).attr("y", y + barH / 2 + 4).attr("fill", "#9ca3af").attr("font-size", "12px").attr("font-family", "Inter,system-ui,sans-serif").text(d.count);
-        });
-      }
-    } catch (e) { console.error("Taxonomy chart error:", e); }
+  function drawTimeline(el, chart) {
+    var points = chart.points || [];
+    var w = el.clientWidth || 800, h = 200, pad = 24;
+    var x = d3.scaleBand().domain(points.map(function(d) { return d.date; })).range([pad, w - pad]).padding(0.15);
+    var max = d3.max(points, function(d) { return d.count; }) || 1;
+    var y = d3.scaleLinear().domain([0, max]).range([h - pad, pad]);
+    var svg = d3.select(el).append("svg").attr("width", w).attr("height", h);
+    var bars = svg.selectAll("rect").data(points).enter().append("rect")
+      .attr("x", function(d) { return x(d.date); }).attr("y", function(d) { return y(d.count); })
+      .attr("width", x.bandwidth()).attr("height", function(d) { return h - pad - y(d.count); })
+      .attr("rx", 2).attr("fill", chartColors[1]).attr("opacity", 0.85);
+    bars.append("title").text(function(d) { return d.date + ": " + d.count; });
+    [points[0], points[points.length - 1]].forEach(function(d, i) {
+      if (!d) return;
+      svg.append("text").attr("x", i ? w - pad : pad).attr("y", h - 6).attr("text-anchor", i ? "end" : "start").attr("fill", "#6b7280").attr("font-size", "11px").attr("font-family", chartFont).text(d.date);
+    });
   }
 
-  // --- All Entities Packed Circles ---
-  var aeDataEl = document.getElementById("all-entities-chart-data");
-  var aeChartEl = document.getElementById("all-entities-chart");
-  if (aeDataEl && aeChartEl && typeof d3 !== "undefined") {
-    try {
-      var aeData = JSON.parse(aeDataEl.textContent.trim());
-      var types = aeData.typeDistribution || [];
-      if (types.length > 0) {
-        var aeW = aeChartEl.clientWidth || 800;
-        var aeH = 320;
-        var aeColors = ["#6366f1", "#3b82f6", "#22c55e", "#f59e0b", "#ef4444", "#a855f7", "#ec4899", "#6b7280"];
-        var root = d3.hierarchy({ children: types }).sum(function(d) { return d.count || 0; });
-        d3.pack().size([aeW, aeH]).padding(4)(root);
-        var svg = d3.select(aeChartEl).append("svg").attr("width", aeW).attr("height", aeH);
-        var node = svg.selectAll("g").data(root.leaves()).enter().append("g").attr("transform", function(d) { return "translate(" + d.x + "," + d.y + ")"; })
-          .style("cursor", "pointer").on("click", function(event, d) { window.location.href = "/" + "node_type/" + toSlug(d.data.name) + ".html"; });
-        node.append("circle").attr("r", function(d) { return d.r; }).attr("fill", function(d, i) { return aeColors[i % aeColors.length]; }).attr("opacity", 0.8).attr("stroke", "#0f1117").attr("stroke-width", 1);
-        node.append("text").attr("text-anchor", "middle").attr("y", -4).attr("fill", "#fff").attr("font-size", function(d) { return Math.max(10, Math.min(16, d.r / 3)) + "px"; }).attr("font-weight", "600").attr("font-family", "Inter,system-ui,sans-serif").text(function(d) { return d.r > 25 ? d.data.name : ""; });
-        node.append("text").attr("text-anchor", "middle").attr("y", 12).attr("fill", "rgba(255,255,255,0.7)").attr("font-size", "11px").attr("font-family", "Inter,system-ui,sans-serif").text(function(d) { return d.r > 20 ? d.data.count : ""; });
-        node.append("title").text(function(d) { return d.data.name + ": " + d.data.count; });
-      }
-    } catch (e) { console.error("All entities chart error:", e); }
-  }
+  var chartDrawers = { bar: drawBar, treemap: drawTreemap, timeline: drawTimeline };
 
-  // --- Letter Page Bar Chart ---
-  var ltDataEl = document.getElementById("letter-chart-data");
-  var ltChartEl = document.getElementById("letter-chart");
-  if (ltDataEl && ltChartEl && typeof d3 !== "undefined") {
+  function drawCharts(dataId, containerIds) {
+    var dataEl = document.getElementById(dataId);
+    var els = containerIds.map(function(id) { return document.getElementById(id); }).filter(Boolean);
+    if (!dataEl || els.length === 0 || typeof d3 === "undefined") return;
     try {
-      var ltData = JSON.parse(ltDataEl.textContent.trim());
-      var ltEntries = (ltData.entries || []).slice(0, 15);
-      var ltKey = ltData.taxonomyKey || "";
-      if (ltEntries.length > 0) {
-        var ltW = ltChartEl.clientWidth || 800;
-        var ltBarH = 26;
-        var ltGap = 4;
-        var ltH = ltEntries.length * (ltBarH + ltGap);
-        var ltLabelW = 180;
-        var ltMax = d3.max(ltEntries, function(d) { return d.count; }) || 1;
-        var ltScale = d3.scaleLinear().domain([0, ltMax]).range([0, ltW - ltLabelW - 80]);
-        var svg = d3.select(ltChartEl).append("svg").attr("width", ltW).attr("height", ltH);
-        ltEntries.forEach(function(d, i) {
-          var y = i * (ltBarH + ltGap);
-          var label = d.name.length > 22 ? d.name.substring(0, 20) + ".." : d.name;
-          var g = svg.append("g").style("cursor", "pointer").on("click", function() { if (ltKey) window.location.href = "/" + ltKey + "/" + toSlug(d.name) + ".html"; });
-          g.append("text").attr("x", ltLabelW - 8).attr("y", y + ltBarH / 2 + 4).attr("text-anchor", "end").attr("fill", "#9ca3af").attr("font-size", "13px").attr("font-family", "Inter,system-ui,sans-serif").text(label);
-          g.append("rect").attr("x", ltLabelW).attr("y", y).attr("width", Math.max(ltScale(d.count), 4)).attr("height", ltBarH).attr("rx", 3).attr("fill", "#6366f1").attr("opacity", 0.85);
-          g.append("text").attr("x", ltLabelW + Math.max(ltScale(d.count), 4) + 8).attr("y", y + ltBarH / 2 + 4).attr("fill", "#9ca3af").attr("font-size", "12px").attr("font-family", "Inter,system-ui,sans-serif").text(d.count);
-        });
-      }
-    } catch (e) { console.error("Letter chart error:", e); }
+      var set = JSON.parse(dataEl.textContent.trim());
+      if (typeof set === "string") set = JSON.parse(set);
+      (set.charts || []).forEach(function(chart, i) {
+        var draw = chartDrawers[chart.type];
+        if (!draw) return;
+        var el = els[Math.min(i, els.length - 1)].appendChild(document.createElement("div"));
+        el.className = "chart chart-" + chart.type;
+        el.setAttribute("data-chart", chart.name);
+        draw(el, chart);
+      });
+    } catch (e) { console.error("Chart error (" + dataId + "):", e); }
   }
 
+  drawCharts("homepage-chart-data", ["homepage-chart"]);
+  drawCharts("hub-chart-data", ["hub-chart", "hub-chart-secondary"]);
+  drawCharts("taxonomy-chart-data", ["taxonomy-chart"]);
+  drawCharts("all-entities-chart-data", ["all-entities-chart"]);
+  drawCharts("letter-chart-data", ["letter-chart"]);
+
   // --- Mermaid Init ---
   if (typeof mermaid !== "undefined") {
     try {
@@ -608,6 +501,24 @@ window.addEventListener("load", function() {
     }
   }
 
+  // --- Runbook Progress ---
+  // Checked steps are remembered per page in localStorage, keyed by the
+  // stable step ids, so progress survives reloads and step reordering.
+  var steps = document.querySelectorAll(".runbook-step[data-step] input[type=checkbox]");
+  if (steps.length) {
+    var key = "runbook:" + location.pathname;
+    var saved = {};
+    try { saved = JSON.parse(localStorage.getItem(key) || "{}"); } catch (e) {}
+    steps.forEach(function(box) {
+      var id = box.closest(".runbook-step").getAttribute("data-step");
+      if (id in saved) box.checked = saved[id];
+      box.addEventListener("change", function() {
+        saved[id] = box.checked;
+        try { localStorage.setItem(key, JSON.stringify(saved)); } catch (e) {}
+      });
+    });
+  }
+
 });
 
 // --- Site Search ---
@@ -637,7 +548,7 @@ window.addEventListener("load", function() {
   }
 
   function loadIndex() {
-    fetch("/search-index.json")
+    fetch(basePath + "/search-index.json")
       .then(function(r) { return r.json(); })
       .then(function(data) { index = data; })
       .catch(function() { resultsEl.innerHTML = '<div class="search-no-results">Failed to load search index.</div>'; });
@@ -651,7 +562,7 @@ window.addEventListener("load", function() {
     var scored = [];
     for (var i = 0; i < index.length; i++) {
       var e = index[i];
-      var haystack = (e.t + " " + (e.d || "") + " " + (e.n || "") + " " + (e.l || "") + " " + (e.m || "")).toLowerCase();
+      var haystack = (e.t + " " + (e.d || "") + " " + (e.n || "") + " " + (e.l || "") + " " + (e.m || "") + " " + (e.k || []).join(" ")).toLowerCase();
       var titleLower = e.t.toLowerCase();
       var allMatch = true;
       for (var j = 0; j < tokens.length; j++) {
@@ -685,7 +596,7 @@ window.addEventListener("load", function() {
     for (var i = 0; i < results.length; i++) {
       var e = results[i].entry;
       var cls = i === activeIdx ? "search-result active" : "search-result";
-      html += '<a href="/' + e.s + '.html" class="' + cls + '">';
+      html += '<a href="' + pageURL(e.s) + '" class="' + cls + '">';
       html += '<div class="search-result-title">' + escHtml(e.t) + '</div>';
       if (e.d) html += '<div class="search-result-desc">' + escHtml(e.d) + '</div>';
       html += '<div class="search-result-meta">';
@@ -715,7 +626,7 @@ window.addEventListener("load", function() {
     if (e.key === "Escape") { closeSearch(); }
     else if (e.key === "ArrowDown") { e.preventDefault(); if (activeIdx < results.length - 1) { activeIdx++; renderResults(); scrollActive(); } }
     else if (e.key === "ArrowUp") { e.preventDefault(); if (activeIdx > 0) { activeIdx--; renderResults(); scrollActive(); } }
-    else if (e.key === "Enter" && activeIdx >= 0 && results[activeIdx]) { e.preventDefault(); window.location.href = "/" + results[activeIdx].entry.s + ".html"; }
+    else if (e.key === "Enter" && activeIdx >= 0 && results[activeIdx]) { e.preventDefault(); window.location.href = pageURL(results[activeIdx].entry.s); }
   });
 
   function scrollActive() {